@@ -78,6 +78,10 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 		account.Privacy.IPv4Config.AnonKeepBits = iputil.IPv4DefaultMaskingBitSize
 	}
 
+	if cookieErrs := account.Cookie.Validate("cookie", nil); len(cookieErrs) > 0 {
+		account.Cookie = cfg.AccountDefaults.Cookie
+	}
+
 	return account, nil
 }
 
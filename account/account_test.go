@@ -27,6 +27,7 @@ var mockAccountData = map[string]json.RawMessage{
 	"valid_acct_dsa":            json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + validDSA + `"}}}`),
 	"invalid_acct_dsa":          json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + invalidDSA + `"}}}`),
 	"invalid_acct_ipv6_ipv4":    json.RawMessage(`{"disabled":false, "privacy": {"ipv6": {"anon_keep_bits": -32}, "ipv4": {"anon_keep_bits": -16}}}`),
+	"invalid_acct_cookie":       json.RawMessage(`{"disabled":false, "cookie": {"attributes": {"same_site": "invalid"}, "cohorts": [{"name": "a", "percent": 120}]}}`),
 	"disabled_acct":             json.RawMessage(`{"disabled":true}`),
 	"malformed_acct":            json.RawMessage(`{"disabled":"invalid type"}`),
 	"gdpr_channel_enabled_acct": json.RawMessage(`{"disabled":false,"gdpr":{"channel_enabled":{"amp":true}}}`),
@@ -63,7 +64,9 @@ func TestGetAccount(t *testing.T) {
 		disabled bool
 		// checkDefaultIP indicates IPv6 and IPv6 should be set to default values
 		wantDefaultIP bool
-		wantDSA       *openrtb_ext.ExtRegsDSA
+		// wantDefaultCookie indicates the cookie attributes and cohorts should be set to default values
+		wantDefaultCookie bool
+		wantDSA           *openrtb_ext.ExtRegsDSA
 		// expected error, or nil if account should be found
 		err error
 	}{
@@ -91,6 +94,7 @@ func TestGetAccount(t *testing.T) {
 		{accountID: "valid_acct_dsa", required: true, disabled: true, wantDSA: validDSA, err: nil},
 
 		{accountID: "invalid_acct_ipv6_ipv4", required: true, disabled: false, err: nil, wantDefaultIP: true},
+		{accountID: "invalid_acct_cookie", required: false, disabled: false, err: nil, wantDefaultCookie: true},
 		{accountID: "invalid_acct_dsa", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
 
 		// pubID given and matches a host account explicitly disabled (Disabled: true on account json)
@@ -117,7 +121,7 @@ func TestGetAccount(t *testing.T) {
 		t.Run(description, func(t *testing.T) {
 			cfg := &config.Configuration{
 				AccountRequired: test.required,
-				AccountDefaults: config.Account{Disabled: test.disabled},
+				AccountDefaults: config.Account{Disabled: test.disabled, Cookie: config.AccountCookie{Attributes: config.CookieAttributes{SameSite: "lax"}}},
 			}
 			fetcher := &mockAccountFetcher{}
 			assert.NoError(t, cfg.MarshalAccountDefaults())
@@ -140,6 +144,9 @@ func TestGetAccount(t *testing.T) {
 				assert.Equal(t, account.Privacy.IPv6Config.AnonKeepBits, iputil.IPv6DefaultMaskingBitSize, "ipv6 should be set to default value")
 				assert.Equal(t, account.Privacy.IPv4Config.AnonKeepBits, iputil.IPv4DefaultMaskingBitSize, "ipv4 should be set to default value")
 			}
+			if test.wantDefaultCookie {
+				assert.Equal(t, cfg.AccountDefaults.Cookie, account.Cookie, "cookie should be set to default value")
+			}
 			if test.wantDSA != nil {
 				assert.Equal(t, test.wantDSA, account.Privacy.DSA.DefaultUnpacked)
 			}
@@ -5,8 +5,11 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
+	"github.com/prebid/prebid-server/v2/usersync"
 )
 
 // Module must be implemented by analytics modules to extract the required information and logging
@@ -32,6 +35,9 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	TCF2Explanations     []openrtb_ext.ExtResponseDebugTCF2Bidder
+	PrivacyAudit         *audit.Record
+	DSAReport            *dsa.Report
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -45,29 +51,48 @@ type AmpObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	TCF2Explanations     []openrtb_ext.ExtResponseDebugTCF2Bidder
+	PrivacyAudit         *audit.Record
+	DSAReport            *dsa.Report
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
 type VideoObject struct {
-	Status         int
-	Errors         []error
-	Response       *openrtb2.BidResponse
-	VideoRequest   *openrtb_ext.BidRequestVideo
-	VideoResponse  *openrtb_ext.BidResponseVideo
-	StartTime      time.Time
-	SeatNonBid     []openrtb_ext.SeatNonBid
-	RequestWrapper *openrtb_ext.RequestWrapper
+	Status           int
+	Errors           []error
+	Response         *openrtb2.BidResponse
+	VideoRequest     *openrtb_ext.BidRequestVideo
+	VideoResponse    *openrtb_ext.BidResponseVideo
+	StartTime        time.Time
+	SeatNonBid       []openrtb_ext.SeatNonBid
+	RequestWrapper   *openrtb_ext.RequestWrapper
+	TCF2Explanations []openrtb_ext.ExtResponseDebugTCF2Bidder
+	PrivacyAudit     *audit.Record
+	DSAReport        *dsa.Report
 }
 
 // Loggable object of a transaction at /setuid
 type SetUIDObject struct {
-	Status  int
-	Bidder  string
-	UID     string
-	Errors  []error
-	Success bool
+	Status           int
+	Bidder           string
+	UID              string
+	Errors           []error
+	Success          bool
+	CookieAttributes *usersync.CookieAttributes
+	// Rejection is the reason the request was rejected by the abuse protection of the endpoint, if it was.
+	Rejection SetUIDRejection
 }
 
+// SetUIDRejection is the reason a /setuid request was rejected by the abuse protection of the endpoint.
+type SetUIDRejection string
+
+const (
+	SetUIDRejectionInvalidSignature SetUIDRejection = "invalid_signature"
+	SetUIDRejectionReplay           SetUIDRejection = "replay"
+	SetUIDRejectionReplayCacheFull  SetUIDRejection = "replay_cache_full"
+	SetUIDRejectionRateLimited      SetUIDRejection = "rate_limited"
+)
+
 // Loggable object of a transaction at /cookie_sync
 type CookieSyncObject struct {
 	Status       int
@@ -130,6 +130,8 @@ func jsonifyAuctionObject(ao *analytics.AuctionObject) string {
 			Account:              ao.Account,
 			StartTime:            ao.StartTime,
 			HookExecutionOutcome: ao.HookExecutionOutcome,
+			PrivacyAudit:         ao.PrivacyAudit,
+			DSAReport:            ao.DSAReport,
 		}
 	}
 
@@ -163,6 +165,8 @@ func jsonifyVideoObject(vo *analytics.VideoObject) string {
 			VideoRequest:  vo.VideoRequest,
 			VideoResponse: vo.VideoResponse,
 			StartTime:     vo.StartTime,
+			PrivacyAudit:  vo.PrivacyAudit,
+			DSAReport:     vo.DSAReport,
 		}
 	}
 
@@ -210,11 +214,13 @@ func jsonifySetUIDObject(so *analytics.SetUIDObject) string {
 	var logEntry *logSetUID
 	if so != nil {
 		logEntry = &logSetUID{
-			Status:  so.Status,
-			Bidder:  so.Bidder,
-			UID:     so.UID,
-			Errors:  so.Errors,
-			Success: so.Success,
+			Status:           so.Status,
+			Bidder:           so.Bidder,
+			UID:              so.UID,
+			Errors:           so.Errors,
+			Success:          so.Success,
+			CookieAttributes: so.CookieAttributes,
+			Rejection:        so.Rejection,
 		}
 	}
 
@@ -249,6 +255,8 @@ func jsonifyAmpObject(ao *analytics.AmpObject) string {
 			Origin:               ao.Origin,
 			StartTime:            ao.StartTime,
 			HookExecutionOutcome: ao.HookExecutionOutcome,
+			PrivacyAudit:         ao.PrivacyAudit,
+			DSAReport:            ao.DSAReport,
 		}
 	}
 
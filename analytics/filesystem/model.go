@@ -6,8 +6,11 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
+	"github.com/prebid/prebid-server/v2/usersync"
 )
 
 type logAuction struct {
@@ -18,6 +21,8 @@ type logAuction struct {
 	Account              *config.Account
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	PrivacyAudit         *audit.Record `json:",omitempty"`
+	DSAReport            *dsa.Report   `json:",omitempty"`
 }
 
 type logVideo struct {
@@ -28,14 +33,18 @@ type logVideo struct {
 	VideoRequest  *openrtb_ext.BidRequestVideo
 	VideoResponse *openrtb_ext.BidResponseVideo
 	StartTime     time.Time
+	PrivacyAudit  *audit.Record `json:",omitempty"`
+	DSAReport     *dsa.Report   `json:",omitempty"`
 }
 
 type logSetUID struct {
-	Status  int
-	Bidder  string
-	UID     string
-	Errors  []error
-	Success bool
+	Status           int
+	Bidder           string
+	UID              string
+	Errors           []error
+	Success          bool
+	CookieAttributes *usersync.CookieAttributes `json:",omitempty"`
+	Rejection        analytics.SetUIDRejection  `json:",omitempty"`
 }
 
 type logUserSync struct {
@@ -53,6 +62,8 @@ type logAMP struct {
 	Origin               string
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	PrivacyAudit         *audit.Record `json:",omitempty"`
+	DSAReport            *dsa.Report   `json:",omitempty"`
 }
 
 type logNotificationEvent struct {
@@ -42,6 +42,21 @@ type Account struct {
 	DefaultBidLimit         int                                         `mapstructure:"default_bid_limit" json:"default_bid_limit"`
 	BidAdjustments          *openrtb_ext.ExtRequestPrebidBidAdjustments `mapstructure:"bidadjustments" json:"bidadjustments"`
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
+	Cookie                  AccountCookie                               `mapstructure:"cookie" json:"cookie"`
+}
+
+// AccountCookie represents the account-specific attributes of the uids cookie written by /setuid. The cohorts
+// replace the ones of the host when set.
+type AccountCookie struct {
+	Attributes CookieAttributes `mapstructure:"attributes" json:"attributes"`
+	Cohorts    []CookieCohort   `mapstructure:"cohorts" json:"cohorts,omitempty"`
+}
+
+// Validate checks the attributes and cohorts the same way as the ones of the host cookie.
+func (cfg *AccountCookie) Validate(prefix string, errs []error) []error {
+	errs = cfg.Attributes.validate(prefix+".attributes", errs)
+	errs = validateCookieCohorts(prefix+".cohorts", cfg.Cohorts, errs)
+	return errs
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
@@ -49,8 +64,18 @@ type CookieSync struct {
 	DefaultLimit    *int  `mapstructure:"default_limit" json:"default_limit"`
 	MaxLimit        *int  `mapstructure:"max_limit" json:"max_limit"`
 	DefaultCoopSync *bool `mapstructure:"default_coop_sync" json:"default_coop_sync"`
+	// Prioritization is how the bidders to sync are ordered, one of CookieSyncPrioritizationRandom, the default,
+	// or CookieSyncPrioritizationYield.
+	Prioritization string `mapstructure:"prioritization" json:"prioritization"`
 }
 
+const (
+	// CookieSyncPrioritizationRandom orders the bidders to sync by the host priority groups and at random.
+	CookieSyncPrioritizationRandom = "random"
+	// CookieSyncPrioritizationYield orders the bidders to sync by the value of a synced UID to each of them.
+	CookieSyncPrioritizationYield = "yield"
+)
+
 // AccountCCPA represents account-specific CCPA configuration
 type AccountCCPA struct {
 	Enabled        *bool          `mapstructure:"enabled" json:"enabled,omitempty"`
@@ -334,11 +359,96 @@ func (m AccountModules) ModuleConfig(id string) (json.RawMessage, error) {
 }
 
 type AccountPrivacy struct {
-	AllowActivities *AllowActivities `mapstructure:"allowactivities" json:"allowactivities"`
-	DSA             *AccountDSA      `mapstructure:"dsa" json:"dsa"`
-	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
-	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
-	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	AllowActivities *AllowActivities  `mapstructure:"allowactivities" json:"allowactivities"`
+	DSA             *AccountDSA       `mapstructure:"dsa" json:"dsa"`
+	IPv6Config      IPv6              `mapstructure:"ipv6" json:"ipv6"`
+	IPv4Config      IPv4              `mapstructure:"ipv4" json:"ipv4"`
+	PrivacySandbox  PrivacySandbox    `mapstructure:"privacysandbox" json:"privacysandbox"`
+	USNat           AccountUSNat      `mapstructure:"usnat" json:"usnat"`
+	GPC             AccountGPC        `mapstructure:"gpc" json:"gpc"`
+	KAnonymity      AccountKAnonymity `mapstructure:"kanonymity" json:"kanonymity"`
+	// DataMinimization overrides the data minimization profiles of the bidders, keyed by bidder name
+	DataMinimization map[string]AccountDataMinimization `mapstructure:"data_minimization" json:"data_minimization"`
+	ChildDirected    AccountChildDirected               `mapstructure:"child_directed" json:"child_directed"`
+}
+
+// AccountChildDirected lists the inventory of the account which is directed to children. Requests for it are
+// treated as COPPA requests even without regs.coppa. Strict adds a stricter profile to all COPPA requests of
+// the account, which also removes the eids, user data and geo sent to bidders and blocks user syncs.
+type AccountChildDirected struct {
+	Bundles          []string `mapstructure:"bundles" json:"bundles"`
+	Domains          []string `mapstructure:"domains" json:"domains"`
+	StoredRequestIDs []string `mapstructure:"stored_request_ids" json:"stored_request_ids"`
+	Strict           bool     `mapstructure:"strict" json:"strict"`
+}
+
+// AccountDataMinimization lists the ORTB paths of the request which may be sent to a bidder for the account.
+type AccountDataMinimization struct {
+	AllowedPaths []string `mapstructure:"allowed_paths" json:"allowed_paths"`
+}
+
+// AccountGPC overrides the host configuration of the Global Privacy Control signal enforcement.
+type AccountGPC struct {
+	Enforce *bool `mapstructure:"enforce" json:"enforce,omitempty"`
+}
+
+// IsEnforced returns true if the Global Privacy Control signal should be enforced, the host setting
+// is used if the account does not specify it.
+func (a AccountGPC) IsEnforced(hostEnforce bool) bool {
+	if a.Enforce != nil {
+		return *a.Enforce
+	}
+	return hostEnforce
+}
+
+// AccountUSNat configures the enforcement of the GPP US national and state sections
+// on the transmitUfpd, transmitPreciseGeo, transmitEids, syncUser and transmitTid activities.
+type AccountUSNat struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// SkipSIDs lists the IDs of the US sections which are not enforced.
+	SkipSIDs []int8 `mapstructure:"skip_sids" json:"skip_sids"`
+	// NormalizeStates derives the US national fields missing from the state sections, e.g. the targeted
+	// advertising opt-out of California from its sharing opt-out.
+	NormalizeStates bool `mapstructure:"normalize_states" json:"normalize_states"`
+}
+
+// AccountKAnonymity configures the minimization of the user.data segments and user.eids sources sent to
+// bidders. Segments and sources with an audience population below MinPopulation are dropped and at most
+// MaxSegments segments are sent, preferring the largest audiences. Zero disables either limit.
+type AccountKAnonymity struct {
+	Enabled       bool                               `mapstructure:"enabled" json:"enabled"`
+	MinPopulation int                                `mapstructure:"min_population" json:"min_population"`
+	MaxSegments   int                                `mapstructure:"max_segments" json:"max_segments"`
+	Bidders       map[string]AccountKAnonymityBidder `mapstructure:"bidders" json:"bidders"`
+}
+
+// AccountKAnonymityBidder overrides the account k-anonymity settings for a single bidder.
+type AccountKAnonymityBidder struct {
+	Enabled       *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	MinPopulation *int  `mapstructure:"min_population" json:"min_population,omitempty"`
+	MaxSegments   *int  `mapstructure:"max_segments" json:"max_segments,omitempty"`
+}
+
+// ForBidder resolves the k-anonymity settings which apply to the bidder, matching its name case insensitively.
+func (a AccountKAnonymity) ForBidder(bidder string) (enabled bool, minPopulation, maxSegments int) {
+	enabled, minPopulation, maxSegments = a.Enabled, a.MinPopulation, a.MaxSegments
+
+	for name, override := range a.Bidders {
+		if !strings.EqualFold(name, bidder) {
+			continue
+		}
+		if override.Enabled != nil {
+			enabled = *override.Enabled
+		}
+		if override.MinPopulation != nil {
+			minPopulation = *override.MinPopulation
+		}
+		if override.MaxSegments != nil {
+			maxSegments = *override.MaxSegments
+		}
+		break
+	}
+	return
 }
 
 type PrivacySandbox struct {
@@ -353,9 +463,21 @@ type CookieDeprecation struct {
 
 // AccountDSA represents DSA configuration
 type AccountDSA struct {
-	Default         string `mapstructure:"default" json:"default"`
-	DefaultUnpacked *openrtb_ext.ExtRegsDSA
-	GDPROnly        bool `mapstructure:"gdpr_only" json:"gdpr_only"`
+	Default           string `mapstructure:"default" json:"default"`
+	DefaultUnpacked   *openrtb_ext.ExtRegsDSA
+	GDPROnly          bool                        `mapstructure:"gdpr_only" json:"gdpr_only"`
+	BidderEnforcement AccountDSABidderEnforcement `mapstructure:"bidder_enforcement" json:"bidder_enforcement"`
+}
+
+// AccountDSABidderEnforcement disables a bidder for requests requiring DSA once the share of its bids failing
+// DSA validation exceeds MaxFailureRate. The rate is evaluated over windows of WindowSeconds once at least
+// MinBids bids were validated, and a disabled bidder is skipped for DisableSeconds.
+type AccountDSABidderEnforcement struct {
+	Enabled        bool    `mapstructure:"enabled" json:"enabled"`
+	MaxFailureRate float64 `mapstructure:"max_failure_rate" json:"max_failure_rate"`
+	MinBids        int     `mapstructure:"min_bids" json:"min_bids"`
+	WindowSeconds  int     `mapstructure:"window_seconds" json:"window_seconds"`
+	DisableSeconds int     `mapstructure:"disable_seconds" json:"disable_seconds"`
 }
 
 type IPv6 struct {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -146,6 +147,45 @@ func TestAccountCCPAEnabledForChannelType(t *testing.T) {
 	}
 }
 
+func TestAccountGPCIsEnforced(t *testing.T) {
+	tests := []struct {
+		description  string
+		giveEnforce  *bool
+		giveHost     bool
+		wantEnforced bool
+	}{
+		{
+			description:  "Account unspecified, host enforced",
+			giveEnforce:  nil,
+			giveHost:     true,
+			wantEnforced: true,
+		},
+		{
+			description:  "Account unspecified, host not enforced",
+			giveEnforce:  nil,
+			giveHost:     false,
+			wantEnforced: false,
+		},
+		{
+			description:  "Account enforced, host not enforced",
+			giveEnforce:  ptrutil.ToPtr(true),
+			giveHost:     false,
+			wantEnforced: true,
+		},
+		{
+			description:  "Account not enforced, host enforced",
+			giveEnforce:  ptrutil.ToPtr(false),
+			giveHost:     true,
+			wantEnforced: false,
+		},
+	}
+
+	for _, tt := range tests {
+		account := Account{Privacy: AccountPrivacy{GPC: AccountGPC{Enforce: tt.giveEnforce}}}
+		assert.Equal(t, tt.wantEnforced, account.Privacy.GPC.IsEnforced(tt.giveHost), tt.description)
+	}
+}
+
 func TestAccountChannelGetByChannelType(t *testing.T) {
 	trueValue, falseValue := true, false
 
@@ -790,6 +830,41 @@ func TestModulesGetConfig(t *testing.T) {
 	}
 }
 
+func TestAccountCookieValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		cookie      AccountCookie
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			cookie: AccountCookie{
+				Attributes: CookieAttributes{SameSite: CookieSameSiteStrict, Domain: "publisher.com"},
+				Cohorts:    []CookieCohort{{Name: "chips", Percent: 20, Browsers: []string{"chrome"}, Attributes: CookieAttributes{Partitioned: ptrutil.ToPtr(true)}}},
+			},
+		},
+		{
+			description: "invalid attributes",
+			cookie:      AccountCookie{Attributes: CookieAttributes{SameSite: "relaxed"}},
+			want:        []error{errors.New("cookie.attributes.same_site must be one of [none, lax, strict]. Got relaxed")},
+		},
+		{
+			description: "invalid cohorts",
+			cookie:      AccountCookie{Cohorts: []CookieCohort{{Name: "a", Percent: 110}}},
+			want: []error{
+				errors.New("cookie.cohorts[0].percent must be between 0 and 100. Got 110"),
+				errors.New("cookie.cohorts percents must not add up to more than 100. Got 110"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cookie.Validate("cookie", nil))
+		})
+	}
+}
+
 func TestAccountPriceFloorsValidate(t *testing.T) {
 	tests := []struct {
 		description string
@@ -1014,3 +1089,69 @@ func TestIPMaskingValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountKAnonymityForBidder(t *testing.T) {
+	account := AccountKAnonymity{
+		Enabled:       true,
+		MinPopulation: 1000,
+		MaxSegments:   5,
+		Bidders: map[string]AccountKAnonymityBidder{
+			"bidderA": {MinPopulation: ptrutil.ToPtr(5000)},
+			"bidderB": {Enabled: ptrutil.ToPtr(false)},
+			"bidderC": {MaxSegments: ptrutil.ToPtr(0)},
+		},
+	}
+
+	testCases := []struct {
+		description           string
+		bidder                string
+		expectedEnabled       bool
+		expectedMinPopulation int
+		expectedMaxSegments   int
+	}{
+		{
+			description:           "no-override",
+			bidder:                "bidderD",
+			expectedEnabled:       true,
+			expectedMinPopulation: 1000,
+			expectedMaxSegments:   5,
+		},
+		{
+			description:           "min-population-override",
+			bidder:                "bidderA",
+			expectedEnabled:       true,
+			expectedMinPopulation: 5000,
+			expectedMaxSegments:   5,
+		},
+		{
+			description:           "override-case-insensitive",
+			bidder:                "BIDDERA",
+			expectedEnabled:       true,
+			expectedMinPopulation: 5000,
+			expectedMaxSegments:   5,
+		},
+		{
+			description:           "disabled-override",
+			bidder:                "bidderB",
+			expectedEnabled:       false,
+			expectedMinPopulation: 1000,
+			expectedMaxSegments:   5,
+		},
+		{
+			description:           "max-segments-override",
+			bidder:                "bidderC",
+			expectedEnabled:       true,
+			expectedMinPopulation: 1000,
+			expectedMaxSegments:   0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			enabled, minPopulation, maxSegments := account.ForBidder(test.bidder)
+			assert.Equal(t, test.expectedEnabled, enabled)
+			assert.Equal(t, test.expectedMinPopulation, minPopulation)
+			assert.Equal(t, test.expectedMaxSegments, maxSegments)
+		})
+	}
+}
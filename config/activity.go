@@ -9,6 +9,7 @@ type AllowActivities struct {
 	TransmitPreciseGeo       Activity `mapstructure:"transmitPreciseGeo" json:"transmitPreciseGeo"`
 	TransmitUniqueRequestIds Activity `mapstructure:"transmitUniqueRequestIds" json:"transmitUniqueRequestIds"`
 	TransmitTids             Activity `mapstructure:"transmitTid" json:"transmitTid"`
+	TransmitEids             Activity `mapstructure:"transmitEids" json:"transmitEids"`
 }
 
 type Activity struct {
@@ -24,4 +25,14 @@ type ActivityRule struct {
 type ActivityCondition struct {
 	ComponentName []string `mapstructure:"componentName" json:"componentName"`
 	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	GPPSID        []int8   `mapstructure:"gppSid" json:"gppSid"`
+	// Geo lists ISO-3166-1 alpha-3 country codes, optionally followed by a dot and a region code, e.g. "USA.CA".
+	Geo []string `mapstructure:"geo" json:"geo"`
+	// GDPR and COPPA match the regs.gdpr and regs.coppa signals of the request.
+	GDPR  *int8 `mapstructure:"gdpr" json:"gdpr"`
+	COPPA *int8 `mapstructure:"coppa" json:"coppa"`
+	// GPC matches the Global Privacy Control signal of the request, read from regs.ext.gpc or the Sec-GPC header.
+	GPC *int8 `mapstructure:"gpc" json:"gpc"`
+	// Channel lists the request channels, e.g. "web", "app", "amp", "dooh" or the ext.prebid.channel.name of the request.
+	Channel []string `mapstructure:"channel" json:"channel"`
 }
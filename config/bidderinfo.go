@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/prebid/prebid-server/v2/macros"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/minimization"
 
 	validator "github.com/asaskevich/govalidator"
 	"gopkg.in/yaml.v3"
@@ -29,8 +31,10 @@ type BidderInfo struct {
 	Maintainer              *MaintainerInfo   `yaml:"maintainer" mapstructure:"maintainer"`
 	Capabilities            *CapabilitiesInfo `yaml:"capabilities" mapstructure:"capabilities"`
 	ModifyingVastXmlAllowed bool              `yaml:"modifyingVastXmlAllowed" mapstructure:"modifyingVastXmlAllowed"`
-	Debug                   *DebugInfo        `yaml:"debug" mapstructure:"debug"`
-	GVLVendorID             uint16            `yaml:"gvlVendorID" mapstructure:"gvlVendorID"`
+	// ResponseCachingAllowed permits serving unused bids of the bidder to subsequent identical requests
+	ResponseCachingAllowed bool       `yaml:"responseCachingAllowed" mapstructure:"responseCachingAllowed"`
+	Debug                  *DebugInfo `yaml:"debug" mapstructure:"debug"`
+	GVLVendorID            uint16     `yaml:"gvlVendorID" mapstructure:"gvlVendorID"`
 
 	Syncer *Syncer `yaml:"userSync" mapstructure:"userSync"`
 
@@ -45,11 +49,20 @@ type BidderInfo struct {
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string       `yaml:"endpointCompression" mapstructure:"endpointCompression"`
 	OpenRTB             *OpenRTBInfo `yaml:"openrtb" mapstructure:"openrtb"`
+	// DataMinimization, if set, restricts the requests sent to the bidder to an allowlist of ORTB paths
+	DataMinimization *DataMinimization `yaml:"dataMinimization" mapstructure:"dataMinimization"`
+}
+
+// DataMinimization lists the ORTB paths of the request which may be sent to a bidder, such as "site.page" or
+// "user.eids[source=adserver.org]". Everything else is stripped right before the bidder builds its requests.
+type DataMinimization struct {
+	AllowedPaths []string `yaml:"allowedPaths" mapstructure:"allowedPaths"`
 }
 
 type aliasNillableFields struct {
 	Disabled                *bool                 `yaml:"disabled" mapstructure:"disabled"`
 	ModifyingVastXmlAllowed *bool                 `yaml:"modifyingVastXmlAllowed" mapstructure:"modifyingVastXmlAllowed"`
+	ResponseCachingAllowed  *bool                 `yaml:"responseCachingAllowed" mapstructure:"responseCachingAllowed"`
 	Experiment              *BidderInfoExperiment `yaml:"experiment" mapstructure:"experiment"`
 	XAPI                    *AdapterXAPI          `yaml:"xapi" mapstructure:"xapi"`
 }
@@ -133,8 +146,26 @@ type Syncer struct {
 
 	// SkipWhen allows bidders to specify when they don't want to sync
 	SkipWhen *SkipWhen `yaml:"skipwhen" mapstructure:"skipwhen"`
+
+	// ResyncMaxAgeHours is the age of a live UID, in hours, after which the user is synced again. Zero never
+	// resyncs a live UID. It's a pointer so a host can reset the value of the bidder to zero.
+	ResyncMaxAgeHours *int `yaml:"resyncMaxAgeHours" mapstructure:"resync_max_age_hours"`
+
+	// MaxAttempts caps how many syncs are offered to a user without succeeding within the attempt window.
+	// Zero disables the cap. It's a pointer so a host can reset the value of the bidder to zero.
+	MaxAttempts *int `yaml:"maxAttempts" mapstructure:"max_attempts"`
+
+	// AttemptWindowHours is the window, in hours, the sync attempts are counted over. Defaults to 24 hours.
+	AttemptWindowHours *int `yaml:"attemptWindowHours" mapstructure:"attempt_window_hours"`
+
+	// SetUIDKeys are the HMAC keys the bidder signs its /setuid redirects with. Unsigned redirects are rejected
+	// when keys are set. Several keys may be set while rotating them.
+	SetUIDKeys []string `yaml:"setUidKeys" mapstructure:"set_uid_keys"`
 }
 
+// defaultSyncAttemptWindow is the window sync attempts are counted over if the syncer doesn't specify one.
+const defaultSyncAttemptWindow = 24 * time.Hour
+
 type SkipWhen struct {
 	GDPR   bool     `yaml:"gdpr" mapstructure:"gdpr"`
 	GPPSID []string `yaml:"gpp_sid" mapstructure:"gpp_sid"`
@@ -197,6 +228,31 @@ type SyncerEndpoint struct {
 	UserMacro string `yaml:"userMacro" mapstructure:"user_macro"`
 }
 
+// ResyncMaxAge returns the age of a live UID after which the user is synced again, or zero if never.
+func (s *Syncer) ResyncMaxAge() time.Duration {
+	if s == nil || s.ResyncMaxAgeHours == nil {
+		return 0
+	}
+	return time.Duration(*s.ResyncMaxAgeHours) * time.Hour
+}
+
+// MaxSyncAttempts returns how many syncs are offered to a user without succeeding within the attempt window, or
+// zero if they aren't capped.
+func (s *Syncer) MaxSyncAttempts() int {
+	if s == nil || s.MaxAttempts == nil {
+		return 0
+	}
+	return *s.MaxAttempts
+}
+
+// SyncAttemptWindow returns the window the sync attempts are counted over.
+func (s *Syncer) SyncAttemptWindow() time.Duration {
+	if s == nil || s.AttemptWindowHours == nil || *s.AttemptWindowHours <= 0 {
+		return defaultSyncAttemptWindow
+	}
+	return time.Duration(*s.AttemptWindowHours) * time.Hour
+}
+
 func (bi BidderInfo) IsEnabled() bool {
 	return !bi.Disabled
 }
@@ -347,6 +403,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.OpenRTB == nil {
 			aliasBidderInfo.OpenRTB = parentBidderInfo.OpenRTB
 		}
+		if aliasBidderInfo.DataMinimization == nil {
+			aliasBidderInfo.DataMinimization = parentBidderInfo.DataMinimization
+		}
 		if aliasBidderInfo.PlatformID == "" {
 			aliasBidderInfo.PlatformID = parentBidderInfo.PlatformID
 		}
@@ -367,6 +426,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if alias.ModifyingVastXmlAllowed == nil {
 			aliasBidderInfo.ModifyingVastXmlAllowed = parentBidderInfo.ModifyingVastXmlAllowed
 		}
+		if alias.ResponseCachingAllowed == nil {
+			aliasBidderInfo.ResponseCachingAllowed = parentBidderInfo.ResponseCachingAllowed
+		}
 		if alias.XAPI == nil {
 			aliasBidderInfo.XAPI = parentBidderInfo.XAPI
 		}
@@ -387,6 +449,16 @@ func (infos BidderInfos) ToGVLVendorIDMap() map[openrtb_ext.BidderName]uint16 {
 	return gvlVendorIds
 }
 
+// hasSetUIDKeys returns true if an enabled bidder signs its /setuid redirects.
+func (infos BidderInfos) hasSetUIDKeys() bool {
+	for _, info := range infos {
+		if info.IsEnabled() && info.Syncer != nil && len(info.Syncer.SetUIDKeys) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // validateBidderInfos validates bidder endpoint, info and syncer data
 func (infos BidderInfos) validate(errs []error) []error {
 	for bidderName, bidder := range infos {
@@ -472,6 +544,11 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 			return err
 		}
 	}
+	if bidder.DataMinimization != nil {
+		if err := minimization.ValidatePaths(bidder.DataMinimization.AllowedPaths); err != nil {
+			return fmt.Errorf("invalid dataMinimization for adapter: %s: %v", bidderName, err)
+		}
+	}
 	return nil
 }
 
@@ -595,6 +672,24 @@ func validateSyncer(bidderInfo BidderInfo) error {
 		}
 	}
 
+	if bidderInfo.Syncer.ResyncMaxAgeHours != nil && *bidderInfo.Syncer.ResyncMaxAgeHours < 0 {
+		return fmt.Errorf("syncer could not be created, invalid resync max age hours: %d", *bidderInfo.Syncer.ResyncMaxAgeHours)
+	}
+
+	if bidderInfo.Syncer.MaxAttempts != nil && *bidderInfo.Syncer.MaxAttempts < 0 {
+		return fmt.Errorf("syncer could not be created, invalid max attempts: %d", *bidderInfo.Syncer.MaxAttempts)
+	}
+
+	if bidderInfo.Syncer.AttemptWindowHours != nil && *bidderInfo.Syncer.AttemptWindowHours < 0 {
+		return fmt.Errorf("syncer could not be created, invalid attempt window hours: %d", *bidderInfo.Syncer.AttemptWindowHours)
+	}
+
+	for _, key := range bidderInfo.Syncer.SetUIDKeys {
+		if key == "" {
+			return errors.New("syncer could not be created, empty setuid key")
+		}
+	}
+
 	return nil
 }
 
@@ -652,6 +747,9 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.nillableFields.ModifyingVastXmlAllowed != nil {
 			mergedBidderInfo.ModifyingVastXmlAllowed = configBidderInfo.bidderInfo.ModifyingVastXmlAllowed
 		}
+		if configBidderInfo.nillableFields.ResponseCachingAllowed != nil {
+			mergedBidderInfo.ResponseCachingAllowed = configBidderInfo.bidderInfo.ResponseCachingAllowed
+		}
 		if configBidderInfo.bidderInfo.Experiment.AdsCert.Enabled {
 			mergedBidderInfo.Experiment.AdsCert.Enabled = true
 		}
@@ -661,6 +759,9 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		if configBidderInfo.bidderInfo.DataMinimization != nil {
+			mergedBidderInfo.DataMinimization = configBidderInfo.bidderInfo.DataMinimization
+		}
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
@@ -710,6 +811,22 @@ func (s *Syncer) Override(original *Syncer) *Syncer {
 		copy.SupportCORS = s.SupportCORS
 	}
 
+	if s.ResyncMaxAgeHours != nil {
+		copy.ResyncMaxAgeHours = s.ResyncMaxAgeHours
+	}
+
+	if s.MaxAttempts != nil {
+		copy.MaxAttempts = s.MaxAttempts
+	}
+
+	if s.AttemptWindowHours != nil {
+		copy.AttemptWindowHours = s.AttemptWindowHours
+	}
+
+	if len(s.SetUIDKeys) > 0 {
+		copy.SetUIDKeys = s.SetUIDKeys
+	}
+
 	return &copy
 }
 
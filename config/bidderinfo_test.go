@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -312,10 +313,14 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			Email: "some-email@domain.com",
 		},
 		ModifyingVastXmlAllowed: true,
+		ResponseCachingAllowed:  true,
 		OpenRTB: &OpenRTBInfo{
 			GPPSupported: true,
 			Version:      "2.6",
 		},
+		DataMinimization: &DataMinimization{
+			AllowedPaths: []string{"site.page"},
+		},
 		PlatformID: "123",
 		Syncer: &Syncer{
 			Key: "foo",
@@ -363,6 +368,9 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			GPPSupported: false,
 			Version:      "2.5",
 		},
+		DataMinimization: &DataMinimization{
+			AllowedPaths: []string{"device.ua"},
+		},
 		PlatformID: "456",
 		Syncer: &Syncer{
 			Key: "alias",
@@ -484,6 +492,7 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 				"bidderB": {
 					Disabled:                &aliasBidderInfo.Disabled,
 					ModifyingVastXmlAllowed: &aliasBidderInfo.ModifyingVastXmlAllowed,
+					ResponseCachingAllowed:  &aliasBidderInfo.ResponseCachingAllowed,
 					Experiment:              &aliasBidderInfo.Experiment,
 					XAPI:                    &aliasBidderInfo.XAPI,
 				},
@@ -1383,6 +1392,78 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("syncer could not be created, invalid format override value: x"),
 			},
 		},
+		{
+			"Invalid max attempts value",
+			BidderInfos{
+				"bidderB": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						Site: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeBanner,
+							},
+						},
+					},
+					Syncer: &Syncer{
+						MaxAttempts: ptrutil.ToPtr(-1),
+					},
+				},
+			},
+			[]error{
+				errors.New("syncer could not be created, invalid max attempts: -1"),
+			},
+		},
+		{
+			"Empty setuid key",
+			BidderInfos{
+				"bidderB": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						Site: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeBanner,
+							},
+						},
+					},
+					Syncer: &Syncer{
+						SetUIDKeys: []string{"key", ""},
+					},
+				},
+			},
+			[]error{
+				errors.New("syncer could not be created, empty setuid key"),
+			},
+		},
+		{
+			"Invalid data minimization path",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						Site: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeBanner,
+							},
+						},
+					},
+					DataMinimization: &DataMinimization{
+						AllowedPaths: []string{"user.eids[source]"},
+					},
+				},
+			},
+			[]error{
+				errors.New("invalid dataMinimization for adapter: bidderA: data minimization path user.eids[source] must use selectors of the form [field=value]"),
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1463,6 +1544,30 @@ func TestSyncerOverride(t *testing.T) {
 			givenOverride: &Syncer{SupportCORS: &falseValue},
 			expected:      &Syncer{SupportCORS: &falseValue},
 		},
+		{
+			description:   "Override Sync Limits",
+			givenOriginal: &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(24), MaxAttempts: ptrutil.ToPtr(3), AttemptWindowHours: ptrutil.ToPtr(12)},
+			givenOverride: &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(48), MaxAttempts: ptrutil.ToPtr(5)},
+			expected:      &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(48), MaxAttempts: ptrutil.ToPtr(5), AttemptWindowHours: ptrutil.ToPtr(12)},
+		},
+		{
+			description:   "Override Sync Limits To Zero",
+			givenOriginal: &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(24), MaxAttempts: ptrutil.ToPtr(3), AttemptWindowHours: ptrutil.ToPtr(12)},
+			givenOverride: &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(0), MaxAttempts: ptrutil.ToPtr(0)},
+			expected:      &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(0), MaxAttempts: ptrutil.ToPtr(0), AttemptWindowHours: ptrutil.ToPtr(12)},
+		},
+		{
+			description:   "Override SetUID Keys",
+			givenOriginal: &Syncer{SetUIDKeys: []string{"old"}},
+			givenOverride: &Syncer{SetUIDKeys: []string{"new", "old"}},
+			expected:      &Syncer{SetUIDKeys: []string{"new", "old"}},
+		},
+		{
+			description:   "Keep SetUID Keys",
+			givenOriginal: &Syncer{SetUIDKeys: []string{"old"}},
+			givenOverride: &Syncer{ExternalURL: "override"},
+			expected:      &Syncer{SetUIDKeys: []string{"old"}, ExternalURL: "override"},
+		},
 		{
 			description:   "Override Partial - Other Fields Untouched",
 			givenOriginal: &Syncer{Key: "originalKey", ExternalURL: "originalExternalURL"},
@@ -1477,6 +1582,42 @@ func TestSyncerOverride(t *testing.T) {
 	}
 }
 
+func TestSyncerLimitDurations(t *testing.T) {
+	testCases := []struct {
+		description          string
+		givenSyncer          *Syncer
+		expectedResyncMaxAge time.Duration
+		expectedMaxAttempts  int
+		expectedWindow       time.Duration
+	}{
+		{
+			description:          "Nil",
+			givenSyncer:          nil,
+			expectedResyncMaxAge: 0,
+			expectedWindow:       24 * time.Hour,
+		},
+		{
+			description:          "Empty",
+			givenSyncer:          &Syncer{},
+			expectedResyncMaxAge: 0,
+			expectedWindow:       24 * time.Hour,
+		},
+		{
+			description:          "Specified",
+			givenSyncer:          &Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(720), MaxAttempts: ptrutil.ToPtr(3), AttemptWindowHours: ptrutil.ToPtr(6)},
+			expectedResyncMaxAge: 720 * time.Hour,
+			expectedMaxAttempts:  3,
+			expectedWindow:       6 * time.Hour,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedResyncMaxAge, test.givenSyncer.ResyncMaxAge(), test.description+":resync_max_age")
+		assert.Equal(t, test.expectedMaxAttempts, test.givenSyncer.MaxSyncAttempts(), test.description+":max_attempts")
+		assert.Equal(t, test.expectedWindow, test.givenSyncer.SyncAttemptWindow(), test.description+":attempt_window")
+	}
+}
+
 func TestSyncerEndpointOverride(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -1815,6 +1956,18 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{ModifyingVastXmlAllowed: false, Syncer: &Syncer{Key: "override"}}, nillableFields: bidderInfoNillableFields{ModifyingVastXmlAllowed: &falseValue}}},
 			expectedBidderInfos:    BidderInfos{"a": {ModifyingVastXmlAllowed: false, Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override ResponseCachingAllowed",
+			givenFsBidderInfos:     BidderInfos{"a": {ResponseCachingAllowed: true}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{ResponseCachingAllowed: false, Syncer: &Syncer{Key: "override"}}, nillableFields: bidderInfoNillableFields{ResponseCachingAllowed: nil}}},
+			expectedBidderInfos:    BidderInfos{"a": {ResponseCachingAllowed: true, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override ResponseCachingAllowed",
+			givenFsBidderInfos:     BidderInfos{"a": {ResponseCachingAllowed: true}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{ResponseCachingAllowed: false, Syncer: &Syncer{Key: "override"}}, nillableFields: bidderInfoNillableFields{ResponseCachingAllowed: &falseValue}}},
+			expectedBidderInfos:    BidderInfos{"a": {ResponseCachingAllowed: false, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override OpenRTB",
 			givenFsBidderInfos:     BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "1"}}},
@@ -1827,6 +1980,18 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override DataMinimization",
+			givenFsBidderInfos:     BidderInfos{"a": {DataMinimization: &DataMinimization{AllowedPaths: []string{"site.page"}}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {DataMinimization: &DataMinimization{AllowedPaths: []string{"site.page"}}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override DataMinimization",
+			givenFsBidderInfos:     BidderInfos{"a": {DataMinimization: &DataMinimization{AllowedPaths: []string{"site.page"}}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{DataMinimization: &DataMinimization{AllowedPaths: []string{"device.ua"}}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {DataMinimization: &DataMinimization{AllowedPaths: []string{"device.ua"}}, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override AliasOf",
 			givenFsBidderInfos:     BidderInfos{"a": {AliasOf: "Alias1"}},
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -63,6 +64,9 @@ type Configuration struct {
 	GDPR                 GDPR              `mapstructure:"gdpr"`
 	CCPA                 CCPA              `mapstructure:"ccpa"`
 	LMT                  LMT               `mapstructure:"lmt"`
+	GPC                  GPC               `mapstructure:"gpc"`
+	PrivacyAudit         PrivacyAudit      `mapstructure:"privacy_audit"`
+	KAnonymity           KAnonymity        `mapstructure:"k_anonymity"`
 	CurrencyConverter    CurrencyConverter `mapstructure:"currency_converter"`
 	DefReqConfig         DefReqConfig      `mapstructure:"default_request"`
 
@@ -146,6 +150,13 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
 	errs = cfg.GDPR.validate(v, errs)
+	errs = cfg.PrivacyAudit.validate(errs)
+	errs = cfg.KAnonymity.validate(errs)
+	errs = cfg.HostCookie.validate(errs)
+	errs = cfg.UserSync.UIDStore.validate(errs)
+	errs = cfg.UserSync.SyncValues.validate(errs)
+	errs = validateMatchTables(cfg.UserSync.MatchTables, errs)
+	errs = cfg.UserSync.Protection.validate(cfg.BidderInfos.hasSetUIDKeys(), errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
@@ -162,6 +173,7 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.BidderInfos.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
+	errs = cfg.AccountDefaults.Cookie.Validate("account_defaults.cookie", errs)
 
 	return errs
 }
@@ -238,17 +250,23 @@ type Privacy struct {
 	CCPA CCPA
 	GDPR GDPR
 	LMT  LMT
+	GPC  GPC
 }
 
 type GDPR struct {
-	Enabled                 bool         `mapstructure:"enabled"`
-	HostVendorID            int          `mapstructure:"host_vendor_id"`
-	DefaultValue            string       `mapstructure:"default_value"`
-	Timeouts                GDPRTimeouts `mapstructure:"timeouts_ms"`
-	NonStandardPublishers   []string     `mapstructure:"non_standard_publishers,flow"`
+	Enabled                 bool            `mapstructure:"enabled"`
+	HostVendorID            int             `mapstructure:"host_vendor_id"`
+	DefaultValue            string          `mapstructure:"default_value"`
+	Timeouts                GDPRTimeouts    `mapstructure:"timeouts_ms"`
+	VendorLists             GDPRVendorLists `mapstructure:"vendorlists"`
+	NonStandardPublishers   []string        `mapstructure:"non_standard_publishers,flow"`
 	NonStandardPublisherMap map[string]struct{}
 	TCF2                    TCF2 `mapstructure:"tcf2"`
-	AMPException            bool `mapstructure:"amp_exception"` // Deprecated: Use account-level GDPR settings (gdpr.integration_enabled.amp) instead
+	// Explain records the legal basis evaluated by TCF2 enforcement for each bidder and purpose of every auction
+	// where GDPR applies, reporting it to analytics modules and metrics. Auctions with debug enabled are always
+	// explained in the response debug output.
+	Explain      bool `mapstructure:"explain"`
+	AMPException bool `mapstructure:"amp_exception"` // Deprecated: Use account-level GDPR settings (gdpr.integration_enabled.amp) instead
 	// EEACountries (EEA = European Economic Area) are a list of countries where we should assume GDPR applies.
 	// If the gdpr flag is unset in a request, but geo.country is set, we will assume GDPR applies if and only
 	// if the country matches one on this list. If both the GDPR flag and country are not set, we default
@@ -269,6 +287,9 @@ func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
 	if cfg.HostVendorID == 0 {
 		glog.Warning("gdpr.host_vendor_id was not specified. Host company GDPR checks will be skipped.")
 	}
+	if cfg.VendorLists.Offline && cfg.VendorLists.Directory == "" && !cfg.VendorLists.Embedded {
+		errs = append(errs, fmt.Errorf("gdpr.vendorlists.offline requires gdpr.vendorlists.directory or gdpr.vendorlists.embedded to be set"))
+	}
 	if cfg.AMPException {
 		errs = append(errs, fmt.Errorf("gdpr.amp_exception has been discontinued and must be removed from your config. If you need to disable GDPR for AMP, you may do so per-account (gdpr.integration_enabled.amp) or at the host level for the default account (account_defaults.gdpr.integration_enabled.amp)"))
 	}
@@ -305,6 +326,17 @@ type GDPRTimeouts struct {
 	ActiveVendorlistFetch int `mapstructure:"active_vendorlist_fetch"`
 }
 
+// GDPRVendorLists configures the sources of the TCF global vendor lists, in addition to downloading them.
+type GDPRVendorLists struct {
+	// Directory holds vendor list files loaded at startup, each JSON file must contain one vendor list.
+	Directory string `mapstructure:"directory"`
+	// Embedded loads the snapshot of the vendor lists built into the binary at startup.
+	Embedded bool `mapstructure:"embedded"`
+	// Offline disables downloading the vendor lists, only the lists loaded at startup are used. A list which is not
+	// loaded is replaced by the closest loaded list of the same spec version.
+	Offline bool `mapstructure:"offline"`
+}
+
 func (t *GDPRTimeouts) InitTimeout() time.Duration {
 	return time.Duration(t.InitVendorlistFetch) * time.Millisecond
 }
@@ -450,6 +482,69 @@ type LMT struct {
 	Enforce bool `mapstructure:"enforce"`
 }
 
+// GPC configures the enforcement of the Global Privacy Control signal as an opt-out of the sale or sharing of personal information.
+type GPC struct {
+	Enforce bool `mapstructure:"enforce"`
+}
+
+// PrivacyAudit configures the sampled audit log of the privacy signals received with each auction, the activity
+// decisions made from them and the scrubbing applied to each bidder request.
+type PrivacyAudit struct {
+	Enabled    bool             `mapstructure:"enabled"`
+	SampleRate float64          `mapstructure:"sample_rate"`
+	Sink       string           `mapstructure:"sink"`
+	File       PrivacyAuditFile `mapstructure:"file"`
+}
+
+type PrivacyAuditFile struct {
+	Filename string `mapstructure:"filename"`
+}
+
+const (
+	PrivacyAuditSinkFile      = "file"
+	PrivacyAuditSinkAnalytics = "analytics"
+)
+
+func (cfg *PrivacyAudit) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("privacy_audit.sample_rate must be between 0 and 1. Got %f", cfg.SampleRate))
+	}
+	switch cfg.Sink {
+	case PrivacyAuditSinkFile:
+		if cfg.File.Filename == "" {
+			errs = append(errs, errors.New("privacy_audit.file.filename must be set when privacy_audit.sink is file"))
+		}
+	case PrivacyAuditSinkAnalytics:
+	default:
+		errs = append(errs, fmt.Errorf("privacy_audit.sink must be one of [%s, %s]. Got %s", PrivacyAuditSinkFile, PrivacyAuditSinkAnalytics, cfg.Sink))
+	}
+	return errs
+}
+
+// KAnonymity configures the audience population data used to minimize the user.data segments and user.eids
+// sources sent to bidders for the accounts which enable it.
+type KAnonymity struct {
+	// PopulationsFile is the path of a JSON file holding the audience population of each segment and EID source.
+	PopulationsFile string `mapstructure:"populations_file"`
+	// RefreshIntervalSeconds is how often the file is checked for changes. Zero disables the reload.
+	RefreshIntervalSeconds int `mapstructure:"refresh_interval_seconds"`
+}
+
+// RefreshInterval returns how often the populations file is checked for changes.
+func (cfg *KAnonymity) RefreshInterval() time.Duration {
+	return time.Duration(cfg.RefreshIntervalSeconds) * time.Second
+}
+
+func (cfg *KAnonymity) validate(errs []error) []error {
+	if cfg.RefreshIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("k_anonymity.refresh_interval_seconds must not be negative. Got %d", cfg.RefreshIntervalSeconds))
+	}
+	return errs
+}
+
 type Analytics struct {
 	File     FileLogs      `mapstructure:"file"`
 	Agma     AgmaAnalytics `mapstructure:"agma"`
@@ -533,12 +628,221 @@ type HostCookie struct {
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
 	TTL int64 `mapstructure:"ttl_days"`
+	// Encoding of the uids cookie written by Prebid Server, either json or compact. Cookies in either encoding
+	// are always read.
+	Encoding string `mapstructure:"encoding"`
+	// Compress the compact encoding when it makes the cookie smaller
+	Compress bool `mapstructure:"compress"`
+	// Attributes of the uids cookie, which accounts and experiment cohorts can override
+	Attributes CookieAttributes `mapstructure:"attributes"`
+	Cohorts    []CookieCohort   `mapstructure:"cohorts"`
+	// Definitions are the host cookies of the other domains the host serves. The first definition listing the
+	// account of a request, or else its Host header, is used instead of the host cookie above.
+	Definitions []HostCookieDefinition `mapstructure:"definitions"`
+}
+
+// HostCookieDefinition is the host cookie of a domain the host serves. Empty fields keep the value of the host
+// cookie it's defined in.
+type HostCookieDefinition struct {
+	Name string `mapstructure:"name"`
+	// Hosts are the host names of the requests the definition is used for, either exact or with a leading
+	// "*." to match their subdomains.
+	Hosts []string `mapstructure:"hosts"`
+	// Accounts are the accounts the definition is used for, whatever the host name of their requests.
+	Accounts   []string `mapstructure:"accounts"`
+	Domain     string   `mapstructure:"domain"`
+	Family     string   `mapstructure:"family"`
+	CookieName string   `mapstructure:"cookie_name"`
+	OptOutURL  string   `mapstructure:"opt_out_url"`
+	OptInURL   string   `mapstructure:"opt_in_url"`
+	// Cookie timeout in days
+	TTL int64 `mapstructure:"ttl_days"`
+}
+
+const (
+	CookieSameSiteNone   = "none"
+	CookieSameSiteLax    = "lax"
+	CookieSameSiteStrict = "strict"
+)
+
+// CookieBrowsers are the browser families cookie cohorts can be restricted to.
+var CookieBrowsers = []string{"chrome", "edge", "firefox", "safari", "other"}
+
+// CookieAttributes are the attributes of the uids cookie. Empty values keep the attributes otherwise written.
+type CookieAttributes struct {
+	Partitioned *bool  `mapstructure:"partitioned" json:"partitioned,omitempty"`
+	SameSite    string `mapstructure:"same_site" json:"same_site,omitempty"`
+	Domain      string `mapstructure:"domain" json:"domain,omitempty"`
+}
+
+func (cfg *CookieAttributes) validate(prefix string, errs []error) []error {
+	switch cfg.SameSite {
+	case "", CookieSameSiteNone, CookieSameSiteLax, CookieSameSiteStrict:
+	default:
+		errs = append(errs, fmt.Errorf("%s.same_site must be one of [%s, %s, %s]. Got %s", prefix, CookieSameSiteNone, CookieSameSiteLax, CookieSameSiteStrict, cfg.SameSite))
+	}
+	return errs
+}
+
+// CookieCohort is an experiment cohort of users whose uids cookie is written with its own attributes. Percent is
+// the share of the users in the cohort, optionally restricted to some browsers.
+type CookieCohort struct {
+	Name       string           `mapstructure:"name" json:"name"`
+	Percent    int              `mapstructure:"percent" json:"percent"`
+	Browsers   []string         `mapstructure:"browsers" json:"browsers,omitempty"`
+	Attributes CookieAttributes `mapstructure:"attributes" json:"attributes"`
+}
+
+func validateCookieCohorts(prefix string, cohorts []CookieCohort, errs []error) []error {
+	names := make(map[string]struct{}, len(cohorts))
+	total := 0
+	for i, cohort := range cohorts {
+		cohortPrefix := fmt.Sprintf("%s[%d]", prefix, i)
+		if cohort.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name must be set", cohortPrefix))
+		} else if _, ok := names[cohort.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s.name %s is not unique", cohortPrefix, cohort.Name))
+		}
+		names[cohort.Name] = struct{}{}
+
+		if cohort.Percent < 0 || cohort.Percent > 100 {
+			errs = append(errs, fmt.Errorf("%s.percent must be between 0 and 100. Got %d", cohortPrefix, cohort.Percent))
+		}
+		total += cohort.Percent
+
+		for _, browser := range cohort.Browsers {
+			if !slices.Contains(CookieBrowsers, browser) {
+				errs = append(errs, fmt.Errorf("%s.browsers must only contain %v. Got %s", cohortPrefix, CookieBrowsers, browser))
+			}
+		}
+		errs = cohort.Attributes.validate(cohortPrefix+".attributes", errs)
+	}
+	if total > 100 {
+		errs = append(errs, fmt.Errorf("%s percents must not add up to more than 100. Got %d", prefix, total))
+	}
+	return errs
+}
+
+const (
+	CookieEncodingJSON    = "json"
+	CookieEncodingCompact = "compact"
+)
+
+func (cfg *HostCookie) validate(errs []error) []error {
+	switch cfg.Encoding {
+	case "", CookieEncodingJSON, CookieEncodingCompact:
+	default:
+		errs = append(errs, fmt.Errorf("host_cookie.encoding must be one of [%s, %s]. Got %s", CookieEncodingJSON, CookieEncodingCompact, cfg.Encoding))
+	}
+	errs = cfg.Attributes.validate("host_cookie.attributes", errs)
+	errs = validateCookieCohorts("host_cookie.cohorts", cfg.Cohorts, errs)
+	errs = validateHostCookieDefinitions(cfg.Definitions, errs)
+	return errs
+}
+
+func validateHostCookieDefinitions(definitions []HostCookieDefinition, errs []error) []error {
+	names := make(map[string]struct{}, len(definitions))
+	hosts := make(map[string]string)
+	accounts := make(map[string]string)
+	for i, definition := range definitions {
+		prefix := fmt.Sprintf("host_cookie.definitions[%d]", i)
+		if definition.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name must be set", prefix))
+		} else if _, ok := names[definition.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s.name %s is not unique", prefix, definition.Name))
+		}
+		names[definition.Name] = struct{}{}
+
+		if len(definition.Hosts) == 0 && len(definition.Accounts) == 0 {
+			errs = append(errs, fmt.Errorf("%s must set hosts or accounts", prefix))
+		}
+		for _, host := range definition.Hosts {
+			host = strings.ToLower(host)
+			if other, ok := hosts[host]; ok {
+				errs = append(errs, fmt.Errorf("%s.hosts %s is already used by host_cookie.definitions %s", prefix, host, other))
+			}
+			hosts[host] = definition.Name
+		}
+		for _, account := range definition.Accounts {
+			if other, ok := accounts[account]; ok {
+				errs = append(errs, fmt.Errorf("%s.accounts %s is already used by host_cookie.definitions %s", prefix, account, other))
+			}
+			accounts[account] = definition.Name
+		}
+		if definition.TTL < 0 {
+			errs = append(errs, fmt.Errorf("%s.ttl_days must not be negative. Got %d", prefix, definition.TTL))
+		}
+	}
+	return errs
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
 	return time.Duration(cfg.TTL) * time.Hour * 24
 }
 
+// Resolve returns the host cookie of the account or, if none is defined for it, of the host name a request was
+// sent to. It returns the host cookie itself if no definition matches.
+func (cfg *HostCookie) Resolve(hostname, account string) *HostCookie {
+	if len(cfg.Definitions) == 0 {
+		return cfg
+	}
+
+	if account != "" {
+		for i := range cfg.Definitions {
+			if slices.Contains(cfg.Definitions[i].Accounts, account) {
+				return cfg.withDefinition(&cfg.Definitions[i])
+			}
+		}
+	}
+
+	hostname = strings.ToLower(hostname)
+	if hostname != "" {
+		for i := range cfg.Definitions {
+			for _, host := range cfg.Definitions[i].Hosts {
+				if matchesCookieHost(strings.ToLower(host), hostname) {
+					return cfg.withDefinition(&cfg.Definitions[i])
+				}
+			}
+		}
+	}
+	return cfg
+}
+
+// withDefinition returns a copy of the host cookie overridden by the non empty fields of the definition. The
+// copy has no definitions, so resolving it again returns it unchanged.
+func (cfg *HostCookie) withDefinition(definition *HostCookieDefinition) *HostCookie {
+	resolved := *cfg
+	resolved.Definitions = nil
+	if definition.Domain != "" {
+		// the domain of the definition takes precedence over the one of the host attributes
+		resolved.Domain = definition.Domain
+		resolved.Attributes.Domain = ""
+	}
+	if definition.Family != "" {
+		resolved.Family = definition.Family
+	}
+	if definition.CookieName != "" {
+		resolved.CookieName = definition.CookieName
+	}
+	if definition.OptOutURL != "" {
+		resolved.OptOutURL = definition.OptOutURL
+	}
+	if definition.OptInURL != "" {
+		resolved.OptInURL = definition.OptInURL
+	}
+	if definition.TTL != 0 {
+		resolved.TTL = definition.TTL
+	}
+	return &resolved
+}
+
+func matchesCookieHost(pattern, hostname string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasPrefix(suffix, ".") && strings.HasSuffix(hostname, suffix)
+	}
+	return pattern == hostname
+}
+
 type RequestTimeoutHeaders struct {
 	RequestTimeInQueue    string `mapstructure:"request_time_in_queue"`
 	RequestTimeoutInQueue string `mapstructure:"request_timeout_in_queue"`
@@ -724,6 +1028,10 @@ func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName openrtb_ex
 		return nil, err
 	}
 
+	if err := c.UserSync.Protection.Parse(); err != nil {
+		return nil, err
+	}
+
 	if err := isValidCookieSize(c.HostCookie.MaxCookieSizeBytes); err != nil {
 		glog.Fatal(fmt.Printf("Max cookie size %d cannot be less than %d \n", c.HostCookie.MaxCookieSizeBytes, MIN_COOKIE_SIZE_BYTES))
 		return nil, err
@@ -827,6 +1135,7 @@ func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName openrtb_ex
 type bidderInfoNillableFields struct {
 	Disabled                *bool `yaml:"disabled" mapstructure:"disabled"`
 	ModifyingVastXmlAllowed *bool `yaml:"modifyingVastXmlAllowed" mapstructure:"modifyingVastXmlAllowed"`
+	ResponseCachingAllowed  *bool `yaml:"responseCachingAllowed" mapstructure:"responseCachingAllowed"`
 }
 type nillableFieldBidderInfos map[string]nillableFieldBidderInfo
 type nillableFieldBidderInfo struct {
@@ -849,6 +1158,9 @@ func setConfigBidderInfoNillableFields(v *viper.Viper, bidderInfos BidderInfos)
 		if err := v.UnmarshalKey("adapters."+bidderName+".modifyingvastxmlallowed", &info.nillableFields.ModifyingVastXmlAllowed); err != nil {
 			return nil, fmt.Errorf("viper failed to unmarshal bidder config modifyingvastxmlallowed: %v", err)
 		}
+		if err := v.UnmarshalKey("adapters."+bidderName+".responsecachingallowed", &info.nillableFields.ResponseCachingAllowed); err != nil {
+			return nil, fmt.Errorf("viper failed to unmarshal bidder config responsecachingallowed: %v", err)
+		}
 		infos[bidderName] = info
 	}
 	return infos, nil
@@ -935,6 +1247,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.encoding", CookieEncodingJSON)
+	v.SetDefault("host_cookie.compress", false)
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
@@ -1078,6 +1392,29 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("event.timeout_ms", 1000)
 
 	v.SetDefault("user_sync.priority_groups", [][]string{})
+	v.SetDefault("user_sync.uid_store.enabled", false)
+	v.SetDefault("user_sync.uid_store.backend", UIDStoreBackendMemory)
+	v.SetDefault("user_sync.uid_store.id_cookie_name", "")
+	v.SetDefault("user_sync.uid_store.timeout_ms", 50)
+	v.SetDefault("user_sync.uid_store.memory.size_bytes", 64*1024*1024)
+	v.SetDefault("user_sync.uid_store.redis.address", "")
+	v.SetDefault("user_sync.uid_store.redis.password", "")
+	v.SetDefault("user_sync.uid_store.redis.key_prefix", "uids:")
+	v.SetDefault("user_sync.uid_store.redis.max_idle", 16)
+	v.SetDefault("user_sync.sync_values.source", "")
+	v.SetDefault("user_sync.sync_values.country_header", "")
+	v.SetDefault("user_sync.sync_values.file.path", "")
+	v.SetDefault("user_sync.sync_values.file.refresh_interval_seconds", 300)
+	v.SetDefault("user_sync.sync_values.auction.weight", 0.05)
+	v.SetDefault("user_sync.sync_values.auction.max_entries", 100000)
+	v.SetDefault("user_sync.geo.country_header", "")
+	v.SetDefault("user_sync.geo.region_header", "")
+	v.SetDefault("user_sync.protection.signature_max_age_seconds", 300)
+	v.SetDefault("user_sync.protection.replay_cache_size", 0)
+	v.SetDefault("user_sync.protection.rate_limit.requests_per_minute", 0)
+	v.SetDefault("user_sync.protection.rate_limit.burst", 0)
+	v.SetDefault("user_sync.protection.rate_limit.max_clients", 100000)
+	v.SetDefault("user_sync.protection.trusted_proxies", []string{})
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
@@ -1113,6 +1450,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.host_vendor_id", 0)
 	v.SetDefault("gdpr.timeouts_ms.init_vendorlist_fetches", 0)
 	v.SetDefault("gdpr.timeouts_ms.active_vendorlist_fetch", 0)
+	v.SetDefault("gdpr.vendorlists.directory", "")
+	v.SetDefault("gdpr.vendorlists.embedded", false)
+	v.SetDefault("gdpr.vendorlists.offline", false)
+	v.SetDefault("gdpr.explain", false)
 	v.SetDefault("gdpr.non_standard_publishers", []string{""})
 	v.SetDefault("gdpr.tcf2.enabled", true)
 	v.SetDefault("gdpr.tcf2.purpose1.enforce_vendors", true)
@@ -1142,6 +1483,13 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 		"SVK", "SVN", "ESP", "SWE", "GBR"})
 	v.SetDefault("ccpa.enforce", false)
 	v.SetDefault("lmt.enforce", true)
+	v.SetDefault("gpc.enforce", false)
+	v.SetDefault("privacy_audit.enabled", false)
+	v.SetDefault("privacy_audit.sample_rate", 0.0)
+	v.SetDefault("privacy_audit.sink", "file")
+	v.SetDefault("privacy_audit.file.filename", "")
+	v.SetDefault("k_anonymity.populations_file", "")
+	v.SetDefault("k_anonymity.refresh_interval_seconds", 300)
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
@@ -1175,8 +1523,20 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_defaults.events_enabled", false)
 	v.BindEnv("account_defaults.privacy.dsa.default")
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
+	v.BindEnv("account_defaults.privacy.dsa.bidder_enforcement.enabled")
+	v.BindEnv("account_defaults.privacy.dsa.bidder_enforcement.max_failure_rate")
+	v.BindEnv("account_defaults.privacy.dsa.bidder_enforcement.min_bids")
+	v.BindEnv("account_defaults.privacy.dsa.bidder_enforcement.window_seconds")
+	v.BindEnv("account_defaults.privacy.dsa.bidder_enforcement.disable_seconds")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.usnat.enabled", false)
+	v.SetDefault("account_defaults.privacy.usnat.skip_sids", []int8{})
+	v.SetDefault("account_defaults.privacy.usnat.normalize_states", false)
+	v.SetDefault("account_defaults.privacy.kanonymity.enabled", false)
+	v.SetDefault("account_defaults.privacy.kanonymity.min_population", 0)
+	v.SetDefault("account_defaults.privacy.kanonymity.max_segments", 0)
+	v.SetDefault("account_defaults.privacy.child_directed.strict", false)
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1297,6 +1657,7 @@ func setBidderDefaults(v *viper.Viper, bidder string) {
 	v.BindEnv(adapterCfgPrefix + ".endpoint")
 	v.BindEnv(adapterCfgPrefix + ".extra_info")
 	v.BindEnv(adapterCfgPrefix + ".modifyingVastXmlAllowed")
+	v.BindEnv(adapterCfgPrefix + ".responseCachingAllowed")
 	v.BindEnv(adapterCfgPrefix + ".debug.allow")
 	v.BindEnv(adapterCfgPrefix + ".gvlVendorID")
 	v.BindEnv(adapterCfgPrefix + ".usersync_url")
@@ -150,6 +150,28 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "max_request_size", 1024*256, int(cfg.MaxRequestSize))
 	cmpInts(t, "host_cookie.ttl_days", 90, int(cfg.HostCookie.TTL))
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
+	cmpStrings(t, "host_cookie.encoding", "json", cfg.HostCookie.Encoding)
+	cmpBools(t, "host_cookie.compress", false, cfg.HostCookie.Compress)
+	cmpBools(t, "user_sync.uid_store.enabled", false, cfg.UserSync.UIDStore.Enabled)
+	cmpStrings(t, "user_sync.uid_store.backend", "memory", cfg.UserSync.UIDStore.Backend)
+	cmpInts(t, "user_sync.uid_store.timeout_ms", 50, cfg.UserSync.UIDStore.TimeoutMS)
+	cmpInts(t, "user_sync.uid_store.memory.size_bytes", 64*1024*1024, cfg.UserSync.UIDStore.Memory.SizeBytes)
+	cmpStrings(t, "user_sync.uid_store.redis.key_prefix", "uids:", cfg.UserSync.UIDStore.Redis.KeyPrefix)
+	cmpInts(t, "user_sync.uid_store.redis.max_idle", 16, cfg.UserSync.UIDStore.Redis.MaxIdle)
+	cmpStrings(t, "user_sync.sync_values.source", "", cfg.UserSync.SyncValues.Source)
+	cmpInts(t, "user_sync.sync_values.file.refresh_interval_seconds", 300, cfg.UserSync.SyncValues.File.RefreshIntervalSeconds)
+	assert.Equal(t, 0.05, cfg.UserSync.SyncValues.Auction.Weight, "user_sync.sync_values.auction.weight")
+	cmpInts(t, "user_sync.sync_values.auction.max_entries", 100000, cfg.UserSync.SyncValues.Auction.MaxEntries)
+	cmpInts(t, "user_sync.protection.signature_max_age_seconds", 300, cfg.UserSync.Protection.SignatureMaxAgeSeconds)
+	cmpInts(t, "user_sync.protection.replay_cache_size", 0, cfg.UserSync.Protection.ReplayCacheSize)
+	cmpInts(t, "user_sync.protection.rate_limit.requests_per_minute", 0, cfg.UserSync.Protection.RateLimit.RequestsPerMinute)
+	cmpInts(t, "user_sync.protection.rate_limit.burst", 0, cfg.UserSync.Protection.RateLimit.Burst)
+	cmpInts(t, "user_sync.protection.rate_limit.max_clients", 100000, cfg.UserSync.Protection.RateLimit.MaxClients)
+	assert.Empty(t, cfg.UserSync.Protection.TrustedProxies, "user_sync.protection.trusted_proxies")
+	cmpStrings(t, "user_sync.geo.country_header", "", cfg.UserSync.Geo.CountryHeader)
+	cmpStrings(t, "user_sync.geo.region_header", "", cfg.UserSync.Geo.RegionHeader)
+	cmpStrings(t, "k_anonymity.populations_file", "", cfg.KAnonymity.PopulationsFile)
+	cmpInts(t, "k_anonymity.refresh_interval_seconds", 300, cfg.KAnonymity.RefreshIntervalSeconds)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
@@ -350,6 +372,11 @@ gdpr:
   default_value: "1"
   non_standard_publishers: ["pub1", "pub2"]
   eea_countries: ["eea1", "eea2"]
+  vendorlists:
+    directory: "/etc/pbs/vendorlists"
+    embedded: true
+    offline: true
+  explain: true
   tcf2:
     purpose1:
       enforce_vendors: false
@@ -390,6 +417,14 @@ ccpa:
   enforce: true
 lmt:
   enforce: true
+gpc:
+  enforce: true
+privacy_audit:
+  enabled: true
+  sample_rate: 0.25
+  sink: file
+  file:
+    filename: /var/log/pbs/privacy_audit.log
 host_cookie:
   cookie_name: userid
   family: prebid
@@ -397,6 +432,17 @@ host_cookie:
   opt_out_url: http://prebid.org/optout
   opt_in_url: http://prebid.org/optin
   max_cookie_size_bytes: 32768
+  encoding: compact
+  compress: true
+  attributes:
+    same_site: lax
+    partitioned: true
+  cohorts:
+    - name: chips
+      percent: 10
+      browsers: ["chrome"]
+      attributes:
+        partitioned: true
 external_url: http://prebid-server.prebid.org/
 host: prebid-server.prebid.org
 port: 1234
@@ -536,11 +582,29 @@ account_defaults:
         dsa:
             default: "{\"dsarequired\":3,\"pubrender\":1,\"datatopub\":2,\"transparency\":[{\"domain\":\"domain.com\",\"dsaparams\":[1]}]}"
             gdpr_only: true
+            bidder_enforcement:
+                enabled: true
+                max_failure_rate: 0.25
+                min_bids: 50
+                window_seconds: 600
+                disable_seconds: 1800
         privacysandbox:
             topicsdomain: "test.com"
             cookiedeprecation:
                 enabled: true
                 ttl_sec: 86400
+        kanonymity:
+            enabled: true
+            min_population: 1000
+            max_segments: 5
+        child_directed:
+            bundles: ["com.kids.app"]
+            domains: ["kids.com"]
+            stored_request_ids: ["kids-stored-request"]
+            strict: true
+k_anonymity:
+  populations_file: /etc/pbs/segment_populations.json
+  refresh_interval_seconds: 60
 tmax_adjustments:
   enabled: true
   bidder_response_duration_min_ms: 700
@@ -607,6 +671,11 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "cookie family", "prebid", cfg.HostCookie.Family)
 	cmpStrings(t, "opt out", "http://prebid.org/optout", cfg.HostCookie.OptOutURL)
 	cmpStrings(t, "opt in", "http://prebid.org/optin", cfg.HostCookie.OptInURL)
+	cmpStrings(t, "cookie encoding", "compact", cfg.HostCookie.Encoding)
+	cmpBools(t, "cookie compress", true, cfg.HostCookie.Compress)
+	cmpStrings(t, "cookie attributes same site", "lax", cfg.HostCookie.Attributes.SameSite)
+	assert.Equal(t, ptrutil.ToPtr(true), cfg.HostCookie.Attributes.Partitioned, "cookie attributes partitioned")
+	assert.Equal(t, []CookieCohort{{Name: "chips", Percent: 10, Browsers: []string{"chrome"}, Attributes: CookieAttributes{Partitioned: ptrutil.ToPtr(true)}}}, cfg.HostCookie.Cohorts, "cookie cohorts")
 	cmpStrings(t, "external url", "http://prebid-server.prebid.org/", cfg.ExternalURL)
 	cmpStrings(t, "host", "prebid-server.prebid.org", cfg.Host)
 	cmpInts(t, "port", 1234, cfg.Port)
@@ -631,6 +700,10 @@ func TestFullConfig(t *testing.T) {
 	cmpInts(t, "http_client_cache.idle_connection_timeout_seconds", 3, cfg.CacheClient.IdleConnTimeout)
 	cmpInts(t, "gdpr.host_vendor_id", 15, cfg.GDPR.HostVendorID)
 	cmpStrings(t, "gdpr.default_value", "1", cfg.GDPR.DefaultValue)
+	cmpStrings(t, "gdpr.vendorlists.directory", "/etc/pbs/vendorlists", cfg.GDPR.VendorLists.Directory)
+	cmpBools(t, "gdpr.vendorlists.embedded", true, cfg.GDPR.VendorLists.Embedded)
+	cmpBools(t, "gdpr.vendorlists.offline", true, cfg.GDPR.VendorLists.Offline)
+	cmpBools(t, "gdpr.explain", true, cfg.GDPR.Explain)
 	cmpStrings(t, "host_schain_node.asi", "pbshostcompany.com", cfg.HostSChainNode.ASI)
 	cmpStrings(t, "host_schain_node.sid", "00001", cfg.HostSChainNode.SID)
 	cmpStrings(t, "host_schain_node.rid", "BidRequest", cfg.HostSChainNode.RID)
@@ -686,6 +759,13 @@ func TestFullConfig(t *testing.T) {
 			},
 		},
 		GDPROnly: true,
+		BidderEnforcement: AccountDSABidderEnforcement{
+			Enabled:        true,
+			MaxFailureRate: 0.25,
+			MinBids:        50,
+			WindowSeconds:  600,
+			DisableSeconds: 1800,
+		},
 	}
 	assert.Equal(t, &expectedDSA, cfg.AccountDefaults.Privacy.DSA)
 
@@ -698,6 +778,19 @@ func TestFullConfig(t *testing.T) {
 	cmpBools(t, "account_defaults.privacy.cookiedeprecation.enabled", true, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
 	cmpInts(t, "account_defaults.privacy.cookiedeprecation.ttl_sec", 86400, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.TTLSec)
 
+	cmpBools(t, "account_defaults.privacy.kanonymity.enabled", true, cfg.AccountDefaults.Privacy.KAnonymity.Enabled)
+	cmpInts(t, "account_defaults.privacy.kanonymity.min_population", 1000, cfg.AccountDefaults.Privacy.KAnonymity.MinPopulation)
+	cmpInts(t, "account_defaults.privacy.kanonymity.max_segments", 5, cfg.AccountDefaults.Privacy.KAnonymity.MaxSegments)
+	expectedChildDirected := AccountChildDirected{
+		Bundles:          []string{"com.kids.app"},
+		Domains:          []string{"kids.com"},
+		StoredRequestIDs: []string{"kids-stored-request"},
+		Strict:           true,
+	}
+	assert.Equal(t, expectedChildDirected, cfg.AccountDefaults.Privacy.ChildDirected)
+	cmpStrings(t, "k_anonymity.populations_file", "/etc/pbs/segment_populations.json", cfg.KAnonymity.PopulationsFile)
+	cmpInts(t, "k_anonymity.refresh_interval_seconds", 60, cfg.KAnonymity.RefreshIntervalSeconds)
+
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", true, cfg.Compression.Request.GZIP)
 	cmpBools(t, "compression.response.enable_gzip", false, cfg.Compression.Response.GZIP)
@@ -712,6 +805,11 @@ func TestFullConfig(t *testing.T) {
 
 	cmpBools(t, "ccpa.enforce", true, cfg.CCPA.Enforce)
 	cmpBools(t, "lmt.enforce", true, cfg.LMT.Enforce)
+	cmpBools(t, "gpc.enforce", true, cfg.GPC.Enforce)
+	cmpBools(t, "privacy_audit.enabled", true, cfg.PrivacyAudit.Enabled)
+	assert.Equal(t, 0.25, cfg.PrivacyAudit.SampleRate, "privacy_audit.sample_rate")
+	cmpStrings(t, "privacy_audit.sink", "file", cfg.PrivacyAudit.Sink)
+	cmpStrings(t, "privacy_audit.file.filename", "/var/log/pbs/privacy_audit.log", cfg.PrivacyAudit.File.Filename)
 
 	//Assert the NonStandardPublishers was correctly unmarshalled
 	cmpStrings(t, "blacklisted_apps", "spamAppID", cfg.BlacklistedApps[0])
@@ -1234,6 +1332,74 @@ func TestInvalidAMPException(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "gdpr.amp_exception has been discontinued and must be removed from your config. If you need to disable GDPR for AMP, you may do so per-account (gdpr.integration_enabled.amp) or at the host level for the default account (account_defaults.gdpr.integration_enabled.amp)")
 }
 
+func TestInvalidVendorListsOffline(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.GDPR.VendorLists.Offline = true
+	assertOneError(t, cfg.validate(v), "gdpr.vendorlists.offline requires gdpr.vendorlists.directory or gdpr.vendorlists.embedded to be set")
+
+	cfg.GDPR.VendorLists.Embedded = true
+	assert.Empty(t, cfg.validate(v))
+}
+
+func TestValidatePrivacyAudit(t *testing.T) {
+	testCases := []struct {
+		description   string
+		privacyAudit  PrivacyAudit
+		expectedError string
+	}{
+		{
+			description:  "disabled-not-validated",
+			privacyAudit: PrivacyAudit{Enabled: false, SampleRate: 2, Sink: "unknown"},
+		},
+		{
+			description:  "file-sink",
+			privacyAudit: PrivacyAudit{Enabled: true, SampleRate: 0.5, Sink: PrivacyAuditSinkFile, File: PrivacyAuditFile{Filename: "audit.log"}},
+		},
+		{
+			description:  "analytics-sink",
+			privacyAudit: PrivacyAudit{Enabled: true, SampleRate: 1, Sink: PrivacyAuditSinkAnalytics},
+		},
+		{
+			description:   "sample-rate-too-high",
+			privacyAudit:  PrivacyAudit{Enabled: true, SampleRate: 1.5, Sink: PrivacyAuditSinkAnalytics},
+			expectedError: "privacy_audit.sample_rate must be between 0 and 1. Got 1.500000",
+		},
+		{
+			description:   "sample-rate-negative",
+			privacyAudit:  PrivacyAudit{Enabled: true, SampleRate: -0.1, Sink: PrivacyAuditSinkAnalytics},
+			expectedError: "privacy_audit.sample_rate must be between 0 and 1. Got -0.100000",
+		},
+		{
+			description:   "file-sink-without-filename",
+			privacyAudit:  PrivacyAudit{Enabled: true, SampleRate: 1, Sink: PrivacyAuditSinkFile},
+			expectedError: "privacy_audit.file.filename must be set when privacy_audit.sink is file",
+		},
+		{
+			description:   "unknown-sink",
+			privacyAudit:  PrivacyAudit{Enabled: true, SampleRate: 1, Sink: "kafka"},
+			expectedError: "privacy_audit.sink must be one of [file, analytics]. Got kafka",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.privacyAudit.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Len(t, errs, 1)
+				assert.EqualError(t, errs[0], test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateKAnonymity(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.KAnonymity.RefreshIntervalSeconds = -1
+	assertOneError(t, cfg.validate(v), "k_anonymity.refresh_interval_seconds must not be negative. Got -1")
+}
+
 func TestInvalidGDPRDefaultValue(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.GDPR.DefaultValue = "2"
@@ -1962,3 +2128,550 @@ func TestUnpackDSADefault(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHostCookie(t *testing.T) {
+	testCases := []struct {
+		description   string
+		hostCookie    HostCookie
+		expectedError string
+	}{
+		{
+			description: "empty-encoding",
+			hostCookie:  HostCookie{},
+		},
+		{
+			description: "json-encoding",
+			hostCookie:  HostCookie{Encoding: CookieEncodingJSON},
+		},
+		{
+			description: "compact-encoding",
+			hostCookie:  HostCookie{Encoding: CookieEncodingCompact, Compress: true},
+		},
+		{
+			description:   "unknown-encoding",
+			hostCookie:    HostCookie{Encoding: "protobuf"},
+			expectedError: "host_cookie.encoding must be one of [json, compact]. Got protobuf",
+		},
+		{
+			description: "attributes-and-cohorts",
+			hostCookie: HostCookie{
+				Attributes: CookieAttributes{SameSite: CookieSameSiteLax},
+				Cohorts: []CookieCohort{
+					{Name: "chips", Percent: 50, Browsers: []string{"chrome", "edge"}, Attributes: CookieAttributes{Partitioned: ptrutil.ToPtr(true)}},
+					{Name: "strict", Percent: 50, Attributes: CookieAttributes{SameSite: CookieSameSiteStrict}},
+				},
+			},
+		},
+		{
+			description:   "unknown-same-site",
+			hostCookie:    HostCookie{Attributes: CookieAttributes{SameSite: "relaxed"}},
+			expectedError: "host_cookie.attributes.same_site must be one of [none, lax, strict]. Got relaxed",
+		},
+		{
+			description:   "cohort-without-name",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Percent: 10}}},
+			expectedError: "host_cookie.cohorts[0].name must be set",
+		},
+		{
+			description:   "cohort-name-not-unique",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Name: "a", Percent: 10}, {Name: "a", Percent: 10}}},
+			expectedError: "host_cookie.cohorts[1].name a is not unique",
+		},
+		{
+			description:   "cohort-percent-out-of-range",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Name: "a", Percent: -1}}},
+			expectedError: "host_cookie.cohorts[0].percent must be between 0 and 100. Got -1",
+		},
+		{
+			description:   "cohort-percents-above-100",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Name: "a", Percent: 60}, {Name: "b", Percent: 50}}},
+			expectedError: "host_cookie.cohorts percents must not add up to more than 100. Got 110",
+		},
+		{
+			description:   "cohort-unknown-browser",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Name: "a", Percent: 10, Browsers: []string{"opera"}}}},
+			expectedError: "host_cookie.cohorts[0].browsers must only contain [chrome edge firefox safari other]. Got opera",
+		},
+		{
+			description:   "cohort-unknown-same-site",
+			hostCookie:    HostCookie{Cohorts: []CookieCohort{{Name: "a", Percent: 10, Attributes: CookieAttributes{SameSite: "relaxed"}}}},
+			expectedError: "host_cookie.cohorts[0].attributes.same_site must be one of [none, lax, strict]. Got relaxed",
+		},
+		{
+			description: "definitions",
+			hostCookie: HostCookie{Definitions: []HostCookieDefinition{
+				{Name: "a", Hosts: []string{"pbs.a.com", "*.a.com"}, Domain: "a.com", TTL: 30},
+				{Name: "b", Accounts: []string{"1001"}, Family: "b"},
+			}},
+		},
+		{
+			description:   "definition-without-name",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Hosts: []string{"pbs.a.com"}}}},
+			expectedError: "host_cookie.definitions[0].name must be set",
+		},
+		{
+			description:   "definition-name-not-unique",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Name: "a", Hosts: []string{"pbs.a.com"}}, {Name: "a", Accounts: []string{"1001"}}}},
+			expectedError: "host_cookie.definitions[1].name a is not unique",
+		},
+		{
+			description:   "definition-without-hosts-nor-accounts",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Name: "a"}}},
+			expectedError: "host_cookie.definitions[0] must set hosts or accounts",
+		},
+		{
+			description:   "definition-host-not-unique",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Name: "a", Hosts: []string{"pbs.a.com"}}, {Name: "b", Hosts: []string{"PBS.a.com"}}}},
+			expectedError: "host_cookie.definitions[1].hosts pbs.a.com is already used by host_cookie.definitions a",
+		},
+		{
+			description:   "definition-account-not-unique",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Name: "a", Accounts: []string{"1001"}}, {Name: "b", Accounts: []string{"1001"}}}},
+			expectedError: "host_cookie.definitions[1].accounts 1001 is already used by host_cookie.definitions a",
+		},
+		{
+			description:   "definition-negative-ttl",
+			hostCookie:    HostCookie{Definitions: []HostCookieDefinition{{Name: "a", Hosts: []string{"pbs.a.com"}, TTL: -1}}},
+			expectedError: "host_cookie.definitions[0].ttl_days must not be negative. Got -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.hostCookie.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Len(t, errs, 1)
+				assert.EqualError(t, errs[0], test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHostCookieResolve(t *testing.T) {
+	hostCookie := HostCookie{
+		Domain:     "pbs.com",
+		Family:     "pbs",
+		CookieName: "pbs_id",
+		OptOutURL:  "https://pbs.com/optout",
+		OptInURL:   "https://pbs.com/optin",
+		TTL:        90,
+		Encoding:   CookieEncodingCompact,
+		Attributes: CookieAttributes{SameSite: CookieSameSiteLax, Domain: "attributes.pbs.com"},
+		Definitions: []HostCookieDefinition{
+			{
+				Name:       "brand-a",
+				Hosts:      []string{"pbs.brand-a.com", "*.cdn.brand-a.com"},
+				Domain:     "brand-a.com",
+				Family:     "brand-a",
+				CookieName: "a_id",
+				OptOutURL:  "https://brand-a.com/optout",
+				OptInURL:   "https://brand-a.com/optin",
+				TTL:        30,
+			},
+			{
+				Name:      "brand-b",
+				Hosts:     []string{"PBS.Brand-B.com"},
+				Accounts:  []string{"1001"},
+				OptOutURL: "https://brand-b.com/optout",
+			},
+		},
+	}
+	brandA := HostCookie{
+		Domain:     "brand-a.com",
+		Family:     "brand-a",
+		CookieName: "a_id",
+		OptOutURL:  "https://brand-a.com/optout",
+		OptInURL:   "https://brand-a.com/optin",
+		TTL:        30,
+		Encoding:   CookieEncodingCompact,
+		Attributes: CookieAttributes{SameSite: CookieSameSiteLax},
+	}
+	brandB := HostCookie{
+		Domain:     "pbs.com",
+		Family:     "pbs",
+		CookieName: "pbs_id",
+		OptOutURL:  "https://brand-b.com/optout",
+		OptInURL:   "https://pbs.com/optin",
+		TTL:        90,
+		Encoding:   CookieEncodingCompact,
+		Attributes: CookieAttributes{SameSite: CookieSameSiteLax, Domain: "attributes.pbs.com"},
+	}
+
+	testCases := []struct {
+		description   string
+		givenHostname string
+		givenAccount  string
+		expected      *HostCookie
+	}{
+		{
+			description: "no-match",
+			expected:    &hostCookie,
+		},
+		{
+			description:   "unknown-host",
+			givenHostname: "pbs.other.com",
+			givenAccount:  "2002",
+			expected:      &hostCookie,
+		},
+		{
+			description:   "exact-host",
+			givenHostname: "pbs.brand-a.com",
+			expected:      &brandA,
+		},
+		{
+			description:   "wildcard-host",
+			givenHostname: "eu.cdn.brand-a.com",
+			expected:      &brandA,
+		},
+		{
+			description:   "wildcard-excludes-parent-domain",
+			givenHostname: "cdn.brand-a.com",
+			expected:      &hostCookie,
+		},
+		{
+			description:   "host-case-insensitive",
+			givenHostname: "pbs.brand-b.COM",
+			expected:      &brandB,
+		},
+		{
+			description:   "account",
+			givenHostname: "pbs.other.com",
+			givenAccount:  "1001",
+			expected:      &brandB,
+		},
+		{
+			description:   "account-before-host",
+			givenHostname: "pbs.brand-a.com",
+			givenAccount:  "1001",
+			expected:      &brandB,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			resolved := hostCookie.Resolve(test.givenHostname, test.givenAccount)
+			assert.Equal(t, test.expected, resolved)
+			assert.Equal(t, resolved, resolved.Resolve(test.givenHostname, test.givenAccount), "resolving again")
+		})
+	}
+}
+
+func TestHostCookieResolveWithoutDefinitions(t *testing.T) {
+	hostCookie := &HostCookie{Family: "pbs"}
+	assert.Same(t, hostCookie, hostCookie.Resolve("pbs.com", "1001"))
+}
+
+func TestValidateSyncValues(t *testing.T) {
+	testCases := []struct {
+		description   string
+		syncValues    SyncValues
+		expectedError string
+	}{
+		{
+			description: "no-source",
+			syncValues:  SyncValues{},
+		},
+		{
+			description: "file",
+			syncValues:  SyncValues{Source: SyncValuesSourceFile, File: SyncValuesFile{Path: "values.json", RefreshIntervalSeconds: 60}},
+		},
+		{
+			description: "auction",
+			syncValues:  SyncValues{Source: SyncValuesSourceAuction, Auction: SyncValuesAuction{Weight: 0.1, MaxEntries: 10}},
+		},
+		{
+			description:   "file-without-path",
+			syncValues:    SyncValues{Source: SyncValuesSourceFile},
+			expectedError: "user_sync.sync_values.file.path must be set when user_sync.sync_values.source is file",
+		},
+		{
+			description:   "file-negative-refresh-interval",
+			syncValues:    SyncValues{Source: SyncValuesSourceFile, File: SyncValuesFile{Path: "values.json", RefreshIntervalSeconds: -1}},
+			expectedError: "user_sync.sync_values.file.refresh_interval_seconds must not be negative. Got -1",
+		},
+		{
+			description:   "auction-weight-out-of-range",
+			syncValues:    SyncValues{Source: SyncValuesSourceAuction, Auction: SyncValuesAuction{Weight: 1.5, MaxEntries: 10}},
+			expectedError: "user_sync.sync_values.auction.weight must be in the range (0, 1]. Got 1.5",
+		},
+		{
+			description:   "auction-without-max-entries",
+			syncValues:    SyncValues{Source: SyncValuesSourceAuction, Auction: SyncValuesAuction{Weight: 0.1}},
+			expectedError: "user_sync.sync_values.auction.max_entries must be positive. Got 0",
+		},
+		{
+			description:   "unknown-source",
+			syncValues:    SyncValues{Source: "http"},
+			expectedError: "user_sync.sync_values.source must be one of [file, auction]. Got http",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.syncValues.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Len(t, errs, 1)
+				assert.EqualError(t, errs[0], test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateMatchTables(t *testing.T) {
+	testCases := []struct {
+		description    string
+		matchTables    []MatchTable
+		expectedErrors []string
+	}{
+		{
+			description: "none",
+			matchTables: nil,
+		},
+		{
+			description: "file-and-http",
+			matchTables: []MatchTable{
+				{Bidder: "appnexus", Path: "appnexus.csv", RefreshIntervalSeconds: 3600},
+				{Bidder: "rubicon", IDSource: "pubcid.org", URL: "https://rubicon.com/match.csv", TimeoutMS: 1000},
+			},
+		},
+		{
+			description:    "missing-bidder",
+			matchTables:    []MatchTable{{Path: "appnexus.csv"}},
+			expectedErrors: []string{"user_sync.match_tables[0].bidder must be set"},
+		},
+		{
+			description:    "path-and-url",
+			matchTables:    []MatchTable{{Bidder: "appnexus", Path: "appnexus.csv", URL: "https://appnexus.com/match.csv"}},
+			expectedErrors: []string{"user_sync.match_tables[0] must set exactly one of path or url"},
+		},
+		{
+			description:    "no-path-nor-url",
+			matchTables:    []MatchTable{{Bidder: "appnexus"}},
+			expectedErrors: []string{"user_sync.match_tables[0] must set exactly one of path or url"},
+		},
+		{
+			description: "negative-durations",
+			matchTables: []MatchTable{
+				{Bidder: "appnexus", Path: "appnexus.csv"},
+				{Bidder: "rubicon", URL: "https://rubicon.com/match.csv", RefreshIntervalSeconds: -1, TimeoutMS: -1},
+			},
+			expectedErrors: []string{
+				"user_sync.match_tables[1].refresh_interval_seconds must not be negative. Got -1",
+				"user_sync.match_tables[1].timeout_ms must not be negative. Got -1",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := validateMatchTables(test.matchTables, nil)
+			errStrings := make([]string, 0, len(errs))
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+			assert.ElementsMatch(t, test.expectedErrors, errStrings)
+		})
+	}
+}
+
+func TestValidateUserSyncProtection(t *testing.T) {
+	testCases := []struct {
+		description    string
+		protection     UserSyncProtection
+		signed         bool
+		expectedErrors []string
+	}{
+		{
+			description: "defaults",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 300, RateLimit: UserSyncRateLimit{MaxClients: 100000}},
+			signed:      true,
+		},
+		{
+			description: "replay-cache-size",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 300, ReplayCacheSize: 1000},
+			signed:      true,
+		},
+		{
+			description: "replay-cache-size-negative",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 300, ReplayCacheSize: -1},
+			signed:      true,
+			expectedErrors: []string{
+				"user_sync.protection.replay_cache_size must not be negative. Got -1",
+			},
+		},
+		{
+			description: "unsigned-not-validated",
+			protection:  UserSyncProtection{},
+			signed:      false,
+		},
+		{
+			description: "signed-missing-signature-settings",
+			protection:  UserSyncProtection{},
+			signed:      true,
+			expectedErrors: []string{
+				"user_sync.protection.signature_max_age_seconds must be positive when bidders sign their setuid redirects. Got 0",
+			},
+		},
+		{
+			description: "rate-limit-enabled",
+			protection:  UserSyncProtection{RateLimit: UserSyncRateLimit{RequestsPerMinute: 60, Burst: 10, MaxClients: 1000}},
+		},
+		{
+			description: "rate-limit-negative",
+			protection:  UserSyncProtection{RateLimit: UserSyncRateLimit{RequestsPerMinute: -1, Burst: -1}},
+			expectedErrors: []string{
+				"user_sync.protection.rate_limit.requests_per_minute must not be negative. Got -1",
+				"user_sync.protection.rate_limit.burst must not be negative. Got -1",
+			},
+		},
+		{
+			description:    "rate-limit-without-max-clients",
+			protection:     UserSyncProtection{RateLimit: UserSyncRateLimit{RequestsPerMinute: 60}},
+			expectedErrors: []string{"user_sync.protection.rate_limit.max_clients must be positive when the rate limit is enabled. Got 0"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.protection.validate(test.signed, nil)
+			errStrings := make([]string, 0, len(errs))
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+			assert.ElementsMatch(t, test.expectedErrors, errStrings)
+		})
+	}
+}
+
+func TestUserSyncProtectionReplayCacheCapacity(t *testing.T) {
+	testCases := []struct {
+		description string
+		protection  UserSyncProtection
+		expected    int
+	}{
+		{
+			description: "configured",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 300, ReplayCacheSize: 1000},
+			expected:    1000,
+		},
+		{
+			description: "derived-from-max-age",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 300},
+			expected:    300 * defaultReplayCacheSyncsPerSecond,
+		},
+		{
+			description: "derived-from-longer-max-age",
+			protection:  UserSyncProtection{SignatureMaxAgeSeconds: 3600},
+			expected:    3600 * defaultReplayCacheSyncsPerSecond,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.protection.ReplayCacheCapacity())
+		})
+	}
+}
+
+func TestUserSyncProtectionTrustedProxies(t *testing.T) {
+	testCases := []struct {
+		description       string
+		trustedProxies    []string
+		expectedErr       string
+		expectedTrusted   []string
+		expectedUntrusted []string
+	}{
+		{
+			description:       "none",
+			trustedProxies:    nil,
+			expectedUntrusted: []string{"10.0.0.1", "1.1.1.1"},
+		},
+		{
+			description:       "ipv4-and-ipv6",
+			trustedProxies:    []string{"10.0.0.0/8", " 2001:db8::/32 "},
+			expectedTrusted:   []string{"10.1.2.3", "2001:db8::1"},
+			expectedUntrusted: []string{"11.0.0.1", "2001:db9::1"},
+		},
+		{
+			description:    "malformed",
+			trustedProxies: []string{"10.0.0.0/8", "10.0.0.1", "proxy"},
+			expectedErr:    "Invalid user_sync.protection.trusted_proxies network: '10.0.0.1','proxy'",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			protection := UserSyncProtection{TrustedProxies: test.trustedProxies}
+			err := protection.Parse()
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Empty(t, protection.TrustedProxiesParsed)
+				return
+			}
+			assert.NoError(t, err)
+			for _, ip := range test.expectedTrusted {
+				assert.True(t, protection.IsTrustedProxy(net.ParseIP(ip)), ip)
+			}
+			for _, ip := range test.expectedUntrusted {
+				assert.False(t, protection.IsTrustedProxy(net.ParseIP(ip)), ip)
+			}
+		})
+	}
+}
+
+func TestValidateUIDStore(t *testing.T) {
+	testCases := []struct {
+		description   string
+		uidStore      UIDStore
+		expectedError string
+	}{
+		{
+			description: "disabled-not-validated",
+			uidStore:    UIDStore{Enabled: false, Backend: "unknown"},
+		},
+		{
+			description: "memory",
+			uidStore:    UIDStore{Enabled: true, Backend: UIDStoreBackendMemory, Memory: UIDStoreMemory{SizeBytes: 1024}},
+		},
+		{
+			description: "redis",
+			uidStore:    UIDStore{Enabled: true, Backend: UIDStoreBackendRedis, Redis: UIDStoreRedis{Address: "localhost:6379"}},
+		},
+		{
+			description:   "memory-without-size",
+			uidStore:      UIDStore{Enabled: true, Backend: UIDStoreBackendMemory},
+			expectedError: "user_sync.uid_store.memory.size_bytes must be positive. Got 0",
+		},
+		{
+			description:   "redis-without-address",
+			uidStore:      UIDStore{Enabled: true, Backend: UIDStoreBackendRedis},
+			expectedError: "user_sync.uid_store.redis.address must be set when user_sync.uid_store.backend is redis",
+		},
+		{
+			description:   "unknown-backend",
+			uidStore:      UIDStore{Enabled: true, Backend: "cassandra"},
+			expectedError: "user_sync.uid_store.backend must be one of [memory, redis]. Got cassandra",
+		},
+		{
+			description:   "negative-timeout",
+			uidStore:      UIDStore{Enabled: true, Backend: UIDStoreBackendMemory, Memory: UIDStoreMemory{SizeBytes: 1024}, TimeoutMS: -1},
+			expectedError: "user_sync.uid_store.timeout_ms must not be negative. Got -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.uidStore.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Len(t, errs, 1)
+				assert.EqualError(t, errs[0], test.expectedError)
+			}
+		})
+	}
+}
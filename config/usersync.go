@@ -1,14 +1,297 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
 // UserSync specifies the static global user sync configuration.
 type UserSync struct {
 	Cooperative    UserSyncCooperative `mapstructure:"coop_sync"`
 	ExternalURL    string              `mapstructure:"external_url"`
 	RedirectURL    string              `mapstructure:"redirect_url"`
 	PriorityGroups [][]string          `mapstructure:"priority_groups"`
+	UIDStore       UIDStore            `mapstructure:"uid_store"`
+	SyncValues     SyncValues          `mapstructure:"sync_values"`
+	MatchTables    []MatchTable        `mapstructure:"match_tables"`
+	Protection     UserSyncProtection  `mapstructure:"protection"`
+	Geo            UserSyncGeo         `mapstructure:"geo"`
 }
 
 // UserSyncCooperative specifies the static global default cooperative cookie sync
 type UserSyncCooperative struct {
 	EnabledByDefault bool `mapstructure:"default"`
 }
+
+// UserSyncGeo specifies the request headers holding the location of the user, as set by a CDN or load balancer,
+// which the geo conditions of the activity rules are matched against in the /cookie_sync and /setuid endpoints.
+// Syncs of users of unknown location never match a geo condition.
+type UserSyncGeo struct {
+	// CountryHeader holds an ISO-3166-1 alpha-3 country code, as device.geo.country.
+	CountryHeader string `mapstructure:"country_header"`
+	// RegionHeader holds an ISO-3166-2 region code, with or without the country prefix.
+	RegionHeader string `mapstructure:"region_header"`
+}
+
+const (
+	UIDStoreBackendMemory = "memory"
+	UIDStoreBackendRedis  = "redis"
+)
+
+// UIDStore specifies the server-side store of the bidder UIDs, keyed by a host identifier, which complements the
+// uids cookie for browsers limiting the size or the lifetime of third party cookies.
+type UIDStore struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Backend string `mapstructure:"backend"`
+	// IDCookieName is the name of the first party cookie holding the identifier of the user. The host cookie is
+	// used when it is empty.
+	IDCookieName string         `mapstructure:"id_cookie_name"`
+	TimeoutMS    int            `mapstructure:"timeout_ms"`
+	Memory       UIDStoreMemory `mapstructure:"memory"`
+	Redis        UIDStoreRedis  `mapstructure:"redis"`
+}
+
+// UIDStoreMemory specifies the in-memory UID store.
+type UIDStoreMemory struct {
+	SizeBytes int `mapstructure:"size_bytes"`
+}
+
+// UIDStoreRedis specifies the Redis UID store.
+type UIDStoreRedis struct {
+	Address   string `mapstructure:"address"`
+	Password  string `mapstructure:"password"`
+	KeyPrefix string `mapstructure:"key_prefix"`
+	MaxIdle   int    `mapstructure:"max_idle"`
+}
+
+// Timeout returns the maximum time to wait for the store.
+func (cfg *UIDStore) Timeout() time.Duration {
+	return time.Duration(cfg.TimeoutMS) * time.Millisecond
+}
+
+func (cfg *UIDStore) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+
+	switch cfg.Backend {
+	case UIDStoreBackendMemory:
+		if cfg.Memory.SizeBytes <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.memory.size_bytes must be positive. Got %d", cfg.Memory.SizeBytes))
+		}
+	case UIDStoreBackendRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.redis.address must be set when user_sync.uid_store.backend is redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("user_sync.uid_store.backend must be one of [%s, %s]. Got %s", UIDStoreBackendMemory, UIDStoreBackendRedis, cfg.Backend))
+	}
+	if cfg.TimeoutMS < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.uid_store.timeout_ms must not be negative. Got %d", cfg.TimeoutMS))
+	}
+	return errs
+}
+
+const (
+	SyncValuesSourceFile    = "file"
+	SyncValuesSourceAuction = "auction"
+)
+
+// SyncValues specifies where the value of a synced UID to each bidder comes from. The values order the bidders
+// to sync for accounts using the yield cookie sync prioritization.
+type SyncValues struct {
+	// Source is either SyncValuesSourceFile or SyncValuesSourceAuction. The values are unknown if empty.
+	Source string `mapstructure:"source"`
+	// CountryHeader is the request header holding the country of the user, as set by a CDN or load balancer.
+	// The values of the user's country are only used if set. The header must hold the same country codes as
+	// device.geo.country, which the values learned from auctions are keyed by.
+	CountryHeader string            `mapstructure:"country_header"`
+	File          SyncValuesFile    `mapstructure:"file"`
+	Auction       SyncValuesAuction `mapstructure:"auction"`
+}
+
+// SyncValuesFile specifies the local file periodically reloaded with the values of synced UIDs.
+type SyncValuesFile struct {
+	Path                   string `mapstructure:"path"`
+	RefreshIntervalSeconds int    `mapstructure:"refresh_interval_seconds"`
+}
+
+// SyncValuesAuction specifies how the values of synced UIDs are learned from auction outcomes.
+type SyncValuesAuction struct {
+	// Weight is the weight of each auction outcome in the moving average of the values, between 0 and 1.
+	Weight float64 `mapstructure:"weight"`
+	// MaxEntries bounds the number of account, country and bidder values learned.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// RefreshInterval returns how often the file of values is reloaded.
+func (cfg *SyncValuesFile) RefreshInterval() time.Duration {
+	return time.Duration(cfg.RefreshIntervalSeconds) * time.Second
+}
+
+func (cfg *SyncValues) validate(errs []error) []error {
+	switch cfg.Source {
+	case "":
+	case SyncValuesSourceFile:
+		if cfg.File.Path == "" {
+			errs = append(errs, fmt.Errorf("user_sync.sync_values.file.path must be set when user_sync.sync_values.source is file"))
+		}
+		if cfg.File.RefreshIntervalSeconds < 0 {
+			errs = append(errs, fmt.Errorf("user_sync.sync_values.file.refresh_interval_seconds must not be negative. Got %d", cfg.File.RefreshIntervalSeconds))
+		}
+	case SyncValuesSourceAuction:
+		if cfg.Auction.Weight <= 0 || cfg.Auction.Weight > 1 {
+			errs = append(errs, fmt.Errorf("user_sync.sync_values.auction.weight must be in the range (0, 1]. Got %g", cfg.Auction.Weight))
+		}
+		if cfg.Auction.MaxEntries <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.sync_values.auction.max_entries must be positive. Got %d", cfg.Auction.MaxEntries))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("user_sync.sync_values.source must be one of [%s, %s]. Got %s", SyncValuesSourceFile, SyncValuesSourceAuction, cfg.Source))
+	}
+	return errs
+}
+
+// MatchTable specifies a table of a partner mapping the IDs a user is known by to the partner's UIDs, loaded
+// from a local file or an HTTP endpoint. It fills the buyer UIDs of bidders syncing server to server instead
+// of through pixels. The table is a CSV file of rows holding the user ID followed by the partner UID. Prebid
+// Server fails to start if the table cannot be loaded, while a failed reload keeps the previous contents.
+type MatchTable struct {
+	Bidder string `mapstructure:"bidder"`
+	// IDSource is the source of the user.eids ID the table is keyed by. The table is keyed by the host cookie
+	// ID if it is empty.
+	IDSource               string `mapstructure:"id_source"`
+	Path                   string `mapstructure:"path"`
+	URL                    string `mapstructure:"url"`
+	RefreshIntervalSeconds int    `mapstructure:"refresh_interval_seconds"`
+	TimeoutMS              int    `mapstructure:"timeout_ms"`
+}
+
+// RefreshInterval returns how often the table is reloaded.
+func (cfg *MatchTable) RefreshInterval() time.Duration {
+	return time.Duration(cfg.RefreshIntervalSeconds) * time.Second
+}
+
+// Timeout returns the maximum time to wait for the HTTP endpoint of the table.
+func (cfg *MatchTable) Timeout() time.Duration {
+	return time.Duration(cfg.TimeoutMS) * time.Millisecond
+}
+
+func validateMatchTables(tables []MatchTable, errs []error) []error {
+	for i, table := range tables {
+		if table.Bidder == "" {
+			errs = append(errs, fmt.Errorf("user_sync.match_tables[%d].bidder must be set", i))
+		}
+		if (table.Path == "") == (table.URL == "") {
+			errs = append(errs, fmt.Errorf("user_sync.match_tables[%d] must set exactly one of path or url", i))
+		}
+		if table.RefreshIntervalSeconds < 0 {
+			errs = append(errs, fmt.Errorf("user_sync.match_tables[%d].refresh_interval_seconds must not be negative. Got %d", i, table.RefreshIntervalSeconds))
+		}
+		if table.TimeoutMS < 0 {
+			errs = append(errs, fmt.Errorf("user_sync.match_tables[%d].timeout_ms must not be negative. Got %d", i, table.TimeoutMS))
+		}
+	}
+	return errs
+}
+
+// UserSyncProtection specifies how the /setuid and /cookie_sync endpoints are protected against forged and abusive
+// requests.
+type UserSyncProtection struct {
+	// SignatureMaxAgeSeconds is how long a signed /setuid redirect is accepted after it was signed.
+	SignatureMaxAgeSeconds int `mapstructure:"signature_max_age_seconds"`
+	// ReplayCacheSize bounds the number of signatures remembered to reject the redirects replayed within their
+	// max age. A signature is remembered for the whole max age, so at most ReplayCacheSize signed redirects are
+	// accepted per max age, and the others are rejected as replay_cache_full. It defaults to 0, which sizes the
+	// cache for defaultReplayCacheSyncsPerSecond signed redirects per second over the max age.
+	ReplayCacheSize int               `mapstructure:"replay_cache_size"`
+	RateLimit       UserSyncRateLimit `mapstructure:"rate_limit"`
+	// TrustedProxies are the networks, in CIDR notation, of the proxies in front of Prebid Server. The client IP
+	// is only read from the True-Client-IP, X-Forwarded-For and X-Real-IP headers of the requests they forward,
+	// and is the address of the peer otherwise.
+	TrustedProxies       []string `mapstructure:"trusted_proxies,flow"`
+	TrustedProxiesParsed []net.IPNet
+}
+
+// Parse converts the CIDR representation of the trusted proxies as net.IPNet structs, or returns an error if at
+// least one is invalid.
+func (cfg *UserSyncProtection) Parse() error {
+	networks := make([]net.IPNet, 0, len(cfg.TrustedProxies))
+	var invalid []string
+	for _, proxy := range cfg.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if _, network, err := net.ParseCIDR(proxy); err != nil {
+			invalid = append(invalid, "'"+proxy+"'")
+		} else {
+			networks = append(networks, *network)
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New("Invalid user_sync.protection.trusted_proxies network: " + strings.Join(invalid, ","))
+	}
+	cfg.TrustedProxiesParsed = networks
+	return nil
+}
+
+// IsTrustedProxy returns true if the IP belongs to one of the trusted proxies.
+func (cfg *UserSyncProtection) IsTrustedProxy(ip net.IP) bool {
+	for _, network := range cfg.TrustedProxiesParsed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserSyncRateLimit specifies the per client IP rate limit of the /setuid and /cookie_sync endpoints.
+type UserSyncRateLimit struct {
+	// RequestsPerMinute is the sustained rate of requests allowed for a client IP on each endpoint. The requests
+	// are not limited if it is 0.
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	// Burst is the number of requests a client IP may send at once. It defaults to RequestsPerMinute if 0.
+	Burst int `mapstructure:"burst"`
+	// MaxClients bounds the number of client IPs tracked, the least recently seen are forgotten first.
+	MaxClients int `mapstructure:"max_clients"`
+}
+
+// defaultReplayCacheSyncsPerSecond is the sustained rate of signed /setuid redirects the replay cache is sized for
+// when its size isn't configured.
+const defaultReplayCacheSyncsPerSecond = 500
+
+// ReplayCacheCapacity returns the number of signatures remembered to reject the replayed /setuid redirects, the
+// configured size or, if none, the size derived from the signature max age.
+func (cfg *UserSyncProtection) ReplayCacheCapacity() int {
+	if cfg.ReplayCacheSize > 0 {
+		return cfg.ReplayCacheSize
+	}
+	return cfg.SignatureMaxAgeSeconds * defaultReplayCacheSyncsPerSecond
+}
+
+// SignatureMaxAge returns how long a signed /setuid redirect is accepted.
+func (cfg *UserSyncProtection) SignatureMaxAge() time.Duration {
+	return time.Duration(cfg.SignatureMaxAgeSeconds) * time.Second
+}
+
+// validate checks the protection config. The signature settings only matter when a bidder signs its /setuid
+// redirects.
+func (cfg *UserSyncProtection) validate(signed bool, errs []error) []error {
+	if signed && cfg.SignatureMaxAgeSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.protection.signature_max_age_seconds must be positive when bidders sign their setuid redirects. Got %d", cfg.SignatureMaxAgeSeconds))
+	}
+	if cfg.ReplayCacheSize < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.protection.replay_cache_size must not be negative. Got %d", cfg.ReplayCacheSize))
+	}
+	if cfg.RateLimit.RequestsPerMinute < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.protection.rate_limit.requests_per_minute must not be negative. Got %d", cfg.RateLimit.RequestsPerMinute))
+	}
+	if cfg.RateLimit.Burst < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.protection.rate_limit.burst must not be negative. Got %d", cfg.RateLimit.Burst))
+	}
+	if cfg.RateLimit.RequestsPerMinute > 0 && cfg.RateLimit.MaxClients <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.protection.rate_limit.max_clients must be positive when the rate limit is enabled. Got %d", cfg.RateLimit.MaxClients))
+	}
+	return errs
+}
@@ -0,0 +1,122 @@
+package dsa
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+const (
+	defaultWindowSeconds = 300
+	// maxTrackedBidders bounds the number of account and bidder pairs tracked at once
+	maxTrackedBidders = 100000
+)
+
+// Tracker counts the DSA validation failures of each bidder per account and decides which bidders are
+// disabled under the account bidder enforcement policy. It's safe for concurrent use.
+//
+// At most maxBidders pairs of account and bidder are tracked. When full, the pairs whose window and disable
+// period are over are dropped, and new pairs are not tracked until there is room for them.
+type Tracker struct {
+	mutex      sync.Mutex
+	bidders    map[trackerKey]*bidderStats
+	maxBidders int
+}
+
+type trackerKey struct {
+	accountID string
+	bidder    string
+}
+
+type bidderStats struct {
+	windowStart   time.Time
+	windowEnd     time.Time
+	bids          int
+	failures      int
+	disabledUntil time.Time
+}
+
+// expired reports if the stats neither count a current window nor disable the bidder
+func (s *bidderStats) expired(now time.Time) bool {
+	return !now.Before(s.windowEnd) && !now.Before(s.disabledUntil)
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{bidders: make(map[trackerKey]*bidderStats), maxBidders: maxTrackedBidders}
+}
+
+// Record counts the outcome of validating the bids of a bidder for the account and disables the bidder once its
+// failure rate over the current window exceeds the policy. Safe to call on a nil tracker.
+func (t *Tracker) Record(accountID, bidder string, bids, failures int, now time.Time, policy config.AccountDSABidderEnforcement) {
+	if t == nil || !policy.Enabled || bids == 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := trackerKey{accountID: accountID, bidder: bidder}
+	stats, ok := t.bidders[key]
+	if !ok {
+		if len(t.bidders) >= t.maxBidders && !t.purge(now) {
+			return
+		}
+		stats = &bidderStats{windowStart: now}
+		t.bidders[key] = stats
+	}
+	if now.Sub(stats.windowStart) >= windowLength(policy) {
+		stats.windowStart = now
+		stats.bids = 0
+		stats.failures = 0
+	}
+	stats.windowEnd = stats.windowStart.Add(windowLength(policy))
+
+	stats.bids += bids
+	stats.failures += failures
+	if stats.bids < policy.MinBids || float64(stats.failures)/float64(stats.bids) <= policy.MaxFailureRate {
+		return
+	}
+
+	stats.disabledUntil = now.Add(disableLength(policy))
+	stats.windowStart = now
+	stats.windowEnd = now.Add(windowLength(policy))
+	stats.bids = 0
+	stats.failures = 0
+}
+
+// purge drops the expired stats and reports if there is room for new ones. Must be called with the mutex held.
+func (t *Tracker) purge(now time.Time) bool {
+	for key, stats := range t.bidders {
+		if stats.expired(now) {
+			delete(t.bidders, key)
+		}
+	}
+	return len(t.bidders) < t.maxBidders
+}
+
+// Disabled reports if the bidder is disabled for the account. Safe to call on a nil tracker.
+func (t *Tracker) Disabled(accountID, bidder string, now time.Time, policy config.AccountDSABidderEnforcement) bool {
+	if t == nil || !policy.Enabled {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats, ok := t.bidders[trackerKey{accountID: accountID, bidder: bidder}]
+	return ok && now.Before(stats.disabledUntil)
+}
+
+func windowLength(policy config.AccountDSABidderEnforcement) time.Duration {
+	if policy.WindowSeconds <= 0 {
+		return defaultWindowSeconds * time.Second
+	}
+	return time.Duration(policy.WindowSeconds) * time.Second
+}
+
+// disableLength defaults to the window length so a disabled bidder is reevaluated over a full window
+func disableLength(policy config.AccountDSABidderEnforcement) time.Duration {
+	if policy.DisableSeconds <= 0 {
+		return windowLength(policy)
+	}
+	return time.Duration(policy.DisableSeconds) * time.Second
+}
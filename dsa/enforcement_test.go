@@ -0,0 +1,129 @@
+package dsa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := config.AccountDSABidderEnforcement{
+		Enabled:        true,
+		MaxFailureRate: 0.5,
+		MinBids:        4,
+		WindowSeconds:  60,
+		DisableSeconds: 120,
+	}
+
+	type record struct {
+		offset   time.Duration
+		bids     int
+		failures int
+	}
+
+	tests := []struct {
+		name         string
+		givePolicy   config.AccountDSABidderEnforcement
+		giveRecords  []record
+		checkOffset  time.Duration
+		wantDisabled bool
+	}{
+		{
+			name:         "no_records",
+			givePolicy:   policy,
+			checkOffset:  0,
+			wantDisabled: false,
+		},
+		{
+			name:         "below_min_bids",
+			givePolicy:   policy,
+			giveRecords:  []record{{bids: 3, failures: 3}},
+			checkOffset:  time.Second,
+			wantDisabled: false,
+		},
+		{
+			name:         "at_max_failure_rate",
+			givePolicy:   policy,
+			giveRecords:  []record{{bids: 2, failures: 1}, {offset: time.Second, bids: 2, failures: 1}},
+			checkOffset:  2 * time.Second,
+			wantDisabled: false,
+		},
+		{
+			name:         "above_max_failure_rate",
+			givePolicy:   policy,
+			giveRecords:  []record{{bids: 2, failures: 1}, {offset: time.Second, bids: 2, failures: 2}},
+			checkOffset:  2 * time.Second,
+			wantDisabled: true,
+		},
+		{
+			name:         "disable_period_over",
+			givePolicy:   policy,
+			giveRecords:  []record{{bids: 4, failures: 4}},
+			checkOffset:  120 * time.Second,
+			wantDisabled: false,
+		},
+		{
+			name:         "failures_of_previous_window_expire",
+			givePolicy:   policy,
+			giveRecords:  []record{{bids: 3, failures: 3}, {offset: 61 * time.Second, bids: 1, failures: 1}},
+			checkOffset:  62 * time.Second,
+			wantDisabled: false,
+		},
+		{
+			name: "disabled_by_policy",
+			givePolicy: config.AccountDSABidderEnforcement{
+				Enabled:        false,
+				MaxFailureRate: 0.5,
+			},
+			giveRecords:  []record{{bids: 4, failures: 4}},
+			checkOffset:  time.Second,
+			wantDisabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker()
+			for _, r := range tt.giveRecords {
+				tracker.Record("account", "bidder", r.bids, r.failures, start.Add(r.offset), tt.givePolicy)
+			}
+
+			assert.Equal(t, tt.wantDisabled, tracker.Disabled("account", "bidder", start.Add(tt.checkOffset), tt.givePolicy))
+			assert.False(t, tracker.Disabled("other-account", "bidder", start.Add(tt.checkOffset), tt.givePolicy))
+			assert.False(t, tracker.Disabled("account", "other-bidder", start.Add(tt.checkOffset), tt.givePolicy))
+		})
+	}
+}
+
+func TestTrackerNil(t *testing.T) {
+	var tracker *Tracker
+	policy := config.AccountDSABidderEnforcement{Enabled: true}
+
+	tracker.Record("account", "bidder", 1, 1, time.Now(), policy)
+	assert.False(t, tracker.Disabled("account", "bidder", time.Now(), policy))
+}
+
+func TestTrackerBounded(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := config.AccountDSABidderEnforcement{Enabled: true, MaxFailureRate: 0.5, MinBids: 1, WindowSeconds: 60, DisableSeconds: 120}
+	tracker := NewTracker()
+	tracker.maxBidders = 2
+
+	tracker.Record("account", "disabled", 1, 1, start, policy)
+	tracker.Record("account", "counted", 1, 0, start, policy)
+
+	// no room while the window of one pair and the disable period of the other are running
+	tracker.Record("account", "new", 1, 1, start.Add(time.Second), policy)
+	assert.False(t, tracker.Disabled("account", "new", start.Add(time.Second), policy), "New pairs must not be tracked when full")
+	assert.True(t, tracker.Disabled("account", "disabled", start.Add(time.Second), policy), "Tracked pairs must be kept when full")
+	assert.Len(t, tracker.bidders, 2)
+
+	// the window of the counted pair is over, the disabled pair is kept
+	tracker.Record("account", "new", 1, 1, start.Add(61*time.Second), policy)
+	assert.True(t, tracker.Disabled("account", "new", start.Add(61*time.Second), policy))
+	assert.True(t, tracker.Disabled("account", "disabled", start.Add(61*time.Second), policy))
+	assert.Len(t, tracker.bidders, 2)
+}
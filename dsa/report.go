@@ -0,0 +1,81 @@
+package dsa
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// Report holds the DSA transparency information of the bids returned by an auction. Entries lists who the ad
+// was displayed on behalf of and who paid for it on each impression, and Summary aggregates the entries by
+// seat, advertiser and payer.
+type Report struct {
+	Entries []ReportEntry   `json:"entries"`
+	Summary []ReportSummary `json:"summary"`
+}
+
+type ReportEntry struct {
+	ImpID        string                              `json:"impid"`
+	Seat         string                              `json:"seat"`
+	BidID        string                              `json:"bidid"`
+	Behalf       string                              `json:"behalf,omitempty"`
+	Paid         string                              `json:"paid,omitempty"`
+	AdRender     *int8                               `json:"adrender,omitempty"`
+	Transparency []openrtb_ext.ExtBidDSATransparency `json:"transparency,omitempty"`
+}
+
+type ReportSummary struct {
+	Seat        string `json:"seat"`
+	Behalf      string `json:"behalf,omitempty"`
+	Paid        string `json:"paid,omitempty"`
+	Impressions int    `json:"impressions"`
+}
+
+type summaryKey struct {
+	seat   string
+	behalf string
+	paid   string
+}
+
+// BuildReport collects the DSA object of each bid in the response. It returns nil if no bid carries one.
+func BuildReport(response *openrtb2.BidResponse) *Report {
+	if response == nil {
+		return nil
+	}
+
+	report := &Report{}
+	summaries := make(map[summaryKey]int)
+	for _, seatBid := range response.SeatBid {
+		for _, bid := range seatBid.Bid {
+			var bidExt openrtb_ext.ExtBid
+			if err := jsonutil.Unmarshal(bid.Ext, &bidExt); err != nil || bidExt.DSA == nil {
+				continue
+			}
+
+			report.Entries = append(report.Entries, ReportEntry{
+				ImpID:        bid.ImpID,
+				Seat:         seatBid.Seat,
+				BidID:        bid.ID,
+				Behalf:       bidExt.DSA.Behalf,
+				Paid:         bidExt.DSA.Paid,
+				AdRender:     bidExt.DSA.AdRender,
+				Transparency: bidExt.DSA.Transparency,
+			})
+
+			key := summaryKey{seat: seatBid.Seat, behalf: bidExt.DSA.Behalf, paid: bidExt.DSA.Paid}
+			if _, ok := summaries[key]; !ok {
+				report.Summary = append(report.Summary, ReportSummary{Seat: key.seat, Behalf: key.behalf, Paid: key.paid})
+			}
+			summaries[key]++
+		}
+	}
+
+	if len(report.Entries) == 0 {
+		return nil
+	}
+	for i := range report.Summary {
+		s := &report.Summary[i]
+		s.Impressions = summaries[summaryKey{seat: s.Seat, behalf: s.Behalf, paid: s.Paid}]
+	}
+	return report
+}
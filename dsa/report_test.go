@@ -0,0 +1,81 @@
+package dsa
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReport(t *testing.T) {
+	tests := []struct {
+		name         string
+		giveResponse *openrtb2.BidResponse
+		wantReport   *Report
+	}{
+		{
+			name:         "nil",
+			giveResponse: nil,
+			wantReport:   nil,
+		},
+		{
+			name: "no_dsa",
+			giveResponse: &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{
+					{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid1", ImpID: "imp1", Ext: json.RawMessage(`{"prebid":{}}`)}}},
+					{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "bid2", ImpID: "imp1"}}},
+				},
+			},
+			wantReport: nil,
+		},
+		{
+			name: "dsa",
+			giveResponse: &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{
+					{
+						Seat: "appnexus",
+						Bid: []openrtb2.Bid{
+							{ID: "bid1", ImpID: "imp1", Ext: json.RawMessage(`{"dsa":{"behalf":"advertiser","paid":"agency","adrender":1,"transparency":[{"domain":"dsp.com","dsaparams":[1,2]}]}}`)},
+							{ID: "bid2", ImpID: "imp2", Ext: json.RawMessage(`{"dsa":{"behalf":"advertiser","paid":"agency"}}`)},
+						},
+					},
+					{
+						Seat: "rubicon",
+						Bid: []openrtb2.Bid{
+							{ID: "bid3", ImpID: "imp1", Ext: json.RawMessage(`{"dsa":{"behalf":"advertiser"}}`)},
+							{ID: "bid4", ImpID: "imp2"},
+						},
+					},
+				},
+			},
+			wantReport: &Report{
+				Entries: []ReportEntry{
+					{
+						ImpID:        "imp1",
+						Seat:         "appnexus",
+						BidID:        "bid1",
+						Behalf:       "advertiser",
+						Paid:         "agency",
+						AdRender:     ptrutil.ToPtr[int8](1),
+						Transparency: []openrtb_ext.ExtBidDSATransparency{{Domain: "dsp.com", Params: []int{1, 2}}},
+					},
+					{ImpID: "imp2", Seat: "appnexus", BidID: "bid2", Behalf: "advertiser", Paid: "agency"},
+					{ImpID: "imp1", Seat: "rubicon", BidID: "bid3", Behalf: "advertiser"},
+				},
+				Summary: []ReportSummary{
+					{Seat: "appnexus", Behalf: "advertiser", Paid: "agency", Impressions: 2},
+					{Seat: "rubicon", Behalf: "advertiser", Impressions: 1},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantReport, BuildReport(tt.giveResponse))
+		})
+	}
+}
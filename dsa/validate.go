@@ -69,6 +69,11 @@ func Validate(req *openrtb_ext.RequestWrapper, bid *entities.PbsOrtbBid) error {
 	return nil
 }
 
+// IsRequired reports if the request requires bid responses to include a DSA object
+func IsRequired(req *openrtb_ext.RequestWrapper) bool {
+	return dsaRequired(getReqDSA(req))
+}
+
 // dsaRequired examines the bid request to determine if the dsarequired field indicates
 // that bid responses include a dsa object
 func dsaRequired(dsa *openrtb_ext.ExtRegsDSA) bool {
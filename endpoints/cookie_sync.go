@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -25,10 +26,14 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/privacy/ccpa"
+	"github.com/prebid/prebid-server/v2/privacy/coppa"
+	"github.com/prebid/prebid-server/v2/privacy/gpc"
 	gppPrivacy "github.com/prebid/prebid-server/v2/privacy/gpp"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/ratelimit"
 	stringutil "github.com/prebid/prebid-server/v2/util/stringutil"
 	"github.com/prebid/prebid-server/v2/util/timeutil"
 )
@@ -57,7 +62,8 @@ func NewCookieSyncEndpoint(
 	metrics metrics.MetricsEngine,
 	analyticsRunner analytics.Runner,
 	accountsFetcher stored_requests.AccountFetcher,
-	bidders map[string]openrtb_ext.BidderName) HTTPRouterHandler {
+	bidders map[string]openrtb_ext.BidderName,
+	syncValues usersync.SyncValues) HTTPRouterHandler {
 
 	bidderHashSet := make(map[string]struct{}, len(bidders))
 	for _, bidder := range bidders {
@@ -65,8 +71,9 @@ func NewCookieSyncEndpoint(
 	}
 
 	return &cookieSyncEndpoint{
-		chooser: usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos),
-		config:  config,
+		chooser:         usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos, syncValues),
+		syncersByBidder: syncersByBidder,
+		config:          config,
 		privacyConfig: usersyncPrivacyConfig{
 			gdprConfig:             config.GDPR,
 			gdprPermissionsBuilder: gdprPermsBuilder,
@@ -78,20 +85,29 @@ func NewCookieSyncEndpoint(
 		pbsAnalytics:    analyticsRunner,
 		accountsFetcher: accountsFetcher,
 		time:            &timeutil.RealTime{},
+		rateLimiter:     newUserSyncRateLimiter(config),
 	}
 }
 
 type cookieSyncEndpoint struct {
 	chooser         usersync.Chooser
+	syncersByBidder map[string]usersync.Syncer
 	config          *config.Configuration
 	privacyConfig   usersyncPrivacyConfig
 	metrics         metrics.MetricsEngine
 	pbsAnalytics    analytics.Runner
 	accountsFetcher stored_requests.AccountFetcher
 	time            timeutil.Time
+	rateLimiter     *ratelimit.Limiter
 }
 
 func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if !c.rateLimiter.Allow(userSyncClientIP(r, c.config)) {
+		c.metrics.RecordCookieSync(metrics.CookieSyncRateLimited)
+		c.handleError(w, errUserSyncRateLimited, http.StatusTooManyRequests)
+		return
+	}
+
 	request, privacyMacros, account, err := c.parseRequest(r)
 	c.setCookieDeprecationHeader(w, r, account)
 	if err != nil {
@@ -99,10 +115,11 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 		c.handleError(w, err, http.StatusBadRequest)
 		return
 	}
-	decoder := usersync.Base64Decoder{}
+	decoder := usersync.VersionedDecoder{}
 
-	cookie := usersync.ReadCookie(r, decoder, &c.config.HostCookie)
-	usersync.SyncHostCookie(r, cookie, &c.config.HostCookie)
+	hostCookie := c.hostCookie(r, account)
+	cookie := usersync.ReadCookie(r, decoder, hostCookie)
+	usersync.SyncHostCookie(r, cookie, hostCookie)
 
 	result := c.chooser.Choose(request, cookie)
 
@@ -116,6 +133,7 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 	case usersync.StatusOK:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
 		c.writeSyncerMetrics(result.BiddersEvaluated)
+		c.recordSyncAttempts(w, r, cookie, account, result.SyncersChosen)
 		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug)
 	}
 }
@@ -147,6 +165,13 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
 	}
+	privacyPolicies.GPC = gpcSignalPolicy(r.Header.Get("Sec-GPC"))
+	privacyPolicies = userSyncGeoAndChannelPolicies(privacyPolicies, r, c.config.UserSync.Geo)
+
+	childDirected := coppa.DomainChildDirected(account.Privacy.ChildDirected, requestDomain(r))
+	if childDirected {
+		privacyPolicies.COPPA = ptrutil.ToPtr[int8](1)
+	}
 
 	ccpaParsedPolicy := ccpa.ParsedPolicy{}
 	if request.USPrivacy != "" {
@@ -188,13 +213,42 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 			activityControl:  activityControl,
 			activityRequest:  privacy.NewRequestFromPolicies(privacyPolicies),
 			gdprSignal:       gdprSignal,
+			blockUserSync:    childDirected && account.Privacy.ChildDirected.Strict,
 		},
 		SyncTypeFilter: syncTypeFilter,
 		GPPSID:         request.GPPSID,
+		Prioritization: c.prioritization(r, account),
 	}
 	return rx, privacyMacros, account, nil
 }
 
+// prioritization returns how the bidders to sync are ordered for the account. The country of the user is read
+// from the configured request header, if any.
+func (c *cookieSyncEndpoint) prioritization(r *http.Request, account *config.Account) usersync.Prioritization {
+	if account.CookieSync.Prioritization != config.CookieSyncPrioritizationYield {
+		return usersync.Prioritization{}
+	}
+
+	prioritization := usersync.Prioritization{Yield: true, Account: account.ID}
+	if header := c.config.UserSync.SyncValues.CountryHeader; header != "" {
+		prioritization.Country = r.Header.Get(header)
+	}
+	return prioritization
+}
+
+// requestDomain returns the domain of the page calling the endpoint, taken from the Referer or else the Origin
+// header
+func requestDomain(r *http.Request) string {
+	for _, header := range []string{"Referer", "Origin"} {
+		if value := r.Header.Get(header); value != "" {
+			if parsed, err := url.Parse(value); err == nil && parsed.Hostname() != "" {
+				return parsed.Hostname()
+			}
+		}
+	}
+	return ""
+}
+
 func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue string) (macros.UserSyncPrivacy, gdpr.Signal, privacy.Policies, error) {
 	// GDPR
 	gppSID, err := stringutil.StrToInt8Slice(request.GPPSID)
@@ -247,11 +301,45 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 
 	privacyPolicies := privacy.Policies{
 		GPPSID: gppSID,
+		GPP:    request.GPP,
+		GDPR:   gdprSignalPolicy(gdprSignal),
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
 }
 
+// userSyncGeoAndChannelPolicies sets the location of the user, read from the configured request headers, and the
+// web channel of the user syncs, which rely on browser cookies, to match the geo and channel activity conditions.
+func userSyncGeoAndChannelPolicies(policies privacy.Policies, r *http.Request, geo config.UserSyncGeo) privacy.Policies {
+	policies.Channel = string(config.ChannelWeb)
+	if geo.CountryHeader != "" {
+		policies.Country = strings.TrimSpace(r.Header.Get(geo.CountryHeader))
+	}
+	if geo.RegionHeader != "" {
+		policies.Region = strings.TrimSpace(r.Header.Get(geo.RegionHeader))
+	}
+	return policies
+}
+
+// gpcSignalPolicy returns the Global Privacy Control signal of the Sec-GPC header to match the activity
+// conditions against, nil if the header is not set.
+func gpcSignalPolicy(header string) *int8 {
+	if !gpc.ReadFromHeader(header).CanEnforce() {
+		return nil
+	}
+	signal := int8(1)
+	return &signal
+}
+
+// gdprSignalPolicy returns the GDPR signal to match the activity conditions against, nil if ambiguous.
+func gdprSignalPolicy(signal gdpr.Signal) *int8 {
+	if signal == gdpr.SignalAmbiguous {
+		return nil
+	}
+	value := int8(signal)
+	return &value
+}
+
 func extractGDPRSignal(requestGDPR *int, gppSID []int8) (gdpr.Signal, string, error) {
 	if len(gppSID) > 0 {
 		if gppPrivacy.IsSIDInList(gppSID, gppConstants.SectionTCFEU2) {
@@ -408,6 +496,50 @@ func (c *cookieSyncEndpoint) writeSyncerMetrics(biddersEvaluated []usersync.Bidd
 	}
 }
 
+// recordSyncAttempts counts the syncs offered to the user for the bidders capping them and writes the counts
+// to the uids cookie. The cookie is left untouched if no chosen bidder caps its sync attempts. UIDs are ejected
+// from a full cookie by priority, as they are by the /setuid endpoint. No UID is added here, so the ejector never
+// refuses to eject the last non priority UID.
+func (c *cookieSyncEndpoint) recordSyncAttempts(w http.ResponseWriter, r *http.Request, cookie *usersync.Cookie, account *config.Account, syncersChosen []usersync.SyncerChoice) {
+	recorded := false
+	for _, syncerChoice := range syncersChosen {
+		syncerConfig := c.config.BidderInfos[syncerChoice.Bidder].Syncer
+		if syncerConfig.MaxSyncAttempts() <= 0 {
+			continue
+		}
+		cookie.RecordSyncAttempt(syncerChoice.Syncer.Key(), syncerConfig.SyncAttemptWindow())
+		recorded = true
+	}
+	if !recorded {
+		return
+	}
+
+	hostCookie := c.hostCookie(r, account)
+	var accountCookie *config.AccountCookie
+	if account != nil {
+		accountCookie = &account.Cookie
+	}
+	cookieAttributes := usersync.NewCookieAttributes(r, cookie, hostCookie, accountCookie, siteCookieCheck(r.UserAgent()))
+
+	encoder := usersync.NewEncoder(hostCookie)
+	priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: c.config.UserSync.PriorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: c.syncersByBidder, IsSyncerPriority: true}
+	encodedCookie, err := cookie.PrepareCookieForWrite(hostCookie, encoder, priorityEjector)
+	if err != nil || encodedCookie == "" {
+		return
+	}
+	usersync.WriteCookieWithAttributes(w, encodedCookie, hostCookie, cookieAttributes)
+}
+
+// hostCookie returns the host cookie of the account or, if none is defined for it, of the host name the request
+// was sent to.
+func (c *cookieSyncEndpoint) hostCookie(r *http.Request, account *config.Account) *config.HostCookie {
+	var accountID string
+	if account != nil {
+		accountID = account.ID
+	}
+	return usersync.ResolveHostCookie(r, &c.config.HostCookie, accountID)
+}
+
 func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool) {
 	status := "no_cookie"
 	if co.HasAnyLiveSyncs() {
@@ -528,6 +660,8 @@ func getDebugMessage(status usersync.Status) string {
 		return "Type not supported"
 	case usersync.StatusBlockedByDisabledUsersync:
 		return "Sync disabled by config"
+	case usersync.StatusRateLimited:
+		return "Sync attempts limit reached"
 	}
 	return ""
 }
@@ -593,6 +727,8 @@ type usersyncPrivacy struct {
 	activityControl  privacy.ActivityControl
 	activityRequest  privacy.ActivityRequest
 	gdprSignal       gdpr.Signal
+	// blockUserSync is set for child-directed inventory under the stricter child-directed profile
+	blockUserSync bool
 }
 
 func (p usersyncPrivacy) GDPRAllowsHostCookie() bool {
@@ -611,6 +747,9 @@ func (p usersyncPrivacy) CCPAAllowsBidderSync(bidder string) bool {
 }
 
 func (p usersyncPrivacy) ActivityAllowsUserSync(bidder string) bool {
+	if p.blockUserSync {
+		return false
+	}
 	return p.activityControl.Allow(
 		privacy.ActivitySyncUser,
 		privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidder},
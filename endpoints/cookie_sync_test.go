@@ -74,11 +74,12 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		&analytics,
 		&fetcher,
 		bidders,
+		nil,
 	)
 	result := endpoint.(*cookieSyncEndpoint)
 
 	expected := &cookieSyncEndpoint{
-		chooser: usersync.NewChooser(syncersByBidder, biddersKnown, bidderInfo),
+		chooser: usersync.NewChooser(syncersByBidder, biddersKnown, bidderInfo, nil),
 		config: &config.Configuration{
 			UserSync:    configUserSync,
 			HostCookie:  configHostCookie,
@@ -402,6 +403,43 @@ func TestCookieSyncHandle(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandleRateLimited(t *testing.T) {
+	mockMetrics := metrics.MetricsEngineMock{}
+	mockMetrics.On("RecordCookieSync", metrics.CookieSyncRateLimited).Once()
+
+	expectedAnalytics := analytics.CookieSyncObject{
+		Status:       http.StatusTooManyRequests,
+		Errors:       []error{errUserSyncRateLimited},
+		BidderStatus: []*analytics.CookieSyncBidder{},
+	}
+	mockAnalytics := MockAnalyticsRunner{}
+	mockAnalytics.On("LogCookieSyncObject", &expectedAnalytics).Once()
+
+	cfg := &config.Configuration{
+		UserSync: config.UserSync{Protection: config.UserSyncProtection{
+			RateLimit: config.UserSyncRateLimit{RequestsPerMinute: 1, Burst: 1, MaxClients: 10},
+		}},
+	}
+	endpoint := cookieSyncEndpoint{
+		config:       cfg,
+		metrics:      &mockMetrics,
+		pbsAnalytics: &mockAnalytics,
+		rateLimiter:  newUserSyncRateLimiter(cfg),
+	}
+
+	// the first request of the client consumes its only token
+	assert.True(t, endpoint.rateLimiter.Allow("192.0.2.1"))
+
+	request := httptest.NewRequest("POST", "/cookiesync", strings.NewReader(`{}`))
+	writer := httptest.NewRecorder()
+	endpoint.Handle(writer, request, nil)
+
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.Equal(t, errUserSyncRateLimited.Error()+"\n", writer.Body.String())
+	mockMetrics.AssertExpectations(t)
+	mockAnalytics.AssertExpectations(t)
+}
+
 func TestExtractGDPRSignal(t *testing.T) {
 	type testInput struct {
 		requestGDPR *int
@@ -488,6 +526,36 @@ func TestExtractGDPRSignal(t *testing.T) {
 	}
 }
 
+func TestGPCSignalPolicy(t *testing.T) {
+	testCases := []struct {
+		description string
+		header      string
+		expected    *int8
+	}{
+		{
+			description: "Opt-Out",
+			header:      "1",
+			expected:    ptrutil.ToPtr[int8](1),
+		},
+		{
+			description: "Not Set",
+			header:      "",
+			expected:    nil,
+		},
+		{
+			description: "Unknown Value",
+			header:      "0",
+			expected:    nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, gpcSignalPolicy(test.header))
+		})
+	}
+}
+
 func TestExtractPrivacyPolicies(t *testing.T) {
 	type testInput struct {
 		request                  cookieSyncRequest
@@ -565,7 +633,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 					GPPSID:      "6",
 				},
 				gdprSignal: gdpr.SignalNo,
-				policies:   privacy.Policies{GPPSID: []int8{6}},
+				policies:   privacy.Policies{GPPSID: []int8{6}, GPP: "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN", GDPR: ptrutil.ToPtr[int8](0)},
 				err:        nil,
 			},
 		},
@@ -582,7 +650,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 
 func TestCookieSyncParseRequest(t *testing.T) {
 	expectedCCPAParsedPolicy, _ := ccpa.Policy{Consent: "1NYN"}.Parse(map[string]struct{}{})
-	emptyActivityPoliciesRequest := privacy.NewRequestFromPolicies(privacy.Policies{})
+	webActivityPoliciesRequest := privacy.NewRequestFromPolicies(privacy.Policies{Channel: "web"})
 
 	testCases := []struct {
 		description          string
@@ -634,7 +702,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}}),
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}, GPP: "DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA", GDPR: ptrutil.ToPtr[int8](1), Channel: "web"}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -676,7 +744,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  emptyActivityPoliciesRequest,
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GDPR: ptrutil.ToPtr[int8](1), Channel: "web"}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -694,7 +762,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedRequest: usersync.Request{
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -722,7 +790,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -750,7 +818,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -778,7 +846,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -806,7 +874,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -834,7 +902,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -862,7 +930,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -880,7 +948,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedRequest: usersync.Request{
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -900,7 +968,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedRequest: usersync.Request{
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -941,7 +1009,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedRequest: usersync.Request{
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: privacy.NewRequestFromPolicies(privacy.Policies{GDPR: ptrutil.ToPtr[int8](0), Channel: "web"}),
 					gdprSignal:      0,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -968,7 +1036,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedRequest: usersync.Request{
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1014,7 +1082,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Limit: 30,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1047,7 +1115,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Limit: 20,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      -1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1080,7 +1148,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Limit: 20,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
-					activityRequest: emptyActivityPoliciesRequest,
+					activityRequest: webActivityPoliciesRequest,
 					gdprSignal:      0,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1135,6 +1203,58 @@ func TestCookieSyncParseRequest(t *testing.T) {
 	}
 }
 
+func TestCookieSyncParseRequestGeo(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenHeaders    map[string]string
+		expectedAllowed bool
+	}{
+		{
+			description:     "Blocked Region",
+			givenHeaders:    map[string]string{"X-Geo-Country": "USA", "X-Geo-Region": "CA"},
+			expectedAllowed: false,
+		},
+		{
+			description:     "Other Region",
+			givenHeaders:    map[string]string{"X-Geo-Country": "USA", "X-Geo-Region": "NY"},
+			expectedAllowed: true,
+		},
+		{
+			description:     "Unknown Location",
+			givenHeaders:    map[string]string{},
+			expectedAllowed: true,
+		},
+	}
+
+	endpoint := cookieSyncEndpoint{
+		config: &config.Configuration{
+			UserSync: config.UserSync{
+				Geo: config.UserSyncGeo{CountryHeader: "X-Geo-Country", RegionHeader: "X-Geo-Region"},
+			},
+		},
+		privacyConfig: usersyncPrivacyConfig{
+			gdprPermissionsBuilder: fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder,
+			tcf2ConfigBuilder:      fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder,
+		},
+		accountsFetcher: FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+			"GeoAccount": json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"geo":["USA.CA"],"channel":["web"]},"allow":false}]}}}}`),
+		}},
+	}
+	assert.NoError(t, endpoint.config.MarshalAccountDefaults())
+
+	for _, test := range testCases {
+		httpRequest := httptest.NewRequest("POST", "/cookiesync", strings.NewReader(`{"bidders":["a"],"gdpr":0,"account":"GeoAccount"}`))
+		for name, value := range test.givenHeaders {
+			httpRequest.Header.Set(name, value)
+		}
+
+		request, _, _, err := endpoint.parseRequest(httpRequest)
+
+		assert.NoError(t, err, test.description+":err")
+		assert.Equal(t, test.expectedAllowed, request.Privacy.ActivityAllowsUserSync("a"), test.description)
+	}
+}
+
 func TestSetLimit(t *testing.T) {
 	intNegative1 := -1
 	int20 := 20
@@ -1702,6 +1822,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 		{Bidder: "Bidder5", Status: usersync.StatusTypeNotSupported},
 		{Bidder: "Bidder6", Status: usersync.StatusBlockedByUserOptOut},
 		{Bidder: "Bidder7", Status: usersync.StatusBlockedByDisabledUsersync},
+		{Bidder: "Bidder8", Status: usersync.StatusRateLimited},
 		{Bidder: "BidderA", Status: usersync.StatusDuplicate, SyncerKey: "syncerB"},
 	}
 
@@ -1792,7 +1913,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			givenCookieHasSyncs: true,
 			givenDebug:          true,
 			givenSyncersChosen:  []usersync.SyncerChoice{},
-			expectedJSON:        `{"status":"ok","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Type not supported"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}]}` + "\n",
+			expectedJSON:        `{"status":"ok","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Type not supported"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"Bidder8","error":"Sync attempts limit reached"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}]}` + "\n",
 			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
 		},
 	}
@@ -2056,6 +2177,74 @@ func TestCookieSyncActivityControlIntegration(t *testing.T) {
 	}
 }
 
+func TestUsersyncPrivacyActivityAllowsUserSyncChildDirected(t *testing.T) {
+	testCases := []struct {
+		name           string
+		blockUserSync  bool
+		expectedResult bool
+	}{
+		{
+			name:           "not_blocked",
+			blockUserSync:  false,
+			expectedResult: true,
+		},
+		{
+			name:           "blocked",
+			blockUserSync:  true,
+			expectedResult: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			up := usersyncPrivacy{
+				activityControl: privacy.NewActivityControl(nil),
+				blockUserSync:   test.blockUserSync,
+			}
+			assert.Equal(t, test.expectedResult, up.ActivityAllowsUserSync("bidderA"))
+		})
+	}
+}
+
+func TestRequestDomain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "none",
+			headers:  map[string]string{},
+			expected: "",
+		},
+		{
+			name:     "referer",
+			headers:  map[string]string{"Referer": "https://www.kids.com/games?id=1", "Origin": "https://other.com"},
+			expected: "www.kids.com",
+		},
+		{
+			name:     "origin",
+			headers:  map[string]string{"Origin": "https://kids.com"},
+			expected: "kids.com",
+		},
+		{
+			name:     "invalid_referer",
+			headers:  map[string]string{"Referer": "not a url", "Origin": "https://kids.com"},
+			expected: "kids.com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/cookie_sync", nil)
+			for header, value := range test.headers {
+				request.Header.Set(header, value)
+			}
+			assert.Equal(t, test.expected, requestDomain(request))
+		})
+	}
+}
+
 func TestUsersyncPrivacyGDPRInScope(t *testing.T) {
 	testCases := []struct {
 		description     string
@@ -2273,6 +2462,152 @@ func getDefaultActivityConfig(componentName string, allow bool) *config.AccountP
 	}
 }
 
+func TestCookieSyncPrioritization(t *testing.T) {
+	testCases := []struct {
+		description            string
+		givenAccount           config.Account
+		givenCountryHeader     string
+		expectedPrioritization usersync.Prioritization
+	}{
+		{
+			description:            "Default",
+			givenAccount:           config.Account{ID: "account"},
+			givenCountryHeader:     "X-Country",
+			expectedPrioritization: usersync.Prioritization{},
+		},
+		{
+			description:            "Random",
+			givenAccount:           config.Account{ID: "account", CookieSync: config.CookieSync{Prioritization: config.CookieSyncPrioritizationRandom}},
+			givenCountryHeader:     "X-Country",
+			expectedPrioritization: usersync.Prioritization{},
+		},
+		{
+			description:            "Yield",
+			givenAccount:           config.Account{ID: "account", CookieSync: config.CookieSync{Prioritization: config.CookieSyncPrioritizationYield}},
+			givenCountryHeader:     "X-Country",
+			expectedPrioritization: usersync.Prioritization{Yield: true, Account: "account", Country: "USA"},
+		},
+		{
+			description:            "Yield Without Country Header",
+			givenAccount:           config.Account{ID: "account", CookieSync: config.CookieSync{Prioritization: config.CookieSyncPrioritizationYield}},
+			givenCountryHeader:     "",
+			expectedPrioritization: usersync.Prioritization{Yield: true, Account: "account"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			endpoint := cookieSyncEndpoint{config: &config.Configuration{
+				UserSync: config.UserSync{SyncValues: config.SyncValues{CountryHeader: test.givenCountryHeader}},
+			}}
+			request := httptest.NewRequest("POST", "/cookie_sync", nil)
+			request.Header.Set("X-Country", "USA")
+
+			assert.Equal(t, test.expectedPrioritization, endpoint.prioritization(request, &test.givenAccount))
+		})
+	}
+}
+
+func TestRecordSyncAttempts(t *testing.T) {
+	syncerA := MockSyncer{}
+	syncerA.On("Key").Return("keyA")
+	syncerB := MockSyncer{}
+	syncerB.On("Key").Return("keyB")
+
+	bidderInfos := config.BidderInfos{
+		"a": {Syncer: &config.Syncer{MaxAttempts: ptrutil.ToPtr(3)}},
+		"b": {Syncer: &config.Syncer{}},
+	}
+
+	testCases := []struct {
+		description           string
+		givenSyncersChosen    []usersync.SyncerChoice
+		expectedCookieWritten bool
+		expectedAttempts      map[string]int
+	}{
+		{
+			description:           "No Syncers Chosen",
+			givenSyncersChosen:    []usersync.SyncerChoice{},
+			expectedCookieWritten: false,
+		},
+		{
+			description:           "Uncapped Syncer Chosen",
+			givenSyncersChosen:    []usersync.SyncerChoice{{Bidder: "b", Syncer: &syncerB}},
+			expectedCookieWritten: false,
+		},
+		{
+			description:           "Capped Syncer Chosen",
+			givenSyncersChosen:    []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncerA}, {Bidder: "b", Syncer: &syncerB}},
+			expectedCookieWritten: true,
+			expectedAttempts:      map[string]int{"keyA": 1, "keyB": 0},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			endpoint := cookieSyncEndpoint{config: &config.Configuration{BidderInfos: bidderInfos}}
+			writer := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/cookie_sync", nil)
+
+			endpoint.recordSyncAttempts(writer, request, usersync.NewCookie(), nil, test.givenSyncersChosen)
+
+			setCookie := writer.Header().Get("Set-Cookie")
+			if !test.expectedCookieWritten {
+				assert.Empty(t, setCookie)
+				return
+			}
+
+			response := http.Response{Header: writer.Header()}
+			cookies := response.Cookies()
+			if !assert.Len(t, cookies, 1) {
+				return
+			}
+			cookie := usersync.VersionedDecoder{}.Decode(cookies[0].Value)
+			for key, attempts := range test.expectedAttempts {
+				assert.Equal(t, attempts, cookie.SyncAttemptCount(key, 24*time.Hour), key)
+			}
+		})
+	}
+}
+
+func TestRecordSyncAttemptsPriorityEjection(t *testing.T) {
+	syncerA := MockSyncer{}
+	syncerA.On("Key").Return("keyA")
+	syncerP := MockSyncer{}
+	syncerP.On("Key").Return("keyP")
+	syncerO := MockSyncer{}
+	syncerO.On("Key").Return("keyO")
+
+	endpoint := cookieSyncEndpoint{
+		syncersByBidder: map[string]usersync.Syncer{"a": &syncerA, "p": &syncerP, "o": &syncerO},
+		config: &config.Configuration{
+			BidderInfos: config.BidderInfos{"a": {Syncer: &config.Syncer{MaxAttempts: ptrutil.ToPtr(3)}}},
+			HostCookie:  config.HostCookie{MaxCookieSizeBytes: 600},
+			UserSync:    config.UserSync{PriorityGroups: [][]string{{"p"}}},
+		},
+	}
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cookie_sync", nil)
+
+	// the priority uid is the oldest, which the oldest ejector alone would eject
+	cookie := usersync.NewCookie()
+	assert.NoError(t, cookie.Sync("keyP", strings.Repeat("p", 200)))
+	assert.NoError(t, cookie.Sync("keyO", strings.Repeat("o", 200)))
+
+	endpoint.recordSyncAttempts(writer, request, cookie, nil, []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncerA}})
+
+	response := http.Response{Header: writer.Header()}
+	cookies := response.Cookies()
+	if !assert.Len(t, cookies, 1) {
+		return
+	}
+	written := usersync.VersionedDecoder{}.Decode(cookies[0].Value)
+	_, hasPriority, _ := written.GetUID("keyP")
+	_, hasOther, _ := written.GetUID("keyO")
+	assert.True(t, hasPriority, "The uid of the priority bidder must be kept.")
+	assert.False(t, hasOther, "The uid of the other bidder must be ejected.")
+}
+
 func TestSetCookieDeprecationHeader(t *testing.T) {
 	getTestRequest := func(addCookie bool) *http.Request {
 		r := httptest.NewRequest("POST", "/cookie_sync", nil)
@@ -18,6 +18,7 @@ import (
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/util/httputil"
@@ -47,15 +48,17 @@ type eventEndpoint struct {
 	Cfg           *config.Configuration
 	TrackingPixel *httputil.Pixel
 	MetricsEngine metrics.MetricsEngine
+	EventNotifier *moduledeps.EventNotifier
 }
 
-func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine) httprouter.Handle {
+func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine, notifier *moduledeps.EventNotifier) httprouter.Handle {
 	ee := &eventEndpoint{
 		Accounts:      accounts,
 		Analytics:     analytics,
 		Cfg:           cfg,
 		TrackingPixel: &httputil.Pixel1x1PNG,
 		MetricsEngine: me,
+		EventNotifier: notifier,
 	}
 
 	return ee.Handle
@@ -86,11 +89,6 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 	}
 	eventRequest.AccountID = accountId
 
-	if eventRequest.Analytics != analytics.Enabled {
-		w.WriteHeader(http.StatusNoContent)
-		return
-	}
-
 	ctx := context.Background()
 	if e.Cfg.Event.TimeoutMS > 0 {
 		var cancel context.CancelFunc
@@ -98,9 +96,14 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 		defer cancel()
 	}
 
-	// get account details
+	// get account details, the account is resolved for the modules even if the analytics are disabled
 	account, errs := accountService.GetAccount(ctx, e.Cfg, e.Accounts, eventRequest.AccountID, e.MetricsEngine)
 	if len(errs) > 0 {
+		if eventRequest.Analytics != analytics.Enabled {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		status, messages := HandleAccountServiceErrors(errs)
 		w.WriteHeader(status)
 
@@ -112,18 +115,33 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 
 	// Check if events are enabled for the account
 	if !account.Events.Enabled {
+		if eventRequest.Analytics != analytics.Enabled {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		w.WriteHeader(http.StatusUnauthorized)
 		fmt.Fprintf(w, "Account '%s' doesn't support events", eventRequest.AccountID)
 		return
 	}
 
+	notificationEvent := &analytics.NotificationEvent{
+		Request: eventRequest,
+		Account: account,
+	}
+
+	// modules are notified of the events of the account regardless of the analytics parameter
+	e.EventNotifier.Notify(notificationEvent)
+
+	if eventRequest.Analytics != analytics.Enabled {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	activities := privacy.NewActivityControl(&account.Privacy)
 
 	// handle notification event
-	e.Analytics.LogNotificationEventObject(&analytics.NotificationEvent{
-		Request: eventRequest,
-		Account: account,
-	}, activities)
+	e.Analytics.LogNotificationEventObject(notificationEvent, activities)
 
 	// Add tracking pixel if format == image
 	if eventRequest.Format == analytics.Image {
@@ -16,6 +16,7 @@ import (
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/stretchr/testify/assert"
@@ -123,7 +124,7 @@ func TestShouldReturnBadRequestWhenTypeIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?b=test", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -159,7 +160,7 @@ func TestShouldReturnBadRequestWhenTypeIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=test&b=t", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -195,7 +196,7 @@ func TestShouldReturnBadRequestWhenBidIdIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -231,7 +232,7 @@ func TestShouldReturnBadRequestWhenTimestampIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -267,7 +268,7 @@ func TestShouldReturnUnauthorizedWhenAccountIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -303,7 +304,7 @@ func TestShouldReturnBadRequestWhenFormatValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -339,7 +340,7 @@ func TestShouldReturnBadRequestWhenAnalyticsValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=4", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -378,7 +379,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountNotFoundAndDefaultIsFal
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=testacc", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -412,7 +413,7 @@ func TestShouldReturnBadRequestWhenIntegrationValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=bidId&f=b&ts=1000&x=1&a=accountId&bidder=bidder&int=Te$tIntegrationType", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -451,7 +452,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountEventNotEnabled(t *test
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_disabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -489,7 +490,7 @@ func TestShouldPassEventToAnalyticsReporterWhenAccountEventEnabled(t *testing.T)
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -523,7 +524,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAnalyticsValueIsZero(t *testin
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=0&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -557,7 +558,7 @@ func TestShouldRespondWithPixelAndContentTypeWhenRequestFormatIsImage(t *testing
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=i&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -598,7 +599,7 @@ func TestShouldRespondWithNoContentWhenRequestFormatIsNotDefined(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=imp&b=test&ts=1234&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -615,6 +616,93 @@ func TestShouldRespondWithNoContentWhenRequestFormatIsNotDefined(t *testing.T) {
 	assert.Equal(t, 0, len(d))
 }
 
+func TestShouldNotifyModules(t *testing.T) {
+	tests := []struct {
+		description            string
+		url                    string
+		expectedStatus         int
+		expectedNotified       bool
+		expectedAnalyticsEvent bool
+	}{
+		{
+			description:            "Analytics enabled",
+			url:                    "/event?t=imp&b=test&ts=1234&x=1&a=events_enabled",
+			expectedStatus:         204,
+			expectedNotified:       true,
+			expectedAnalyticsEvent: true,
+		},
+		{
+			description:            "Analytics disabled",
+			url:                    "/event?t=imp&b=test&ts=1234&x=0&a=events_enabled",
+			expectedStatus:         204,
+			expectedNotified:       true,
+			expectedAnalyticsEvent: false,
+		},
+		{
+			description:            "Analytics disabled, events disabled for account",
+			url:                    "/event?t=imp&b=test&ts=1234&x=0&a=testacc",
+			expectedStatus:         204,
+			expectedNotified:       false,
+			expectedAnalyticsEvent: false,
+		},
+		{
+			description:            "Analytics disabled, unknown account",
+			url:                    "/event?t=imp&b=test&ts=1234&x=0&a=unknown",
+			expectedStatus:         204,
+			expectedNotified:       false,
+			expectedAnalyticsEvent: false,
+		},
+		{
+			description:            "Analytics enabled, events disabled for account",
+			url:                    "/event?t=imp&b=test&ts=1234&x=1&a=testacc",
+			expectedStatus:         401,
+			expectedNotified:       false,
+			expectedAnalyticsEvent: false,
+		},
+		{
+			description:            "Missing account",
+			url:                    "/event?t=imp&b=test&ts=1234&x=0",
+			expectedStatus:         401,
+			expectedNotified:       false,
+			expectedAnalyticsEvent: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			mockAnalyticsModule := &eventsMockAnalyticsModule{}
+			cfg := &config.Configuration{AccountDefaults: config.Account{}}
+			cfg.MarshalAccountDefaults()
+
+			var notified *analytics.NotificationEvent
+			notifier := &moduledeps.EventNotifier{}
+			notifier.Subscribe(func(event *analytics.NotificationEvent) {
+				notified = event
+			})
+
+			req := httptest.NewRequest("GET", test.url, strings.NewReader(""))
+			recorder := httptest.NewRecorder()
+
+			e := NewEventEndpoint(cfg, &mockAccountsFetcher{}, mockAnalyticsModule, &metrics.MetricsEngineMock{}, notifier)
+			e(recorder, req, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Result().StatusCode)
+			assert.Equal(t, test.expectedAnalyticsEvent, mockAnalyticsModule.Invoked)
+			if test.expectedNotified {
+				if assert.NotNil(t, notified) {
+					assert.Equal(t, analytics.Imp, notified.Request.Type)
+					assert.Equal(t, "test", notified.Request.BidID)
+					if assert.NotNil(t, notified.Account) {
+						assert.Equal(t, "events_enabled", notified.Account.ID)
+					}
+				}
+			} else {
+				assert.Nil(t, notified)
+			}
+		})
+	}
+}
+
 func TestShouldParseEventCorrectly(t *testing.T) {
 
 	tests := map[string]struct {
@@ -815,7 +903,7 @@ func TestShouldReturnBadRequestWhenVTypeIsInvalid(t *testing.T) {
 
 		recorder := httptest.NewRecorder()
 
-		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil)
 		e(recorder, test.req, nil)
 
 		d, err := io.ReadAll(recorder.Result().Body)
@@ -18,8 +18,9 @@ type userSyncs struct {
 // returns all the existing syncs for the user
 func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		cookie := usersync.ReadCookie(r, usersync.Base64Decoder{}, &cfg)
-		usersync.SyncHostCookie(r, cookie, &cfg)
+		hostCookie := usersync.ResolveHostCookie(r, &cfg, "")
+		cookie := usersync.ReadCookie(r, usersync.VersionedDecoder{}, hostCookie)
+		usersync.SyncHostCookie(r, cookie, hostCookie)
 
 		userSyncs := new(userSyncs)
 		userSyncs.BuyerUIDs = cookie.GetUIDs()
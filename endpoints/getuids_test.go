@@ -39,3 +39,23 @@ func TestGetUIDWIthNoCookie(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.JSONEq(t, `{}`, res.Body.String(), "GetUIDs endpoint shouldn't return anything if there doesn't exist a PBS cookie")
 }
+
+func TestGetUIDsHostCookieDefinition(t *testing.T) {
+	hostCookie := config.HostCookie{
+		Family:     "pbs",
+		CookieName: "pbs_id",
+		Definitions: []config.HostCookieDefinition{
+			{Name: "brand-a", Hosts: []string{"pbs.brand-a.com"}, Family: "brand-a", CookieName: "a_id"},
+		},
+	}
+
+	req := makeRequest("http://pbs.brand-a.com/getuids", map[string]string{"adnxs": "123"})
+	req.AddCookie(&http.Cookie{Name: "pbs_id", Value: "pbs-user"})
+	req.AddCookie(&http.Cookie{Name: "a_id", Value: "brand-a-user"})
+	endpoint := NewGetUIDsEndpoint(hostCookie)
+	res := httptest.NewRecorder()
+	endpoint(res, req, nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"buyeruids": {"adnxs": "123", "brand-a": "brand-a-user"}}`, res.Body.String())
+}
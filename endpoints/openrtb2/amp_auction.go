@@ -189,16 +189,18 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 	defer cancel()
 
+	labels.PubID = getAccountID(reqWrapper.Site.Publisher)
+
 	// Read UserSyncs/Cookie from Request
-	usersyncs := usersync.ReadCookie(r, usersync.Base64Decoder{}, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	hostCookie := usersync.ResolveHostCookie(r, &deps.cfg.HostCookie, labels.PubID)
+	usersyncs := usersync.ReadCookie(r, usersync.VersionedDecoder{}, hostCookie)
+	usersync.SyncHostCookie(r, usersyncs, hostCookie)
 	if usersyncs.HasAnyLiveSyncs() {
 		labels.CookieFlag = metrics.CookieFlagYes
 	} else {
 		labels.CookieFlag = metrics.CookieFlagNo
 	}
 
-	labels.PubID = getAccountID(reqWrapper.Site.Publisher)
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
 	if len(acctIDErrs) > 0 {
@@ -251,7 +253,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithGPCHeader(r.Header.Get("Sec-GPC"))
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
@@ -262,6 +264,8 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		BidRequestWrapper:          reqWrapper,
 		Account:                    *account,
 		UserSyncs:                  usersyncs,
+		HostCookieFamily:           hostCookie.Family,
+		UIDStoreID:                 uidStoreID(r, usersyncs, hostCookie, deps.cfg),
 		RequestType:                labels.RType,
 		StartTime:                  start,
 		LegacyLabels:               labels,
@@ -275,6 +279,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		TCF2Config:                 tcf2Config,
 		Activities:                 activityControl,
 		TmaxAdjustments:            deps.tmaxAdjustments,
+		StoredRequestID:            r.URL.Query().Get("tag_id"),
 	}
 
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
@@ -288,6 +293,9 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		response = auctionResponse.BidResponse
 	}
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.TCF2Explanations = auctionResponse.GetTCF2Explanations()
+	ao.PrivacyAudit = auctionResponse.GetPrivacyAudit()
+	ao.DSAReport = auctionResponse.GetDSAReport()
 	ao.AuctionResponse = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
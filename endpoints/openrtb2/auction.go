@@ -203,7 +203,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithGPCHeader(r.Header.Get("Sec-GPC"))
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
@@ -218,9 +218,10 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	decoder := usersync.VersionedDecoder{}
+	hostCookie := usersync.ResolveHostCookie(r, &deps.cfg.HostCookie, account.ID)
+	usersyncs := usersync.ReadCookie(r, decoder, hostCookie)
+	usersync.SyncHostCookie(r, usersyncs, hostCookie)
 
 	if req.Site != nil {
 		if usersyncs.HasAnyLiveSyncs() {
@@ -245,6 +246,8 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		BidRequestWrapper:          req,
 		Account:                    *account,
 		UserSyncs:                  usersyncs,
+		HostCookieFamily:           hostCookie.Family,
+		UIDStoreID:                 uidStoreID(r, usersyncs, hostCookie, deps.cfg),
 		RequestType:                labels.RType,
 		StartTime:                  start,
 		LegacyLabels:               labels,
@@ -274,6 +277,9 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 	ao.Response = response
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.TCF2Explanations = auctionResponse.GetTCF2Explanations()
+	ao.PrivacyAudit = auctionResponse.GetPrivacyAudit()
+	ao.DSAReport = auctionResponse.GetDSAReport()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
@@ -1890,6 +1896,15 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 	return rc
 }
 
+// uidStoreID returns the identifier of the user in the server-side UID store, or an empty string if the store is
+// disabled or the user opted out of syncs.
+func uidStoreID(r *http.Request, usersyncs *usersync.Cookie, hostCookie *config.HostCookie, cfg *config.Configuration) string {
+	if !cfg.UserSync.UIDStore.Enabled || !usersyncs.AllowSyncs() {
+		return ""
+	}
+	return usersync.StoreID(r, usersyncs, hostCookie, &cfg.UserSync.UIDStore)
+}
+
 // Returns the account ID for the request
 func getAccountID(pub *openrtb2.Publisher) string {
 	if pub != nil {
@@ -36,6 +36,7 @@ import (
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_responses"
+	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/iputil"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
@@ -5922,3 +5923,47 @@ func sortUserData(user *openrtb2.User) {
 		}
 	}
 }
+
+func TestUIDStoreID(t *testing.T) {
+	optOutCookie := usersync.NewCookie()
+	optOutCookie.SetOptOut(true)
+
+	testCases := []struct {
+		description string
+		givenStore  config.UIDStore
+		givenCookie *usersync.Cookie
+		expectedID  string
+	}{
+		{
+			description: "store-enabled",
+			givenStore:  config.UIDStore{Enabled: true},
+			givenCookie: usersync.NewCookie(),
+			expectedID:  "host-id",
+		},
+		{
+			description: "store-disabled",
+			givenStore:  config.UIDStore{Enabled: false},
+			givenCookie: usersync.NewCookie(),
+			expectedID:  "",
+		},
+		{
+			description: "opted-out",
+			givenStore:  config.UIDStore{Enabled: true},
+			givenCookie: optOutCookie,
+			expectedID:  "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := &config.Configuration{
+				HostCookie: config.HostCookie{Family: "host", CookieName: "host_cookie"},
+				UserSync:   config.UserSync{UIDStore: test.givenStore},
+			}
+			request := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			request.AddCookie(&http.Cookie{Name: "host_cookie", Value: "host-id"})
+
+			assert.Equal(t, test.expectedID, uidStoreID(request, test.givenCookie, &cfg.HostCookie, cfg))
+		})
+	}
+}
@@ -273,22 +273,26 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		defer cancel()
 	}
 
-	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
-
 	if bidReqWrapper.App != nil {
 		labels.Source = metrics.DemandApp
 		labels.PubID = getAccountID(bidReqWrapper.App.Publisher)
 	} else { // both bidReqWrapper.App == nil and bidReqWrapper.Site != nil are true
 		labels.Source = metrics.DemandWeb
+		labels.PubID = getAccountID(bidReqWrapper.Site.Publisher)
+	}
+
+	// Read Usersyncs/Cookie
+	decoder := usersync.VersionedDecoder{}
+	hostCookie := usersync.ResolveHostCookie(r, &deps.cfg.HostCookie, labels.PubID)
+	usersyncs := usersync.ReadCookie(r, decoder, hostCookie)
+	usersync.SyncHostCookie(r, usersyncs, hostCookie)
+
+	if labels.Source == metrics.DemandWeb {
 		if usersyncs.HasAnyLiveSyncs() {
 			labels.CookieFlag = metrics.CookieFlagYes
 		} else {
 			labels.CookieFlag = metrics.CookieFlagNo
 		}
-		labels.PubID = getAccountID(bidReqWrapper.Site.Publisher)
 	}
 
 	// Look up account now that we have resolved the pubID value
@@ -310,7 +314,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithGPCHeader(r.Header.Get("Sec-GPC"))
 
 	warnings := errortypes.WarningOnly(errL)
 
@@ -319,6 +323,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		BidRequestWrapper:          bidReqWrapper,
 		Account:                    *account,
 		UserSyncs:                  usersyncs,
+		HostCookieFamily:           hostCookie.Family,
+		UIDStoreID:                 uidStoreID(r, usersyncs, hostCookie, deps.cfg),
 		RequestType:                labels.RType,
 		StartTime:                  start,
 		LegacyLabels:               labels,
@@ -328,6 +334,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		HookExecutor:               hookexecution.EmptyHookExecutor{},
 		TmaxAdjustments:            deps.tmaxAdjustments,
 		Activities:                 activityControl,
+		StoredRequestID:            storedRequestId,
 	}
 
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, &debugLog)
@@ -343,6 +350,9 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	vo.Response = response
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
+	vo.TCF2Explanations = auctionResponse.GetTCF2Explanations()
+	vo.PrivacyAudit = auctionResponse.GetPrivacyAudit()
+	vo.DSAReport = auctionResponse.GetDSAReport()
 	if err != nil {
 		errL := []error{err}
 		handleError(&labels, w, errL, &vo, &debugLog)
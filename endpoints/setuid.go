@@ -20,10 +20,12 @@ import (
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/privacy/coppa"
 	gppPrivacy "github.com/prebid/prebid-server/v2/privacy/gpp"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	stringutil "github.com/prebid/prebid-server/v2/util/stringutil"
 )
 
@@ -37,9 +39,11 @@ const (
 
 const uidCookieName = "uids"
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, uidStore usersync.UIDStore) httprouter.Handle {
+	encoder := usersync.NewEncoder(&cfg.HostCookie)
+	decoder := usersync.VersionedDecoder{}
+	rateLimiter := newUserSyncRateLimiter(cfg)
+	verifier := newSetUIDVerifier(cfg)
 
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		so := analytics.SetUIDObject{
@@ -49,15 +53,22 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		defer analyticsRunner.LogSetUIDObject(&so)
 
-		cookie := usersync.ReadCookie(r, decoder, &cfg.HostCookie)
-		if !cookie.AllowSyncs() {
-			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
+		if !rateLimiter.Allow(userSyncClientIP(r, cfg)) {
+			so.Rejection = analytics.SetUIDRejectionRateLimited
+			handleBadStatus(w, http.StatusTooManyRequests, metrics.SetUidRejected, errUserSyncRateLimited, metricsEngine, &so)
 			return
 		}
-		usersync.SyncHostCookie(r, cookie, &cfg.HostCookie)
 
 		query := r.URL.Query()
 
+		hostCookie := usersync.ResolveHostCookie(r, &cfg.HostCookie, query.Get("account"))
+		cookie := usersync.ReadCookie(r, decoder, hostCookie)
+		if !cookie.AllowSyncs() {
+			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
+			return
+		}
+		usersync.SyncHostCookie(r, cookie, hostCookie)
+
 		syncer, bidderName, err := getSyncer(query, syncersByBidder)
 		if err != nil {
 			handleBadStatus(w, http.StatusBadRequest, metrics.SetUidSyncerUnknown, err, metricsEngine, &so)
@@ -65,6 +76,16 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		}
 		so.Bidder = syncer.Key()
 
+		if rejection, err := verifier.verify(bidderName, query); err != nil {
+			so.Rejection = rejection
+			if rejection == analytics.SetUIDRejectionReplayCacheFull {
+				handleBadStatus(w, http.StatusServiceUnavailable, metrics.SetUidReplayCacheFull, err, metricsEngine, &so)
+			} else {
+				handleBadStatus(w, http.StatusForbidden, metrics.SetUidRejected, err, metricsEngine, &so)
+			}
+			return
+		}
+
 		responseFormat, err := getResponseFormat(query, syncer)
 		if err != nil {
 			handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, err, metricsEngine, &so)
@@ -106,24 +127,37 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			return
 		}
 
+		// GDPR errors are reported once the sync user activity is allowed
+		gdprRequestInfo, gdprErr := extractGDPRInfo(query)
+
 		policies := privacy.Policies{
 			GPPSID: gppSID,
+			GPP:    query.Get("gpp"),
+			GDPR:   gdprSignalPolicy(gdprRequestInfo.GDPRSignal),
+			GPC:    gpcSignalPolicy(r.Header.Get("Sec-GPC")),
+		}
+		policies = userSyncGeoAndChannelPolicies(policies, r, cfg.UserSync.Geo)
+
+		childDirected := coppa.DomainChildDirected(account.Privacy.ChildDirected, requestDomain(r))
+		if childDirected {
+			policies.COPPA = ptrutil.ToPtr[int8](1)
 		}
 
-		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
-			privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName},
-			privacy.NewRequestFromPolicies(policies))
+		// user syncs of child-directed inventory are blocked under the stricter child-directed profile
+		userSyncActivityAllowed := !(childDirected && account.Privacy.ChildDirected.Strict) &&
+			activityControl.Allow(privacy.ActivitySyncUser,
+				privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName},
+				privacy.NewRequestFromPolicies(policies))
 
 		if !userSyncActivityAllowed {
 			w.WriteHeader(http.StatusUnavailableForLegalReasons)
 			return
 		}
 
-		gdprRequestInfo, err := extractGDPRInfo(query)
-		if err != nil {
+		if gdprErr != nil {
 			// Only exit if non-warning
-			if !errortypes.IsWarning(err) {
-				handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, err, metricsEngine, &so)
+			if !errortypes.IsWarning(gdprErr) {
+				handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, gdprErr, metricsEngine, &so)
 				return
 			}
 		}
@@ -156,6 +190,12 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			so.Success = true
 		}
 
+		if so.Success {
+			if err := updateUIDStore(r, uidStore, cfg, hostCookie, cookie, syncer.Key(), uid); err != nil {
+				so.Errors = append(so.Errors, err)
+			}
+		}
+
 		setSiteCookie := siteCookieCheck(r.UserAgent())
 
 		// Priority Ejector Set Up
@@ -163,7 +203,8 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
 
 		// Write Cookie
-		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
+		cookieAttributes := usersync.NewCookieAttributes(r, cookie, hostCookie, &account.Cookie, setSiteCookie)
+		encodedCookie, err := cookie.PrepareCookieForWrite(hostCookie, encoder, priorityEjector)
 		if err != nil {
 			if err.Error() == errSyncerIsNotPriority.Error() {
 				w.WriteHeader(http.StatusOK)
@@ -175,7 +216,14 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 				return
 			}
 		}
-		usersync.WriteCookie(w, encodedCookie, &cfg.HostCookie, setSiteCookie)
+		recordUIDCookieSize(metricsEngine, hostCookie, cookie, encodedCookie)
+		usersync.WriteCookieWithAttributes(w, encodedCookie, hostCookie, cookieAttributes)
+		metricsEngine.RecordSetUidCookieAttributes(metrics.CookieAttributesLabels{
+			Cohort:      cookieCohortLabel(cookieAttributes.Cohort, hostCookie.Cohorts),
+			SameSite:    cookieAttributes.SameSite,
+			Partitioned: cookieAttributes.Partitioned,
+		})
+		so.CookieAttributes = &cookieAttributes
 
 		switch responseFormat {
 		case "i":
@@ -191,6 +239,44 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 	})
 }
 
+// updateUIDStore writes the UID in the server-side UID store, or removes it if it was cleared, when the user can be
+// identified.
+func updateUIDStore(r *http.Request, uidStore usersync.UIDStore, cfg *config.Configuration, hostCookie *config.HostCookie, cookie *usersync.Cookie, key string, uid string) error {
+	if uidStore == nil {
+		return nil
+	}
+	id := usersync.StoreID(r, cookie, hostCookie, &cfg.UserSync.UIDStore)
+	if id == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	if timeout := cfg.UserSync.UIDStore.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if uid == "" {
+		return uidStore.Delete(ctx, id, key)
+	}
+	return usersync.StoreUID(ctx, uidStore, cookie, id, key, uid)
+}
+
+// recordUIDCookieSize records the size of the cookie written. When the compact encoding is configured, the size
+// the same cookie would have in the json encoding is recorded too, so the hosts can compare both.
+func recordUIDCookieSize(metricsEngine metrics.MetricsEngine, cfg *config.HostCookie, cookie *usersync.Cookie, encodedCookie string) {
+	if cfg.Encoding != config.CookieEncodingCompact {
+		metricsEngine.RecordUIDCookieSize(metrics.CookieEncodingJSON, len(encodedCookie))
+		return
+	}
+
+	metricsEngine.RecordUIDCookieSize(metrics.CookieEncodingCompact, len(encodedCookie))
+	if jsonCookie, err := (usersync.Base64Encoder{}).Encode(cookie); err == nil {
+		metricsEngine.RecordUIDCookieSize(metrics.CookieEncodingJSON, len(jsonCookie))
+	}
+}
+
 // extractGDPRInfo looks for the GDPR consent string and GDPR signal in the GPP query params
 // first and the 'gdpr' and 'gdpr_consent' query params second. If found in both, throws a
 // warning. Can also throw a parsing or validation error
@@ -339,6 +425,20 @@ func getSyncer(query url.Values, syncersByBidder map[string]usersync.Syncer) (us
 	return syncer, bidder, nil
 }
 
+// cookieCohortLabel returns the metrics label of the cookie cohort. The cohorts of accounts are labeled as other, so
+// they can't grow the number of labels unbounded.
+func cookieCohortLabel(cohort string, hostCohorts []config.CookieCohort) string {
+	if cohort == "" {
+		return ""
+	}
+	for _, hostCohort := range hostCohorts {
+		if hostCohort.Name == cohort {
+			return cohort
+		}
+	}
+	return metrics.CookieCohortOther
+}
+
 func isSyncerPriority(bidderNameFromSyncerQuery string, priorityGroups [][]string) bool {
 	for _, group := range priorityGroups {
 		for _, bidder := range group {
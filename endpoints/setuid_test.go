@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -21,7 +22,9 @@ import (
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/usersync/uidstore"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	metricsConf "github.com/prebid/prebid-server/v2/metrics/config"
 )
@@ -1137,14 +1140,17 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 			expectedMetrics: func(m *metrics.MetricsEngineMock) {
 				m.On("RecordSetUid", metrics.SetUidOK).Once()
 				m.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidOK).Once()
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingJSON, mock.Anything).Once()
+				m.On("RecordSetUidCookieAttributes", metrics.CookieAttributesLabels{}).Once()
 			},
 			expectedAnalytics: func(a *MockAnalyticsRunner) {
 				expected := analytics.SetUIDObject{
-					Status:  200,
-					Bidder:  "pubmatic",
-					UID:     "123",
-					Errors:  []error{},
-					Success: true,
+					Status:           200,
+					Bidder:           "pubmatic",
+					UID:              "123",
+					Errors:           []error{},
+					Success:          true,
+					CookieAttributes: &usersync.CookieAttributes{},
 				}
 				a.On("LogSetUIDObject", &expected).Once()
 			},
@@ -1159,14 +1165,17 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 			expectedMetrics: func(m *metrics.MetricsEngineMock) {
 				m.On("RecordSetUid", metrics.SetUidOK).Once()
 				m.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidCleared).Once()
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingJSON, mock.Anything).Once()
+				m.On("RecordSetUidCookieAttributes", metrics.CookieAttributesLabels{}).Once()
 			},
 			expectedAnalytics: func(a *MockAnalyticsRunner) {
 				expected := analytics.SetUIDObject{
-					Status:  200,
-					Bidder:  "pubmatic",
-					UID:     "",
-					Errors:  []error{},
-					Success: true,
+					Status:           200,
+					Bidder:           "pubmatic",
+					UID:              "",
+					Errors:           []error{},
+					Success:          true,
+					CookieAttributes: &usersync.CookieAttributes{},
 				}
 				a.On("LogSetUIDObject", &expected).Once()
 			},
@@ -1363,6 +1372,323 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 	}
 }
 
+func TestSetUIDEndpointProtection(t *testing.T) {
+	cfg := config.Configuration{
+		BidderInfos: config.BidderInfos{
+			"pubmatic": {Syncer: &config.Syncer{SetUIDKeys: []string{"key"}}},
+		},
+		UserSync: config.UserSync{
+			PriorityGroups: [][]string{{"pubmatic", "appnexus"}},
+			Protection: config.UserSyncProtection{
+				SignatureMaxAgeSeconds: 300,
+				ReplayCacheSize:        1,
+				RateLimit:              config.UserSyncRateLimit{RequestsPerMinute: 1, Burst: 5, MaxClients: 10},
+			},
+		},
+	}
+	cfg.MarshalAccountDefaults()
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+		"appnexus": fakeSyncer{key: "adnxs", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+
+	timestamp := time.Now().Unix()
+	signedURI := "/setuid?bidder=pubmatic&uid=123&ts=" + strconv.FormatInt(timestamp, 10) + "&sig=" + usersync.SignSetUID("key", "pubmatic", "123", timestamp)
+	otherSignedURI := "/setuid?bidder=pubmatic&uid=456&ts=" + strconv.FormatInt(timestamp, 10) + "&sig=" + usersync.SignSetUID("key", "pubmatic", "456", timestamp)
+
+	testCases := []struct {
+		description          string
+		uri                  string
+		expectedResponseCode int
+		expectedMetric       metrics.SetUidStatus
+		expectedSuccess      bool
+		expectedRejection    analytics.SetUIDRejection
+		expectedError        error
+	}{
+		{
+			description:          "Unsigned Bidder",
+			uri:                  "/setuid?bidder=appnexus&uid=123",
+			expectedResponseCode: http.StatusOK,
+			expectedMetric:       metrics.SetUidOK,
+			expectedSuccess:      true,
+		},
+		{
+			description:          "Signature Missing",
+			uri:                  "/setuid?bidder=pubmatic&uid=123",
+			expectedResponseCode: http.StatusForbidden,
+			expectedMetric:       metrics.SetUidRejected,
+			expectedRejection:    analytics.SetUIDRejectionInvalidSignature,
+			expectedError:        usersync.ErrSetUIDSignatureMissing,
+		},
+		{
+			description:          "Signed",
+			uri:                  signedURI,
+			expectedResponseCode: http.StatusOK,
+			expectedMetric:       metrics.SetUidOK,
+			expectedSuccess:      true,
+		},
+		{
+			description:          "Replayed",
+			uri:                  signedURI,
+			expectedResponseCode: http.StatusForbidden,
+			expectedMetric:       metrics.SetUidRejected,
+			expectedRejection:    analytics.SetUIDRejectionReplay,
+			expectedError:        usersync.ErrSetUIDSignatureReplay,
+		},
+		{
+			description:          "Replay Cache Full",
+			uri:                  otherSignedURI,
+			expectedResponseCode: http.StatusServiceUnavailable,
+			expectedMetric:       metrics.SetUidReplayCacheFull,
+			expectedRejection:    analytics.SetUIDRejectionReplayCacheFull,
+			expectedError:        usersync.ErrReplayGuardFull,
+		},
+		{
+			description:          "Rate Limited",
+			uri:                  "/setuid?bidder=appnexus&uid=123",
+			expectedResponseCode: http.StatusTooManyRequests,
+			expectedMetric:       metrics.SetUidRejected,
+			expectedRejection:    analytics.SetUIDRejectionRateLimited,
+			expectedError:        errUserSyncRateLimited,
+		},
+	}
+
+	analyticsEngine := &MockAnalyticsRunner{}
+	analyticsEngine.On("LogSetUIDObject", mock.Anything)
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordSetUid", mock.Anything)
+	metricsEngine.On("RecordSyncerSet", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordUIDCookieSize", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordSetUidCookieAttributes", mock.Anything)
+
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsEngine, FakeAccountsFetcher{}, metricsEngine, nil)
+
+	for i, test := range testCases {
+		request := httptest.NewRequest("GET", test.uri, nil)
+		response := httptest.NewRecorder()
+		endpoint(response, request, nil)
+
+		assert.Equal(t, test.expectedResponseCode, response.Code, test.description)
+		var lastStatus metrics.SetUidStatus
+		for _, call := range metricsEngine.Calls {
+			if call.Method == "RecordSetUid" {
+				lastStatus = call.Arguments.Get(0).(metrics.SetUidStatus)
+			}
+		}
+		assert.Equal(t, test.expectedMetric, lastStatus, test.description+":metric")
+
+		so := analyticsEngine.Calls[i].Arguments.Get(0).(*analytics.SetUIDObject)
+		assert.Equal(t, test.expectedResponseCode, so.Status, test.description+":status")
+		assert.Equal(t, test.expectedSuccess, so.Success, test.description+":success")
+		assert.Equal(t, test.expectedRejection, so.Rejection, test.description+":rejection")
+		if test.expectedError != nil {
+			assert.Equal(t, []error{test.expectedError}, so.Errors, test.description+":errors")
+		} else {
+			assert.Empty(t, so.Errors, test.description+":errors")
+		}
+	}
+}
+
+func TestSetUIDEndpointHostCookieDefinition(t *testing.T) {
+	cfg := config.Configuration{
+		HostCookie: config.HostCookie{
+			Domain: "pbs.com",
+			TTL:    90,
+			Definitions: []config.HostCookieDefinition{
+				{Name: "brand-a", Hosts: []string{"pbs.brand-a.com"}, Domain: "brand-a.com", TTL: 30},
+				{Name: "brand-b", Accounts: []string{"valid_acct"}, Domain: "brand-b.com"},
+			},
+		},
+		UserSync: config.UserSync{PriorityGroups: [][]string{{"pubmatic"}}},
+	}
+	cfg.MarshalAccountDefaults()
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	fakeAccountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"valid_acct": json.RawMessage(`{"disabled":false}`),
+	}}
+
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), fakeAccountsFetcher, &metricsConf.NilMetricsEngine{}, nil)
+
+	testCases := []struct {
+		description     string
+		uri             string
+		expectedDomain  string
+		expectedTTLDays int
+	}{
+		{
+			description:     "Default",
+			uri:             "http://pbs.com/setuid?bidder=pubmatic&uid=123",
+			expectedDomain:  "pbs.com",
+			expectedTTLDays: 90,
+		},
+		{
+			description:     "Host",
+			uri:             "http://pbs.brand-a.com/setuid?bidder=pubmatic&uid=123",
+			expectedDomain:  "brand-a.com",
+			expectedTTLDays: 30,
+		},
+		{
+			description:     "Account",
+			uri:             "http://pbs.brand-a.com/setuid?bidder=pubmatic&uid=123&account=valid_acct",
+			expectedDomain:  "brand-b.com",
+			expectedTTLDays: 90,
+		},
+	}
+
+	for _, test := range testCases {
+		request := httptest.NewRequest("GET", test.uri, nil)
+		response := httptest.NewRecorder()
+		endpoint(response, request, nil)
+
+		assert.Equal(t, http.StatusOK, response.Code, test.description)
+		cookies := response.Result().Cookies()
+		if assert.Len(t, cookies, 1, test.description) {
+			assert.Equal(t, test.expectedDomain, cookies[0].Domain, test.description+":domain")
+			expectedExpires := time.Now().Add(time.Duration(test.expectedTTLDays) * 24 * time.Hour)
+			assert.WithinDuration(t, expectedExpires, cookies[0].Expires, time.Minute, test.description+":expires")
+		}
+	}
+}
+
+func TestSetUIDEndpointGeo(t *testing.T) {
+	cfg := config.Configuration{
+		UserSync: config.UserSync{
+			PriorityGroups: [][]string{{"pubmatic"}},
+			Geo:            config.UserSyncGeo{CountryHeader: "X-Geo-Country", RegionHeader: "X-Geo-Region"},
+		},
+	}
+	cfg.MarshalAccountDefaults()
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	fakeAccountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"geo_acct": json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"geo":["USA.CA"],"channel":["web"]},"allow":false}]}}}}`),
+	}}
+
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), fakeAccountsFetcher, &metricsConf.NilMetricsEngine{}, nil)
+
+	testCases := []struct {
+		description          string
+		headers              map[string]string
+		expectedResponseCode int
+	}{
+		{
+			description:          "Blocked Region",
+			headers:              map[string]string{"X-Geo-Country": "USA", "X-Geo-Region": "CA"},
+			expectedResponseCode: http.StatusUnavailableForLegalReasons,
+		},
+		{
+			description:          "Other Region",
+			headers:              map[string]string{"X-Geo-Country": "USA", "X-Geo-Region": "NY"},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			description:          "Unknown Location",
+			headers:              map[string]string{},
+			expectedResponseCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123&account=geo_acct", nil)
+		for name, value := range test.headers {
+			request.Header.Set(name, value)
+		}
+		response := httptest.NewRecorder()
+		endpoint(response, request, nil)
+
+		assert.Equal(t, test.expectedResponseCode, response.Code, test.description)
+	}
+}
+
+func TestSetUIDEndpointChildDirected(t *testing.T) {
+	cfg := config.Configuration{
+		UserSync: config.UserSync{PriorityGroups: [][]string{{"pubmatic"}}},
+	}
+	cfg.MarshalAccountDefaults()
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	fakeAccountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"strict_acct": json.RawMessage(`{"privacy":{"child_directed":{"domains":["kids.com"],"strict":true}}}`),
+		"coppa_acct":  json.RawMessage(`{"privacy":{"child_directed":{"domains":["kids.com"]},"allowactivities":{"syncUser":{"rules":[{"condition":{"coppa":1},"allow":false}]}}}}`),
+		"lax_acct":    json.RawMessage(`{"privacy":{"child_directed":{"domains":["kids.com"]}}}`),
+	}}
+
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), fakeAccountsFetcher, &metricsConf.NilMetricsEngine{}, nil)
+
+	testCases := []struct {
+		description          string
+		account              string
+		referer              string
+		expectedResponseCode int
+	}{
+		{
+			description:          "Strict Child-Directed",
+			account:              "strict_acct",
+			referer:              "https://www.kids.com/game",
+			expectedResponseCode: http.StatusUnavailableForLegalReasons,
+		},
+		{
+			description:          "Strict Not Child-Directed",
+			account:              "strict_acct",
+			referer:              "https://news.com/article",
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			description:          "COPPA Activity Condition",
+			account:              "coppa_acct",
+			referer:              "https://kids.com/",
+			expectedResponseCode: http.StatusUnavailableForLegalReasons,
+		},
+		{
+			description:          "Not Strict",
+			account:              "lax_acct",
+			referer:              "https://kids.com/",
+			expectedResponseCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123&account="+test.account, nil)
+		request.Header.Set("Referer", test.referer)
+		response := httptest.NewRecorder()
+		endpoint(response, request, nil)
+
+		assert.Equal(t, test.expectedResponseCode, response.Code, test.description)
+	}
+}
+
 func TestOptedOut(t *testing.T) {
 	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
 	cookie := usersync.NewCookie()
@@ -1506,6 +1832,38 @@ func TestGetResponseFormat(t *testing.T) {
 	}
 }
 
+func TestCookieCohortLabel(t *testing.T) {
+	hostCohorts := []config.CookieCohort{{Name: "partitioned", Percent: 10}}
+
+	testCases := []struct {
+		name        string
+		givenCohort string
+		expected    string
+	}{
+		{
+			name:        "no-cohort",
+			givenCohort: "",
+			expected:    "",
+		},
+		{
+			name:        "host-cohort",
+			givenCohort: "partitioned",
+			expected:    "partitioned",
+		},
+		{
+			name:        "account-cohort",
+			givenCohort: "account-experiment",
+			expected:    metrics.CookieCohortOther,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, cookieCohortLabel(test.givenCohort, hostCohorts))
+		})
+	}
+}
+
 func TestIsSyncerPriority(t *testing.T) {
 	testCases := []struct {
 		name                           string
@@ -1639,7 +1997,7 @@ func doRequest(req *http.Request, analytics analytics.Runner, metrics metrics.Me
 		"valid_acct_with_invalid_activities":                 json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"componentName": ["bidderA.bidderB.bidderC"]}}]}}}}`),
 	}}
 
-	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics, nil)
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
@@ -1766,3 +2124,121 @@ func getUIDFromHeader(setCookieHeader string) string {
 	}
 	return ""
 }
+
+func TestRecordUIDCookieSize(t *testing.T) {
+	cookie := usersync.NewCookie()
+	cookie.Sync("pubmatic", "123")
+
+	jsonCookie, err := usersync.Base64Encoder{}.Encode(cookie)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		description     string
+		encoding        string
+		encodedCookie   string
+		expectedMetrics func(*metrics.MetricsEngineMock)
+	}{
+		{
+			description:   "default",
+			encoding:      "",
+			encodedCookie: jsonCookie,
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingJSON, len(jsonCookie)).Once()
+			},
+		},
+		{
+			description:   "json",
+			encoding:      config.CookieEncodingJSON,
+			encodedCookie: jsonCookie,
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingJSON, len(jsonCookie)).Once()
+			},
+		},
+		{
+			description:   "compact-records-json-size-too",
+			encoding:      config.CookieEncodingCompact,
+			encodedCookie: "c1.compact",
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingCompact, len("c1.compact")).Once()
+				m.On("RecordUIDCookieSize", metrics.CookieEncodingJSON, len(jsonCookie)).Once()
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			test.expectedMetrics(metricsEngine)
+
+			recordUIDCookieSize(metricsEngine, &config.HostCookie{Encoding: test.encoding}, cookie, test.encodedCookie)
+
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateUIDStore(t *testing.T) {
+	cfg := &config.Configuration{
+		HostCookie: config.HostCookie{Family: "host", CookieName: "host_cookie"},
+		UserSync:   config.UserSync{UIDStore: config.UIDStore{Enabled: true, TimeoutMS: 100}},
+	}
+	ctx := context.Background()
+
+	testCases := []struct {
+		description     string
+		givenHostCookie string
+		givenStored     map[string]string
+		givenUID        string
+		expectedStored  map[string]string
+	}{
+		{
+			description:     "stored",
+			givenHostCookie: "host-id",
+			givenUID:        "123",
+			expectedStored:  map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:     "cleared",
+			givenHostCookie: "host-id",
+			givenStored:     map[string]string{"pubmatic": "123", "adnxs": "456"},
+			givenUID:        "",
+			expectedStored:  map[string]string{"adnxs": "456"},
+		},
+		{
+			description:     "user-not-identified",
+			givenHostCookie: "",
+			givenUID:        "123",
+			expectedStored:  map[string]string{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			store := uidstore.NewMemoryStore(1024 * 1024)
+			for key, uid := range test.givenStored {
+				assert.NoError(t, store.Set(ctx, "host-id", key, usersync.UIDEntry{UID: uid, Expires: time.Now().Add(time.Hour)}))
+			}
+
+			request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid="+test.givenUID, nil)
+			if test.givenHostCookie != "" {
+				request.AddCookie(&http.Cookie{Name: "host_cookie", Value: test.givenHostCookie})
+			}
+
+			err := updateUIDStore(request, store, cfg, &cfg.HostCookie, usersync.NewCookie(), "pubmatic", test.givenUID)
+			assert.NoError(t, err)
+
+			entries, err := store.Get(ctx, "host-id")
+			assert.NoError(t, err)
+			stored := make(map[string]string, len(entries))
+			for key, entry := range entries {
+				stored[key] = entry.UID
+			}
+			assert.Equal(t, test.expectedStored, stored)
+		})
+	}
+}
+
+func TestUpdateUIDStoreDisabled(t *testing.T) {
+	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
+	assert.NoError(t, updateUIDStore(request, nil, &config.Configuration{}, &config.HostCookie{}, usersync.NewCookie(), "pubmatic", "123"))
+}
@@ -0,0 +1,103 @@
+package endpoints
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/iputil"
+	"github.com/prebid/prebid-server/v2/util/ratelimit"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+var errUserSyncRateLimited = errors.New("too many requests, please retry later")
+
+// newUserSyncRateLimiter returns the per client IP rate limiter of a user sync endpoint, or nil if the requests
+// are not limited.
+func newUserSyncRateLimiter(cfg *config.Configuration) *ratelimit.Limiter {
+	rateLimit := cfg.UserSync.Protection.RateLimit
+	return ratelimit.New(rateLimit.RequestsPerMinute, rateLimit.Burst, rateLimit.MaxClients)
+}
+
+// userSyncClientIP returns the address of the peer. The public IP of the client is read from the request headers
+// instead only when the peer is a trusted proxy, since any other client could forge them to dodge the rate limits.
+func userSyncClientIP(r *http.Request, cfg *config.Configuration) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		peer = host
+	}
+	if peerIP := net.ParseIP(peer); peerIP == nil || !cfg.UserSync.Protection.IsTrustedProxy(peerIP) {
+		return peer
+	}
+
+	ipValidator := iputil.PublicNetworkIPValidator{
+		IPv4PrivateNetworks: cfg.RequestValidation.IPv4PrivateNetworksParsed,
+		IPv6PrivateNetworks: cfg.RequestValidation.IPv6PrivateNetworksParsed,
+	}
+	if ip, _ := httputil.FindIP(r, ipValidator); ip != nil {
+		return ip.String()
+	}
+	return peer
+}
+
+// setUIDVerifier verifies the signature of the /setuid redirects of the bidders having setuid keys, and rejects
+// the signed redirects replayed within the signature max age.
+type setUIDVerifier struct {
+	keysByBidder map[string][]string
+	maxAge       time.Duration
+	replayGuard  *usersync.ReplayGuard
+	time         timeutil.Time
+}
+
+func newSetUIDVerifier(cfg *config.Configuration) *setUIDVerifier {
+	keysByBidder := make(map[string][]string)
+	for bidder, info := range cfg.BidderInfos {
+		if info.Syncer != nil && len(info.Syncer.SetUIDKeys) > 0 {
+			keysByBidder[strings.ToLower(bidder)] = info.Syncer.SetUIDKeys
+		}
+	}
+	if len(keysByBidder) == 0 {
+		return nil
+	}
+
+	return &setUIDVerifier{
+		keysByBidder: keysByBidder,
+		maxAge:       cfg.UserSync.Protection.SignatureMaxAge(),
+		replayGuard:  usersync.NewReplayGuard(cfg.UserSync.Protection.ReplayCacheCapacity()),
+		time:         &timeutil.RealTime{},
+	}
+}
+
+// verify returns the reason to reject the redirect of the bidder, if its signature is required and either
+// invalid or already used, or if too many signatures are in use to tell.
+func (v *setUIDVerifier) verify(bidder string, query url.Values) (analytics.SetUIDRejection, error) {
+	if v == nil {
+		return "", nil
+	}
+	keys, signed := v.keysByBidder[strings.ToLower(bidder)]
+	if !signed {
+		return "", nil
+	}
+
+	now := v.time.Now()
+	signature := query.Get("sig")
+	signedAt, err := usersync.VerifySetUIDSignature(keys, bidder, query.Get("uid"), query.Get("ts"), signature, now, v.maxAge)
+	if err != nil {
+		return analytics.SetUIDRejectionInvalidSignature, err
+	}
+	switch err := v.replayGuard.Use(strings.ToLower(bidder)+":"+signature, signedAt.Add(v.maxAge), now); err {
+	case nil:
+		return "", nil
+	case usersync.ErrReplayGuardFull:
+		return analytics.SetUIDRejectionReplayCacheFull, err
+	default:
+		return analytics.SetUIDRejectionReplay, err
+	}
+}
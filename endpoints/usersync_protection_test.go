@@ -0,0 +1,209 @@
+package endpoints
+
+import (
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserSyncRateLimiter(t *testing.T) {
+	disabled := newUserSyncRateLimiter(&config.Configuration{})
+	assert.Nil(t, disabled)
+
+	enabled := newUserSyncRateLimiter(&config.Configuration{
+		UserSync: config.UserSync{Protection: config.UserSyncProtection{
+			RateLimit: config.UserSyncRateLimit{RequestsPerMinute: 1, Burst: 1, MaxClients: 10},
+		}},
+	})
+	assert.True(t, enabled.Allow("1.1.1.1"))
+	assert.False(t, enabled.Allow("1.1.1.1"))
+}
+
+func TestUserSyncClientIP(t *testing.T) {
+	_, trustedProxy, _ := net.ParseCIDR("2.2.2.0/24")
+	cfg := &config.Configuration{UserSync: config.UserSync{Protection: config.UserSyncProtection{TrustedProxiesParsed: []net.IPNet{*trustedProxy}}}}
+
+	testCases := []struct {
+		description  string
+		givenHeaders map[string]string
+		givenRemote  string
+		expectedIP   string
+	}{
+		{
+			description:  "forwarded-for-from-trusted-proxy",
+			givenHeaders: map[string]string{"X-Forwarded-For": "1.1.1.1"},
+			givenRemote:  "2.2.2.2:1234",
+			expectedIP:   "1.1.1.1",
+		},
+		{
+			description:  "real-ip-from-trusted-proxy",
+			givenHeaders: map[string]string{"X-Real-IP": "1.1.1.1"},
+			givenRemote:  "2.2.2.2:1234",
+			expectedIP:   "1.1.1.1",
+		},
+		{
+			description:  "true-client-ip-from-trusted-proxy",
+			givenHeaders: map[string]string{"True-Client-IP": "1.1.1.1"},
+			givenRemote:  "2.2.2.2:1234",
+			expectedIP:   "1.1.1.1",
+		},
+		{
+			description:  "trusted-proxy-without-header",
+			givenHeaders: nil,
+			givenRemote:  "2.2.2.2:1234",
+			expectedIP:   "2.2.2.2",
+		},
+		{
+			description:  "forwarded-for-from-untrusted-peer",
+			givenHeaders: map[string]string{"X-Forwarded-For": "1.1.1.1"},
+			givenRemote:  "3.3.3.3:1234",
+			expectedIP:   "3.3.3.3",
+		},
+		{
+			description:  "true-client-ip-from-untrusted-peer",
+			givenHeaders: map[string]string{"True-Client-IP": "1.1.1.1", "X-Real-IP": "1.1.1.2"},
+			givenRemote:  "3.3.3.3:1234",
+			expectedIP:   "3.3.3.3",
+		},
+		{
+			description: "remote-addr",
+			givenRemote: "2.2.2.2:1234",
+			expectedIP:  "2.2.2.2",
+		},
+		{
+			description: "remote-addr-without-port",
+			givenRemote: "3.3.3.3",
+			expectedIP:  "3.3.3.3",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/setuid", nil)
+			request.RemoteAddr = test.givenRemote
+			for name, value := range test.givenHeaders {
+				request.Header.Set(name, value)
+			}
+			assert.Equal(t, test.expectedIP, userSyncClientIP(request, cfg))
+		})
+	}
+}
+
+func TestUserSyncClientIPWithoutTrustedProxies(t *testing.T) {
+	request := httptest.NewRequest("GET", "/setuid", nil)
+	request.RemoteAddr = "2.2.2.2:1234"
+	request.Header.Set("X-Forwarded-For", "1.1.1.1")
+	assert.Equal(t, "2.2.2.2", userSyncClientIP(request, &config.Configuration{}), "The headers must be ignored by default.")
+}
+
+func TestNewSetUIDVerifier(t *testing.T) {
+	unsigned := newSetUIDVerifier(&config.Configuration{
+		BidderInfos: config.BidderInfos{"appnexus": {Syncer: &config.Syncer{Key: "adnxs"}}},
+	})
+	assert.Nil(t, unsigned)
+
+	signed := newSetUIDVerifier(&config.Configuration{
+		BidderInfos: config.BidderInfos{
+			"appnexus": {Syncer: &config.Syncer{Key: "adnxs"}},
+			"Rubicon":  {Syncer: &config.Syncer{SetUIDKeys: []string{"key"}}},
+		},
+		UserSync: config.UserSync{Protection: config.UserSyncProtection{SignatureMaxAgeSeconds: 60, ReplayCacheSize: 10}},
+	})
+	if assert.NotNil(t, signed) {
+		assert.Equal(t, map[string][]string{"rubicon": {"key"}}, signed.keysByBidder)
+		assert.Equal(t, time.Minute, signed.maxAge)
+	}
+}
+
+func TestSetUIDVerifierVerify(t *testing.T) {
+	now := time.Date(2024, 2, 22, 9, 42, 4, 0, time.UTC)
+	signedAt := now.Add(-time.Minute).Unix()
+	signedQuery := func(bidder, uid string) url.Values {
+		return url.Values{
+			"uid": {uid},
+			"ts":  {strconv.FormatInt(signedAt, 10)},
+			"sig": {usersync.SignSetUID("key", bidder, uid, signedAt)},
+		}
+	}
+
+	verifier := &setUIDVerifier{
+		keysByBidder: map[string][]string{"rubicon": {"key"}},
+		maxAge:       5 * time.Minute,
+		replayGuard:  usersync.NewReplayGuard(2),
+		time:         &fakeTime{time: now},
+	}
+
+	testCases := []struct {
+		description       string
+		givenBidder       string
+		givenQuery        url.Values
+		expectedRejection analytics.SetUIDRejection
+		expectedError     error
+	}{
+		{
+			description: "unsigned-bidder",
+			givenBidder: "appnexus",
+			givenQuery:  url.Values{"uid": {"123"}},
+		},
+		{
+			description:       "missing-signature",
+			givenBidder:       "rubicon",
+			givenQuery:        url.Values{"uid": {"123"}},
+			expectedRejection: analytics.SetUIDRejectionInvalidSignature,
+			expectedError:     usersync.ErrSetUIDSignatureMissing,
+		},
+		{
+			description: "signed",
+			givenBidder: "rubicon",
+			givenQuery:  signedQuery("rubicon", "123"),
+		},
+		{
+			description:       "replayed",
+			givenBidder:       "rubicon",
+			givenQuery:        signedQuery("rubicon", "123"),
+			expectedRejection: analytics.SetUIDRejectionReplay,
+			expectedError:     usersync.ErrSetUIDSignatureReplay,
+		},
+		{
+			description: "signed-case-insensitive-bidder",
+			givenBidder: "Rubicon",
+			givenQuery:  signedQuery("Rubicon", "456"),
+		},
+		{
+			description:       "replay-cache-full",
+			givenBidder:       "rubicon",
+			givenQuery:        signedQuery("rubicon", "789"),
+			expectedRejection: analytics.SetUIDRejectionReplayCacheFull,
+			expectedError:     usersync.ErrReplayGuardFull,
+		},
+		{
+			description:       "tampered-uid",
+			givenBidder:       "rubicon",
+			givenQuery:        url.Values{"uid": {"789"}, "ts": signedQuery("rubicon", "123")["ts"], "sig": signedQuery("rubicon", "123")["sig"]},
+			expectedRejection: analytics.SetUIDRejectionInvalidSignature,
+			expectedError:     usersync.ErrSetUIDSignatureInvalid,
+		},
+	}
+
+	for _, test := range testCases {
+		rejection, err := verifier.verify(test.givenBidder, test.givenQuery)
+		assert.Equal(t, test.expectedRejection, rejection, test.description)
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
+
+func TestSetUIDVerifierNil(t *testing.T) {
+	var verifier *setUIDVerifier
+
+	rejection, err := verifier.verify("rubicon", url.Values{})
+	assert.Empty(t, rejection)
+	assert.NoError(t, err)
+}
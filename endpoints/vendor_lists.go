@@ -0,0 +1,35 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/gdpr"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// vendorListsInfo holds the TCF global vendor lists loaded by the server.
+type vendorListsInfo struct {
+	VendorLists []gdpr.VendorListVersion `json:"vendorLists"`
+}
+
+// NewVendorListsEndpoint returns the spec and list versions of the TCF global vendor lists currently loaded,
+// along with the source they were loaded from.
+func NewVendorListsEndpoint(versions gdpr.VendorListVersions) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		info := vendorListsInfo{VendorLists: []gdpr.VendorListVersion{}}
+		if versions != nil {
+			info.VendorLists = versions()
+		}
+
+		jsonOutput, err := jsonutil.Marshal(info)
+		if err != nil {
+			glog.Errorf("/gdpr/vendorlists Critical error when trying to marshal vendorListsInfo: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
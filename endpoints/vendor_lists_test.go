@@ -0,0 +1,44 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/gdpr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorListsEndpoint(t *testing.T) {
+	var testCases = []struct {
+		description string
+		versions    gdpr.VendorListVersions
+		expected    string
+	}{
+		{
+			description: "Not Loaded",
+			versions:    nil,
+			expected:    `{"vendorLists":[]}`,
+		},
+		{
+			description: "Loaded",
+			versions: func() []gdpr.VendorListVersion {
+				return []gdpr.VendorListVersion{
+					{SpecVersion: 2, ListVersion: 10, Source: gdpr.VendorListSourceEmbedded},
+					{SpecVersion: 3, ListVersion: 20, Source: gdpr.VendorListSourceHTTP},
+				}
+			},
+			expected: `{"vendorLists":[{"specVersion":2,"listVersion":10,"source":"embedded"},{"specVersion":3,"listVersion":20,"source":"http"}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		handler := NewVendorListsEndpoint(test.versions)
+		w := httptest.NewRecorder()
+
+		handler(w, nil)
+
+		assert.Equal(t, 200, w.Code, test.description)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"), test.description)
+		assert.JSONEq(t, test.expected, w.Body.String(), test.description)
+	}
+}
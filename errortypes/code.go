@@ -35,6 +35,9 @@ const (
 	InvalidBidResponseDSAWarningCode
 	SecCookieDeprecationLenWarningCode
 	SecBrowsingTopicsWarningCode
+	BidderDisabledDSAWarningCode
+	EIDNormalizationWarningCode
+	KAnonymityScrubWarningCode
 )
 
 // Coder provides an error or warning code with severity.
@@ -2,13 +2,22 @@ package exchange
 
 import (
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
 )
 
 // AuctionResponse contains OpenRTB Bid Response object and its extension (un-marshalled) object
 type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
+	// TCF2Explanations holds the legal basis evaluated by TCF2 enforcement for each bidder when explain mode is on
+	TCF2Explanations []openrtb_ext.ExtResponseDebugTCF2Bidder
+	// PrivacyAudit holds the privacy audit record of the auction when it was sampled for the privacy audit
+	// and the analytics modules are the audit sink
+	PrivacyAudit *audit.Record
+	// DSAReport holds the DSA transparency information of the returned bids, if any
+	DSAReport *dsa.Report
 }
 
 // GetSeatNonBid returns array of seat non-bid if present. nil otherwise
@@ -18,3 +27,27 @@ func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
 	}
 	return nil
 }
+
+// GetTCF2Explanations returns the TCF2 enforcement decisions explained for each bidder if present. nil otherwise
+func (ar *AuctionResponse) GetTCF2Explanations() []openrtb_ext.ExtResponseDebugTCF2Bidder {
+	if ar != nil {
+		return ar.TCF2Explanations
+	}
+	return nil
+}
+
+// GetDSAReport returns the DSA transparency information of the returned bids if present. nil otherwise
+func (ar *AuctionResponse) GetDSAReport() *dsa.Report {
+	if ar != nil {
+		return ar.DSAReport
+	}
+	return nil
+}
+
+// GetPrivacyAudit returns the privacy audit record of the auction if it was sampled. nil otherwise
+func (ar *AuctionResponse) GetPrivacyAudit() *audit.Record {
+	if ar != nil {
+		return ar.PrivacyAudit
+	}
+	return nil
+}
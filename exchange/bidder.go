@@ -76,6 +76,7 @@ type bidRequestOptions struct {
 
 type extraBidderRespInfo struct {
 	respProcessingStartTime time.Time
+	strippedPaths           []string
 }
 
 type extraAuctionResponseInfo struct {
@@ -158,6 +159,17 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		if bidRequestOptions.tmaxAdjustments != nil && bidRequestOptions.tmaxAdjustments.IsEnforced {
 			bidderRequest.BidRequest.TMax = getBidderTmax(&bidderTmaxCtx{ctx}, bidderRequest.BidRequest.TMax, *bidRequestOptions.tmaxAdjustments)
 		}
+
+		// data minimization runs last so nothing added by modules or adjustments escapes the bidder's profile
+		if bidderRequest.DataMinimization != nil {
+			minimizedRequest, strippedPaths, err := bidderRequest.DataMinimization.Apply(bidderRequest.BidRequest)
+			if err != nil {
+				return nil, extraBidderRespInfo{}, []error{&errortypes.FailedToRequestBids{Message: fmt.Sprintf("unable to apply the data minimization profile: %v", err)}}
+			}
+			bidderRequest.BidRequest = minimizedRequest
+			extraRespInfo.strippedPaths = strippedPaths
+		}
+
 		reqData, errs = bidder.Bidder.MakeRequests(bidderRequest.BidRequest, reqInfo)
 
 		if len(reqData) == 0 {
@@ -165,7 +177,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			if len(errs) == 0 {
 				errs = append(errs, &errortypes.FailedToRequestBids{Message: "The adapter failed to generate any bid requests, but also failed to generate an error explaining why"})
 			}
-			return nil, extraBidderRespInfo{}, errs
+			return nil, extraRespInfo, errs
 		}
 		xPrebidHeader := version.BuildXPrebidHeaderForRequest(bidderRequest.BidRequest, version.Ver)
 
@@ -32,11 +32,13 @@ import (
 	"github.com/prebid/prebid-server/v2/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/minimization"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/prebid/prebid-server/v2/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSingleBidder makes sure that the following things work if the Bidder needs only one request.
@@ -463,6 +465,57 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].HttpCalls, expectedHttpCall)
 }
 
+func TestRequestBidDataMinimization(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method: "POST",
+			Uri:    server.URL,
+			Body:   []byte("requestJson"),
+		},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{},
+		},
+	}
+
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{Allow: true}, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+	profile, err := minimization.NewProfile([]string{"site.page"})
+	require.NoError(t, err)
+
+	request := &openrtb2.BidRequest{
+		ID:     "some-request-id",
+		Imp:    []openrtb2.Imp{{ID: "impId"}},
+		Site:   &openrtb2.Site{Page: "https://example.com", Keywords: "some-keywords"},
+		Device: &openrtb2.Device{UA: "some-ua"},
+	}
+	bidderReq := BidderRequest{
+		BidRequest:       request,
+		BidderName:       "test",
+		DataMinimization: profile,
+	}
+	bidReqOptions := bidRequestOptions{
+		accountDebugAllowed: true,
+		bidAdjustments:      map[string]float64{"test": 1},
+	}
+	_, extraBidderRespInfo, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+
+	expectedRequest := &openrtb2.BidRequest{
+		ID:   "some-request-id",
+		Imp:  []openrtb2.Imp{{ID: "impId"}},
+		Site: &openrtb2.Site{Page: "https://example.com"},
+		Cur:  []string{"USD"},
+	}
+
+	assert.Empty(t, errs)
+	assert.Equal(t, expectedRequest, bidderImpl.bidRequest)
+	assert.Equal(t, []string{"device", "site.keywords"}, extraBidderRespInfo.strippedPaths)
+	assert.Equal(t, "some-ua", request.Device.UA, "original request must not be modified")
+}
+
 // TestMultiBidder makes sure all the requests get sent, and the responses processed.
 // Because this is done in parallel, it should be run under the race detector.
 func TestMultiBidder(t *testing.T) {
@@ -0,0 +1,218 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/firstpartydata"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// eidSourceRule constrains the UIDs of a well known EID source.
+type eidSourceRule struct {
+	atype adcom1.AgentType
+	// singleUID indicates the source identifies a user with a single UID, so several UIDs are conflicting.
+	singleUID bool
+}
+
+var eidSourceRules = map[string]eidSourceRule{
+	"adserver.org": {atype: adcom1.AgentTypeWeb},
+	"criteo.com":   {atype: adcom1.AgentTypeWeb},
+	"euid.eu":      {atype: adcom1.AgentTypePerson, singleUID: true},
+	"id5-sync.com": {atype: adcom1.AgentTypeWeb, singleUID: true},
+	"liveramp.com": {atype: adcom1.AgentTypePerson, singleUID: true},
+	"pubcid.org":   {atype: adcom1.AgentTypeWeb, singleUID: true},
+	"uidapi.com":   {atype: adcom1.AgentTypePerson, singleUID: true},
+}
+
+// normalizeRequestEIDs normalizes the user.eids and user.ext.eids of the request and of the bidder specific
+// first party data users, before the EID permissions are enforced. The normalizations are returned as debug
+// warnings, those of the first party data are only reported when the request didn't already report them.
+func normalizeRequestEIDs(req *openrtb_ext.RequestWrapper, fpd map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData) []error {
+	messages, err := normalizeUserEIDs(req)
+	if err != nil {
+		return []error{err}
+	}
+
+	reported := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		reported[message] = true
+	}
+
+	bidders := make([]string, 0, len(fpd))
+	for bidder, bidderFPD := range fpd {
+		if bidderFPD != nil && bidderFPD.User != nil {
+			bidders = append(bidders, bidder.String())
+		}
+	}
+	sort.Strings(bidders)
+
+	for _, bidder := range bidders {
+		bidderFPD := fpd[openrtb_ext.BidderName(bidder)]
+		fpdReq := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: bidderFPD.User}}
+		fpdMessages, err := normalizeUserEIDs(fpdReq)
+		if err == nil {
+			err = fpdReq.RebuildRequest()
+		}
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("%s first party data user.eids not normalized: %v", bidder, err))
+			continue
+		}
+		bidderFPD.User = fpdReq.User
+
+		for _, message := range fpdMessages {
+			if !reported[message] {
+				messages = append(messages, bidder+" first party data "+message)
+			}
+		}
+	}
+
+	warnings := make([]error, 0, len(messages))
+	for _, message := range messages {
+		warnings = append(warnings, &errortypes.DebugWarning{
+			Message:     message,
+			WarningCode: errortypes.EIDNormalizationWarningCode,
+		})
+	}
+	return warnings
+}
+
+// normalizeUserEIDs normalizes the user.eids and user.ext.eids of the request. The user is copied before it's
+// changed since it may be shared.
+func normalizeUserEIDs(req *openrtb_ext.RequestWrapper) ([]string, error) {
+	if req.User == nil {
+		return nil, nil
+	}
+
+	userExt, err := req.GetUserExt()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	if len(req.User.EIDs) > 0 {
+		eids, eidMessages := normalizeEIDs("user.eids", req.User.EIDs)
+		if len(eidMessages) > 0 {
+			user := *req.User
+			user.EIDs = eids
+			req.User = &user
+			messages = append(messages, eidMessages...)
+		}
+	}
+
+	if extEIDs := userExt.GetEid(); extEIDs != nil && len(*extEIDs) > 0 {
+		eids, eidMessages := normalizeEIDs("user.ext.eids", *extEIDs)
+		if len(eidMessages) > 0 {
+			user := *req.User
+			req.User = &user
+			if len(eids) == 0 {
+				userExt.SetEid(nil)
+			} else {
+				userExt.SetEid(&eids)
+			}
+			messages = append(messages, eidMessages...)
+		}
+	}
+
+	return messages, nil
+}
+
+// normalizeEIDs normalizes the EID sources, merges the entries of a source, drops the malformed entries and
+// UIDs, and enforces the atype and single UID rules of the well known sources. Entries and UIDs keep the order
+// of the request, so the first ones take precedence when a source allows a single UID. The normalizations
+// are described by the returned messages, the EIDs are unchanged when there are none.
+func normalizeEIDs(path string, eids []openrtb2.EID) ([]openrtb2.EID, []string) {
+	var messages []string
+	merged := make([]openrtb2.EID, 0, len(eids))
+	indexBySource := make(map[string]int, len(eids))
+
+	for _, eid := range eids {
+		source := normalizeEIDSource(eid.Source)
+		if source == "" {
+			messages = append(messages, fmt.Sprintf("%s entry without source dropped", path))
+			continue
+		}
+		if source != eid.Source {
+			messages = append(messages, fmt.Sprintf("%s source %q normalized to %q", path, eid.Source, source))
+		}
+
+		if i, ok := indexBySource[source]; ok {
+			messages = append(messages, fmt.Sprintf("%s source %q duplicate entry merged", path, source))
+			merged[i].UIDs = append(merged[i].UIDs, eid.UIDs...)
+			if len(merged[i].Ext) == 0 {
+				merged[i].Ext = eid.Ext
+			}
+			continue
+		}
+
+		eid.Source = source
+		eid.UIDs = append([]openrtb2.UID(nil), eid.UIDs...)
+		indexBySource[source] = len(merged)
+		merged = append(merged, eid)
+	}
+
+	normalized := merged[:0]
+	for _, eid := range merged {
+		var uidMessages []string
+		eid.UIDs, uidMessages = normalizeUIDs(path, eid.Source, eid.UIDs)
+		messages = append(messages, uidMessages...)
+		if len(eid.UIDs) == 0 {
+			messages = append(messages, fmt.Sprintf("%s source %q dropped without valid uids", path, eid.Source))
+			continue
+		}
+		normalized = append(normalized, eid)
+	}
+
+	if len(messages) == 0 {
+		return eids, nil
+	}
+	return normalized, messages
+}
+
+func normalizeUIDs(path, source string, uids []openrtb2.UID) ([]openrtb2.UID, []string) {
+	var messages []string
+	rule, hasRule := eidSourceRules[source]
+	seen := make(map[string]bool, len(uids))
+
+	normalized := uids[:0]
+	for _, uid := range uids {
+		if strings.TrimSpace(uid.ID) == "" {
+			messages = append(messages, fmt.Sprintf("%s source %q uid without id dropped", path, source))
+			continue
+		}
+		if seen[uid.ID] {
+			messages = append(messages, fmt.Sprintf("%s source %q duplicate uid dropped", path, source))
+			continue
+		}
+		seen[uid.ID] = true
+
+		if hasRule && uid.AType != rule.atype {
+			messages = append(messages, fmt.Sprintf("%s source %q uid atype %d set to %d", path, source, uid.AType, rule.atype))
+			uid.AType = rule.atype
+		}
+		normalized = append(normalized, uid)
+	}
+
+	if hasRule && rule.singleUID && len(normalized) > 1 {
+		messages = append(messages, fmt.Sprintf("%s source %q conflicting uids, kept the first", path, source))
+		normalized = normalized[:1]
+	}
+	return normalized, messages
+}
+
+// normalizeEIDSource returns the lower case domain of an EID source, without the scheme, the www prefix or
+// the path some callers add.
+func normalizeEIDSource(source string) string {
+	normalized := strings.ToLower(strings.TrimSpace(source))
+	normalized = strings.TrimPrefix(normalized, "https://")
+	normalized = strings.TrimPrefix(normalized, "http://")
+	normalized = strings.TrimPrefix(normalized, "www.")
+	if i := strings.IndexAny(normalized, "/?#"); i >= 0 {
+		normalized = normalized[:i]
+	}
+	return normalized
+}
@@ -0,0 +1,170 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/firstpartydata"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEIDs(t *testing.T) {
+	testCases := []struct {
+		description      string
+		eids             []openrtb2.EID
+		expectedEIDs     []openrtb2.EID
+		expectedMessages []string
+	}{
+		{
+			description: "unchanged",
+			eids: []openrtb2.EID{
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token", AType: 3}}},
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}, {ID: "id2", AType: 1}}},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token", AType: 3}}},
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}, {ID: "id2", AType: 1}}},
+			},
+		},
+		{
+			description: "source-normalized",
+			eids: []openrtb2.EID{
+				{Source: " HTTPS://www.Other.com/path ", UIDs: []openrtb2.UID{{ID: "id1"}}},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}}},
+			},
+			expectedMessages: []string{`user.eids source " HTTPS://www.Other.com/path " normalized to "other.com"`},
+		},
+		{
+			description: "duplicate-sources-merged-in-order",
+			eids: []openrtb2.EID{
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}}},
+				{Source: "criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: 1}}},
+				{Source: "Other.com", UIDs: []openrtb2.UID{{ID: "id2"}, {ID: "id1", AType: 1}}, Ext: json.RawMessage(`{"a":1}`)},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}, {ID: "id2"}}, Ext: json.RawMessage(`{"a":1}`)},
+				{Source: "criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: 1}}},
+			},
+			expectedMessages: []string{
+				`user.eids source "Other.com" normalized to "other.com"`,
+				`user.eids source "other.com" duplicate entry merged`,
+				`user.eids source "other.com" duplicate uid dropped`,
+			},
+		},
+		{
+			description: "malformed-dropped",
+			eids: []openrtb2.EID{
+				{Source: "", UIDs: []openrtb2.UID{{ID: "id1"}}},
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: " "}, {ID: "id2"}}},
+				{Source: "empty.com"},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id2"}}},
+			},
+			expectedMessages: []string{
+				`user.eids entry without source dropped`,
+				`user.eids source "other.com" uid without id dropped`,
+				`user.eids source "empty.com" dropped without valid uids`,
+			},
+		},
+		{
+			description: "atype-enforced",
+			eids: []openrtb2.EID{
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token"}}},
+				{Source: "criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: adcom1.AgentTypePerson}}},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token", AType: adcom1.AgentTypePerson}}},
+				{Source: "criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: adcom1.AgentTypeWeb}}},
+			},
+			expectedMessages: []string{
+				`user.eids source "uidapi.com" uid atype 0 set to 3`,
+				`user.eids source "criteo.com" uid atype 3 set to 1`,
+			},
+		},
+		{
+			description: "conflicting-uids-first-kept",
+			eids: []openrtb2.EID{
+				{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "first", AType: 1}}},
+				{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "second", AType: 1}}},
+			},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "first", AType: 1}}},
+			},
+			expectedMessages: []string{
+				`user.eids source "pubcid.org" duplicate entry merged`,
+				`user.eids source "pubcid.org" conflicting uids, kept the first`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			original := cloneEIDs(test.eids)
+			eids, messages := normalizeEIDs("user.eids", test.eids)
+			assert.Equal(t, test.expectedEIDs, eids)
+			assert.Equal(t, test.expectedMessages, messages)
+			assert.Equal(t, original, test.eids, "input changed")
+		})
+	}
+}
+
+func TestNormalizeRequestEIDs(t *testing.T) {
+	user := &openrtb2.User{
+		EIDs: []openrtb2.EID{{Source: "Other.com", UIDs: []openrtb2.UID{{ID: "id1"}}}},
+		Ext:  json.RawMessage(`{"eids":[{"source":"uidapi.com","uids":[{"id":"token"}]}],"other":1}`),
+	}
+	fpdUser := &openrtb2.User{
+		EIDs: []openrtb2.EID{
+			{Source: "Other.com", UIDs: []openrtb2.UID{{ID: "id1"}}},
+			{Source: "Criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: 1}}},
+		},
+	}
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: user}}
+	fpd := map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData{
+		"appnexus": {User: fpdUser},
+		"rubicon":  {},
+	}
+
+	warnings := normalizeRequestEIDs(req, fpd)
+	require.NoError(t, req.RebuildRequest())
+
+	assert.Equal(t, []error{
+		&errortypes.DebugWarning{Message: `user.eids source "Other.com" normalized to "other.com"`, WarningCode: errortypes.EIDNormalizationWarningCode},
+		&errortypes.DebugWarning{Message: `user.ext.eids source "uidapi.com" uid atype 0 set to 3`, WarningCode: errortypes.EIDNormalizationWarningCode},
+		&errortypes.DebugWarning{Message: `appnexus first party data user.eids source "Criteo.com" normalized to "criteo.com"`, WarningCode: errortypes.EIDNormalizationWarningCode},
+	}, warnings)
+
+	assert.Equal(t, []openrtb2.EID{{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}}}}, req.User.EIDs)
+	assert.JSONEq(t, `{"eids":[{"source":"uidapi.com","uids":[{"id":"token","atype":3}]}],"other":1}`, string(req.User.Ext))
+	assert.Equal(t, []openrtb2.EID{
+		{Source: "other.com", UIDs: []openrtb2.UID{{ID: "id1"}}},
+		{Source: "criteo.com", UIDs: []openrtb2.UID{{ID: "criteo", AType: 1}}},
+	}, fpd["appnexus"].User.EIDs)
+	assert.Nil(t, fpd["rubicon"].User)
+
+	assert.Equal(t, "Other.com", user.EIDs[0].Source, "shared user unchanged")
+	assert.Equal(t, "Other.com", fpdUser.EIDs[0].Source, "shared first party data user unchanged")
+}
+
+func TestNormalizeRequestEIDsNoUser(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+	assert.Empty(t, normalizeRequestEIDs(req, nil))
+	assert.Nil(t, req.User)
+}
+
+func cloneEIDs(eids []openrtb2.EID) []openrtb2.EID {
+	clone := make([]openrtb2.EID, len(eids))
+	for i, eid := range eids {
+		clone[i] = eid
+		clone[i].UIDs = append([]openrtb2.UID(nil), eid.UIDs...)
+	}
+	return clone
+}
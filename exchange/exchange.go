@@ -9,13 +9,20 @@ import (
 	"math/rand"
 	"net/url"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
+	"github.com/prebid/prebid-server/v2/privacy/coppa"
+	"github.com/prebid/prebid-server/v2/privacy/gpc"
+	"github.com/prebid/prebid-server/v2/privacy/kanonymity"
+	"github.com/prebid/prebid-server/v2/privacy/minimization"
 
 	"github.com/prebid/prebid-server/v2/adapters"
 	"github.com/prebid/prebid-server/v2/adservertargeting"
@@ -85,6 +92,11 @@ type exchange struct {
 	macroReplacer            macros.Replacer
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
+	privacyAudit             *audit.Logger
+	dsaTracker               *dsa.Tracker
+	uidStore                 usersync.UIDStore
+	uidStoreTimeout          time.Duration
+	syncValueRecorder        usersync.SyncValueRecorder
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -95,6 +107,8 @@ type seatResponseExtra struct {
 	// httpCalls is the list of debugging info. It should only be populated if the request.test == 1.
 	// This will become response.ext.debug.httpcalls.{bidder} on the final Response.
 	HttpCalls []*openrtb_ext.ExtHttpCall
+	// StrippedPaths lists the request paths removed by the data minimization profile of the bidder.
+	StrippedPaths []string
 }
 
 type bidResponseWrapper struct {
@@ -133,7 +147,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, uidStore usersync.UIDStore, syncValueRecorder usersync.SyncValueRecorder, segmentPopulations *kanonymity.FilePopulations, privacyAudit *audit.Logger, idMatcher usersync.IDMatcher) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -148,15 +162,37 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		CCPA: cfg.CCPA,
 		GDPR: cfg.GDPR,
 		LMT:  cfg.LMT,
+		GPC:  cfg.GPC,
 	}
+
+	dataMinimizationProfiles := make(map[string]*minimization.Profile)
+	for bidder, info := range infos {
+		if info.DataMinimization == nil {
+			continue
+		}
+		profile, err := minimization.NewProfile(info.DataMinimization.AllowedPaths)
+		if err != nil {
+			glog.Errorf("Data minimization profile of bidder %s could not be compiled: %v", bidder, err)
+			continue
+		}
+		dataMinimizationProfiles[bidder] = profile
+	}
+
+	dsaTracker := dsa.NewTracker()
+
 	requestSplitter := requestSplitter{
-		bidderToSyncerKey: bidderToSyncerKey,
-		me:                metricsEngine,
-		privacyConfig:     privacyConfig,
-		gdprPermsBuilder:  gdprPermsBuilder,
-		hostSChainNode:    cfg.HostSChainNode,
-		bidderInfo:        infos,
-		requestValidator:  requestValidator,
+		bidderToSyncerKey:        bidderToSyncerKey,
+		me:                       metricsEngine,
+		privacyConfig:            privacyConfig,
+		gdprPermsBuilder:         gdprPermsBuilder,
+		hostSChainNode:           cfg.HostSChainNode,
+		bidderInfo:               infos,
+		requestValidator:         requestValidator,
+		segmentPopulations:       segmentPopulations,
+		dataMinimizationProfiles: dataMinimizationProfiles,
+		dsaTracker:               dsaTracker,
+		idMatcher:                idMatcher,
+		hostCookieFamily:         cfg.HostCookie.Family,
 	}
 
 	return &exchange{
@@ -181,9 +217,63 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		macroReplacer:            macroReplacer,
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
+		privacyAudit:             privacyAudit,
+		dsaTracker:               dsaTracker,
+		uidStore:                 uidStore,
+		uidStoreTimeout:          cfg.UserSync.UIDStore.Timeout(),
+		syncValueRecorder:        syncValueRecorder,
 	}
 }
 
+// readStoredUIDs returns the UIDs kept in the server-side UID store for the user of the request, or nil if the
+// user can't be identified. The store is only read once a bidder has no UID in the cookie, so requests whose
+// cookie already holds every UID don't wait for it.
+func (e *exchange) readStoredUIDs(ctx context.Context, r *AuctionRequest) IdFetcher {
+	if e.uidStore == nil || r.UIDStoreID == "" {
+		return nil
+	}
+	return &lazyStoredUIDs{read: func() usersync.StoredUIDs {
+		return e.getStoredUIDs(ctx, r.UIDStoreID)
+	}}
+}
+
+func (e *exchange) getStoredUIDs(ctx context.Context, id string) usersync.StoredUIDs {
+	if e.uidStoreTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.uidStoreTimeout)
+		defer cancel()
+	}
+
+	entries, err := e.uidStore.Get(ctx, id)
+	if err != nil {
+		glog.Errorf("UIDs could not be read from the UID store: %v", err)
+		return nil
+	}
+	return usersync.StoredUIDs(entries)
+}
+
+// lazyStoredUIDs reads the UIDs from the UID store the first time one is requested.
+type lazyStoredUIDs struct {
+	read func() usersync.StoredUIDs
+	once sync.Once
+	uids usersync.StoredUIDs
+}
+
+func (l *lazyStoredUIDs) load() usersync.StoredUIDs {
+	l.once.Do(func() {
+		l.uids = l.read()
+	})
+	return l.uids
+}
+
+func (l *lazyStoredUIDs) GetUID(key string) (string, bool, bool) {
+	return l.load().GetUID(key)
+}
+
+func (l *lazyStoredUIDs) HasAnyLiveSyncs() bool {
+	return l.load().HasAnyLiveSyncs()
+}
+
 type ImpExtInfo struct {
 	EchoVideoAttrs bool
 	StoredImp      []byte
@@ -193,10 +283,19 @@ type ImpExtInfo struct {
 // AuctionRequest holds the bid request for the auction
 // and all other information needed to process that request
 type AuctionRequest struct {
-	BidRequestWrapper          *openrtb_ext.RequestWrapper
-	ResolvedBidRequest         json.RawMessage
-	Account                    config.Account
-	UserSyncs                  IdFetcher
+	BidRequestWrapper  *openrtb_ext.RequestWrapper
+	ResolvedBidRequest json.RawMessage
+	Account            config.Account
+	UserSyncs          IdFetcher
+	// HostCookieFamily is the family of the host cookie resolved for the request. The family of the configured
+	// host cookie is used if it is empty.
+	HostCookieFamily string
+	// UIDStoreID identifies the user in the server-side UID store. It's empty if the user can't be identified or
+	// opted out of syncs.
+	UIDStoreID string
+	// StoredUserSyncs holds the UIDs read from the server-side UID store, used for the bidders the UserSyncs have
+	// no UID for.
+	StoredUserSyncs            IdFetcher
 	RequestType                metrics.RequestType
 	StartTime                  time.Time
 	Warnings                   []error
@@ -219,6 +318,14 @@ type AuctionRequest struct {
 	QueryParams             url.Values
 	BidderResponseStartTime time.Time
 	TmaxAdjustments         *TmaxAdjustmentsPreprocessed
+	// TCF2Explanations records the legal basis evaluated by TCF2 enforcement when explain mode is on. Set by HoldAuction.
+	TCF2Explanations *gdpr.Explanations
+	// PrivacyAudit records the privacy signals, activity decisions and scrubs of the auction when it's sampled
+	// for the privacy audit. Set by HoldAuction.
+	PrivacyAudit *audit.Record
+	// StoredRequestID is the ID of the stored request the request was built from when it isn't given by
+	// ext.prebid.storedrequest.id, as for the AMP tag_id
+	StoredRequestID string
 }
 
 // BidderRequest holds the bidder specific request and all other
@@ -231,6 +338,9 @@ type BidderRequest struct {
 	BidderStoredResponses map[string]json.RawMessage
 	IsRequestAlias        bool
 	ImpReplaceImpId       map[string]bool
+	// DataMinimization, if set, restricts the request to an allowlist of ORTB paths right before the bidder
+	// builds its requests.
+	DataMinimization *minimization.Profile
 }
 
 func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog *DebugLog) (*AuctionResponse, error) {
@@ -254,6 +364,11 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		requestExtPrebid = &openrtb_ext.ExtRequestPrebid{}
 	}
 
+	// child-directed inventory is treated as COPPA inventory by bidders and all privacy enforcement alike
+	if coppa.ChildDirected(r.Account.Privacy.ChildDirected, r.BidRequestWrapper.BidRequest, getStoredRequestID(r, requestExtPrebid)) {
+		setCOPPA(r.BidRequestWrapper)
+	}
+
 	if !e.server.Empty() {
 		requestExtPrebid.Server = &openrtb_ext.ExtRequestPrebidServer{
 			ExternalUrl: e.server.ExternalUrl,
@@ -328,17 +443,40 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	if err := dsaWriter.Write(r.BidRequestWrapper); err != nil {
 		return nil, err
 	}
+	gpcPolicy, err := gpc.ReadFromRequestWrapper(r.BidRequestWrapper, r.GlobalPrivacyControlHeader)
+	if err != nil {
+		return nil, err
+	}
+	if r.Account.Privacy.GPC.IsEnforced(e.privacyConfig.GPC.Enforce) {
+		if err := gpcPolicy.Write(r.BidRequestWrapper); err != nil {
+			return nil, err
+		}
+	}
+
+	// EIDs are normalized before the EID permissions are enforced for each bidder
+	r.Warnings = append(r.Warnings, normalizeRequestEIDs(r.BidRequestWrapper, r.FirstPartyData)...)
 
 	// rebuild/resync the request in the request wrapper.
 	if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
 		return nil, err
 	}
 
+	if gdprEnforced && (e.privacyConfig.GDPR.Explain || responseDebugAllow) {
+		r.TCF2Explanations = gdpr.NewExplanations()
+	}
+
+	r.PrivacyAudit = e.privacyAudit.Sample(r.StartTime, r.BidRequestWrapper.ID, r.Account.ID)
+	if r.PrivacyAudit != nil {
+		r.Activities = r.Activities.WithRecorder(r.PrivacyAudit)
+	}
+
 	// Slice of BidRequests, each a copy of the original cleaned to only contain bidder data for the named bidder
 	requestExtLegacy := &openrtb_ext.ExtRequest{
 		Prebid: *requestExtPrebid,
 		SChain: requestExt.GetSChain(),
 	}
+	r.StoredUserSyncs = e.readStoredUIDs(ctx, r)
+
 	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, gdprSignal, gdprEnforced, bidAdjustmentFactors)
 	for _, err := range errs {
 		if errortypes.ReadCode(err) == errortypes.InvalidImpFirstPartyDataErrorCode {
@@ -347,6 +485,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 	errs = append(errs, floorErrs...)
 
+	e.privacyAudit.Write(r.PrivacyAudit)
+
 	mergedBidAdj, err := bidadjustment.Merge(r.BidRequestWrapper, r.Account.BidAdjustments)
 	if err != nil {
 		if errortypes.ContainsFatalError([]error{err}) {
@@ -358,6 +498,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 
 	e.me.RecordRequestPrivacy(privacyLabels)
 
+	tcf2Explanations := r.TCF2Explanations.Bidders()
+	recordTCF2LegalBasis(e.me, tcf2Explanations)
+
 	if len(r.StoredAuctionResponses) > 0 || len(r.StoredBidResponses) > 0 {
 		e.me.RecordStoredResponse(r.PubID)
 	}
@@ -374,6 +517,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		anyBidsReturned bool
 		// List of bidders we have requests for.
 		liveAdapters []openrtb_ext.BidderName
+		// DSA validation results of the bids, reused when the bids are added to the response
+		dsaErrs map[*entities.PbsOrtbBid]error
 	)
 
 	if len(r.StoredAuctionResponses) > 0 {
@@ -396,6 +541,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 		var extraRespInfo extraAuctionResponseInfo
 		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow)
+		dsaErrs = e.recordDSAValidation(r, adapterBids)
+		e.recordSyncValues(r, bidderRequests, adapterBids)
 		fledge = extraRespInfo.fledge
 		anyBidsReturned = extraRespInfo.bidsFound
 		r.BidderResponseStartTime = extraRespInfo.bidderResponseStartTime
@@ -408,6 +555,13 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		seatNonBids    = nonBids{}
 	)
 
+	// bids supplied by hooks in place of calling the bidders, e.g. bids served from a module cache, go
+	// through the same floors enforcement, category mapping, bid ID generation and event tracking as the others
+	hookSeatBids := r.HookExecutor.GetSeatBids()
+	liveAdapters = appendBiddersWithBids(liveAdapters, hookSeatBids)
+	adapterBids, adapterExtra = addSeatBids(adapterBids, adapterExtra, hookSeatBids)
+	anyBidsReturned = anyBidsReturned || len(hookSeatBids) > 0
+
 	if anyBidsReturned {
 		if e.priceFloorEnabled {
 			var rejectedBids []*entities.PbsOrtbSeatBid
@@ -419,9 +573,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 				errs = append(errs, &errortypes.Warning{
 					Message:     fmt.Sprintf("%s bid id %s rejected - bid price %.4f %s is less than bid floor %.4f %s for imp %s", rejectedBid.Seat, rejectedBid.Bids[0].Bid.ID, rejectedBid.Bids[0].Bid.Price, rejectedBid.Currency, rejectedBid.Bids[0].BidFloors.FloorValue, rejectedBid.Bids[0].BidFloors.FloorCurrency, rejectedBid.Bids[0].Bid.ImpID),
 					WarningCode: errortypes.FloorBidRejectionWarningCode})
-				rejectionReason := ResponseRejectedBelowFloor
+				rejectionReason := openrtb_ext.ResponseRejectedBelowFloor
 				if rejectedBid.Bids[0].Bid.DealID != "" {
-					rejectionReason = ResponseRejectedBelowDealFloor
+					rejectionReason = openrtb_ext.ResponseRejectedBelowDealFloor
 				}
 				seatNonBids.addBid(rejectedBid.Bids[0], int(rejectionReason), rejectedBid.Seat)
 			}
@@ -504,6 +658,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 	}
 
+	seatNonBids.append(r.HookExecutor.GetSeatNonBid())
+
 	if !accountDebugAllow && !debugLog.DebugOverride {
 		accountDebugDisabledWarning := openrtb_ext.ExtBidderMessage{
 			Code:    errortypes.AccountLevelDebugDisabledWarningCode,
@@ -512,6 +668,21 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], accountDebugDisabledWarning)
 	}
 
+	dataMinimization := buildDataMinimizationDebug(adapterExtra)
+	if bidResponseExt.Debug != nil && (privacyLabels.GPCProvided || len(tcf2Explanations) > 0 || len(dataMinimization) > 0) {
+		bidResponseExt.Debug.Privacy = &openrtb_ext.ExtResponseDebugPrivacy{DataMinimization: dataMinimization}
+		if privacyLabels.GPCProvided {
+			bidResponseExt.Debug.Privacy.GPC = &openrtb_ext.ExtResponseDebugGPC{
+				Signal:   gpcPolicy.Signal,
+				Source:   gpcPolicy.Source,
+				Enforced: privacyLabels.GPCEnforced,
+			}
+		}
+		if len(tcf2Explanations) > 0 {
+			bidResponseExt.Debug.Privacy.TCF2 = &openrtb_ext.ExtResponseDebugTCF2{Bidders: tcf2Explanations}
+		}
+	}
+
 	for _, warning := range r.Warnings {
 		if errortypes.ReadScope(warning) == errortypes.ScopeDebug && !responseDebugAllow {
 			continue
@@ -526,7 +697,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
 
 	// Build the response
-	bidResponse := e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, r.PubID, errs, &seatNonBids)
+	bidResponse := e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, r.PubID, errs, &seatNonBids, dsaErrs)
 	bidResponse = adservertargeting.Apply(r.BidRequestWrapper, r.ResolvedBidRequest, bidResponse, r.QueryParams, bidResponseExt, r.Account.TruncateTargetAttribute)
 
 	bidResponse.Ext, err = encodeBidResponseExt(bidResponseExt)
@@ -536,11 +707,111 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	bidResponseExt = setSeatNonBid(bidResponseExt, seatNonBids)
 
 	return &AuctionResponse{
-		BidResponse:    bidResponse,
-		ExtBidResponse: bidResponseExt,
+		BidResponse:      bidResponse,
+		ExtBidResponse:   bidResponseExt,
+		TCF2Explanations: tcf2Explanations,
+		PrivacyAudit:     e.privacyAudit.AnalyticsRecord(r.PrivacyAudit),
+		DSAReport:        dsa.BuildReport(bidResponse),
 	}, nil
 }
 
+// getStoredRequestID returns the ID of the stored request the auction request was built from, if any
+func getStoredRequestID(r *AuctionRequest, requestExtPrebid *openrtb_ext.ExtRequestPrebid) string {
+	if r.StoredRequestID != "" {
+		return r.StoredRequestID
+	}
+	if requestExtPrebid.StoredRequest != nil {
+		return requestExtPrebid.StoredRequest.ID
+	}
+	return ""
+}
+
+func setCOPPA(req *openrtb_ext.RequestWrapper) {
+	// regs is copied as it may be shared with the incoming request
+	var regs openrtb2.Regs
+	if req.Regs != nil {
+		regs = *req.Regs
+	}
+	regs.COPPA = 1
+	req.Regs = &regs
+}
+
+// recordDSAValidation counts the bids of each bidder failing DSA validation for the account bidder enforcement.
+// It returns the validation result of each bid, which is nil if the enforcement is disabled.
+func (e *exchange) recordDSAValidation(r *AuctionRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) map[*entities.PbsOrtbBid]error {
+	policy := dsaBidderEnforcement(r.Account)
+	if !policy.Enabled || !dsa.IsRequired(r.BidRequestWrapper) {
+		return nil
+	}
+
+	now := time.Now()
+	dsaErrs := make(map[*entities.PbsOrtbBid]error)
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		failures := 0
+		for _, bid := range seatBid.Bids {
+			err := dsa.Validate(r.BidRequestWrapper, bid)
+			if err != nil {
+				failures++
+			}
+			dsaErrs[bid] = err
+		}
+		e.dsaTracker.Record(r.Account.ID, bidder.String(), len(seatBid.Bids), failures, now, policy)
+	}
+	return dsaErrs
+}
+
+// recordSyncValues records, for each bidder sent a synced UID, its highest bid as the value of the UID to the
+// bidder for the account and the country of the auction
+func (e *exchange) recordSyncValues(r *AuctionRequest, bidderRequests []BidderRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+	if e.syncValueRecorder == nil {
+		return
+	}
+
+	var country string
+	if device := r.BidRequestWrapper.Device; device != nil && device.Geo != nil {
+		country = device.Geo.Country
+	}
+
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.BidRequest.User == nil || bidderRequest.BidRequest.User.BuyerUID == "" {
+			continue
+		}
+		var value float64
+		if seatBid := adapterBids[bidderRequest.BidderName]; seatBid != nil {
+			for _, bid := range seatBid.Bids {
+				if bid != nil && bid.Bid != nil && bid.Bid.Price > value {
+					value = bid.Bid.Price
+				}
+			}
+		}
+		e.syncValueRecorder.RecordSyncValue(r.Account.ID, country, bidderRequest.BidderName.String(), value)
+	}
+}
+
+func dsaBidderEnforcement(account config.Account) config.AccountDSABidderEnforcement {
+	if account.Privacy.DSA == nil {
+		return config.AccountDSABidderEnforcement{}
+	}
+	return account.Privacy.DSA.BidderEnforcement
+}
+
+// buildDataMinimizationDebug reports the request paths stripped for each bidder, sorted by bidder name
+func buildDataMinimizationDebug(adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra) []openrtb_ext.ExtResponseDebugDataMinimization {
+	var result []openrtb_ext.ExtResponseDebugDataMinimization
+	for bidder, extra := range adapterExtra {
+		if extra != nil && len(extra.StrippedPaths) > 0 {
+			result = append(result, openrtb_ext.ExtResponseDebugDataMinimization{Bidder: bidder.String(), Stripped: extra.StrippedPaths})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Bidder < result[j].Bidder
+	})
+	return result
+}
+
 func buildMultiBidMap(prebid *openrtb_ext.ExtRequestPrebid) map[string]openrtb_ext.ExtMultiBid {
 	if prebid == nil || prebid.MultiBid == nil {
 		return nil
@@ -756,6 +1027,7 @@ func (e *exchange) getAllBids(
 			// Structure to record extra tracking data generated during bidding
 			ae := new(seatResponseExtra)
 			ae.ResponseTimeMillis = int(elapsed / time.Millisecond)
+			ae.StrippedPaths = extraBidderRespInfo.strippedPaths
 			if len(seatBids) != 0 {
 				ae.HttpCalls = seatBids[0].HttpCalls
 			}
@@ -922,7 +1194,7 @@ func errsToBidderWarnings(errs []error) []openrtb_ext.ExtBidderMessage {
 }
 
 // This piece takes all the bids supplied by the adapters and crafts an openRTB response to send back to the requester
-func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterSeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidRequest *openrtb_ext.RequestWrapper, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, bidResponseExt *openrtb_ext.ExtBidResponse, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, pubID string, errList []error, seatNonBids *nonBids) *openrtb2.BidResponse {
+func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterSeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidRequest *openrtb_ext.RequestWrapper, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, bidResponseExt *openrtb_ext.ExtBidResponse, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, pubID string, errList []error, seatNonBids *nonBids, dsaErrs map[*entities.PbsOrtbBid]error) *openrtb2.BidResponse {
 	bidResponse := new(openrtb2.BidResponse)
 
 	bidResponse.ID = bidRequest.ID
@@ -937,7 +1209,7 @@ func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_
 	for a, adapterSeatBids := range adapterSeatBids {
 		//while processing every single bib, do we need to handle categories here?
 		if adapterSeatBids != nil && len(adapterSeatBids.Bids) > 0 {
-			sb := e.makeSeatBid(adapterSeatBids, a, adapterExtra, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, pubID, seatNonBids)
+			sb := e.makeSeatBid(adapterSeatBids, a, adapterExtra, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, pubID, seatNonBids, dsaErrs)
 			seatBids = append(seatBids, *sb)
 			bidResponse.Cur = adapterSeatBids.Currency
 		}
@@ -1019,7 +1291,7 @@ func applyCategoryMapping(ctx context.Context, targeting openrtb_ext.ExtRequestT
 					//on receiving bids from adapters if no unique IAB category is returned  or if no ad server category is returned discard the bid
 					bidsToRemove = append(bidsToRemove, bidInd)
 					rejections = updateRejections(rejections, bidID, "Bid did not contain a category")
-					seatNonBids.addBid(bid, int(ResponseRejectedCategoryMappingInvalid), string(bidderName))
+					seatNonBids.addBid(bid, int(openrtb_ext.ResponseRejectedCategoryMappingInvalid), string(bidderName))
 					continue
 				}
 				if translateCategories {
@@ -1256,14 +1528,14 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 
 // Return an openrtb seatBid for a bidder
 // buildBidResponse is responsible for ensuring nil bid seatbids are not included
-func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter openrtb_ext.BidderName, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, pubID string, seatNonBids *nonBids) *openrtb2.SeatBid {
+func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter openrtb_ext.BidderName, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, pubID string, seatNonBids *nonBids, dsaErrs map[*entities.PbsOrtbBid]error) *openrtb2.SeatBid {
 	seatBid := &openrtb2.SeatBid{
 		Seat:  adapter.String(),
 		Group: 0, // Prebid cannot support roadblocking
 	}
 
 	var errList []error
-	seatBid.Bid, errList = e.makeBid(adapterBid.Bids, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, adapter, pubID, seatNonBids)
+	seatBid.Bid, errList = e.makeBid(adapterBid.Bids, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, adapter, pubID, seatNonBids, dsaErrs)
 	if len(errList) > 0 {
 		adapterExtra[adapter].Errors = append(adapterExtra[adapter].Errors, errsToBidderErrors(errList)...)
 	}
@@ -1271,24 +1543,29 @@ func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter open
 	return seatBid
 }
 
-func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, adapter openrtb_ext.BidderName, pubID string, seatNonBids *nonBids) ([]openrtb2.Bid, []error) {
+func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, adapter openrtb_ext.BidderName, pubID string, seatNonBids *nonBids, dsaErrs map[*entities.PbsOrtbBid]error) ([]openrtb2.Bid, []error) {
 	result := make([]openrtb2.Bid, 0, len(bids))
 	errs := make([]error, 0, 1)
 
 	for _, bid := range bids {
-		if err := dsa.Validate(bidRequest, bid); err != nil {
+		// bids added or replaced by the hooks since the DSA validation are validated now
+		err, validated := dsaErrs[bid]
+		if !validated {
+			err = dsa.Validate(bidRequest, bid)
+		}
+		if err != nil {
 			dsaMessage := openrtb_ext.ExtBidderMessage{
 				Code:    errortypes.InvalidBidResponseDSAWarningCode,
 				Message: fmt.Sprintf("bid rejected: %s", err.Error()),
 			}
 			bidResponseExt.Warnings[adapter] = append(bidResponseExt.Warnings[adapter], dsaMessage)
 
-			seatNonBids.addBid(bid, int(ResponseRejectedGeneral), adapter.String())
+			seatNonBids.addBid(bid, int(openrtb_ext.ResponseRejectedGeneral), adapter.String())
 			continue // Don't add bid to result
 		}
 		if e.bidValidationEnforcement.BannerCreativeMaxSize == config.ValidationEnforce && bid.BidType == openrtb_ext.BidTypeBanner {
 			if !e.validateBannerCreativeSize(bid, bidResponseExt, adapter, pubID, e.bidValidationEnforcement.BannerCreativeMaxSize) {
-				seatNonBids.addBid(bid, int(ResponseRejectedCreativeSizeNotAllowed), adapter.String())
+				seatNonBids.addBid(bid, int(openrtb_ext.ResponseRejectedCreativeSizeNotAllowed), adapter.String())
 				continue // Don't add bid to result
 			}
 		} else if e.bidValidationEnforcement.BannerCreativeMaxSize == config.ValidationWarn && bid.BidType == openrtb_ext.BidTypeBanner {
@@ -1297,7 +1574,7 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 		if _, ok := impExtInfoMap[bid.Bid.ImpID]; ok {
 			if e.bidValidationEnforcement.SecureMarkup == config.ValidationEnforce && (bid.BidType == openrtb_ext.BidTypeBanner || bid.BidType == openrtb_ext.BidTypeVideo) {
 				if !e.validateBidAdM(bid, bidResponseExt, adapter, pubID, e.bidValidationEnforcement.SecureMarkup) {
-					seatNonBids.addBid(bid, int(ResponseRejectedCreativeNotSecure), adapter.String())
+					seatNonBids.addBid(bid, int(openrtb_ext.ResponseRejectedCreativeNotSecure), adapter.String())
 					continue // Don't add bid to result
 				}
 			} else if e.bidValidationEnforcement.SecureMarkup == config.ValidationWarn && (bid.BidType == openrtb_ext.BidTypeBanner || bid.BidType == openrtb_ext.BidTypeVideo) {
@@ -1444,6 +1721,41 @@ func buildCacheURL(cache prebid_cache_client.Client, uuid string) string {
 	return strings.TrimPrefix(cacheURL.String(), "//")
 }
 
+// addSeatBids adds the bids supplied by hooks to the bids of the bidders. The bidders which were not called
+// get an empty response extra to collect the errors of their bids.
+func addSeatBids(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, map[openrtb_ext.BidderName]*seatResponseExtra) {
+	for bidder, seatBid := range seatBids {
+		if seatBid == nil || len(seatBid.Bids) == 0 {
+			continue
+		}
+		if adapterBids == nil {
+			adapterBids = make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+		}
+		if existing, ok := adapterBids[bidder]; ok && existing != nil {
+			existing.Bids = append(existing.Bids, seatBid.Bids...)
+		} else {
+			adapterBids[bidder] = seatBid
+		}
+		if adapterExtra == nil {
+			adapterExtra = make(map[openrtb_ext.BidderName]*seatResponseExtra)
+		}
+		if adapterExtra[bidder] == nil {
+			adapterExtra[bidder] = &seatResponseExtra{}
+		}
+	}
+	return adapterBids, adapterExtra
+}
+
+// appendBiddersWithBids adds the bidders having bids which were not requested, e.g. when the bids were supplied by hooks.
+func appendBiddersWithBids(liveAdapters []openrtb_ext.BidderName, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []openrtb_ext.BidderName {
+	for bidder, seatBid := range adapterBids {
+		if seatBid != nil && len(seatBid.Bids) > 0 && !slices.Contains(liveAdapters, bidder) {
+			liveAdapters = append(liveAdapters, bidder)
+		}
+	}
+	return liveAdapters
+}
+
 func listBiddersWithRequests(bidderRequests []BidderRequest) []openrtb_ext.BidderName {
 	liveAdapters := make([]openrtb_ext.BidderName, len(bidderRequests))
 	i := 0
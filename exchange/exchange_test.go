@@ -23,6 +23,7 @@ import (
 	"github.com/prebid/prebid-server/v2/adapters"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
@@ -42,6 +43,7 @@ import (
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/file_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_responses"
 	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/usersync/uidstore"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -84,7 +86,7 @@ func TestNewExchange(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 	for _, bidderName := range knownAdapters {
 		if _, ok := e.adapterMap[bidderName]; !ok {
 			if biddersInfo[string(bidderName)].IsEnabled() {
@@ -134,7 +136,7 @@ func TestCharacterEscape(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	// 	3) Build all the parameters e.buildBidResponse(ctx.Background(), liveA... ) needs
 	//liveAdapters []openrtb_ext.BidderName,
@@ -172,7 +174,7 @@ func TestCharacterEscape(t *testing.T) {
 	var errList []error
 
 	// 	4) Build bid response
-	bidResp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, adapterExtra, nil, nil, true, nil, "", errList, &nonBids{})
+	bidResp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, adapterExtra, nil, nil, true, nil, "", errList, &nonBids{}, nil)
 
 	// 	5) Assert we have no errors and one '&' character as we are supposed to
 	if len(errList) > 0 {
@@ -1237,7 +1239,7 @@ func TestGetBidCacheInfoEndToEnd(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, pbc, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, pbc, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 	// 	3) Build all the parameters e.buildBidResponse(ctx.Background(), liveA... ) needs
 	liveAdapters := []openrtb_ext.BidderName{bidderName}
 
@@ -1343,7 +1345,7 @@ func TestGetBidCacheInfoEndToEnd(t *testing.T) {
 	var errList []error
 
 	// 	4) Build bid response
-	bid_resp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, adapterExtra, auc, nil, true, nil, "", errList, &nonBids{})
+	bid_resp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, adapterExtra, auc, nil, true, nil, "", errList, &nonBids{}, nil)
 
 	expectedBidResponse := &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{
@@ -1433,7 +1435,7 @@ func TestBidReturnsCreative(t *testing.T) {
 
 	//Run tests
 	for _, test := range testCases {
-		resultingBids, resultingErrs := e.makeBid(sampleBids, sampleAuction, test.inReturnCreative, nil, &openrtb_ext.RequestWrapper{}, nil, "", "", &nonBids{})
+		resultingBids, resultingErrs := e.makeBid(sampleBids, sampleAuction, test.inReturnCreative, nil, &openrtb_ext.RequestWrapper{}, nil, "", "", &nonBids{}, nil)
 
 		assert.Equal(t, 0, len(resultingErrs), "%s. Test should not return errors \n", test.description)
 		assert.Equal(t, test.expectedCreativeMarkup, resultingBids[0].AdM, "%s. Ad markup string doesn't match expected \n", test.description)
@@ -1596,7 +1598,7 @@ func TestBidResponseCurrency(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	liveAdapters := make([]openrtb_ext.BidderName, 1)
 	liveAdapters[0] = "appnexus"
@@ -1718,7 +1720,7 @@ func TestBidResponseCurrency(t *testing.T) {
 	}
 	// Run tests
 	for i := range testCases {
-		actualBidResp := e.buildBidResponse(context.Background(), liveAdapters, testCases[i].adapterBids, bidRequest, adapterExtra, nil, bidResponseExt, true, nil, "", errList, &nonBids{})
+		actualBidResp := e.buildBidResponse(context.Background(), liveAdapters, testCases[i].adapterBids, bidRequest, adapterExtra, nil, bidResponseExt, true, nil, "", errList, &nonBids{}, nil)
 		assert.Equalf(t, testCases[i].expectedBidResponse, actualBidResp, fmt.Sprintf("[TEST_FAILED] Objects must be equal for test: %s \n Expected: >>%s<< \n Actual: >>%s<< ", testCases[i].description, testCases[i].expectedBidResponse.Ext, actualBidResp.Ext))
 	}
 }
@@ -1744,7 +1746,7 @@ func TestBidResponseImpExtInfo(t *testing.T) {
 		t.Fatalf("Error intializing adapters: %v", adaptersErr)
 	}
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, nil, gdprPermsBuilder, nil, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, nil, gdprPermsBuilder, nil, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	liveAdapters := make([]openrtb_ext.BidderName, 1)
 	liveAdapters[0] = "appnexus"
@@ -1786,7 +1788,7 @@ func TestBidResponseImpExtInfo(t *testing.T) {
 
 	expectedBidResponseExt := `{"origbidcpm":0,"prebid":{"meta":{"adaptercode":"appnexus"},"type":"video","passthrough":{"imp_passthrough_val":1}},"storedrequestattributes":{"h":480,"mimes":["video/mp4"]}}`
 
-	actualBidResp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, nil, nil, nil, true, impExtInfo, "", errList, &nonBids{})
+	actualBidResp := e.buildBidResponse(context.Background(), liveAdapters, adapterBids, bidRequest, nil, nil, nil, true, impExtInfo, "", errList, &nonBids{}, nil)
 
 	resBidExt := string(actualBidResp.SeatBid[0].Bid[0].Ext)
 	assert.Equalf(t, expectedBidResponseExt, resBidExt, "Expected bid response extension is incorrect")
@@ -1838,7 +1840,7 @@ func TestRaceIntegration(t *testing.T) {
 		},
 	}.Builder
 
-	ex := NewExchange(adapters, &wellBehavedCache{}, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, &nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	ex := NewExchange(adapters, &wellBehavedCache{}, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, &nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 	_, err = ex.HoldAuction(context.Background(), auctionRequest, &debugLog)
 	if err != nil {
 		t.Errorf("HoldAuction returned unexpected error: %v", err)
@@ -1936,7 +1938,7 @@ func TestPanicRecovery(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	chBids := make(chan *bidResponseWrapper, 1)
 	panicker := func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -2006,7 +2008,7 @@ func TestPanicRecoveryHighLevel(t *testing.T) {
 			allowAllBidders: true,
 		},
 	}.Builder
-	e := NewExchange(adapters, &mockCache{}, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, categoriesFetcher, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, &mockCache{}, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, categoriesFetcher, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	e.adapterMap[openrtb_ext.BidderBeachfront] = panicingAdapter{}
 	e.adapterMap[openrtb_ext.BidderAppnexus] = panicingAdapter{}
@@ -2133,6 +2135,9 @@ func runSpec(t *testing.T, filename string, spec *exchangeSpec) {
 		LMT: config.LMT{
 			Enforce: spec.EnforceLMT,
 		},
+		GPC: config.GPC{
+			Enforce: spec.EnforceGPC,
+		},
 		GDPR: config.GDPR{
 			Enabled:         spec.GDPREnabled,
 			DefaultValue:    gdprDefaultValue,
@@ -2198,6 +2203,8 @@ func runSpec(t *testing.T, filename string, spec *exchangeSpec) {
 		HookExecutor:  &hookexecution.EmptyHookExecutor{},
 		TCF2Config:    gdpr.NewTCF2Config(privacyConfig.GDPR.TCF2, config.AccountGDPR{}),
 		Activities:    activityControl,
+
+		GlobalPrivacyControlHeader: spec.IncomingRequest.GlobalPrivacyControlHeader,
 	}
 
 	if spec.MultiBid != nil {
@@ -2334,6 +2341,11 @@ func runSpec(t *testing.T, filename string, spec *exchangeSpec) {
 	if expectedBidRespExt.Prebid != nil {
 		assert.ElementsMatch(t, expectedBidRespExt.Prebid.SeatNonBid, bidExt.Prebid.SeatNonBid, "Expected seatNonBids from response ext do not match")
 	}
+	if expectedBidRespExt.Debug != nil && expectedBidRespExt.Debug.Privacy != nil {
+		if assert.NotNil(t, bidExt.Debug, "Expected debug info in response ext") {
+			assert.Equal(t, expectedBidRespExt.Debug.Privacy, bidExt.Debug.Privacy, "Expected debug privacy info from response ext do not match")
+		}
+	}
 }
 
 func findBiddersInAuction(t *testing.T, context string, req *openrtb2.BidRequest) []string {
@@ -4207,6 +4219,103 @@ func TestStoredAuctionResponses(t *testing.T) {
 	}
 }
 
+func TestHoldAuctionWithHookSeatBids(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		impBidFloor      float64
+		hookSeatBids     map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		expectedSeatBids []openrtb2.SeatBid
+	}{
+		{
+			desc: "Hook bids get new bid IDs",
+			hookSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {
+					Seat:     "appnexus",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "bid_id", ImpID: "impression-id", Price: 1}, BidType: openrtb_ext.BidTypeBanner, GeneratedBidID: "previous-auction-bid-id"},
+					},
+				},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{
+					Bid: []openrtb2.Bid{
+						{ID: "bid_id", ImpID: "impression-id", Price: 1, Ext: json.RawMessage(`{"origbidcpm":0,"prebid":{"bidid":"bid-appnexus-1","meta":{},"type":"banner"}}`)},
+					},
+					Seat: "appnexus",
+				},
+			},
+		},
+		{
+			desc:        "Hook bids below the floor are rejected",
+			impBidFloor: 2,
+			hookSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {
+					Seat:     "appnexus",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "bid_id", ImpID: "impression-id", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+					},
+				},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{},
+		},
+		{
+			desc: "Invalid hook bids of a bidder not called",
+			hookSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {
+					Seat:     "appnexus",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{
+						{Bid: &openrtb2.Bid{ID: "bid_id", ImpID: "impression-id", Price: 1, Ext: json.RawMessage(`{invalid json}`)}, BidType: openrtb_ext.BidTypeBanner},
+					},
+				},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{}, Seat: "appnexus"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			e := new(exchange)
+			e.cache = &wellBehavedCache{}
+			e.me = &metricsConf.NilMetricsEngine{}
+			e.bidIDGenerator = &fakeBidIDGenerator{GenerateBidID: true}
+			e.priceFloorEnabled = true
+			e.currencyConverter = currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+			e.gdprPermsBuilder = fakePermissionsBuilder{
+				permissions: &permissionsMock{
+					allowAllBidders: true,
+				},
+			}.Builder
+
+			auctionRequest := &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+					ID:  "request-id",
+					Imp: []openrtb2.Imp{{ID: "impression-id", Banner: &openrtb2.Banner{}, BidFloor: test.impBidFloor, BidFloorCur: "USD"}},
+				}},
+				Account:      config.Account{PriceFloors: config.AccountPriceFloors{Enabled: true, EnforceFloorsRate: 100}},
+				UserSyncs:    &emptyUsersync{},
+				HookExecutor: &seatBidsHookExecutor{seatBids: test.hookSeatBids},
+				TCF2Config:   gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			}
+
+			outBidResponse, err := e.HoldAuction(context.Background(), auctionRequest, &DebugLog{})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedSeatBids, outBidResponse.BidResponse.SeatBid)
+		})
+	}
+}
+
+type seatBidsHookExecutor struct {
+	hookexecution.EmptyHookExecutor
+	seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+}
+
+func (e *seatBidsHookExecutor) GetSeatBids() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+	return e.seatBids
+}
+
 func TestBuildStoredAuctionResponses(t *testing.T) {
 
 	type testIn struct {
@@ -4577,7 +4686,7 @@ func TestPassExperimentConfigsToHoldAuction(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &signer, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &signer, macros.NewStringIndexBasedReplacer(), nil, nil, nil, nil, nil, nil).(*exchange)
 
 	// Define mock incoming bid requeset
 	mockBidRequest := &openrtb2.BidRequest{
@@ -4926,7 +5035,7 @@ func TestMakeBidWithValidation(t *testing.T) {
 			e.bidValidationEnforcement = test.givenValidations
 			sampleBids := test.givenBids
 			nonBids := &nonBids{}
-			resultingBids, resultingErrs := e.makeBid(sampleBids, sampleAuction, true, ImpExtInfoMap, bidRequest, bidExtResponse, test.givenSeat, "", nonBids)
+			resultingBids, resultingErrs := e.makeBid(sampleBids, sampleAuction, true, ImpExtInfoMap, bidRequest, bidExtResponse, test.givenSeat, "", nonBids, nil)
 
 			assert.Equal(t, 0, len(resultingErrs))
 			assert.Equal(t, test.expectedNumOfBids, len(resultingBids))
@@ -5498,6 +5607,7 @@ type exchangeSpec struct {
 	Response                   exchangeResponse       `json:"response,omitempty"`
 	EnforceCCPA                bool                   `json:"enforceCcpa"`
 	EnforceLMT                 bool                   `json:"enforceLmt"`
+	EnforceGPC                 bool                   `json:"enforceGpc"`
 	AssumeGDPRApplies          bool                   `json:"assume_gdpr_applies"`
 	DebugLog                   *DebugLog              `json:"debuglog,omitempty"`
 	EventsEnabled              bool                   `json:"events_enabled,omitempty"`
@@ -5522,8 +5632,9 @@ type multiBidSpec struct {
 }
 
 type exchangeRequest struct {
-	OrtbRequest openrtb2.BidRequest `json:"ortbRequest"`
-	Usersyncs   map[string]string   `json:"usersyncs"`
+	OrtbRequest                openrtb2.BidRequest `json:"ortbRequest"`
+	Usersyncs                  map[string]string   `json:"usersyncs"`
+	GlobalPrivacyControlHeader string              `json:"globalPrivacyControlHeader"`
 }
 
 type exchangeResponse struct {
@@ -6091,6 +6202,186 @@ func TestSetSeatNonBid(t *testing.T) {
 	}
 }
 
+func TestGetStoredRequestID(t *testing.T) {
+	testCases := []struct {
+		description      string
+		auctionRequest   *AuctionRequest
+		requestExtPrebid *openrtb_ext.ExtRequestPrebid
+		expected         string
+	}{
+		{
+			description:      "none",
+			auctionRequest:   &AuctionRequest{},
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{},
+			expected:         "",
+		},
+		{
+			description:      "request-ext",
+			auctionRequest:   &AuctionRequest{},
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{StoredRequest: &openrtb_ext.ExtStoredRequest{ID: "ext-id"}},
+			expected:         "ext-id",
+		},
+		{
+			description:      "auction-request",
+			auctionRequest:   &AuctionRequest{StoredRequestID: "tag-id"},
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{StoredRequest: &openrtb_ext.ExtStoredRequest{ID: "ext-id"}},
+			expected:         "tag-id",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, getStoredRequestID(test.auctionRequest, test.requestExtPrebid))
+		})
+	}
+}
+
+func TestSetCOPPA(t *testing.T) {
+	testCases := []struct {
+		description  string
+		regs         *openrtb2.Regs
+		expectedRegs *openrtb2.Regs
+	}{
+		{
+			description:  "nil-regs",
+			regs:         nil,
+			expectedRegs: &openrtb2.Regs{COPPA: 1},
+		},
+		{
+			description:  "regs",
+			regs:         &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			expectedRegs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1), COPPA: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var originalRegs *openrtb2.Regs
+			if test.regs != nil {
+				regsCopy := *test.regs
+				originalRegs = &regsCopy
+			}
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: test.regs}}
+			setCOPPA(req)
+			assert.Equal(t, test.expectedRegs, req.Regs)
+			assert.Equal(t, originalRegs, test.regs, "incoming regs must not be modified")
+		})
+	}
+}
+
+func TestRecordDSAValidation(t *testing.T) {
+	enforcement := config.AccountDSABidderEnforcement{Enabled: true, MaxFailureRate: 0.5, MinBids: 2}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid1"}},
+			{Bid: &openrtb2.Bid{ID: "bid2"}},
+		}},
+		"rubicon": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid3", Ext: json.RawMessage(`{"dsa":{"behalf":"advertiser","paid":"agency"}}`)}},
+			{Bid: &openrtb2.Bid{ID: "bid4"}},
+		}},
+		"pubmatic": nil,
+	}
+
+	testCases := []struct {
+		description       string
+		regsExt           json.RawMessage
+		expectedDisabled  []string
+		expectedValidated int
+	}{
+		{
+			description:       "dsa-required",
+			regsExt:           json.RawMessage(`{"dsa":{"dsarequired":2}}`),
+			expectedDisabled:  []string{"appnexus"},
+			expectedValidated: 4,
+		},
+		{
+			description:       "dsa-not-required",
+			regsExt:           json.RawMessage(`{"dsa":{"dsarequired":1}}`),
+			expectedDisabled:  []string{},
+			expectedValidated: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := &exchange{dsaTracker: dsa.NewTracker()}
+			r := &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: test.regsExt}}},
+				Account: config.Account{
+					ID:      "some-account",
+					Privacy: config.AccountPrivacy{DSA: &config.AccountDSA{BidderEnforcement: enforcement}},
+				},
+			}
+
+			dsaErrs := e.recordDSAValidation(r, adapterBids)
+			assert.Len(t, dsaErrs, test.expectedValidated)
+
+			disabled := []string{}
+			for _, bidder := range []string{"appnexus", "pubmatic", "rubicon"} {
+				if e.dsaTracker.Disabled("some-account", bidder, time.Now(), enforcement) {
+					disabled = append(disabled, bidder)
+				}
+			}
+			assert.Equal(t, test.expectedDisabled, disabled)
+		})
+	}
+}
+
+func TestMakeBidReusesDSAValidation(t *testing.T) {
+	rejected := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "rejected", ImpID: "1"}, BidType: openrtb_ext.BidTypeBanner}
+	accepted := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "accepted", ImpID: "1"}, BidType: openrtb_ext.BidTypeBanner}
+	added := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "added", ImpID: "1"}, BidType: openrtb_ext.BidTypeBanner}
+	dsaErrs := map[*entities.PbsOrtbBid]error{
+		rejected: errors.New("object dsa missing when required"),
+		accepted: nil,
+	}
+
+	e := &exchange{me: &metricsConf.NilMetricsEngine{}}
+	bidRequest := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"dsa":{"dsarequired":2}}`)}}}
+	bidResponseExt := &openrtb_ext.ExtBidResponse{Warnings: make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage)}
+
+	bids, _ := e.makeBid([]*entities.PbsOrtbBid{rejected, accepted, added}, &auction{}, false, nil, bidRequest, bidResponseExt, "appnexus", "", &nonBids{}, dsaErrs)
+
+	// the bid added since the validation lacks the required dsa object
+	if assert.Len(t, bids, 1) {
+		assert.Equal(t, "accepted", bids[0].ID)
+	}
+	assert.Len(t, bidResponseExt.Warnings["appnexus"], 2)
+}
+
+func TestBuildDataMinimizationDebug(t *testing.T) {
+	testCases := []struct {
+		description  string
+		adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra
+		expected     []openrtb_ext.ExtResponseDebugDataMinimization
+	}{
+		{
+			description:  "nothing-stripped",
+			adapterExtra: map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {}, "rubicon": nil},
+			expected:     nil,
+		},
+		{
+			description: "sorted-by-bidder",
+			adapterExtra: map[openrtb_ext.BidderName]*seatResponseExtra{
+				"rubicon":  {StrippedPaths: []string{"device.ua"}},
+				"appnexus": {StrippedPaths: []string{"site.keywords", "user.eids[source=x]"}},
+				"pubmatic": {},
+			},
+			expected: []openrtb_ext.ExtResponseDebugDataMinimization{
+				{Bidder: "appnexus", Stripped: []string{"site.keywords", "user.eids[source=x]"}},
+				{Bidder: "rubicon", Stripped: []string{"device.ua"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, buildDataMinimizationDebug(test.adapterExtra))
+		})
+	}
+}
+
 func TestBuildMultiBidMap(t *testing.T) {
 	type testCase struct {
 		desc     string
@@ -6352,3 +6643,251 @@ type mockRequestValidator struct {
 func (mrv *mockRequestValidator) ValidateImp(imp *openrtb_ext.ImpWrapper, cfg ortb.ValidationConfig, index int, aliases map[string]string, hasStoredResponses bool, storedBidResponses stored_responses.ImpBidderStoredResp) []error {
 	return mrv.errors
 }
+
+func TestAppendBiddersWithBids(t *testing.T) {
+	testCases := []struct {
+		description          string
+		liveAdapters         []openrtb_ext.BidderName
+		adapterBids          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		expectedLiveAdapters []openrtb_ext.BidderName
+	}{
+		{
+			description:          "No bids",
+			liveAdapters:         []openrtb_ext.BidderName{"appnexus"},
+			adapterBids:          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			expectedLiveAdapters: []openrtb_ext.BidderName{"appnexus"},
+		},
+		{
+			description:  "Bids of requested bidder",
+			liveAdapters: []openrtb_ext.BidderName{"appnexus"},
+			adapterBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "1"}}}},
+			},
+			expectedLiveAdapters: []openrtb_ext.BidderName{"appnexus"},
+		},
+		{
+			description:  "Bids of bidder not requested",
+			liveAdapters: nil,
+			adapterBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "1"}}}},
+				"rubicon":  {Bids: []*entities.PbsOrtbBid{}},
+				"openx":    nil,
+			},
+			expectedLiveAdapters: []openrtb_ext.BidderName{"appnexus"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedLiveAdapters, appendBiddersWithBids(test.liveAdapters, test.adapterBids))
+		})
+	}
+}
+
+func TestReadStoredUIDs(t *testing.T) {
+	ctx := context.Background()
+	store := uidstore.NewMemoryStore(1024 * 1024)
+	entry := usersync.UIDEntry{UID: "stored-uid", Expires: time.Now().Add(time.Hour).UTC()}
+	assert.NoError(t, store.Set(ctx, "user", "adnxs", entry))
+
+	tests := []struct {
+		name          string
+		giveStore     usersync.UIDStore
+		giveStoreID   string
+		wantNil       bool
+		wantUID       string
+		wantHasUID    bool
+		wantLiveSyncs bool
+	}{
+		{
+			name:        "no_store",
+			giveStore:   nil,
+			giveStoreID: "user",
+			wantNil:     true,
+		},
+		{
+			name:        "no_store_id",
+			giveStore:   store,
+			giveStoreID: "",
+			wantNil:     true,
+		},
+		{
+			name:          "unknown_user",
+			giveStore:     store,
+			giveStoreID:   "other-user",
+			wantHasUID:    false,
+			wantLiveSyncs: false,
+		},
+		{
+			name:          "stored_uids",
+			giveStore:     store,
+			giveStoreID:   "user",
+			wantUID:       "stored-uid",
+			wantHasUID:    true,
+			wantLiveSyncs: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &exchange{uidStore: tt.giveStore, uidStoreTimeout: time.Second}
+			got := e.readStoredUIDs(ctx, &AuctionRequest{UIDStoreID: tt.giveStoreID})
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+			uid, hasUID, _ := got.GetUID("adnxs")
+			assert.Equal(t, tt.wantUID, uid)
+			assert.Equal(t, tt.wantHasUID, hasUID)
+			assert.Equal(t, tt.wantLiveSyncs, got.HasAnyLiveSyncs())
+		})
+	}
+}
+
+func TestReadStoredUIDsLazy(t *testing.T) {
+	store := &countingUIDStore{UIDStore: uidstore.NewMemoryStore(1024 * 1024)}
+	e := &exchange{uidStore: store}
+	storedUIDs := e.readStoredUIDs(context.Background(), &AuctionRequest{UIDStoreID: "user"})
+	assert.Zero(t, store.gets, "The store must not be read before a UID is needed.")
+
+	cookie := usersync.NewCookie()
+	assert.NoError(t, cookie.Sync("adnxs", "cookie-uid"))
+	req := &openrtb2.BidRequest{}
+	assert.True(t, prepareUser(req, "appnexus", "adnxs", nil, cookie, storedUIDs))
+	assert.Equal(t, "cookie-uid", req.User.BuyerUID)
+	assert.Zero(t, store.gets, "The store must not be read when the cookie holds the UID.")
+
+	assert.False(t, prepareUser(&openrtb2.BidRequest{}, "pubmatic", "pubmatic", nil, cookie, storedUIDs))
+	assert.False(t, prepareUser(&openrtb2.BidRequest{}, "rubicon", "rubicon", nil, cookie, storedUIDs))
+	assert.Equal(t, 1, store.gets, "The store must be read once.")
+}
+
+// countingUIDStore counts the reads of the wrapped store.
+type countingUIDStore struct {
+	usersync.UIDStore
+	gets int
+}
+
+func (s *countingUIDStore) Get(ctx context.Context, id string) (map[string]usersync.UIDEntry, error) {
+	s.gets++
+	return s.UIDStore.Get(ctx, id)
+}
+
+func TestRecordSyncValues(t *testing.T) {
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{BuyerUID: "appnexus-uid"}}},
+		{BidderName: "rubicon", BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{BuyerUID: "rubicon-uid"}}},
+		{BidderName: "pubmatic", BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{}}},
+		{BidderName: "openx", BidRequest: &openrtb2.BidRequest{}},
+	}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid1", Price: 1.5}},
+			{Bid: &openrtb2.Bid{ID: "bid2", Price: 2.5}},
+		}},
+		"pubmatic": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid3", Price: 3}},
+		}},
+	}
+
+	testCases := []struct {
+		description      string
+		givenDevice      *openrtb2.Device
+		expectedRecorded []recordedSyncValue
+	}{
+		{
+			description: "with-country",
+			givenDevice: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+			expectedRecorded: []recordedSyncValue{
+				{account: "some-account", country: "USA", bidder: "appnexus", value: 2.5},
+				{account: "some-account", country: "USA", bidder: "rubicon", value: 0},
+			},
+		},
+		{
+			description: "without-country",
+			givenDevice: nil,
+			expectedRecorded: []recordedSyncValue{
+				{account: "some-account", country: "", bidder: "appnexus", value: 2.5},
+				{account: "some-account", country: "", bidder: "rubicon", value: 0},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &fakeSyncValueRecorder{}
+			e := &exchange{syncValueRecorder: recorder}
+			r := &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.givenDevice}},
+				Account:           config.Account{ID: "some-account"},
+			}
+
+			e.recordSyncValues(r, bidderRequests, adapterBids)
+			assert.Equal(t, test.expectedRecorded, recorder.recorded)
+		})
+	}
+}
+
+type recordedSyncValue struct {
+	account string
+	country string
+	bidder  string
+	value   float64
+}
+
+type fakeSyncValueRecorder struct {
+	recorded []recordedSyncValue
+}
+
+func (r *fakeSyncValueRecorder) RecordSyncValue(account, country, bidder string, value float64) {
+	r.recorded = append(r.recorded, recordedSyncValue{account: account, country: country, bidder: bidder, value: value})
+}
+
+func TestAddSeatBids(t *testing.T) {
+	bid1 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "1"}}
+	bid2 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "2"}}
+
+	testCases := []struct {
+		description          string
+		adapterBids          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		seatBids             map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		adapterExtra         map[openrtb_ext.BidderName]*seatResponseExtra
+		expectedAdapterBids  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		expectedAdapterExtra map[openrtb_ext.BidderName]*seatResponseExtra
+	}{
+		{
+			description:          "No seat bids",
+			adapterBids:          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Bids: []*entities.PbsOrtbBid{bid1}}},
+			seatBids:             nil,
+			adapterExtra:         map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {ResponseTimeMillis: 5}},
+			expectedAdapterBids:  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Bids: []*entities.PbsOrtbBid{bid1}}},
+			expectedAdapterExtra: map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {ResponseTimeMillis: 5}},
+		},
+		{
+			description:          "Seat bids of bidder with bids",
+			adapterBids:          map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Bids: []*entities.PbsOrtbBid{bid1}}},
+			seatBids:             map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Bids: []*entities.PbsOrtbBid{bid2}}},
+			adapterExtra:         map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {ResponseTimeMillis: 5}},
+			expectedAdapterBids:  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Bids: []*entities.PbsOrtbBid{bid1, bid2}}},
+			expectedAdapterExtra: map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {ResponseTimeMillis: 5}},
+		},
+		{
+			description: "Seat bids of bidder without bids",
+			adapterBids: nil,
+			seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Seat: "appnexus", Currency: "USD", Bids: []*entities.PbsOrtbBid{bid2}},
+				"rubicon":  {Seat: "rubicon"},
+			},
+			expectedAdapterBids:  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Seat: "appnexus", Currency: "USD", Bids: []*entities.PbsOrtbBid{bid2}}},
+			expectedAdapterExtra: map[openrtb_ext.BidderName]*seatResponseExtra{"appnexus": {}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			adapterBids, adapterExtra := addSeatBids(test.adapterBids, test.adapterExtra, test.seatBids)
+			assert.Equal(t, test.expectedAdapterBids, adapterBids)
+			assert.Equal(t, test.expectedAdapterExtra, adapterExtra)
+		})
+	}
+}
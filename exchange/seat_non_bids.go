@@ -40,6 +40,19 @@ func (snb *nonBids) addBid(bid *entities.PbsOrtbBid, nonBidReason int, seat stri
 	snb.seatNonBidsMap[seat] = append(snb.seatNonBidsMap[seat], nonBid)
 }
 
+// append adds non-bids collected outside of the exchange, e.g. by hooks. It is not thread safe.
+func (snb *nonBids) append(seatNonBids []openrtb_ext.SeatNonBid) {
+	for _, seatNonBid := range seatNonBids {
+		if len(seatNonBid.NonBid) == 0 {
+			continue
+		}
+		if snb.seatNonBidsMap == nil {
+			snb.seatNonBidsMap = make(map[string][]openrtb_ext.NonBid)
+		}
+		snb.seatNonBidsMap[seatNonBid.Seat] = append(snb.seatNonBidsMap[seatNonBid.Seat], seatNonBid.NonBid...)
+	}
+}
+
 func (snb *nonBids) get() []openrtb_ext.SeatNonBid {
 	if snb == nil {
 		return nil
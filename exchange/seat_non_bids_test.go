@@ -82,6 +82,46 @@ func TestSeatNonBidsGet(t *testing.T) {
 	}
 }
 
+func TestSeatNonBidsAppend(t *testing.T) {
+	tests := []struct {
+		name  string
+		snb   nonBids
+		given []openrtb_ext.SeatNonBid
+		want  map[string][]openrtb_ext.NonBid
+	}{
+		{
+			name:  "nil-seat-nonbids",
+			snb:   nonBids{},
+			given: nil,
+			want:  nil,
+		},
+		{
+			name:  "empty-nonbids-skipped",
+			snb:   nonBids{},
+			given: []openrtb_ext.SeatNonBid{{Seat: "bidder1"}},
+			want:  nil,
+		},
+		{
+			name:  "nonbids-added-to-new-seat",
+			snb:   nonBids{},
+			given: sampleSeatBids("bidder1", 2),
+			want:  sampleSeatNonBidMap("bidder1", 2),
+		},
+		{
+			name:  "nonbids-merged-with-existing-seat",
+			snb:   nonBids{sampleSeatNonBidMap("bidder1", 1)},
+			given: sampleSeatBids("bidder1", 1),
+			want:  sampleSeatNonBidMap("bidder1", 2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.snb.append(tt.given)
+			assert.Equal(t, tt.want, tt.snb.seatNonBidsMap)
+		})
+	}
+}
+
 var sampleSeatNonBidMap = func(seat string, nonBidCount int) map[string][]openrtb_ext.NonBid {
 	nonBids := make([]openrtb_ext.NonBid, 0)
 	for i := 0; i < nonBidCount; i++ {
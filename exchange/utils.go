@@ -8,13 +8,16 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"time"
 
+	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/go-gdpr/vendorconsent"
 	gpplib "github.com/prebid/go-gpp"
 	gppConstants "github.com/prebid/go-gpp/constants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/firstpartydata"
 	"github.com/prebid/prebid-server/v2/gdpr"
@@ -22,10 +25,15 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
 	"github.com/prebid/prebid-server/v2/privacy/ccpa"
+	"github.com/prebid/prebid-server/v2/privacy/gpc"
+	"github.com/prebid/prebid-server/v2/privacy/kanonymity"
 	"github.com/prebid/prebid-server/v2/privacy/lmt"
+	"github.com/prebid/prebid-server/v2/privacy/minimization"
 	"github.com/prebid/prebid-server/v2/schain"
 	"github.com/prebid/prebid-server/v2/stored_responses"
+	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 )
@@ -50,6 +58,15 @@ type requestSplitter struct {
 	hostSChainNode    *openrtb2.SupplyChainNode
 	bidderInfo        config.BidderInfos
 	requestValidator  ortb.RequestValidator
+	// segmentPopulations holds the audience populations used by the account k-anonymity settings
+	segmentPopulations *kanonymity.FilePopulations
+	// dataMinimizationProfiles holds the host data minimization profiles by bidder
+	dataMinimizationProfiles map[string]*minimization.Profile
+	// dsaTracker holds the bidders disabled by the account DSA bidder enforcement
+	dsaTracker *dsa.Tracker
+	// idMatcher fills the buyer UIDs of bidders matched to the user through partner match tables
+	idMatcher        usersync.IDMatcher
+	hostCookieFamily string
 }
 
 // cleanOpenRTBRequests splits the input request into requests which are sanitized for each bidder. Intended behavior is:
@@ -95,8 +112,8 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	allBidderRequests = mergeBidderRequests(allBidderRequests, bidderNameToBidderReq)
 
 	var gpp gpplib.GppContainer
+	var gppErrs []error
 	if req.BidRequest.Regs != nil && len(req.BidRequest.Regs.GPP) > 0 {
-		var gppErrs []error
 		gpp, gppErrs = gpplib.Parse(req.BidRequest.Regs.GPP)
 		if len(gppErrs) > 0 {
 			errs = append(errs, gppErrs[0])
@@ -120,6 +137,11 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 
 	lmtEnforcer := extractLMT(req.BidRequest, rs.privacyConfig)
 
+	gpcEnforcer, err := extractGPC(req, auctionReq.GlobalPrivacyControlHeader, rs.privacyConfig, &auctionReq.Account)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	// request level privacy policies
 	coppa := req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1
 	lmt := lmtEnforcer.ShouldEnforce(unknownBidder)
@@ -128,6 +150,12 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	privacyLabels.CCPAEnforced = ccpaEnforcer.ShouldEnforce(unknownBidder)
 	privacyLabels.COPPAEnforced = coppa
 	privacyLabels.LMTEnforced = lmt
+	privacyLabels.GPCProvided = gpcEnforcer.CanEnforce()
+	privacyLabels.GPCEnforced = gpcEnforcer.ShouldEnforce(unknownBidder)
+
+	if auctionReq.PrivacyAudit != nil {
+		auctionReq.PrivacyAudit.SetSignals(buildPrivacyAuditSignals(req.BidRequest, gdprSignal, gdprEnforced, consent, gpp, len(gppErrs) == 0, gpcEnforcer.CanEnforce(), coppa, lmt))
+	}
 
 	var gdprPerms gdpr.Permissions = &gdpr.AlwaysAllow{}
 
@@ -140,15 +168,27 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		}
 
 		gdprRequestInfo := gdpr.RequestInfo{
-			AliasGVLIDs: requestAliasesGVLIDs,
-			Consent:     consent,
-			GDPRSignal:  gdprSignal,
-			PublisherID: auctionReq.LegacyLabels.PubID,
+			AliasGVLIDs:  requestAliasesGVLIDs,
+			Consent:      consent,
+			GDPRSignal:   gdprSignal,
+			PublisherID:  auctionReq.LegacyLabels.PubID,
+			Explanations: auctionReq.TCF2Explanations,
 		}
 		gdprPerms = rs.gdprPermsBuilder(auctionReq.TCF2Config, gdprRequestInfo)
 	}
 
 	// bidder level privacy policies
+	dsaEnforcement := dsaBidderEnforcement(auctionReq.Account)
+	dsaRequired := dsaEnforcement.Enabled && dsa.IsRequired(req)
+	now := time.Now()
+
+	// the privacy warnings which are only useful to debug a request are returned if debug is allowed
+	var requestExtPrebid *openrtb_ext.ExtRequestPrebid
+	if requestExt != nil {
+		requestExtPrebid = &requestExt.Prebid
+	}
+	debugWarnings := auctionReq.Account.DebugAllow && parseRequestDebugValues(req.Test, requestExtPrebid)
+
 	for _, bidderRequest := range allBidderRequests {
 		// fetchBids activity
 		scopedName := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderRequest.BidderName.String()}
@@ -159,6 +199,14 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 			continue
 		}
 
+		if dsaRequired && rs.dsaTracker.Disabled(auctionReq.Account.ID, bidderRequest.BidderName.String(), now, dsaEnforcement) {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("bidder %s is disabled for requests requiring DSA because too many of its bids failed DSA validation", bidderRequest.BidderName),
+				WarningCode: errortypes.BidderDisabledDSAWarningCode,
+			})
+			continue
+		}
+
 		var auctionPermissions gdpr.AuctionPermissions
 		var gdprErr error
 
@@ -181,25 +229,50 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 			BidRequest: ortb.CloneBidRequestPartial(bidderRequest.BidRequest),
 		}
 
+		// audience segments are minimized after FPD is applied so bidder specific user data is covered too
+		if minimizeUserSegments(reqWrapper.BidRequest, bidderRequest.BidderName.String(), auctionReq.Account.Privacy.KAnonymity, rs.segmentPopulations.Load()) {
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubUserSegments, audit.ReasonKAnonymity)
+			if debugWarnings {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("user segments below the account k-anonymity threshold were removed from the request of bidder %s", bidderRequest.BidderName),
+					WarningCode: errortypes.KAnonymityScrubWarningCode,
+				})
+			}
+		}
+
+		ccpaEnforced := ccpaEnforcer.ShouldEnforce(bidderRequest.BidderName.String())
+		gpcEnforced := gpcEnforcer.ShouldEnforce(bidderRequest.BidderName.String())
+
 		passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scopedName, privacy.NewRequestFromBidRequest(*req))
 		buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
 		buyerUIDRemoved := false
 		if !passIDActivityAllowed {
 			privacy.ScrubUserFPD(reqWrapper)
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubUserFPD, privacy.ActivityTransmitUserFPD.String())
 			buyerUIDRemoved = true
 		} else {
 			// run existing policies (GDPR, CCPA, COPPA, LMT)
 			// potentially block passing IDs based on GDPR
 			if gdprEnforced && (gdprErr != nil || !auctionPermissions.PassID) {
 				privacy.ScrubGdprID(reqWrapper)
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubGdprID, audit.ReasonGDPR)
 				buyerUIDRemoved = true
 			}
-			// potentially block passing IDs based on CCPA
-			if ccpaEnforcer.ShouldEnforce(bidderRequest.BidderName.String()) {
+			// potentially block passing IDs based on CCPA or the Global Privacy Control signal
+			if ccpaEnforced || gpcEnforced {
 				privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubDeviceIDsIPsUserDemoExt, optOutScrubReason(ccpaEnforced))
 				buyerUIDRemoved = true
 			}
 		}
+		passEIDsAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitEIDs, scopedName, privacy.NewRequestFromBidRequest(*req))
+		if !passEIDsAllowed {
+			if err := privacy.ScrubEIDs(reqWrapper); err != nil {
+				errs = append(errs, err)
+			}
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubEIDs, privacy.ActivityTransmitEIDs.String())
+		}
+
 		if buyerUIDSet && buyerUIDRemoved {
 			rs.me.RecordAdapterBuyerUIDScrubbed(bidderRequest.BidderCoreName)
 		}
@@ -207,25 +280,45 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scopedName, privacy.NewRequestFromBidRequest(*req))
 		if !passGeoActivityAllowed {
 			privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubGeoAndDeviceIP, privacy.ActivityTransmitPreciseGeo.String())
 		} else {
 			// run existing policies (GDPR, CCPA, COPPA, LMT)
 			// potentially block passing geo based on GDPR
 			if gdprEnforced && (gdprErr != nil || !auctionPermissions.PassGeo) {
 				privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubGeoAndDeviceIP, audit.ReasonGDPR)
 			}
-			// potentially block passing geo based on CCPA
-			if ccpaEnforcer.ShouldEnforce(bidderRequest.BidderName.String()) {
+			// potentially block passing geo based on CCPA or the Global Privacy Control signal
+			if ccpaEnforced || gpcEnforced {
 				privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubDeviceIDsIPsUserDemoExt, optOutScrubReason(ccpaEnforced))
 			}
 		}
 
 		if lmt || coppa {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
+			if coppa {
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubDeviceIDsIPsUserDemoExt, audit.ReasonCOPPA)
+			} else {
+				auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubDeviceIDsIPsUserDemoExt, audit.ReasonLMT)
+			}
+		}
+
+		if coppa && auctionReq.Account.Privacy.ChildDirected.Strict {
+			if err := privacy.ScrubChildDirected(reqWrapper); err != nil {
+				errs = append(errs, err)
+			}
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubChildDirected, audit.ReasonCOPPA)
+		}
+
+		if !buyerUIDRemoved && !lmt && !coppa {
+			rs.matchBuyerUID(reqWrapper, bidderRequest.BidderCoreName, auctionReq, scopedName, req)
 		}
 
 		passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scopedName, privacy.NewRequestFromBidRequest(*req))
 		if !passTIDAllowed {
 			privacy.ScrubTID(reqWrapper)
+			auctionReq.PrivacyAudit.RecordScrub(scopedName, audit.ScrubTID, privacy.ActivityTransmitTIDs.String())
 		}
 
 		err := reqWrapper.RebuildRequest()
@@ -234,6 +327,12 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		}
 		bidderRequest.BidRequest = reqWrapper.BidRequest
 
+		profile, err := rs.dataMinimizationProfile(bidderRequest, auctionReq.Account.Privacy.DataMinimization)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		bidderRequest.DataMinimization = profile
+
 		allowedBidderRequests = append(allowedBidderRequests, bidderRequest)
 
 		// GPP downgrade: always downgrade unless we can confirm GPP is supported
@@ -246,6 +345,99 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	return
 }
 
+// matchBuyerUID fills the buyer UID of a bidder the user hasn't synced with from the partner match tables. A
+// matched UID stands in for a user sync, so it's only passed to bidders allowed to sync the user.
+func (rs *requestSplitter) matchBuyerUID(reqWrapper *openrtb_ext.RequestWrapper, bidder openrtb_ext.BidderName, auctionReq AuctionRequest, scopedName privacy.Component, req *openrtb_ext.RequestWrapper) {
+	if rs.idMatcher == nil || (reqWrapper.User != nil && reqWrapper.User.BuyerUID != "") {
+		return
+	}
+	if !auctionReq.Activities.Allow(privacy.ActivitySyncUser, scopedName, privacy.NewRequestFromBidRequest(*req)) {
+		return
+	}
+
+	var hostID string
+	if auctionReq.UserSyncs != nil {
+		hostCookieFamily := auctionReq.HostCookieFamily
+		if hostCookieFamily == "" {
+			hostCookieFamily = rs.hostCookieFamily
+		}
+		hostID, _, _ = auctionReq.UserSyncs.GetUID(hostCookieFamily)
+	}
+	var eids []openrtb2.EID
+	if reqWrapper.User != nil {
+		eids = reqWrapper.User.EIDs
+	}
+	if uid, ok := rs.idMatcher.Match(bidder.String(), hostID, eids); ok {
+		reqWrapper.User = copyWithBuyerUID(reqWrapper.User, uid)
+	}
+}
+
+// optOutScrubReason reports which US opt-out signal caused a scrub, preferring CCPA when both apply
+func optOutScrubReason(ccpaEnforced bool) string {
+	if ccpaEnforced {
+		return audit.ReasonCCPA
+	}
+	return audit.ReasonGPC
+}
+
+// buildPrivacyAuditSignals describes how the privacy signals of the request were interpreted. Consent strings
+// are only kept as fingerprints.
+func buildPrivacyAuditSignals(req *openrtb2.BidRequest, gdprSignal gdpr.Signal, gdprEnforced bool, consent string, gpp gpplib.GppContainer, gppValid bool, gpcProvided, coppa, lmt bool) audit.Signals {
+	signals := audit.Signals{
+		GDPR: audit.GDPRSignals{
+			Signal:             gdprSignalName(gdprSignal),
+			Enforced:           gdprEnforced,
+			ConsentProvided:    consent != "",
+			ConsentFingerprint: audit.Fingerprint(consent),
+		},
+		GPC:   gpcProvided,
+		COPPA: coppa,
+		LMT:   lmt,
+	}
+
+	if parsedConsent, err := vendorconsent.ParseString(consent); consent != "" && err == nil {
+		signals.GDPR.ConsentValid = true
+		signals.GDPR.TCFVersion = int(parsedConsent.Version())
+		signals.GDPR.CMPID = int(parsedConsent.CmpID())
+		signals.GDPR.VendorListVersion = int(parsedConsent.VendorListVersion())
+		for purpose := consentconstants.Purpose(1); purpose <= consentconstants.Purpose(10); purpose++ {
+			if parsedConsent.PurposeAllowed(purpose) {
+				signals.GDPR.PurposesConsented = append(signals.GDPR.PurposesConsented, int(purpose))
+			}
+		}
+	}
+
+	if req.Regs != nil {
+		signals.USPrivacy = req.Regs.USPrivacy
+
+		if len(req.Regs.GPP) > 0 {
+			signals.GPP = &audit.GPPSignals{
+				Fingerprint: audit.Fingerprint(req.Regs.GPP),
+				Valid:       gppValid,
+			}
+			for _, sectionID := range gpp.SectionTypes {
+				signals.GPP.SectionIDs = append(signals.GPP.SectionIDs, int(sectionID))
+			}
+			for _, sid := range req.Regs.GPPSID {
+				signals.GPP.ApplicableSIDs = append(signals.GPP.ApplicableSIDs, int(sid))
+			}
+		}
+	}
+
+	return signals
+}
+
+func gdprSignalName(signal gdpr.Signal) string {
+	switch signal {
+	case gdpr.SignalYes:
+		return "yes"
+	case gdpr.SignalNo:
+		return "no"
+	default:
+		return "ambiguous"
+	}
+}
+
 func shouldSetLegacyPrivacy(bidderInfo config.BidderInfos, bidder string) bool {
 	binfo, defined := bidderInfo[bidder]
 
@@ -290,6 +482,30 @@ func extractLMT(orig *openrtb2.BidRequest, privacyConfig config.Privacy) privacy
 	}
 }
 
+// extractGPC returns the enforcer of the Global Privacy Control signal, which opts the user out of the
+// sale or sharing of their personal information in the same way as a CCPA opt-out if enforced.
+func extractGPC(req *openrtb_ext.RequestWrapper, header string, privacyConfig config.Privacy, account *config.Account) (privacy.PolicyEnforcer, error) {
+	gpcPolicy, err := gpc.ReadFromRequestWrapper(req, header)
+	gpcEnforcer := privacy.EnabledPolicyEnforcer{
+		Enabled:        account.Privacy.GPC.IsEnforced(privacyConfig.GPC.Enforce),
+		PolicyEnforcer: gpcPolicy,
+	}
+	return gpcEnforcer, err
+}
+
+// recordTCF2LegalBasis records the legal basis of each TCF2 enforcement decision explained for the bidders
+func recordTCF2LegalBasis(me metrics.MetricsEngine, explanations []openrtb_ext.ExtResponseDebugTCF2Bidder) {
+	for _, bidder := range explanations {
+		for _, decision := range bidder.Decisions {
+			me.RecordTCF2LegalBasis(metrics.TCF2LegalBasisLabels{
+				Activity:   metrics.TCF2Activity(decision.Activity),
+				LegalBasis: metrics.TCF2LegalBasis(decision.LegalBasis),
+				Allowed:    decision.Allowed,
+			})
+		}
+	}
+}
+
 func ExtractReqExtBidderParamsMap(bidRequest *openrtb2.BidRequest) (map[string]json.RawMessage, error) {
 	if bidRequest == nil {
 		return nil, errors.New("error bidRequest should not be nil")
@@ -381,7 +597,7 @@ func getAuctionBidderRequests(auctionRequest AuctionRequest,
 		}
 
 		syncerKey := bidderToSyncerKey[string(coreBidder)]
-		if hadSync := prepareUser(&reqCopy, bidder, syncerKey, lowerCaseExplicitBuyerUIDs, auctionRequest.UserSyncs); !hadSync && req.BidRequest.App == nil {
+		if hadSync := prepareUser(&reqCopy, bidder, syncerKey, lowerCaseExplicitBuyerUIDs, auctionRequest.UserSyncs, auctionRequest.StoredUserSyncs); !hadSync && req.BidRequest.App == nil {
 			bidderRequest.BidderLabels.CookieFlag = metrics.CookieFlagNo
 		} else {
 			bidderRequest.BidderLabels.CookieFlag = metrics.CookieFlagYes
@@ -691,9 +907,13 @@ func createSanitizedImpExt(impExt, impExtPrebid map[string]json.RawMessage) (map
 // This *will* mutate the request, but will *not* mutate any objects nested inside it.
 //
 // In this function, "givenBidder" may or may not be an alias. "coreBidder" must *not* be an alias.
-// It returns true if a Cookie User Sync existed, and false otherwise.
-func prepareUser(req *openrtb2.BidRequest, givenBidder, syncerKey string, explicitBuyerUIDs map[string]string, usersyncs IdFetcher) bool {
+// The UID stored server-side for the user is used when the cookie has none for the bidder.
+// It returns true if a Cookie User Sync or a stored UID existed, and false otherwise.
+func prepareUser(req *openrtb2.BidRequest, givenBidder, syncerKey string, explicitBuyerUIDs map[string]string, usersyncs IdFetcher, storedUserSyncs IdFetcher) bool {
 	cookieId, hadCookie, _ := usersyncs.GetUID(syncerKey)
+	if !hadCookie && storedUserSyncs != nil {
+		cookieId, hadCookie, _ = storedUserSyncs.GetUID(syncerKey)
+	}
 
 	if id, ok := explicitBuyerUIDs[strings.ToLower(givenBidder)]; ok {
 		req.User = copyWithBuyerUID(req.User, id)
@@ -754,15 +974,16 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 	}
 
 	// translate eid permissions to a map for quick lookup
+	// sources are matched the way EIDs are normalized
 	eidRules := make(map[string][]string)
 	for _, p := range requestExt.Prebid.Data.EidPermissions {
-		eidRules[p.Source] = p.Bidders
+		eidRules[normalizeEIDSource(p.Source)] = p.Bidders
 	}
 
 	eidsAllowed := make([]openrtb2.EID, 0, len(eids))
 	for _, eid := range eids {
 		allowed := false
-		if rule, hasRule := eidRules[eid.Source]; hasRule {
+		if rule, hasRule := eidRules[normalizeEIDSource(eid.Source)]; hasRule {
 			for _, ruleBidder := range rule {
 				if ruleBidder == "*" || strings.EqualFold(ruleBidder, bidder) {
 					allowed = true
@@ -808,6 +1029,44 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 	return nil
 }
 
+// minimizeUserSegments drops the user.data segments and user.eids sources which don't meet the account
+// k-anonymity settings for the bidder. Returns true if any was dropped.
+func minimizeUserSegments(request *openrtb2.BidRequest, bidder string, cfg config.AccountKAnonymity, populations *kanonymity.Populations) bool {
+	enabled, minPopulation, maxSegments := cfg.ForBidder(bidder)
+	if !enabled {
+		return false
+	}
+
+	policy := kanonymity.Policy{MinPopulation: minPopulation, MaxSegments: maxSegments}
+	var changed bool
+	request.User, changed = policy.Minimize(request.User, populations)
+	return changed
+}
+
+// dataMinimizationProfile returns the data minimization profile of the bidder, if any. An account profile
+// takes precedence over the host profile. An invalid account profile restricts the bidder to the paths which
+// are always sent.
+func (rs *requestSplitter) dataMinimizationProfile(bidderRequest BidderRequest, accountProfiles map[string]config.AccountDataMinimization) (*minimization.Profile, error) {
+	for bidder, accountProfile := range accountProfiles {
+		if !strings.EqualFold(bidder, bidderRequest.BidderName.String()) {
+			continue
+		}
+		profile, err := minimization.NewProfile(accountProfile.AllowedPaths)
+		if err != nil {
+			profile, _ = minimization.NewProfile(nil)
+			return profile, &errortypes.Warning{
+				Message: fmt.Sprintf("invalid account data minimization profile for bidder %s, only the required fields are sent: %v", bidderRequest.BidderName, err),
+			}
+		}
+		return profile, nil
+	}
+
+	if profile, ok := rs.dataMinimizationProfiles[bidderRequest.BidderName.String()]; ok {
+		return profile, nil
+	}
+	return rs.dataMinimizationProfiles[bidderRequest.BidderCoreName.String()], nil
+}
+
 func setUserExtWithCopy(request *openrtb2.BidRequest, userExtJSON json.RawMessage) {
 	userCopy := *request.User
 	userCopy.Ext = userExtJSON
@@ -5,21 +5,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v2/stored_responses"
 
+	"github.com/prebid/go-gdpr/vendorlist"
 	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/go-gpp/constants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/dsa"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/firstpartydata"
 	"github.com/prebid/prebid-server/v2/gdpr"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/privacy/audit"
+	"github.com/prebid/prebid-server/v2/privacy/kanonymity"
+	"github.com/prebid/prebid-server/v2/privacy/minimization"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -2133,6 +2141,128 @@ func TestGetExtBidAdjustmentFactors(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsGPC(t *testing.T) {
+	testCases := []struct {
+		description         string
+		regsExt             json.RawMessage
+		header              string
+		enforceGPC          bool
+		accountEnforceGPC   *bool
+		expectDataScrub     bool
+		expectPrivacyLabels metrics.PrivacyLabels
+	}{
+		{
+			description:     "Feature Flag Enabled - Request Opt-Out",
+			regsExt:         json.RawMessage(`{"gpc":"1"}`),
+			enforceGPC:      true,
+			expectDataScrub: true,
+			expectPrivacyLabels: metrics.PrivacyLabels{
+				GPCProvided: true,
+				GPCEnforced: true,
+			},
+		},
+		{
+			description:     "Feature Flag Enabled - Header Opt-Out",
+			header:          "1",
+			enforceGPC:      true,
+			expectDataScrub: true,
+			expectPrivacyLabels: metrics.PrivacyLabels{
+				GPCProvided: true,
+				GPCEnforced: true,
+			},
+		},
+		{
+			description:     "Feature Flag Enabled - No Opt-Out",
+			regsExt:         json.RawMessage(`{"gpc":"0"}`),
+			header:          "1",
+			enforceGPC:      true,
+			expectDataScrub: false,
+			expectPrivacyLabels: metrics.PrivacyLabels{
+				GPCProvided: true,
+				GPCEnforced: false,
+			},
+		},
+		{
+			description:     "Feature Flag Enabled - No Signal",
+			enforceGPC:      true,
+			expectDataScrub: false,
+		},
+		{
+			description:     "Feature Flag Disabled - Request Opt-Out",
+			regsExt:         json.RawMessage(`{"gpc":"1"}`),
+			enforceGPC:      false,
+			expectDataScrub: false,
+			expectPrivacyLabels: metrics.PrivacyLabels{
+				GPCProvided: true,
+				GPCEnforced: false,
+			},
+		},
+		{
+			description:       "Feature Flag Disabled - Account Enabled - Request Opt-Out",
+			regsExt:           json.RawMessage(`{"gpc":"1"}`),
+			enforceGPC:        false,
+			accountEnforceGPC: ptrutil.ToPtr(true),
+			expectDataScrub:   true,
+			expectPrivacyLabels: metrics.PrivacyLabels{
+				GPCProvided: true,
+				GPCEnforced: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		if test.regsExt != nil {
+			req.Regs = &openrtb2.Regs{Ext: test.regsExt}
+		}
+
+		auctionReq := AuctionRequest{
+			BidRequestWrapper:          &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:                  &emptyUsersync{},
+			TCF2Config:                 gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			Account:                    config.Account{Privacy: config.AccountPrivacy{GPC: config.AccountGPC{Enforce: test.accountEnforceGPC}}},
+			GlobalPrivacyControlHeader: test.header,
+		}
+
+		gdprPermissionsBuilder := fakePermissionsBuilder{
+			permissions: &permissionsMock{
+				allowAllBidders: true,
+			},
+		}.Builder
+
+		privacyConfig := config.Privacy{
+			GPC: config.GPC{
+				Enforce: test.enforceGPC,
+			},
+		}
+
+		metricsMock := metrics.MetricsEngineMock{}
+		metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+
+		reqSplitter := &requestSplitter{
+			bidderToSyncerKey: map[string]string{},
+			me:                &metricsMock,
+			privacyConfig:     privacyConfig,
+			gdprPermsBuilder:  gdprPermissionsBuilder,
+			hostSChainNode:    nil,
+			bidderInfo:        config.BidderInfos{},
+		}
+
+		results, privacyLabels, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+		result := results[0]
+
+		assert.Nil(t, errs)
+		if test.expectDataScrub {
+			assert.Equal(t, result.BidRequest.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.Equal(t, result.BidRequest.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		} else {
+			assert.NotEqual(t, result.BidRequest.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.NotEqual(t, result.BidRequest.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		}
+		assert.Equal(t, test.expectPrivacyLabels, privacyLabels, test.description+":PrivacyLabels")
+	}
+}
+
 func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 	var (
 		enabled  int8 = 1
@@ -2442,6 +2572,576 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsTCF2Explanations(t *testing.T) {
+	testCases := []struct {
+		description  string
+		explanations *gdpr.Explanations
+		expected     []openrtb_ext.ExtResponseDebugTCF2Bidder
+	}{
+		{
+			description:  "explain mode off",
+			explanations: nil,
+			expected:     nil,
+		},
+		{
+			description:  "explain mode on",
+			explanations: gdpr.NewExplanations(),
+			expected: []openrtb_ext.ExtResponseDebugTCF2Bidder{
+				{
+					Bidder:   "appnexus",
+					VendorID: 32,
+					Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+						{Activity: "bid_request", Purpose: 2, LegalBasis: "not_enforced", Allowed: true},
+						{Activity: "pass_geo", SpecialFeature: 1, LegalBasis: "no_consent", Allowed: false},
+						{Activity: "pass_id", LegalBasis: "no_consent", Allowed: false},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		req.Regs = &openrtb2.Regs{
+			Ext: json.RawMessage(`{"gdpr":1}`),
+		}
+		req.User.Ext = nil
+
+		privacyConfig := config.Privacy{
+			GDPR: config.GDPR{
+				Enabled:      true,
+				DefaultValue: "1",
+				TCF2: config.TCF2{
+					Enabled:         true,
+					SpecialFeature1: config.TCF2SpecialFeature{Enforce: true},
+				},
+			},
+		}
+		accountConfig := config.Account{}
+
+		auctionReq := AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:         &emptyUsersync{},
+			Account:           accountConfig,
+			TCF2Config:        gdpr.NewTCF2Config(privacyConfig.GDPR.TCF2, accountConfig.GDPR),
+			TCF2Explanations:  test.explanations,
+		}
+
+		vendorIDs := map[openrtb_ext.BidderName]uint16{openrtb_ext.BidderAppnexus: 32}
+		fetcher := func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
+			return nil, errors.New("vendor list not expected to be fetched")
+		}
+
+		metricsMock := metrics.MetricsEngineMock{}
+		metricsMock.Mock.On("RecordAdapterGDPRRequestBlocked", mock.Anything).Return()
+		metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+
+		reqSplitter := &requestSplitter{
+			bidderToSyncerKey: map[string]string{},
+			me:                &metricsMock,
+			privacyConfig:     privacyConfig,
+			gdprPermsBuilder:  gdpr.NewPermissionsBuilder(privacyConfig.GDPR, vendorIDs, fetcher),
+			hostSChainNode:    nil,
+			bidderInfo:        config.BidderInfos{},
+		}
+
+		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalYes, true, map[string]float64{})
+
+		assert.Empty(t, errs, test.description)
+		assert.Len(t, results, 1, test.description)
+		assert.Equal(t, test.expected, test.explanations.Bidders(), test.description)
+	}
+}
+
+func TestCleanOpenRTBRequestsPrivacyAudit(t *testing.T) {
+	req := newBidRequest(t)
+	req.Regs = &openrtb2.Regs{COPPA: 1, Ext: json.RawMessage(`{"gpc":"1"}`)}
+
+	record := audit.NewRecord(time.Time{}, "req", "acct")
+	auctionReq := AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+		UserSyncs:         &emptyUsersync{},
+		TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		Activities:        privacy.ActivityControl{}.WithRecorder(record),
+		PrivacyAudit:      record,
+	}
+
+	gdprPermissionsBuilder := fakePermissionsBuilder{
+		permissions: &permissionsMock{
+			allowAllBidders: true,
+		},
+	}.Builder
+
+	metricsMock := metrics.MetricsEngineMock{}
+	metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+
+	reqSplitter := &requestSplitter{
+		bidderToSyncerKey: map[string]string{},
+		me:                &metricsMock,
+		privacyConfig:     config.Privacy{GPC: config.GPC{Enforce: true}},
+		gdprPermsBuilder:  gdprPermissionsBuilder,
+		hostSChainNode:    nil,
+		bidderInfo:        config.BidderInfos{},
+	}
+
+	_, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+	assert.Empty(t, errs)
+
+	expectedSignals := audit.Signals{
+		GDPR:  audit.GDPRSignals{Signal: "no"},
+		GPC:   true,
+		COPPA: true,
+	}
+	assert.Equal(t, expectedSignals, record.Signals)
+
+	expectedComponents := []*audit.ComponentRecord{
+		{
+			Type: "bidder",
+			Name: "appnexus",
+			Activities: []audit.ActivityDecision{
+				{Activity: "fetchBids", Allowed: true},
+				{Activity: "transmitUfpd", Allowed: true},
+				{Activity: "transmitEids", Allowed: true},
+				{Activity: "transmitPreciseGeo", Allowed: true},
+				{Activity: "transmitTid", Allowed: true},
+			},
+			Scrubs: []audit.ScrubRecord{
+				{Function: audit.ScrubDeviceIDsIPsUserDemoExt, Reason: "gpc"},
+				{Function: audit.ScrubDeviceIDsIPsUserDemoExt, Reason: "coppa"},
+			},
+		},
+	}
+	assert.Equal(t, expectedComponents, record.Components)
+}
+
+func TestCleanOpenRTBRequestsKAnonymity(t *testing.T) {
+	populationsFile := filepath.Join(t.TempDir(), "populations.json")
+	err := os.WriteFile(populationsFile, []byte(`{"segments":{"provider":{"large":50000,"small":10}},"eids":{"eids-source":100000}}`), 0644)
+	require.NoError(t, err)
+	populations, err := kanonymity.NewFilePopulations(config.KAnonymity{PopulationsFile: populationsFile})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description    string
+		kAnonymity     config.AccountKAnonymity
+		debug          bool
+		expectedData   []openrtb2.Data
+		expectedEIDs   []openrtb2.EID
+		expectedScrubs []audit.ScrubRecord
+		expectedErrs   []error
+	}{
+		{
+			description:  "disabled",
+			kAnonymity:   config.AccountKAnonymity{Enabled: false, MinPopulation: 1000},
+			expectedData: []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}, {ID: "small"}}}},
+			expectedEIDs: []openrtb2.EID{{Source: "eids-source"}, {Source: "rare-source"}},
+		},
+		{
+			description:    "enabled",
+			kAnonymity:     config.AccountKAnonymity{Enabled: true, MinPopulation: 1000},
+			expectedData:   []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}}}},
+			expectedEIDs:   []openrtb2.EID{{Source: "eids-source"}},
+			expectedScrubs: []audit.ScrubRecord{{Function: audit.ScrubUserSegments, Reason: "kanonymity"}},
+		},
+		{
+			description:    "enabled-debug",
+			kAnonymity:     config.AccountKAnonymity{Enabled: true, MinPopulation: 1000},
+			debug:          true,
+			expectedData:   []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}}}},
+			expectedEIDs:   []openrtb2.EID{{Source: "eids-source"}},
+			expectedScrubs: []audit.ScrubRecord{{Function: audit.ScrubUserSegments, Reason: "kanonymity"}},
+			expectedErrs: []error{&errortypes.Warning{
+				Message:     "user segments below the account k-anonymity threshold were removed from the request of bidder appnexus",
+				WarningCode: errortypes.KAnonymityScrubWarningCode,
+			}},
+		},
+		{
+			description: "disabled-for-bidder",
+			kAnonymity: config.AccountKAnonymity{
+				Enabled:       true,
+				MinPopulation: 1000,
+				Bidders:       map[string]config.AccountKAnonymityBidder{"appnexus": {Enabled: ptrutil.ToPtr(false)}},
+			},
+			expectedData: []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}, {ID: "small"}}}},
+			expectedEIDs: []openrtb2.EID{{Source: "eids-source"}, {Source: "rare-source"}},
+		},
+		{
+			description: "bidder-max-segments",
+			kAnonymity: config.AccountKAnonymity{
+				Enabled: true,
+				Bidders: map[string]config.AccountKAnonymityBidder{"appnexus": {MaxSegments: ptrutil.ToPtr(1)}},
+			},
+			expectedData:   []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}}}},
+			expectedEIDs:   []openrtb2.EID{{Source: "eids-source"}, {Source: "rare-source"}},
+			expectedScrubs: []audit.ScrubRecord{{Function: audit.ScrubUserSegments, Reason: "kanonymity"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := newBidRequest(t)
+			req.User.Data = []openrtb2.Data{{ID: "provider", Segment: []openrtb2.Segment{{ID: "large"}, {ID: "small"}}}}
+			req.User.EIDs = []openrtb2.EID{{Source: "eids-source"}, {Source: "rare-source"}}
+			if test.debug {
+				req.Test = 1
+			}
+
+			record := audit.NewRecord(time.Time{}, "req", "acct")
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+				UserSyncs:         &emptyUsersync{},
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+				Account:           config.Account{DebugAllow: true, Privacy: config.AccountPrivacy{KAnonymity: test.kAnonymity}},
+				PrivacyAudit:      record,
+			}
+
+			gdprPermissionsBuilder := fakePermissionsBuilder{
+				permissions: &permissionsMock{
+					allowAllBidders: true,
+				},
+			}.Builder
+
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey:  map[string]string{},
+				me:                 &metrics.MetricsEngineMock{},
+				privacyConfig:      config.Privacy{},
+				gdprPermsBuilder:   gdprPermissionsBuilder,
+				hostSChainNode:     nil,
+				bidderInfo:         config.BidderInfos{},
+				segmentPopulations: populations,
+			}
+
+			results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+			assert.Equal(t, test.expectedErrs, errs)
+			assert.Len(t, results, 1)
+			assert.Equal(t, test.expectedData, results[0].BidRequest.User.Data)
+			assert.Equal(t, test.expectedEIDs, results[0].BidRequest.User.EIDs)
+			assert.Len(t, req.User.Data[0].Segment, 2, "original request must not be modified")
+			assert.Len(t, req.User.EIDs, 2, "original request must not be modified")
+
+			var scrubs []audit.ScrubRecord
+			for _, component := range record.Components {
+				scrubs = append(scrubs, component.Scrubs...)
+			}
+			assert.Equal(t, test.expectedScrubs, scrubs)
+		})
+	}
+}
+
+func TestCleanOpenRTBRequestsDSABidderEnforcement(t *testing.T) {
+	enforcement := config.AccountDSABidderEnforcement{Enabled: true, MaxFailureRate: 0.5, MinBids: 1}
+
+	testCases := []struct {
+		description       string
+		regsExt           json.RawMessage
+		enforcement       config.AccountDSABidderEnforcement
+		expectedBidders   int
+		expectedErrorCode int
+	}{
+		{
+			description:       "dsa-required",
+			regsExt:           json.RawMessage(`{"dsa":{"dsarequired":2}}`),
+			enforcement:       enforcement,
+			expectedBidders:   0,
+			expectedErrorCode: errortypes.BidderDisabledDSAWarningCode,
+		},
+		{
+			description:     "dsa-not-required",
+			regsExt:         json.RawMessage(`{"dsa":{"dsarequired":1}}`),
+			enforcement:     enforcement,
+			expectedBidders: 1,
+		},
+		{
+			description:     "enforcement-disabled",
+			regsExt:         json.RawMessage(`{"dsa":{"dsarequired":2}}`),
+			enforcement:     config.AccountDSABidderEnforcement{},
+			expectedBidders: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := newBidRequest(t)
+			req.Regs = &openrtb2.Regs{Ext: test.regsExt}
+
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+				UserSyncs:         &emptyUsersync{},
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+				Account: config.Account{
+					ID:      "some-account",
+					Privacy: config.AccountPrivacy{DSA: &config.AccountDSA{BidderEnforcement: test.enforcement}},
+				},
+			}
+
+			gdprPermissionsBuilder := fakePermissionsBuilder{
+				permissions: &permissionsMock{
+					allowAllBidders: true,
+				},
+			}.Builder
+
+			tracker := dsa.NewTracker()
+			tracker.Record("some-account", "appnexus", 1, 1, time.Now(), enforcement)
+
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey: map[string]string{},
+				me:                &metrics.MetricsEngineMock{},
+				privacyConfig:     config.Privacy{},
+				gdprPermsBuilder:  gdprPermissionsBuilder,
+				hostSChainNode:    nil,
+				bidderInfo:        config.BidderInfos{},
+				dsaTracker:        tracker,
+			}
+
+			results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+			assert.Len(t, results, test.expectedBidders)
+			if test.expectedErrorCode != 0 {
+				require.Len(t, errs, 1)
+				assert.Equal(t, test.expectedErrorCode, errortypes.ReadCode(errs[0]))
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
+
+func TestCleanOpenRTBRequestsChildDirectedStrict(t *testing.T) {
+	testCases := []struct {
+		description  string
+		coppa        int8
+		strict       bool
+		expectedUser *openrtb2.User
+	}{
+		{
+			description:  "not-coppa",
+			coppa:        0,
+			strict:       true,
+			expectedUser: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "source"}}, Data: []openrtb2.Data{{ID: "data"}}},
+		},
+		{
+			description:  "coppa",
+			coppa:        1,
+			strict:       false,
+			expectedUser: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "source"}}, Data: []openrtb2.Data{{ID: "data"}}},
+		},
+		{
+			description:  "coppa-strict",
+			coppa:        1,
+			strict:       true,
+			expectedUser: &openrtb2.User{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := newBidRequest(t)
+			req.Regs = &openrtb2.Regs{COPPA: test.coppa}
+			req.User = &openrtb2.User{EIDs: []openrtb2.EID{{Source: "source"}}, Data: []openrtb2.Data{{ID: "data"}}}
+
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+				UserSyncs:         &emptyUsersync{},
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+				Account:           config.Account{Privacy: config.AccountPrivacy{ChildDirected: config.AccountChildDirected{Strict: test.strict}}},
+			}
+
+			gdprPermissionsBuilder := fakePermissionsBuilder{
+				permissions: &permissionsMock{
+					allowAllBidders: true,
+				},
+			}.Builder
+
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey: map[string]string{},
+				me:                &metrics.MetricsEngineMock{},
+				privacyConfig:     config.Privacy{},
+				gdprPermsBuilder:  gdprPermissionsBuilder,
+				hostSChainNode:    nil,
+				bidderInfo:        config.BidderInfos{},
+			}
+
+			results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+			assert.Empty(t, errs)
+			require.Len(t, results, 1)
+			assert.Equal(t, test.expectedUser, results[0].BidRequest.User)
+		})
+	}
+}
+
+func TestDataMinimizationProfile(t *testing.T) {
+	hostProfiles := map[string]*minimization.Profile{}
+	for bidder, paths := range map[string][]string{"appnexus": {"site.page"}, "rubicon": {"device.ua"}} {
+		profile, err := minimization.NewProfile(paths)
+		require.NoError(t, err)
+		hostProfiles[bidder] = profile
+	}
+
+	testCases := []struct {
+		description      string
+		bidderRequest    BidderRequest
+		accountProfiles  map[string]config.AccountDataMinimization
+		expectedProfile  bool
+		expectedStripped []string
+		expectedErr      bool
+	}{
+		{
+			description:     "no-profile",
+			bidderRequest:   BidderRequest{BidderName: "pubmatic", BidderCoreName: "pubmatic"},
+			expectedProfile: false,
+		},
+		{
+			description:      "host-profile",
+			bidderRequest:    BidderRequest{BidderName: "appnexus", BidderCoreName: "appnexus"},
+			expectedProfile:  true,
+			expectedStripped: []string{"device"},
+		},
+		{
+			description:      "host-profile-of-core-bidder",
+			bidderRequest:    BidderRequest{BidderName: "alias", BidderCoreName: "rubicon"},
+			expectedProfile:  true,
+			expectedStripped: []string{"site"},
+		},
+		{
+			description:      "account-profile-overrides-host-profile",
+			bidderRequest:    BidderRequest{BidderName: "appnexus", BidderCoreName: "appnexus"},
+			accountProfiles:  map[string]config.AccountDataMinimization{"AppNexus": {AllowedPaths: []string{"device.ua"}}},
+			expectedProfile:  true,
+			expectedStripped: []string{"site"},
+		},
+		{
+			description:      "invalid-account-profile",
+			bidderRequest:    BidderRequest{BidderName: "appnexus", BidderCoreName: "appnexus"},
+			accountProfiles:  map[string]config.AccountDataMinimization{"appnexus": {AllowedPaths: []string{""}}},
+			expectedProfile:  true,
+			expectedStripped: []string{"device", "site"},
+			expectedErr:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			rs := &requestSplitter{dataMinimizationProfiles: hostProfiles}
+
+			profile, err := rs.dataMinimizationProfile(test.bidderRequest, test.accountProfiles)
+			if test.expectedErr {
+				assert.IsType(t, &errortypes.Warning{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !test.expectedProfile {
+				assert.Nil(t, profile)
+				return
+			}
+			require.NotNil(t, profile)
+
+			request := &openrtb2.BidRequest{
+				ID:     "some-request-id",
+				Site:   &openrtb2.Site{Page: "https://example.com"},
+				Device: &openrtb2.Device{UA: "some-ua"},
+			}
+			_, stripped, err := profile.Apply(request)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedStripped, stripped)
+		})
+	}
+}
+
+func TestBuildPrivacyAuditSignals(t *testing.T) {
+	testCases := []struct {
+		description string
+		req         *openrtb2.BidRequest
+		gdprSignal  gdpr.Signal
+		consent     string
+		gppValid    bool
+		expected    audit.Signals
+	}{
+		{
+			description: "no-signals",
+			req:         &openrtb2.BidRequest{},
+			gdprSignal:  gdpr.SignalAmbiguous,
+			expected:    audit.Signals{GDPR: audit.GDPRSignals{Signal: "ambiguous"}},
+		},
+		{
+			description: "invalid-consent",
+			req:         &openrtb2.BidRequest{},
+			gdprSignal:  gdpr.SignalYes,
+			consent:     "invalid",
+			expected: audit.Signals{
+				GDPR: audit.GDPRSignals{Signal: "yes", ConsentProvided: true, ConsentFingerprint: audit.Fingerprint("invalid")},
+			},
+		},
+		{
+			description: "tcf2-consent-usp-and-gpp",
+			req: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{
+					USPrivacy: "1YNN",
+					GPP:       "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1NYN",
+					GPPSID:    []int8{2, 6},
+				},
+			},
+			gdprSignal: gdpr.SignalYes,
+			consent:    "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+			gppValid:   true,
+			expected: audit.Signals{
+				GDPR: audit.GDPRSignals{
+					Signal:             "yes",
+					ConsentProvided:    true,
+					ConsentValid:       true,
+					ConsentFingerprint: audit.Fingerprint("CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"),
+					TCFVersion:         2,
+					CMPID:              31,
+					VendorListVersion:  126,
+				},
+				GPP: &audit.GPPSignals{
+					Fingerprint:    audit.Fingerprint("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1NYN"),
+					Valid:          true,
+					SectionIDs:     []int{2, 6},
+					ApplicableSIDs: []int{2, 6},
+				},
+				USPrivacy: "1YNN",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var gpp gpplib.GppContainer
+			if test.req.Regs != nil && test.req.Regs.GPP != "" {
+				gpp, _ = gpplib.Parse(test.req.Regs.GPP)
+			}
+			signals := buildPrivacyAuditSignals(test.req, test.gdprSignal, false, test.consent, gpp, test.gppValid, false, false, false)
+			assert.Equal(t, test.expected, signals)
+		})
+	}
+}
+
+func TestRecordTCF2LegalBasis(t *testing.T) {
+	explanations := []openrtb_ext.ExtResponseDebugTCF2Bidder{
+		{
+			Bidder: "appnexus",
+			Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+				{Activity: "bid_request", Purpose: 2, LegalBasis: "consent", Allowed: true},
+				{Activity: "pass_geo", SpecialFeature: 1, LegalBasis: "none", Allowed: false},
+			},
+		},
+		{
+			Bidder: "rubicon",
+			Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+				{Activity: "bid_request", Purpose: 2, LegalBasis: "consent", Allowed: true},
+			},
+		},
+	}
+
+	metricsMock := metrics.MetricsEngineMock{}
+	metricsMock.Mock.On("RecordTCF2LegalBasis", mock.Anything).Return()
+
+	recordTCF2LegalBasis(&metricsMock, explanations)
+
+	metricsMock.AssertNumberOfCalls(t, "RecordTCF2LegalBasis", 3)
+	metricsMock.AssertCalled(t, "RecordTCF2LegalBasis", metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityBidRequest, LegalBasis: metrics.TCF2LegalBasisConsent, Allowed: true})
+	metricsMock.AssertCalled(t, "RecordTCF2LegalBasis", metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityPassGeo, LegalBasis: metrics.TCF2LegalBasisNone, Allowed: false})
+}
+
 func TestCleanOpenRTBRequestsWithOpenRTBDowngrade(t *testing.T) {
 	emptyTCF2Config := gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})
 
@@ -3002,6 +3702,14 @@ func TestRemoveUnpermissionedEids(t *testing.T) {
 			},
 			expectedUserExt: json.RawMessage(`{"eids":[{"source":"source1","uids":[{"id":"anyID1"}]},{"source":"source2","uids":[{"id":"anyID2"}]}],"other":42}`),
 		},
+		{
+			description: "Denied - Source Matched Case Insensitive",
+			userExt:     json.RawMessage(`{"eids":[{"source":"source1.com","uids":[{"id":"anyID"}]}]}`),
+			eidPermissions: []openrtb_ext.ExtRequestPrebidDataEidPermission{
+				{Source: "Source1.com", Bidders: []string{"otherBidder"}},
+			},
+			expectedUserExt: nil,
+		},
 	}
 
 	for _, test := range testCases {
@@ -4818,6 +5526,24 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			},
 			expectedSource: expectedSourceDefault,
 		},
+		{
+			//remove user.eids and user.ext.eids
+			name:              "transmit_eids_deny",
+			req:               newBidRequest(t),
+			privacyConfig:     getTransmitEIDsActivityConfig("appnexus", false),
+			expectedReqNumber: 1,
+			expectedUser: openrtb2.User{
+				ID:       "our-id",
+				BuyerUID: "their-id",
+				Yob:      1982,
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+				Gender:   "test",
+				Ext:      json.RawMessage(`{"data": 1, "test": 2}`),
+				Data:     []openrtb2.Data{{ID: "data-id"}},
+			},
+			expectedDevice: expectedDeviceDefault,
+			expectedSource: expectedSourceDefault,
+		},
 		{
 			name:              "transmit_tid_allowed",
 			req:               newBidRequest(t),
@@ -4932,6 +5658,14 @@ func getTransmitPreciseGeoActivityConfig(componentName string, allow bool) confi
 	}
 }
 
+func getTransmitEIDsActivityConfig(componentName string, allow bool) config.AccountPrivacy {
+	return config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitEids: buildDefaultActivityConfig(componentName, allow),
+		},
+	}
+}
+
 func getTransmitTIDActivityConfig(componentName string, allow bool) config.AccountPrivacy {
 	return config.AccountPrivacy{
 		AllowActivities: &config.AllowActivities{
@@ -4940,6 +5674,93 @@ func getTransmitTIDActivityConfig(componentName string, allow bool) config.Accou
 	}
 }
 
+func TestCleanOpenRTBRequestsIDMatch(t *testing.T) {
+	testCases := []struct {
+		name             string
+		buyerUID         string
+		lmt              int8
+		privacyConfig    config.AccountPrivacy
+		hostCookieFamily string
+		expectedBuyerUID string
+	}{
+		{
+			name:             "matched",
+			expectedBuyerUID: "matched-uid",
+		},
+		{
+			name:             "matched_resolved_host_cookie",
+			hostCookieFamily: "brand",
+			expectedBuyerUID: "brand-matched-uid",
+		},
+		{
+			name:             "synced_uid_kept",
+			buyerUID:         "their-id",
+			expectedBuyerUID: "their-id",
+		},
+		{
+			name:             "sync_user_allowed",
+			privacyConfig:    config.AccountPrivacy{AllowActivities: &config.AllowActivities{SyncUser: buildDefaultActivityConfig("appnexus", true)}},
+			expectedBuyerUID: "matched-uid",
+		},
+		{
+			name:             "sync_user_deny",
+			privacyConfig:    config.AccountPrivacy{AllowActivities: &config.AllowActivities{SyncUser: buildDefaultActivityConfig("appnexus", false)}},
+			expectedBuyerUID: "",
+		},
+		{
+			name:             "transmit_ufpd_deny",
+			privacyConfig:    getTransmitUFPDActivityConfig("appnexus", false),
+			expectedBuyerUID: "",
+		},
+		{
+			name:             "lmt",
+			lmt:              1,
+			expectedBuyerUID: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := newBidRequest(t)
+			req.User.BuyerUID = test.buyerUID
+			req.Device.Lmt = &test.lmt
+
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+				UserSyncs:         mockIdFetcher{"host": "host-id", "brand": "brand-id"},
+				HostCookieFamily:  test.hostCookieFamily,
+				Activities:        privacy.NewActivityControl(&test.privacyConfig),
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			}
+
+			metricsMock := metrics.MetricsEngineMock{}
+			metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey: map[string]string{},
+				me:                &metricsMock,
+				privacyConfig:     config.Privacy{LMT: config.LMT{Enforce: true}},
+				bidderInfo:        config.BidderInfos{},
+				idMatcher:         fakeIDMatcher{"appnexus": {"host-id": "matched-uid", "brand-id": "brand-matched-uid"}},
+				hostCookieFamily:  "host",
+			}
+
+			bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+			assert.Empty(t, errs)
+			if assert.Len(t, bidderRequests, 1) {
+				assert.Equal(t, test.expectedBuyerUID, bidderRequests[0].BidRequest.User.BuyerUID)
+			}
+		})
+	}
+}
+
+type fakeIDMatcher map[string]map[string]string
+
+func (m fakeIDMatcher) Match(bidder, hostID string, eids []openrtb2.EID) (string, bool) {
+	uid, ok := m[bidder][hostID]
+	return uid, ok
+}
+
 func TestApplyBidAdjustmentToFloor(t *testing.T) {
 	type args struct {
 		allBidderRequests    []BidderRequest
@@ -5298,3 +6119,58 @@ func TestBuildBidResponseRequestBidderName(t *testing.T) {
 	assert.Equal(t, resultAppneXUS.ImpReplaceImpId, map[string]bool{"impId3": true, "impId4": false})
 
 }
+
+func TestPrepareUserStoredUIDs(t *testing.T) {
+	testCases := []struct {
+		name              string
+		explicitBuyerUIDs map[string]string
+		userSyncs         IdFetcher
+		storedUserSyncs   IdFetcher
+		expectedBuyerUID  string
+		expectedHadSync   bool
+	}{
+		{
+			name:             "cookie-uid",
+			userSyncs:        mockIdFetcher{"appnexus": "cookie-uid"},
+			storedUserSyncs:  mockIdFetcher{"appnexus": "stored-uid"},
+			expectedBuyerUID: "cookie-uid",
+			expectedHadSync:  true,
+		},
+		{
+			name:             "stored-uid",
+			userSyncs:        mockIdFetcher{},
+			storedUserSyncs:  mockIdFetcher{"appnexus": "stored-uid"},
+			expectedBuyerUID: "stored-uid",
+			expectedHadSync:  true,
+		},
+		{
+			name:              "explicit-uid",
+			explicitBuyerUIDs: map[string]string{"appnexus": "explicit-uid"},
+			userSyncs:         mockIdFetcher{},
+			storedUserSyncs:   mockIdFetcher{"appnexus": "stored-uid"},
+			expectedBuyerUID:  "explicit-uid",
+			expectedHadSync:   true,
+		},
+		{
+			name:             "no-store",
+			userSyncs:        mockIdFetcher{},
+			storedUserSyncs:  nil,
+			expectedBuyerUID: "",
+			expectedHadSync:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb2.BidRequest{}
+			hadSync := prepareUser(req, "appnexus", "appnexus", test.explicitBuyerUIDs, test.userSyncs, test.storedUserSyncs)
+
+			assert.Equal(t, test.expectedHadSync, hadSync)
+			if test.expectedBuyerUID == "" {
+				assert.Nil(t, req.User)
+			} else {
+				assert.Equal(t, test.expectedBuyerUID, req.User.BuyerUID)
+			}
+		})
+	}
+}
@@ -16,30 +16,36 @@ type BasicEnforcement struct {
 // LegalBasis determines if legal basis is satisfied for a given purpose and bidder/analytics adapter based on user consent
 // and legal basis signals.
 func (be *BasicEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool {
+	return be.ExplainLegalBasis(vendorInfo, name, consent, overrides).Allowed
+}
+
+// ExplainLegalBasis determines if legal basis is satisfied in the same way as LegalBasis and describes the basis
+// on which the decision was made.
+func (be *BasicEnforcement) ExplainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) LegalBasisExplanation {
 	enforcePurpose, enforceVendors := be.applyEnforceOverrides(overrides)
 
 	if !enforcePurpose && !enforceVendors {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced}
 	}
 	if be.cfg.vendorException(name) && !overrides.blockVendorExceptions {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException}
 	}
 	if !enforcePurpose && be.cfg.basicEnforcementVendor(name) {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisBasicEnforcementVendor}
 	}
 	if enforcePurpose && consent.PurposeAllowed(be.cfg.PurposeID) && be.cfg.basicEnforcementVendor(name) {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent}
 	}
 	if enforcePurpose && consent.PurposeLITransparency(be.cfg.PurposeID) && overrides.allowLITransparency {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisLegitimateInterest}
 	}
 	if enforcePurpose && !consent.PurposeAllowed(be.cfg.PurposeID) {
-		return false
+		return LegalBasisExplanation{Basis: LegalBasisNone}
 	}
-	if !enforceVendors {
-		return true
+	if !enforceVendors || consent.VendorConsent(vendorInfo.vendorID) {
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent}
 	}
-	return consent.VendorConsent(vendorInfo.vendorID)
+	return LegalBasisExplanation{Basis: LegalBasisNone}
 }
 
 // applyEnforceOverrides returns the enforce purpose and enforce vendor configuration values unless
@@ -241,3 +241,111 @@ func TestBasicLegalBasis(t *testing.T) {
 		assert.Equal(t, tt.wantResult, result, tt.description)
 	}
 }
+
+func TestBasicExplainLegalBasis(t *testing.T) {
+	var (
+		appnexus   = string(openrtb_ext.BidderAppnexus)
+		appnexusID = uint16(32)
+	)
+
+	noConsents := "CPerMsAPerMsAAAAAAENCfCAAAAAAAAAAAAAAAAAAAAA"
+	purpose2Consent := "CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAAAAAAAA"
+	purpose2LI := "CPerMsAPerMsAAAAAAENCfCAAAAAAEAAAAAAAAAAAAAA"
+	purpose2AndVendor32Consent := "CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAQAAAAAEAAAAAAAA"
+
+	tests := []struct {
+		description     string
+		config          purposeConfig
+		consent         string
+		overrides       Overrides
+		wantExplanation LegalBasisExplanation
+	}{
+		{
+			description: "enforce purpose & vendors are off",
+			consent:     noConsents,
+			config: purposeConfig{
+				PurposeID: consentconstants.Purpose(2),
+			},
+			wantExplanation: LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced},
+		},
+		{
+			description: "enforce purpose & vendors are on, bidder is a vendor exception",
+			consent:     noConsents,
+			config: purposeConfig{
+				PurposeID:          consentconstants.Purpose(2),
+				EnforcePurpose:     true,
+				EnforceVendors:     true,
+				VendorExceptionMap: map[string]struct{}{appnexus: {}},
+			},
+			wantExplanation: LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException},
+		},
+		{
+			description: "enforce vendors on, bidder is a basic enforcement vendor",
+			consent:     noConsents,
+			config: purposeConfig{
+				PurposeID:                  consentconstants.Purpose(2),
+				EnforceVendors:             true,
+				BasicEnforcementVendorsMap: map[string]struct{}{appnexus: {}},
+			},
+			wantExplanation: LegalBasisExplanation{Allowed: true, Basis: LegalBasisBasicEnforcementVendor},
+		},
+		{
+			description: "enforce purpose on, purpose LI Transparency Y, LI Transparency allowed",
+			consent:     purpose2LI,
+			config: purposeConfig{
+				PurposeID:      consentconstants.Purpose(2),
+				EnforcePurpose: true,
+			},
+			overrides:       Overrides{allowLITransparency: true},
+			wantExplanation: LegalBasisExplanation{Allowed: true, Basis: LegalBasisLegitimateInterest},
+		},
+		{
+			description: "enforce purpose on, purpose consent N",
+			consent:     noConsents,
+			config: purposeConfig{
+				PurposeID:      consentconstants.Purpose(2),
+				EnforcePurpose: true,
+			},
+			wantExplanation: LegalBasisExplanation{Basis: LegalBasisNone},
+		},
+		{
+			description: "enforce purpose & vendors are on, purpose consent Y, vendor consent N",
+			consent:     purpose2Consent,
+			config: purposeConfig{
+				PurposeID:      consentconstants.Purpose(2),
+				EnforcePurpose: true,
+				EnforceVendors: true,
+			},
+			wantExplanation: LegalBasisExplanation{Basis: LegalBasisNone},
+		},
+		{
+			description: "enforce purpose & vendors are on, purpose consent Y, vendor consent Y",
+			consent:     purpose2AndVendor32Consent,
+			config: purposeConfig{
+				PurposeID:      consentconstants.Purpose(2),
+				EnforcePurpose: true,
+				EnforceVendors: true,
+			},
+			wantExplanation: LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent},
+		},
+	}
+
+	for _, tt := range tests {
+		parsedConsent, err := vendorconsent.ParseString(tt.consent)
+		if err != nil {
+			t.Fatalf("Failed to parse consent %s: %s\n", tt.consent, tt.description)
+		}
+		consentMeta, ok := parsedConsent.(tcf2.ConsentMetadata)
+		if !ok {
+			t.Fatalf("Failed to convert consent %s: %s\n", tt.consent, tt.description)
+		}
+
+		enforcer := BasicEnforcement{cfg: tt.config}
+
+		vendorInfo := VendorInfo{vendorID: appnexusID, vendor: nil}
+		explanation := enforcer.ExplainLegalBasis(vendorInfo, appnexus, consentMeta, tt.overrides)
+
+		assert.Equal(t, tt.wantExplanation, explanation, tt.description)
+		assert.Equal(t, explanation.Allowed, enforcer.LegalBasis(vendorInfo, appnexus, consentMeta, tt.overrides), tt.description+" -- legal basis")
+	}
+}
@@ -0,0 +1,121 @@
+package gdpr
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// LegalBasisReason describes the basis on which TCF2 enforcement allowed or blocked an activity
+type LegalBasisReason string
+
+const (
+	LegalBasisNotEnforced            LegalBasisReason = "not_enforced"
+	LegalBasisConsent                LegalBasisReason = "consent"
+	LegalBasisLegitimateInterest     LegalBasisReason = "legitimate_interest"
+	LegalBasisFlexible               LegalBasisReason = "flexible_basis"
+	LegalBasisPublisherRestriction   LegalBasisReason = "publisher_restriction"
+	LegalBasisVendorException        LegalBasisReason = "vendor_exception"
+	LegalBasisPurposeOneTreatment    LegalBasisReason = "purpose_one_treatment"
+	LegalBasisBasicEnforcementVendor LegalBasisReason = "basic_enforcement_vendor"
+	LegalBasisNoConsent              LegalBasisReason = "no_consent"
+	LegalBasisNone                   LegalBasisReason = "none"
+)
+
+// Publisher restriction types declared in the consent string for a purpose and vendor
+const (
+	PubRestrictionNotAllowed           = "not_allowed"
+	PubRestrictionRequireConsent       = "require_consent"
+	PubRestrictionRequireLegitInterest = "require_legitimate_interest"
+)
+
+// Activities for which TCF2 enforcement decisions are explained
+const (
+	ExplainActivitySync       = "sync"
+	ExplainActivityBidRequest = "bid_request"
+	ExplainActivityPassID     = "pass_id"
+	ExplainActivityPassGeo    = "pass_geo"
+)
+
+// LegalBasisExplanation describes the outcome of a legal basis check performed by a purpose enforcer
+type LegalBasisExplanation struct {
+	Allowed              bool
+	Basis                LegalBasisReason
+	PublisherRestriction string
+}
+
+// Explanations records the legal basis evaluated by TCF2 enforcement for each bidder, activity and purpose
+// of a request. It is safe for concurrent use. A nil *Explanations records nothing.
+type Explanations struct {
+	mu      sync.Mutex
+	bidders map[openrtb_ext.BidderName]*openrtb_ext.ExtResponseDebugTCF2Bidder
+}
+
+// NewExplanations returns an empty Explanations recorder to be provided in the RequestInfo of a request
+// for which enforcement decisions must be explained
+func NewExplanations() *Explanations {
+	return &Explanations{
+		bidders: make(map[openrtb_ext.BidderName]*openrtb_ext.ExtResponseDebugTCF2Bidder),
+	}
+}
+
+// Bidders returns the decisions recorded for each bidder sorted by bidder name
+func (e *Explanations) Bidders() []openrtb_ext.ExtResponseDebugTCF2Bidder {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bidders := make([]openrtb_ext.ExtResponseDebugTCF2Bidder, 0, len(e.bidders))
+	for _, bidder := range e.bidders {
+		bidders = append(bidders, *bidder)
+	}
+	sort.Slice(bidders, func(i, j int) bool {
+		return bidders[i].Bidder < bidders[j].Bidder
+	})
+	return bidders
+}
+
+// recordPurpose records the legal basis evaluated for an activity of a bidder under a purpose
+func (e *Explanations) recordPurpose(bidder openrtb_ext.BidderName, vendorID uint16, activity string, purpose consentconstants.Purpose, enforcer PurposeEnforcer, explanation LegalBasisExplanation) {
+	e.record(bidder, vendorID, openrtb_ext.ExtResponseDebugTCF2Decision{
+		Activity:             activity,
+		Purpose:              int(purpose),
+		Enforcement:          enforcementName(enforcer),
+		LegalBasis:           string(explanation.Basis),
+		PublisherRestriction: explanation.PublisherRestriction,
+		Allowed:              explanation.Allowed,
+	})
+}
+
+// record appends a decision to those recorded for a bidder
+func (e *Explanations) record(bidder openrtb_ext.BidderName, vendorID uint16, decision openrtb_ext.ExtResponseDebugTCF2Decision) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	explained, ok := e.bidders[bidder]
+	if !ok {
+		explained = &openrtb_ext.ExtResponseDebugTCF2Bidder{Bidder: string(bidder), VendorID: vendorID}
+		e.bidders[bidder] = explained
+	}
+	explained.Decisions = append(explained.Decisions, decision)
+}
+
+// enforcementName returns the name of the enforcement algorithm implemented by a purpose enforcer
+func enforcementName(enforcer PurposeEnforcer) string {
+	switch enforcer.(type) {
+	case *FullEnforcement:
+		return "full"
+	case *BasicEnforcement:
+		return "basic"
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package gdpr
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplanations(t *testing.T) {
+	decisionA := openrtb_ext.ExtResponseDebugTCF2Decision{Activity: ExplainActivityBidRequest, Purpose: 2, LegalBasis: string(LegalBasisConsent), Allowed: true}
+	decisionB := openrtb_ext.ExtResponseDebugTCF2Decision{Activity: ExplainActivityPassGeo, SpecialFeature: 1, LegalBasis: string(LegalBasisNone)}
+
+	t.Run("nil", func(t *testing.T) {
+		var explanations *Explanations
+		explanations.record(openrtb_ext.BidderAppnexus, 32, decisionA)
+		assert.Nil(t, explanations.Bidders())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, NewExplanations().Bidders())
+	})
+
+	t.Run("sorted-by-bidder", func(t *testing.T) {
+		explanations := NewExplanations()
+		explanations.record(openrtb_ext.BidderRubicon, 52, decisionA)
+		explanations.record(openrtb_ext.BidderAppnexus, 32, decisionA)
+		explanations.record(openrtb_ext.BidderAppnexus, 32, decisionB)
+
+		expected := []openrtb_ext.ExtResponseDebugTCF2Bidder{
+			{Bidder: "appnexus", VendorID: 32, Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{decisionA, decisionB}},
+			{Bidder: "rubicon", VendorID: 52, Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{decisionA}},
+		}
+		assert.Equal(t, expected, explanations.Bidders())
+	})
+}
+
+func TestEnforcementName(t *testing.T) {
+	assert.Equal(t, "full", enforcementName(&FullEnforcement{}))
+	assert.Equal(t, "basic", enforcementName(&BasicEnforcement{}))
+	assert.Equal(t, "", enforcementName(nil))
+}
@@ -22,29 +22,66 @@ type FullEnforcement struct {
 // LegalBasis determines if legal basis is satisfied for a given purpose and bidder/analytics adapter based on the
 // vendor claims in the GVL, publisher restrictions and user consent.
 func (fe *FullEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool {
+	return fe.ExplainLegalBasis(vendorInfo, name, consent, overrides).Allowed
+}
+
+// ExplainLegalBasis determines if legal basis is satisfied in the same way as LegalBasis and describes the basis
+// on which the decision was made.
+func (fe *FullEnforcement) ExplainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) LegalBasisExplanation {
 	enforcePurpose, enforceVendors := fe.applyEnforceOverrides(overrides)
 
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictNotAllowed, vendorInfo.vendorID) {
-		return false
+		return LegalBasisExplanation{Basis: LegalBasisPublisherRestriction, PublisherRestriction: PubRestrictionNotAllowed}
 	}
 	if !enforcePurpose && !enforceVendors {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced}
 	}
 	if fe.cfg.vendorException(name) && !overrides.blockVendorExceptions {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException}
 	}
 
 	purposeAllowed := fe.consentEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
 	legitInterest := fe.legitInterestEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
 
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictRequireConsent, vendorInfo.vendorID) {
-		return purposeAllowed
+		explanation := fe.explainConsent(vendorInfo, purposeAllowed)
+		explanation.PublisherRestriction = PubRestrictionRequireConsent
+		return explanation
 	}
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictRequireLegitInterest, vendorInfo.vendorID) {
-		return legitInterest
+		explanation := fe.explainLegitInterest(vendorInfo, legitInterest)
+		explanation.PublisherRestriction = PubRestrictionRequireLegitInterest
+		return explanation
+	}
+
+	if purposeAllowed {
+		return fe.explainConsent(vendorInfo, purposeAllowed)
+	}
+	return fe.explainLegitInterest(vendorInfo, legitInterest)
+}
+
+// explainConsent describes a decision made on the consent legal basis, which is reported as flexible
+// when the vendor declares the purpose only as a flexible purpose in the GVL
+func (fe *FullEnforcement) explainConsent(vi VendorInfo, established bool) LegalBasisExplanation {
+	if !established {
+		return LegalBasisExplanation{Basis: LegalBasisNone}
+	}
+	if !vi.vendor.PurposeStrict(fe.cfg.PurposeID) {
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisFlexible}
 	}
+	return LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent}
+}
 
-	return purposeAllowed || legitInterest
+// explainLegitInterest describes a decision made on the legitimate interest legal basis, which is reported as
+// flexible when the vendor declares the purpose only as a flexible purpose in the GVL
+func (fe *FullEnforcement) explainLegitInterest(vi VendorInfo, established bool) LegalBasisExplanation {
+	if !established {
+		return LegalBasisExplanation{Basis: LegalBasisNone}
+	}
+	if !vi.vendor.LegitimateInterestStrict(fe.cfg.PurposeID) {
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisFlexible}
+	}
+	return LegalBasisExplanation{Allowed: true, Basis: LegalBasisLegitimateInterest}
 }
 
 // applyEnforceOverrides returns the enforce purpose and enforce vendor configuration values unless
@@ -971,6 +971,103 @@ func TestLegalBasisWithoutVendor(t *testing.T) {
 	}
 }
 
+func TestFullExplainLegalBasis(t *testing.T) {
+	var (
+		appnexus   = string(openrtb_ext.BidderAppnexus)
+		appnexusID = uint16(32)
+	)
+
+	NoConsentsWithP1P2P3V32RestrictionAllowNone := "CPfMKEAPfMKEAAAAAAENCgCAAAAAAAAAAAAAAQAAAAAAAIAAAAAAAGCAAgAgCAAQAQBgAIAIAAAA"
+	P1P2P3PurposeConsentAndV32VendorConsent := "CPfCRQAPfCRQAAAAAAENCgCAAOAAAAAAAAAAAQAAAAAEAIAAAAAAAAAA"
+	P1P2P3PurposeLIAndV32VendorLI := "CPfCRQAPfCRQAAAAAAENCgCAAAAAAOAAAAAAAQAAAAAAAIAAAAACAAAA"
+	P1P2P3PurposeConsentAndV32VendorConsentWithP1P2P3V32RestrictionRequireConsent := "CPfFkMAPfFkMAAAAAAENCgCAAOAAAAAAAAAAAQAAAAAEAIAAAAAAAGCgAgAgCQAQAQBoAIAIAAAA"
+
+	tests := []struct {
+		description        string
+		config             purposeConfig
+		consent            string
+		wantConsentPurpose LegalBasisExplanation
+		wantLIPurpose      LegalBasisExplanation
+		wantFlexPurpose    LegalBasisExplanation
+	}{
+		{
+			description:        "publisher restriction allow none",
+			config:             purposeConfig{EnforcePurpose: true, EnforceVendors: true},
+			consent:            NoConsentsWithP1P2P3V32RestrictionAllowNone,
+			wantConsentPurpose: LegalBasisExplanation{Basis: LegalBasisPublisherRestriction, PublisherRestriction: PubRestrictionNotAllowed},
+			wantLIPurpose:      LegalBasisExplanation{Basis: LegalBasisPublisherRestriction, PublisherRestriction: PubRestrictionNotAllowed},
+			wantFlexPurpose:    LegalBasisExplanation{Basis: LegalBasisPublisherRestriction, PublisherRestriction: PubRestrictionNotAllowed},
+		},
+		{
+			description:        "enforce purpose & vendors off",
+			config:             purposeConfig{},
+			consent:            P1P2P3PurposeLIAndV32VendorLI,
+			wantConsentPurpose: LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced},
+			wantLIPurpose:      LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced},
+			wantFlexPurpose:    LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced},
+		},
+		{
+			description:        "enforce purpose & vendors on, bidder is a vendor exception",
+			config:             purposeConfig{EnforcePurpose: true, EnforceVendors: true, VendorExceptionMap: map[string]struct{}{appnexus: {}}},
+			consent:            P1P2P3PurposeLIAndV32VendorLI,
+			wantConsentPurpose: LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException},
+			wantLIPurpose:      LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException},
+			wantFlexPurpose:    LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException},
+		},
+		{
+			description:        "enforce purpose & vendors on, purpose consent Y, vendor consent Y",
+			config:             purposeConfig{EnforcePurpose: true, EnforceVendors: true},
+			consent:            P1P2P3PurposeConsentAndV32VendorConsent,
+			wantConsentPurpose: LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent},
+			wantLIPurpose:      LegalBasisExplanation{Basis: LegalBasisNone},
+			wantFlexPurpose:    LegalBasisExplanation{Allowed: true, Basis: LegalBasisFlexible},
+		},
+		{
+			description:        "enforce purpose & vendors on, purpose LI Transparency Y, vendor LI Transparency Y",
+			config:             purposeConfig{EnforcePurpose: true, EnforceVendors: true},
+			consent:            P1P2P3PurposeLIAndV32VendorLI,
+			wantConsentPurpose: LegalBasisExplanation{Basis: LegalBasisNone},
+			wantLIPurpose:      LegalBasisExplanation{Allowed: true, Basis: LegalBasisLegitimateInterest},
+			wantFlexPurpose:    LegalBasisExplanation{Allowed: true, Basis: LegalBasisFlexible},
+		},
+		{
+			description:        "publisher restriction require consent, purpose consent Y, vendor consent Y",
+			config:             purposeConfig{EnforcePurpose: true, EnforceVendors: true},
+			consent:            P1P2P3PurposeConsentAndV32VendorConsentWithP1P2P3V32RestrictionRequireConsent,
+			wantConsentPurpose: LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent, PublisherRestriction: PubRestrictionRequireConsent},
+			wantLIPurpose:      LegalBasisExplanation{Basis: LegalBasisNone, PublisherRestriction: PubRestrictionRequireConsent},
+			wantFlexPurpose:    LegalBasisExplanation{Allowed: true, Basis: LegalBasisFlexible, PublisherRestriction: PubRestrictionRequireConsent},
+		},
+	}
+
+	for _, tt := range tests {
+		parsedConsent, err := vendorconsent.ParseString(tt.consent)
+		if err != nil {
+			t.Fatalf("Failed to parse consent %s: %s\n", tt.consent, tt.description)
+		}
+		consentMeta, ok := parsedConsent.(tcf2.ConsentMetadata)
+		if !ok {
+			t.Fatalf("Failed to convert consent %s: %s\n", tt.consent, tt.description)
+		}
+
+		vendor := getVendorList(t).Vendor(appnexusID)
+		vendorInfo := VendorInfo{vendorID: appnexusID, vendor: vendor}
+		enforcer := FullEnforcement{cfg: tt.config}
+
+		enforcer.cfg.PurposeID = consentconstants.Purpose(1)
+		consentPurpose := enforcer.ExplainLegalBasis(vendorInfo, appnexus, consentMeta, Overrides{})
+		assert.Equal(t, tt.wantConsentPurpose, consentPurpose, tt.description+" -- GVL consent purpose")
+
+		enforcer.cfg.PurposeID = consentconstants.Purpose(2)
+		LIPurpose := enforcer.ExplainLegalBasis(vendorInfo, appnexus, consentMeta, Overrides{})
+		assert.Equal(t, tt.wantLIPurpose, LIPurpose, tt.description+" -- GVL LI purpose")
+
+		enforcer.cfg.PurposeID = consentconstants.Purpose(3)
+		flexPurpose := enforcer.ExplainLegalBasis(vendorInfo, appnexus, consentMeta, Overrides{})
+		assert.Equal(t, tt.wantFlexPurpose, flexPurpose, tt.description+" -- GVL flex purpose")
+	}
+}
+
 func getVendorList(t *testing.T) vendorlist.VendorList {
 	GVL := makeVendorList()
 
@@ -31,6 +31,8 @@ type RequestInfo struct {
 	Consent     string
 	GDPRSignal  Signal
 	PublisherID string
+	// Explanations, when set, records the legal basis evaluated for each bidder and purpose
+	Explanations *Explanations
 }
 
 // NewPermissionsBuilder takes host config data used to configure the builder function it returns
@@ -58,6 +60,7 @@ func NewPermissions(cfg config.GDPR, tcf2Config TCF2ConfigReader, vendorIDs map[
 		publisherID:            requestInfo.PublisherID,
 		gdprSignal:             SignalNormalize(requestInfo.GDPRSignal, cfg.DefaultValue),
 		consent:                requestInfo.Consent,
+		explanations:           requestInfo.Explanations,
 		aliasGVLIDs:            requestInfo.AliasGVLIDs,
 		purposeEnforcerBuilder: purposeEnforcerBuilder,
 	}
@@ -23,11 +23,12 @@ type permissionsImpl struct {
 	purposeEnforcerBuilder PurposeEnforcerBuilder
 	vendorIDs              map[openrtb_ext.BidderName]uint16
 	// request-specific
-	aliasGVLIDs map[string]uint16
-	cfg         TCF2ConfigReader
-	consent     string
-	gdprSignal  Signal
-	publisherID string
+	aliasGVLIDs  map[string]uint16
+	cfg          TCF2ConfigReader
+	consent      string
+	explanations *Explanations
+	gdprSignal   Signal
+	publisherID  string
 }
 
 // HostCookiesAllowed determines whether the host is allowed to set cookies on the user's device
@@ -63,24 +64,24 @@ func (p *permissionsImpl) AuctionActivitiesAllowed(ctx context.Context, bidderCo
 	if p.gdprSignal != SignalYes {
 		return AllowAll, nil
 	}
+	vendorID, _ := p.resolveVendorID(bidderCoreName, bidder)
 	if p.consent == "" {
-		return p.defaultPermissions(), nil
+		return p.explainDefaultPermissions(bidder, vendorID, p.defaultPermissions()), nil
 	}
 	pc, err := parseConsent(p.consent)
 	if err != nil {
-		return p.defaultPermissions(), err
+		return p.explainDefaultPermissions(bidder, vendorID, p.defaultPermissions()), err
 	}
-	vendorID, _ := p.resolveVendorID(bidderCoreName, bidder)
 	vendor, err := p.getVendor(ctx, vendorID, *pc)
 	if err != nil {
-		return p.defaultPermissions(), err
+		return p.explainDefaultPermissions(bidder, vendorID, p.defaultPermissions()), err
 	}
 	vendorInfo := VendorInfo{vendorID: vendorID, vendor: vendor}
 
 	permissions = AuctionPermissions{}
-	permissions.AllowBidRequest = p.allowBidRequest(bidderCoreName, pc.consentMeta, vendorInfo)
-	permissions.PassGeo = p.allowGeo(bidderCoreName, pc.consentMeta, vendor)
-	permissions.PassID = p.allowID(bidderCoreName, pc.consentMeta, vendorInfo)
+	permissions.AllowBidRequest = p.allowBidRequest(bidderCoreName, pc.consentMeta, vendorInfo, bidder)
+	permissions.PassGeo = p.allowGeo(bidderCoreName, pc.consentMeta, vendorInfo, bidder)
+	permissions.PassID = p.allowID(bidderCoreName, pc.consentMeta, vendorInfo, bidder)
 
 	return permissions, nil
 }
@@ -101,6 +102,32 @@ func (p *permissionsImpl) defaultPermissions() AuctionPermissions {
 	return perms
 }
 
+// explainDefaultPermissions records the default permissions applied to a bidder when the consent string is
+// missing or malformed, or the vendor list could not be retrieved, and returns them unchanged
+func (p *permissionsImpl) explainDefaultPermissions(bidder openrtb_ext.BidderName, vendorID uint16, perms AuctionPermissions) AuctionPermissions {
+	if p.explanations == nil {
+		return perms
+	}
+
+	defaultDecision := func(activity string, purpose, specialFeature int, allowed bool) openrtb_ext.ExtResponseDebugTCF2Decision {
+		basis := LegalBasisNoConsent
+		if allowed {
+			basis = LegalBasisNotEnforced
+		}
+		return openrtb_ext.ExtResponseDebugTCF2Decision{
+			Activity:       activity,
+			Purpose:        purpose,
+			SpecialFeature: specialFeature,
+			LegalBasis:     string(basis),
+			Allowed:        allowed,
+		}
+	}
+	p.explanations.record(bidder, vendorID, defaultDecision(ExplainActivityBidRequest, 2, 0, perms.AllowBidRequest))
+	p.explanations.record(bidder, vendorID, defaultDecision(ExplainActivityPassGeo, 0, 1, perms.PassGeo))
+	p.explanations.record(bidder, vendorID, defaultDecision(ExplainActivityPassID, 0, 0, perms.PassID))
+	return perms
+}
+
 // resolveVendorID gets the vendor ID for the specified bidder from either the alias GVL IDs
 // provided in the request or from the bidder configs loaded at startup
 func (p *permissionsImpl) resolveVendorID(bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) (id uint16, ok bool) {
@@ -116,68 +143,106 @@ func (p *permissionsImpl) resolveVendorID(bidderCoreName openrtb_ext.BidderName,
 // allowSync computes cookie sync activity legal basis for a given bidder using the enforcement
 // algorithms selected by the purpose enforcer builder
 func (p *permissionsImpl) allowSync(ctx context.Context, vendorID uint16, bidder openrtb_ext.BidderName, vendorException bool) (bool, error) {
+	purpose := consentconstants.Purpose(1)
+
 	if p.consent == "" {
+		p.explainSync(bidder, vendorID, purpose, nil, LegalBasisExplanation{Basis: LegalBasisNoConsent})
 		return false, nil
 	}
 	pc, err := parseConsent(p.consent)
 	if err != nil {
+		p.explainSync(bidder, vendorID, purpose, nil, LegalBasisExplanation{Basis: LegalBasisNoConsent})
 		return false, err
 	}
 	vendor, err := p.getVendor(ctx, vendorID, *pc)
 	if err != nil {
+		p.explainSync(bidder, vendorID, purpose, nil, LegalBasisExplanation{Basis: LegalBasisNoConsent})
 		return false, nil
 	}
 	vendorInfo := VendorInfo{vendorID: vendorID, vendor: vendor}
 
-	if !p.cfg.PurposeEnforced(consentconstants.Purpose(1)) {
+	if !p.cfg.PurposeEnforced(purpose) {
+		p.explainSync(bidder, vendorID, purpose, nil, LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced})
 		return true, nil
 	}
 
 	if p.cfg.PurposeOneTreatmentEnabled() && pc.consentMeta.PurposeOneTreatment() {
-		return p.cfg.PurposeOneTreatmentAccessAllowed(), nil
+		allowed := p.cfg.PurposeOneTreatmentAccessAllowed()
+		p.explainSync(bidder, vendorID, purpose, nil, LegalBasisExplanation{Allowed: allowed, Basis: LegalBasisPurposeOneTreatment})
+		return allowed, nil
 	}
 
-	purpose := consentconstants.Purpose(1)
 	enforcer := p.purposeEnforcerBuilder(purpose, string(bidder))
 
-	if enforcer.LegalBasis(vendorInfo, string(bidder), pc.consentMeta, Overrides{blockVendorExceptions: !vendorException}) {
-		return true, nil
+	explanation := enforcer.ExplainLegalBasis(vendorInfo, string(bidder), pc.consentMeta, Overrides{blockVendorExceptions: !vendorException})
+	p.explainSync(bidder, vendorID, purpose, enforcer, explanation)
+	return explanation.Allowed, nil
+}
+
+// explainSync records the legal basis evaluated for a bidder cookie sync. Host cookie decisions are not recorded.
+func (p *permissionsImpl) explainSync(bidder openrtb_ext.BidderName, vendorID uint16, purpose consentconstants.Purpose, enforcer PurposeEnforcer, explanation LegalBasisExplanation) {
+	if bidder == noBidder {
+		return
 	}
-	return false, nil
+	p.explanations.recordPurpose(bidder, vendorID, ExplainActivitySync, purpose, enforcer, explanation)
 }
 
 // allowBidRequest computes legal basis for a given bidder using the enforcement algorithms selected
 // by the purpose enforcer builder
-func (p *permissionsImpl) allowBidRequest(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo) bool {
-	enforcer := p.purposeEnforcerBuilder(consentconstants.Purpose(2), string(bidder))
+func (p *permissionsImpl) allowBidRequest(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo, explainedBidder openrtb_ext.BidderName) bool {
+	purpose := consentconstants.Purpose(2)
+	enforcer := p.purposeEnforcerBuilder(purpose, string(bidder))
 
 	overrides := Overrides{}
 	if _, ok := enforcer.(*BasicEnforcement); ok {
 		overrides.allowLITransparency = true
 	}
-	return enforcer.LegalBasis(vendorInfo, string(bidder), consentMeta, overrides)
+	explanation := enforcer.ExplainLegalBasis(vendorInfo, string(bidder), consentMeta, overrides)
+	p.explanations.recordPurpose(explainedBidder, vendorInfo.vendorID, ExplainActivityBidRequest, purpose, enforcer, explanation)
+	return explanation.Allowed
 }
 
 // allowGeo computes legal basis for a given bidder using the configs, consent and GVL pertaining to
 // feature one
-func (p *permissionsImpl) allowGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendor api.Vendor) bool {
+func (p *permissionsImpl) allowGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo, explainedBidder openrtb_ext.BidderName) bool {
+	explanation := p.explainGeo(bidder, consentMeta, vendorInfo.vendor)
+	p.explanations.record(explainedBidder, vendorInfo.vendorID, openrtb_ext.ExtResponseDebugTCF2Decision{
+		Activity:       ExplainActivityPassGeo,
+		SpecialFeature: 1,
+		LegalBasis:     string(explanation.Basis),
+		Allowed:        explanation.Allowed,
+	})
+	return explanation.Allowed
+}
+
+// explainGeo determines if special feature one may be used by a given bidder and describes the basis on which
+// the decision was made
+func (p *permissionsImpl) explainGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendor api.Vendor) LegalBasisExplanation {
 	if !p.cfg.FeatureOneEnforced() {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisNotEnforced}
 	}
 	if p.cfg.FeatureOneVendorException(bidder) {
-		return true
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisVendorException}
+	}
+	if !consentMeta.SpecialFeatureOptIn(1) {
+		return LegalBasisExplanation{Basis: LegalBasisNone}
+	}
+	if vendor != nil && vendor.SpecialFeature(1) {
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisConsent}
 	}
 
 	basicEnforcementVendors := p.cfg.BasicEnforcementVendors()
-	_, weakVendorEnforcement := basicEnforcementVendors[string(bidder)]
-	return consentMeta.SpecialFeatureOptIn(1) && ((vendor != nil && vendor.SpecialFeature(1)) || weakVendorEnforcement)
+	if _, weakVendorEnforcement := basicEnforcementVendors[string(bidder)]; weakVendorEnforcement {
+		return LegalBasisExplanation{Allowed: true, Basis: LegalBasisBasicEnforcementVendor}
+	}
+	return LegalBasisExplanation{Basis: LegalBasisNone}
 }
 
 // allowID computes the pass user ID activity legal basis for a given bidder using the enforcement algorithms
 // selected by the purpose enforcer builder. For the user ID activity, the selected enforcement algorithm must
 // always assume we are enforcing the purpose.
 // If the purpose for which we are computing legal basis is purpose 2, the algorithm should allow LI transparency.
-func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo) bool {
+func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo, explainedBidder openrtb_ext.BidderName) bool {
 	for i := 2; i <= 10; i++ {
 		purpose := consentconstants.Purpose(i)
 		enforcer := p.purposeEnforcerBuilder(purpose, string(bidder))
@@ -186,7 +251,9 @@ func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf
 		if _, ok := enforcer.(*BasicEnforcement); ok && purpose == consentconstants.Purpose(2) {
 			overrides.allowLITransparency = true
 		}
-		if enforcer.LegalBasis(vendorInfo, string(bidder), consentMeta, overrides) {
+		explanation := enforcer.ExplainLegalBasis(vendorInfo, string(bidder), consentMeta, overrides)
+		p.explanations.recordPurpose(explainedBidder, vendorInfo.vendorID, ExplainActivityPassID, purpose, enforcer, explanation)
+		if explanation.Allowed {
 			return true
 		}
 	}
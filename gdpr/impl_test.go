@@ -807,6 +807,102 @@ func TestAllowSync(t *testing.T) {
 	assert.EqualValuesf(t, true, allowSync, "BidderSyncAllowed failure")
 }
 
+func TestExplainPermissions(t *testing.T) {
+	const fullConsentToPurposesAndVendorsTwoSixEight = "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
+
+	vendorListData := MarshalVendorList(buildVendorList34())
+	tcf2AggConfig := allPurposesEnabledTCF2Config()
+
+	newPerms := func(consent string) permissionsImpl {
+		return permissionsImpl{
+			cfg:          &tcf2AggConfig,
+			hostVendorID: 2,
+			vendorIDs: map[openrtb_ext.BidderName]uint16{
+				openrtb_ext.BidderRubicon: 8,
+			},
+			fetchVendorList: listFetcher(map[uint16]map[uint16]vendorlist.VendorList{
+				2: {
+					34: parseVendorListDataV2(t, vendorListData),
+				},
+			}),
+			purposeEnforcerBuilder: NewPurposeEnforcerBuilder(&tcf2AggConfig),
+			gdprSignal:             SignalYes,
+			consent:                consent,
+			explanations:           NewExplanations(),
+		}
+	}
+
+	testCases := []struct {
+		description string
+		consent     string
+		sync        bool
+		expected    []openrtb_ext.ExtResponseDebugTCF2Bidder
+	}{
+		{
+			description: "sync",
+			consent:     fullConsentToPurposesAndVendorsTwoSixEight,
+			sync:        true,
+			expected: []openrtb_ext.ExtResponseDebugTCF2Bidder{
+				{
+					Bidder:   "rubicon",
+					VendorID: 8,
+					Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+						{Activity: "sync", Purpose: 1, Enforcement: "full", LegalBasis: "consent", Allowed: true},
+					},
+				},
+			},
+		},
+		{
+			description: "auction",
+			consent:     fullConsentToPurposesAndVendorsTwoSixEight,
+			expected: []openrtb_ext.ExtResponseDebugTCF2Bidder{
+				{
+					Bidder:   "rubicon",
+					VendorID: 8,
+					Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+						{Activity: "bid_request", Purpose: 2, Enforcement: "full", LegalBasis: "legitimate_interest", Allowed: true},
+						{Activity: "pass_geo", SpecialFeature: 1, LegalBasis: "none", Allowed: false},
+						{Activity: "pass_id", Purpose: 2, Enforcement: "full", LegalBasis: "legitimate_interest", Allowed: true},
+					},
+				},
+			},
+		},
+		{
+			description: "auction_without_consent",
+			consent:     "",
+			expected: []openrtb_ext.ExtResponseDebugTCF2Bidder{
+				{
+					Bidder:   "rubicon",
+					VendorID: 8,
+					Decisions: []openrtb_ext.ExtResponseDebugTCF2Decision{
+						{Activity: "bid_request", Purpose: 2, LegalBasis: "no_consent", Allowed: false},
+						{Activity: "pass_geo", SpecialFeature: 1, LegalBasis: "no_consent", Allowed: false},
+						{Activity: "pass_id", LegalBasis: "no_consent", Allowed: false},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			perms := newPerms(test.consent)
+
+			if test.sync {
+				_, err := perms.HostCookiesAllowed(context.Background())
+				assert.NoError(t, err)
+				_, err = perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderRubicon)
+				assert.NoError(t, err)
+			} else {
+				_, err := perms.AuctionActivitiesAllowed(context.Background(), openrtb_ext.BidderRubicon, openrtb_ext.BidderRubicon)
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, perms.explanations.Bidders())
+		})
+	}
+}
+
 func TestProhibitedPurposeSync(t *testing.T) {
 	const fullConsentToPurposesAndVendorsTwoSixEight = "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
 
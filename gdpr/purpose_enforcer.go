@@ -11,6 +11,7 @@ import (
 // PurposeEnforcer represents the enforcement strategy for determining if legal basis is achieved for a purpose
 type PurposeEnforcer interface {
 	LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool
+	ExplainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) LegalBasisExplanation
 }
 
 // PurposeEnforcerBuilder generates an instance of PurposeEnforcer for a given purpose and bidder
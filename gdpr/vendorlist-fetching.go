@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -21,6 +23,23 @@ import (
 type saveVendors func(uint16, uint16, api.VendorList)
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16) (vendorlist.VendorList, error)
 
+// VendorListVersions lists the vendor lists loaded by a VendorListFetcher.
+type VendorListVersions func() []VendorListVersion
+
+// Vendor list sources.
+const (
+	VendorListSourceEmbedded = "embedded"
+	VendorListSourceFile     = "file"
+	VendorListSourceHTTP     = "http"
+)
+
+// VendorListVersion identifies a loaded vendor list and where it was loaded from.
+type VendorListVersion struct {
+	SpecVersion uint16 `json:"specVersion"`
+	ListVersion uint16 `json:"listVersion"`
+	Source      string `json:"source"`
+}
+
 // This file provides the vendorlist-fetching function for Prebid Server.
 //
 // For more info, see https://github.com/prebid/prebid-server/issues/504
@@ -28,32 +47,71 @@ type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion
 // Nothing in this file is exported. Public APIs can be found in gdpr.go
 
 func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, urlMaker func(uint16, uint16) string) VendorListFetcher {
-	cacheSave, cacheLoad := newVendorListCache()
+	fetcher, _, err := NewVendorListLoader(initCtx, cfg, client, urlMaker)
+	if err != nil {
+		glog.Error(err)
+	}
+	return fetcher
+}
+
+// NewVendorListLoader returns a VendorListFetcher and the function listing the vendor lists it loaded.
+//
+// The vendor lists of the configured directory and of the embedded snapshot are loaded first, the lists are
+// then downloaded over HTTP on top of them unless the vendor lists are configured to be offline. A requested
+// list which is not loaded, and cannot be downloaded, is replaced by the closest loaded list of the same spec
+// version. An error is returned if the embedded snapshot is enabled but holds no vendor list.
+func NewVendorListLoader(initCtx context.Context, cfg config.GDPR, client *http.Client, urlMaker func(uint16, uint16) string) (VendorListFetcher, VendorListVersions, error) {
+	cache := &vendorListCache{}
+
+	var err error
+	if cfg.VendorLists.Embedded {
+		err = loadVendorListSnapshot(embeddedVendorLists, cache.saver(VendorListSourceEmbedded))
+	}
+	if cfg.VendorLists.Directory != "" {
+		loadVendorListFiles(os.DirFS(cfg.VendorLists.Directory), cfg.VendorLists.Directory, cache.saver(VendorListSourceFile))
+	}
+
+	if cfg.VendorLists.Offline {
+		return func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
+			if list := cache.load(specVersion, listVersion); list != nil {
+				return list, nil
+			}
+			if list := cache.loadClosest(specVersion, listVersion); list != nil {
+				return list, nil
+			}
+			return nil, makeVendorListNotFoundError(specVersion, listVersion)
+		}, cache.versions, err
+	}
 
 	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
 	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave)
+	preloadCache(preloadContext, client, urlMaker, cache.saver(VendorListSourceHTTP))
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
 	return func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
 		// Attempt To Load From Cache
-		if list := cacheLoad(specVersion, listVersion); list != nil {
+		if list := cache.load(specVersion, listVersion); list != nil {
 			return list, nil
 		}
 
 		// Attempt To Download
 		// - May not add to cache immediately.
-		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cacheSave)
+		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cache.saver(VendorListSourceHTTP))
 
 		// Attempt To Load From Cache Again
 		// - May have been added by the call to saveOneRateLimited.
-		if list := cacheLoad(specVersion, listVersion); list != nil {
+		if list := cache.load(specVersion, listVersion); list != nil {
+			return list, nil
+		}
+
+		// Fall Back To The Closest Loaded Version
+		if list := cache.loadClosest(specVersion, listVersion); list != nil {
 			return list, nil
 		}
 
 		// Give Up
 		return nil, makeVendorListNotFoundError(specVersion, listVersion)
-	}
+	}, cache.versions, err
 }
 
 func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
@@ -149,21 +207,73 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 	return newList.Version()
 }
 
-func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList) {
-	cache := &sync.Map{}
+// vendorListCache holds the loaded vendor lists by spec and list version along with their source.
+type vendorListCache struct {
+	lists sync.Map
+}
+
+type cachedVendorList struct {
+	list   api.VendorList
+	source string
+}
 
-	save = func(specVersion uint16, listVersion uint16, list api.VendorList) {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		cache.Store(key, list)
+func vendorListKey(specVersion, listVersion uint16) string {
+	return fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
+}
+
+func (c *vendorListCache) saver(source string) saveVendors {
+	return func(specVersion uint16, listVersion uint16, list api.VendorList) {
+		c.lists.Store(vendorListKey(specVersion, listVersion), cachedVendorList{list: list, source: source})
 	}
+}
 
-	load = func(specVersion, listVersion uint16) api.VendorList {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		list, ok := cache.Load(key)
-		if ok {
-			return list.(vendorlist.VendorList)
-		}
-		return nil
+func (c *vendorListCache) load(specVersion, listVersion uint16) api.VendorList {
+	if cached, ok := c.lists.Load(vendorListKey(specVersion, listVersion)); ok {
+		return cached.(cachedVendorList).list
 	}
-	return
+	return nil
+}
+
+// loadClosest returns the loaded vendor list of the spec version closest to the list version, the older list
+// is preferred if two lists are as close. Returns nil if no list of the spec version is loaded.
+func (c *vendorListCache) loadClosest(specVersion, listVersion uint16) api.VendorList {
+	var closest api.VendorList
+	closestDistance := -1
+	c.lists.Range(func(_, value any) bool {
+		list := value.(cachedVendorList).list
+		if list.SpecVersion() != specVersion {
+			return true
+		}
+		distance := int(list.Version()) - int(listVersion)
+		if distance < 0 {
+			distance = -distance
+		}
+		if closest == nil || distance < closestDistance || distance == closestDistance && list.Version() < closest.Version() {
+			closest = list
+			closestDistance = distance
+		}
+		return true
+	})
+	return closest
+}
+
+// versions lists the loaded vendor lists ordered by spec and list version.
+func (c *vendorListCache) versions() []VendorListVersion {
+	versions := []VendorListVersion{}
+	c.lists.Range(func(_, value any) bool {
+		cached := value.(cachedVendorList)
+		versions = append(versions, VendorListVersion{
+			SpecVersion: cached.list.SpecVersion(),
+			ListVersion: cached.list.Version(),
+			Source:      cached.source,
+		})
+		return true
+	})
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].SpecVersion != versions[j].SpecVersion {
+			return versions[i].SpecVersion < versions[j].SpecVersion
+		}
+		return versions[i].ListVersion < versions[j].ListVersion
+	})
+	return versions
 }
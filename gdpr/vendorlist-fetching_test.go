@@ -2,15 +2,20 @@ package gdpr
 
 import (
 	"context"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist2"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
@@ -56,18 +61,30 @@ func TestFetcherDynamicLoadListDoesntExist(t *testing.T) {
 	})))
 	defer server.Close()
 
-	test := test{
-		description: "No Fallback - Vendor Doesn't Exist",
-		setup: testSetup{
-			specVersion: 3,
-			listVersion: 2,
+	tests := []test{
+		{
+			description: "Fallback - Closest Loaded List Of The Spec Version",
+			setup: testSetup{
+				specVersion: 3,
+				listVersion: 2,
+			},
+			expected: vendorList1Expected,
 		},
-		expected: testExpected{
-			errorMessage: "gdpr vendor list spec version 3 list version 2 does not exist, or has not been loaded yet. Try again in a few minutes",
+		{
+			description: "No Fallback - No List Of The Spec Version",
+			setup: testSetup{
+				specVersion: 2,
+				listVersion: 2,
+			},
+			expected: testExpected{
+				errorMessage: "gdpr vendor list spec version 2 list version 2 does not exist, or has not been loaded yet. Try again in a few minutes",
+			},
 		},
 	}
 
-	runTest(t, test, server)
+	for _, test := range tests {
+		runTest(t, test, server)
+	}
 }
 
 func TestFetcherThrottling(t *testing.T) {
@@ -102,9 +119,166 @@ func TestFetcherThrottling(t *testing.T) {
 	assert.NoError(t, errList1)
 
 	// Fail To Load List 3 Due To Rate Limiting
-	// - The request is rate limited after dynamically list 2.
-	_, errList2 := fetcher(context.Background(), 3, 3)
-	assert.EqualError(t, errList2, "gdpr vendor list spec version 3 list version 3 does not exist, or has not been loaded yet. Try again in a few minutes")
+	// - The request is rate limited after dynamically list 2, the closest loaded list is used instead.
+	list, errList2 := fetcher(context.Background(), 3, 3)
+	assert.NoError(t, errList2)
+	assert.Equal(t, uint16(2), list.Version())
+}
+
+func TestVendorListLoaderDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeVendorListFile(t, dir, "vendor-list-v1.json", vendorList1)
+	writeVendorListFile(t, dir, "v3/vendor-list-v2.json", vendorList2)
+
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 3,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: vendorList1,
+				2: vendorList2,
+				3: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 3}),
+			},
+		},
+	})))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.VendorLists.Directory = dir
+	fetcher, versions, err := NewVendorListLoader(context.Background(), cfg, server.Client(), testURLMaker(server))
+	assert.NoError(t, err)
+
+	vendorList, err := fetcher(context.Background(), 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), vendorList.Version())
+
+	// lists are downloaded on top of the files, the most recent source is reported
+	expectedVersions := []VendorListVersion{
+		{SpecVersion: 3, ListVersion: 1, Source: VendorListSourceHTTP},
+		{SpecVersion: 3, ListVersion: 2, Source: VendorListSourceHTTP},
+		{SpecVersion: 3, ListVersion: 3, Source: VendorListSourceHTTP},
+	}
+	assert.Equal(t, expectedVersions, versions())
+}
+
+func TestVendorListLoaderOffline(t *testing.T) {
+	dir := t.TempDir()
+	writeVendorListFile(t, dir, "vendor-list-v2.json", vendorList2)
+
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requested = true
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.VendorLists.Directory = dir
+	cfg.VendorLists.Offline = true
+	fetcher, versions, err := NewVendorListLoader(context.Background(), cfg, server.Client(), testURLMaker(server))
+	assert.NoError(t, err)
+
+	test := test{
+		description: "Offline - List Loaded From Directory",
+		setup:       testSetup{specVersion: 3, listVersion: 2},
+		expected:    vendorList2Expected,
+	}
+	vendorList, err := fetcher(context.Background(), test.setup.specVersion, test.setup.listVersion)
+	assert.NoError(t, err, test.description)
+	assert.Equal(t, test.expected.vendorListVersion, vendorList.Version(), test.description)
+
+	vendorList, err = fetcher(context.Background(), 3, 1)
+	assert.NoError(t, err, "The closest loaded list must be used")
+	assert.Equal(t, uint16(2), vendorList.Version())
+
+	_, err = fetcher(context.Background(), 2, 1)
+	assert.EqualError(t, err, "gdpr vendor list spec version 2 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
+
+	assert.Equal(t, []VendorListVersion{{SpecVersion: 3, ListVersion: 2, Source: VendorListSourceFile}}, versions())
+	assert.False(t, requested, "The vendor lists must not be downloaded when offline")
+}
+
+func TestLoadVendorListFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vendor-list-v1.json":    {Data: []byte(vendorList1)},
+		"v3/vendor-list-v2.json": {Data: []byte(vendorList2)},
+		"malformed.json":         {Data: []byte("malformed")},
+		"README.md":              {Data: []byte("# Vendor Lists")},
+	}
+
+	s := make(saver, 0, 2)
+	loaded := loadVendorListFiles(fsys, "test", s.saveVendorLists)
+
+	assert.Equal(t, 2, loaded)
+	assert.ElementsMatch(t, []versionInfo{{specVersion: 3, listVersion: 1}, {specVersion: 3, listVersion: 2}}, s)
+}
+
+func TestLoadVendorListSnapshot(t *testing.T) {
+	s := make(saver, 0, 1)
+	err := loadVendorListSnapshot(fstest.MapFS{"vendor-list-v1.json": {Data: []byte(vendorList1)}}, s.saveVendorLists)
+	assert.NoError(t, err)
+	assert.Equal(t, saver{{specVersion: 3, listVersion: 1}}, s)
+
+	err = loadVendorListSnapshot(fstest.MapFS{"README.md": {Data: []byte("# Vendor Lists")}}, s.saveVendorLists)
+	assert.EqualError(t, err, "gdpr.vendorlists.embedded is enabled but the embedded snapshot holds no vendor list, see gdpr/vendorlists/README.md")
+}
+
+func TestLoadEmbeddedVendorListFiles(t *testing.T) {
+	files := 0
+	err := fs.WalkDir(embeddedVendorLists, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(filePath) == ".json" {
+			files++
+		}
+		return err
+	})
+	assert.NoError(t, err)
+
+	s := make(saver, 0, files)
+	loaded := loadVendorListFiles(embeddedVendorLists, VendorListSourceEmbedded, s.saveVendorLists)
+	assert.Equal(t, files, loaded, "The embedded snapshot must only contain valid vendor lists")
+}
+
+func TestVendorListCacheLoadClosest(t *testing.T) {
+	cache := &vendorListCache{}
+	save := cache.saver(VendorListSourceFile)
+	for _, version := range []uint16{2, 6, 10} {
+		list, err := vendorlist2.ParseEagerly([]byte(MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: version})))
+		assert.NoError(t, err)
+		save(3, version, list)
+	}
+
+	testCases := []struct {
+		description         string
+		specVersion         uint16
+		listVersion         uint16
+		expectedListVersion uint16
+	}{
+		{description: "Older than all lists", specVersion: 3, listVersion: 1, expectedListVersion: 2},
+		{description: "Closest newer list", specVersion: 3, listVersion: 5, expectedListVersion: 6},
+		{description: "Closest older list", specVersion: 3, listVersion: 7, expectedListVersion: 6},
+		{description: "Older list preferred when as close", specVersion: 3, listVersion: 8, expectedListVersion: 6},
+		{description: "Newer than all lists", specVersion: 3, listVersion: 200, expectedListVersion: 10},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			list := cache.loadClosest(test.specVersion, test.listVersion)
+			if assert.NotNil(t, list) {
+				assert.Equal(t, test.expectedListVersion, list.Version())
+			}
+		})
+	}
+
+	assert.Nil(t, cache.loadClosest(2, 6), "Lists of another spec version must not be used")
+}
+
+func writeVendorListFile(t *testing.T, dir, name, content string) {
+	filePath := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatalf("Failed to create vendor list directory: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write vendor list file: %v", err)
+	}
 }
 
 func TestMalformedVendorlist(t *testing.T) {
@@ -272,6 +446,12 @@ var vendorList1 = MarshalVendorList(vendorList{
 	Vendors:                 map[string]*vendor{"12": {ID: 12, Purposes: []int{2}}},
 })
 
+var vendorList1Expected = testExpected{
+	vendorListVersion: 1,
+	vendorID:          12,
+	vendorPurposes:    map[int]bool{1: false, 2: true, 3: false},
+}
+
 var vendorList2 = MarshalVendorList(vendorList{
 	GVLSpecificationVersion: 3,
 	VendorListVersion:       2,
@@ -0,0 +1,61 @@
+package gdpr
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"path"
+
+	"github.com/golang/glog"
+	"github.com/prebid/go-gdpr/vendorlist2"
+)
+
+// embeddedVendorLists is the snapshot of the vendor lists built into the binary, see vendorlists/README.md.
+//
+//go:embed vendorlists
+var embeddedVendorLists embed.FS
+
+// loadVendorListSnapshot saves the vendor lists of the embedded snapshot fsys. Returns an error if it holds no
+// vendor list, as the snapshot is expected to be refreshed when building a release.
+func loadVendorListSnapshot(fsys fs.FS, saver saveVendors) error {
+	if loadVendorListFiles(fsys, VendorListSourceEmbedded, saver) == 0 {
+		return errors.New("gdpr.vendorlists.embedded is enabled but the embedded snapshot holds no vendor list, see gdpr/vendorlists/README.md")
+	}
+	return nil
+}
+
+// loadVendorListFiles saves the vendor lists of all JSON files in fsys and its subdirectories. The spec and list
+// versions are read from the vendor lists themselves, so the files may be named freely. Files which cannot be
+// read or parsed are logged and skipped. Returns the number of vendor lists saved.
+func loadVendorListFiles(fsys fs.FS, name string, saver saveVendors) int {
+	loaded := 0
+	err := fs.WalkDir(fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(filePath) != ".json" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			glog.Errorf("Failed to read vendor list file %s from %s: %v", filePath, name, err)
+			return nil
+		}
+		list, err := vendorlist2.ParseEagerly(data)
+		if err != nil {
+			glog.Errorf("Vendor list file %s from %s is malformed: %v", filePath, name, err)
+			return nil
+		}
+
+		saver(list.SpecVersion(), list.Version(), list)
+		loaded++
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("Failed to load the vendor lists from %s: %v", name, err)
+	}
+
+	glog.Infof("Loaded %d vendor lists from %s", loaded, name)
+	return loaded
+}
@@ -20,7 +20,7 @@ type executionContext struct {
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
-	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint}
+	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint, AccountID: ctx.accountID}
 	if ctx.moduleContexts != nil {
 		if mc, ok := ctx.moduleContexts.get(moduleName); ok {
 			moduleInvocationCtx.ModuleContext = mc
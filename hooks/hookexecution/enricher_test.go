@@ -8,6 +8,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
@@ -31,14 +32,16 @@ type GroupOutcomeTest struct {
 
 type HookOutcomeTest struct {
 	ExecutionTime
-	AnalyticsTags hookanalytics.Analytics `json:"analytics_tags"`
-	HookID        HookID                  `json:"hook_id"`
-	Status        Status                  `json:"status"`
-	Action        Action                  `json:"action"`
-	Message       string                  `json:"message"`
-	DebugMessages []string                `json:"debug_messages"`
-	Errors        []string                `json:"errors"`
-	Warnings      []string                `json:"warnings"`
+	AnalyticsTags hookanalytics.Analytics   `json:"analytics_tags"`
+	HookID        HookID                    `json:"hook_id"`
+	Status        Status                    `json:"status"`
+	Action        Action                    `json:"action"`
+	Message       string                    `json:"message"`
+	DebugMessages []string                  `json:"debug_messages"`
+	Errors        []string                  `json:"errors"`
+	Warnings      []string                  `json:"warnings"`
+	SeatNonBid    []openrtb_ext.SeatNonBid  `json:"seatnonbid"`
+	SeatBids      []entities.PbsOrtbSeatBid `json:"seatbids"`
 }
 
 func TestEnrichBidResponse(t *testing.T) {
@@ -201,6 +201,8 @@ func handleHookResponse[P any](
 		rejectErr = handleHookReject(ctx, hr, &hookOutcome, metricEngine, labels)
 	} else {
 		payload = handleHookMutations(payload, hr, &hookOutcome, metricEngine, labels)
+		hookOutcome.SeatNonBid = hr.Result.SeatNonBid
+		hookOutcome.SeatBids = hr.Result.SeatBids
 	}
 
 	return payload, hookOutcome, rejectErr
@@ -39,6 +39,8 @@ type StageExecutor interface {
 	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
+	GetSeatNonBid() []openrtb_ext.SeatNonBid
+	GetSeatBids() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
 }
 
 type HookStageExecutor interface {
@@ -88,6 +90,55 @@ func (e *hookExecutor) GetOutcomes() []StageOutcome {
 	return e.stageOutcomes
 }
 
+// GetSeatNonBid returns the bids rejected by hooks at all stages executed so far.
+func (e *hookExecutor) GetSeatNonBid() []openrtb_ext.SeatNonBid {
+	e.Lock()
+	defer e.Unlock()
+
+	var seatNonBid []openrtb_ext.SeatNonBid
+	for _, stageOutcome := range e.stageOutcomes {
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				seatNonBid = append(seatNonBid, hookOutcome.SeatNonBid...)
+			}
+		}
+	}
+
+	return seatNonBid
+}
+
+// GetSeatBids returns the bids supplied by hooks at all stages executed so far, grouped by seat.
+func (e *hookExecutor) GetSeatBids() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+	e.Lock()
+	defer e.Unlock()
+
+	var seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+	for _, stageOutcome := range e.stageOutcomes {
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				for _, seatBid := range hookOutcome.SeatBids {
+					if len(seatBid.Bids) == 0 {
+						continue
+					}
+					if seatBids == nil {
+						seatBids = make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+					}
+					seat := openrtb_ext.BidderName(seatBid.Seat)
+					if existing, ok := seatBids[seat]; ok {
+						existing.Bids = append(existing.Bids, seatBid.Bids...)
+					} else {
+						seatBidCopy := seatBid
+						seatBidCopy.Bids = append([]*entities.PbsOrtbBid(nil), seatBid.Bids...)
+						seatBids[seat] = &seatBidCopy
+					}
+				}
+			}
+		}
+	}
+
+	return seatBids
+}
+
 func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError) {
 	plan := e.planBuilder.PlanForEntrypointStage(e.endpoint)
 	if len(plan) == 0 {
@@ -354,3 +405,11 @@ func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[ope
 }
 
 func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {}
+
+func (executor EmptyHookExecutor) GetSeatNonBid() []openrtb_ext.SeatNonBid {
+	return nil
+}
+
+func (executor EmptyHookExecutor) GetSeatBids() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+	return nil
+}
@@ -44,6 +44,8 @@ func TestEmptyHookExecutor(t *testing.T) {
 	outcomes := executor.GetOutcomes()
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
 	assert.Empty(t, outcomes, "EmptyHookExecutor shouldn't return stage outcomes.")
+	assert.Nil(t, executor.GetSeatNonBid(), "EmptyHookExecutor shouldn't return seat non-bids.")
+	assert.Nil(t, executor.GetSeatBids(), "EmptyHookExecutor shouldn't return seat bids.")
 
 	assert.Nil(t, entrypointRejectErr, "EmptyHookExecutor shouldn't return reject error at entrypoint stage.")
 	assert.Equal(t, body, entrypointBody, "EmptyHookExecutor shouldn't change body at entrypoint stage.")
@@ -1433,6 +1435,67 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 	}
 }
 
+func TestGetSeatNonBid(t *testing.T) {
+	nonBid := openrtb_ext.NonBid{ImpId: "imp-id", StatusCode: 300}
+	executor := NewHookExecutor(hooks.EmptyPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	executor.pushStageOutcome(StageOutcome{
+		Entity: entity("bidder1"),
+		Stage:  hooks.StageRawBidderResponse.String(),
+		Groups: []GroupOutcome{
+			{InvocationResults: []HookOutcome{
+				{SeatNonBid: []openrtb_ext.SeatNonBid{{Seat: "bidder1", NonBid: []openrtb_ext.NonBid{nonBid}}}},
+				{},
+			}},
+		},
+	})
+	executor.pushStageOutcome(StageOutcome{
+		Entity: entity("bidder2"),
+		Stage:  hooks.StageRawBidderResponse.String(),
+		Groups: []GroupOutcome{
+			{InvocationResults: []HookOutcome{
+				{SeatNonBid: []openrtb_ext.SeatNonBid{{Seat: "bidder2", NonBid: []openrtb_ext.NonBid{nonBid}}}},
+			}},
+		},
+	})
+
+	expectedSeatNonBid := []openrtb_ext.SeatNonBid{
+		{Seat: "bidder1", NonBid: []openrtb_ext.NonBid{nonBid}},
+		{Seat: "bidder2", NonBid: []openrtb_ext.NonBid{nonBid}},
+	}
+	assert.Equal(t, expectedSeatNonBid, executor.GetSeatNonBid())
+}
+
+func TestGetSeatBids(t *testing.T) {
+	bid1 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid1"}}
+	bid2 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid2"}}
+	bid3 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid3"}}
+	executor := NewHookExecutor(hooks.EmptyPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	assert.Nil(t, executor.GetSeatBids())
+
+	executor.pushStageOutcome(StageOutcome{
+		Entity: entityAuctionRequest,
+		Stage:  hooks.StageProcessedAuctionRequest.String(),
+		Groups: []GroupOutcome{
+			{InvocationResults: []HookOutcome{
+				{SeatBids: []entities.PbsOrtbSeatBid{{Seat: "bidder1", Currency: "USD", Bids: []*entities.PbsOrtbBid{bid1}}}},
+				{SeatBids: []entities.PbsOrtbSeatBid{{Seat: "bidder2", Currency: "USD"}}},
+			}},
+			{InvocationResults: []HookOutcome{
+				{SeatBids: []entities.PbsOrtbSeatBid{
+					{Seat: "bidder1", Currency: "USD", Bids: []*entities.PbsOrtbBid{bid2}},
+					{Seat: "bidder3", Currency: "EUR", Bids: []*entities.PbsOrtbBid{bid3}},
+				}},
+			}},
+		},
+	})
+
+	expectedSeatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"bidder1": {Seat: "bidder1", Currency: "USD", Bids: []*entities.PbsOrtbBid{bid1, bid2}},
+		"bidder3": {Seat: "bidder3", Currency: "EUR", Bids: []*entities.PbsOrtbBid{bid3}},
+	}
+	assert.Equal(t, expectedSeatBids, executor.GetSeatBids())
+}
+
 func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 	foobarModuleCtx := &moduleContexts{ctxs: map[string]hookstage.ModuleContext{"foobar": nil}}
 
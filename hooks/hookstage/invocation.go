@@ -3,7 +3,9 @@ package hookstage
 import (
 	"encoding/json"
 
+	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
@@ -16,7 +18,9 @@ type HookResult[T any] struct {
 	Warnings      []string
 	DebugMessages []string
 	AnalyticsTags hookanalytics.Analytics
-	ModuleContext ModuleContext // holds values that the module wants to pass to itself at later stages
+	ModuleContext ModuleContext             // holds values that the module wants to pass to itself at later stages
+	SeatNonBid    []openrtb_ext.SeatNonBid  // bids rejected by the hook to be reported in the seat non-bid response extension
+	SeatBids      []entities.PbsOrtbSeatBid // bids supplied by the hook in place of calling the bidders, added to the bidder responses before the all processed bid responses stage
 }
 
 // ModuleInvocationContext holds data passed to the module hook during invocation.
@@ -25,6 +29,8 @@ type ModuleInvocationContext struct {
 	AccountConfig json.RawMessage
 	// Endpoint represents the path of the current endpoint.
 	Endpoint string
+	// AccountID represents the ID of the account the request belongs to, if resolved.
+	AccountID string
 	// ModuleContext holds values that the module passes to itself from the previous stages.
 	ModuleContext ModuleContext
 }
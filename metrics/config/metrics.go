@@ -217,6 +217,20 @@ func (me *MultiMetricsEngine) RecordSyncerSet(key string, status metrics.SyncerS
 	}
 }
 
+// RecordUIDCookieSize across all engines
+func (me *MultiMetricsEngine) RecordUIDCookieSize(encoding metrics.CookieEncoding, size int) {
+	for _, thisME := range *me {
+		thisME.RecordUIDCookieSize(encoding, size)
+	}
+}
+
+// RecordSetUidCookieAttributes across all engines
+func (me *MultiMetricsEngine) RecordSetUidCookieAttributes(labels metrics.CookieAttributesLabels) {
+	for _, thisME := range *me {
+		thisME.RecordSetUidCookieAttributes(labels)
+	}
+}
+
 // RecordStoredReqCacheResult across all engines
 func (me *MultiMetricsEngine) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 	for _, thisME := range *me {
@@ -280,6 +294,13 @@ func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ex
 	}
 }
 
+// RecordTCF2LegalBasis across all engines
+func (me *MultiMetricsEngine) RecordTCF2LegalBasis(labels metrics.TCF2LegalBasisLabels) {
+	for _, thisME := range *me {
+		thisME.RecordTCF2LegalBasis(labels)
+	}
+}
+
 // RecordDebugRequest across all engines
 func (me *MultiMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 	for _, thisME := range *me {
@@ -463,6 +484,14 @@ func (me *NilMetricsEngine) RecordSetUid(status metrics.SetUidStatus) {
 func (me *NilMetricsEngine) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
 }
 
+// RecordUIDCookieSize as a noop
+func (me *NilMetricsEngine) RecordUIDCookieSize(encoding metrics.CookieEncoding, size int) {
+}
+
+// RecordSetUidCookieAttributes as a noop
+func (me *NilMetricsEngine) RecordSetUidCookieAttributes(labels metrics.CookieAttributesLabels) {
+}
+
 // RecordStoredReqCacheResult as a noop
 func (me *NilMetricsEngine) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 }
@@ -495,6 +524,10 @@ func (me *NilMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels)
 func (me *NilMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.BidderName) {
 }
 
+// RecordTCF2LegalBasis as a noop
+func (me *NilMetricsEngine) RecordTCF2LegalBasis(labels metrics.TCF2LegalBasisLabels) {
+}
+
 // RecordAdapterGDPRRequestBlocked as a noop
 func (me *NilMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
@@ -144,6 +144,7 @@ func TestMultiMetricsEngine(t *testing.T) {
 
 	metricsEngine.RecordAdapterBuyerUIDScrubbed(openrtb_ext.BidderAppnexus)
 	metricsEngine.RecordAdapterGDPRRequestBlocked(openrtb_ext.BidderAppnexus)
+	metricsEngine.RecordTCF2LegalBasis(metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityPassID, LegalBasis: metrics.TCF2LegalBasisConsent, Allowed: true})
 
 	metricsEngine.RecordRequestQueueTime(false, metrics.ReqTypeVideo, time.Duration(1))
 
@@ -190,6 +191,7 @@ func TestMultiMetricsEngine(t *testing.T) {
 
 	VerifyMetrics(t, "AdapterMetrics.appNexus.BuyerUIDScrubbed", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].BuyerUIDScrubbed.Count(), 1)
 	VerifyMetrics(t, "AdapterMetrics.appNexus.GDPRRequestBlocked", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].GDPRRequestBlocked.Count(), 1)
+	VerifyMetrics(t, "PrivacyTCF2LegalBasis.pass_id.consent.Allowed", goEngine.PrivacyTCF2LegalBasis[metrics.TCF2ActivityPassID][metrics.TCF2LegalBasisConsent].Allowed.Count(), 1)
 
 	// verify that each module has its own metric recorded
 	for module, stages := range modulesStages {
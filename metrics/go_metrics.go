@@ -45,6 +45,7 @@ type Metrics struct {
 	SetUidMeter           metrics.Meter
 	SetUidStatusMeter     map[SetUidStatus]metrics.Meter
 	SyncerSetsMeter       map[string]map[SyncerSetUidStatus]metrics.Meter
+	UIDCookieSize         map[CookieEncoding]metrics.Histogram
 
 	// Media types found in the "imp" JSON object
 	ImpsTypeBanner metrics.Meter
@@ -60,8 +61,11 @@ type Metrics struct {
 	PrivacyCCPARequest       metrics.Meter
 	PrivacyCCPARequestOptOut metrics.Meter
 	PrivacyCOPPARequest      metrics.Meter
+	PrivacyGPCRequest        metrics.Meter
+	PrivacyGPCRequestOptOut  metrics.Meter
 	PrivacyLMTRequest        metrics.Meter
 	PrivacyTCFRequestVersion map[TCFVersionValue]metrics.Meter
+	PrivacyTCF2LegalBasis    map[TCF2Activity]map[TCF2LegalBasis]*TCF2LegalBasisMeters
 
 	AdapterMetrics map[string]*AdapterMetrics
 	// Don't export accountMetrics because we need helper functions here to insure its properly populated dynamically
@@ -114,6 +118,12 @@ type MarkupDeliveryMetrics struct {
 	NurlMeter metrics.Meter
 }
 
+// TCF2LegalBasisMeters counts the TCF2 enforcement decisions made on a legal basis by outcome
+type TCF2LegalBasisMeters struct {
+	Allowed metrics.Meter
+	Blocked metrics.Meter
+}
+
 type accountMetrics struct {
 	requestMeter      metrics.Meter
 	debugRequestMeter metrics.Meter
@@ -180,6 +190,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SetUidMeter:                    blankMeter,
 		SetUidStatusMeter:              make(map[SetUidStatus]metrics.Meter),
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
+		UIDCookieSize:                  make(map[CookieEncoding]metrics.Histogram),
 		StoredResponsesMeter:           blankMeter,
 
 		ImpsTypeBanner: blankMeter,
@@ -193,8 +204,11 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		PrivacyCCPARequest:       blankMeter,
 		PrivacyCCPARequestOptOut: blankMeter,
 		PrivacyCOPPARequest:      blankMeter,
+		PrivacyGPCRequest:        blankMeter,
+		PrivacyGPCRequestOptOut:  blankMeter,
 		PrivacyLMTRequest:        blankMeter,
 		PrivacyTCFRequestVersion: make(map[TCFVersionValue]metrics.Meter, len(TCFVersions())),
+		PrivacyTCF2LegalBasis:    make(map[TCF2Activity]map[TCF2LegalBasis]*TCF2LegalBasisMeters, len(TCF2Activities())),
 
 		AdapterMetrics:  make(map[string]*AdapterMetrics, len(exchanges)),
 		accountMetrics:  make(map[string]*accountMetrics),
@@ -238,6 +252,13 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		newMetrics.PrivacyTCFRequestVersion[v] = blankMeter
 	}
 
+	for _, a := range TCF2Activities() {
+		newMetrics.PrivacyTCF2LegalBasis[a] = make(map[TCF2LegalBasis]*TCF2LegalBasisMeters, len(TCF2LegalBases()))
+		for _, b := range TCF2LegalBases() {
+			newMetrics.PrivacyTCF2LegalBasis[a][b] = &TCF2LegalBasisMeters{Allowed: blankMeter, Blocked: blankMeter}
+		}
+	}
+
 	for _, dt := range StoredDataTypes() {
 		newMetrics.StoredDataFetchTimer[dt] = make(map[StoredDataFetchType]metrics.Timer)
 		newMetrics.StoredDataErrorMeter[dt] = make(map[StoredDataError]metrics.Meter)
@@ -326,6 +347,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.SetUidStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_requests.%s", s), registry)
 	}
 
+	for _, e := range CookieEncodings() {
+		newMetrics.UIDCookieSize[e] = metrics.GetOrRegisterHistogram(fmt.Sprintf("uid_cookie_size.%s", e), registry, metrics.NewExpDecaySample(1028, 0.015))
+	}
+
 	for _, syncerKey := range syncerKeys {
 		newMetrics.SyncerRequestsMeter[syncerKey] = make(map[SyncerCookieSyncStatus]metrics.Meter)
 		for _, status := range SyncerRequestStatuses() {
@@ -363,10 +388,20 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrivacyCCPARequest = metrics.GetOrRegisterMeter("privacy.request.ccpa.specified", registry)
 	newMetrics.PrivacyCCPARequestOptOut = metrics.GetOrRegisterMeter("privacy.request.ccpa.opt-out", registry)
 	newMetrics.PrivacyCOPPARequest = metrics.GetOrRegisterMeter("privacy.request.coppa", registry)
+	newMetrics.PrivacyGPCRequest = metrics.GetOrRegisterMeter("privacy.request.gpc.specified", registry)
+	newMetrics.PrivacyGPCRequestOptOut = metrics.GetOrRegisterMeter("privacy.request.gpc.opt-out", registry)
 	newMetrics.PrivacyLMTRequest = metrics.GetOrRegisterMeter("privacy.request.lmt", registry)
 	for _, version := range TCFVersions() {
 		newMetrics.PrivacyTCFRequestVersion[version] = metrics.GetOrRegisterMeter(fmt.Sprintf("privacy.request.tcf.%s", string(version)), registry)
 	}
+	for _, activity := range TCF2Activities() {
+		for _, basis := range TCF2LegalBases() {
+			newMetrics.PrivacyTCF2LegalBasis[activity][basis] = &TCF2LegalBasisMeters{
+				Allowed: metrics.GetOrRegisterMeter(fmt.Sprintf("privacy.tcf.v2.%s.%s.allowed", string(activity), string(basis)), registry),
+				Blocked: metrics.GetOrRegisterMeter(fmt.Sprintf("privacy.tcf.v2.%s.%s.blocked", string(activity), string(basis)), registry),
+			}
+		}
+	}
 
 	newMetrics.AdsCertRequestsSuccess = metrics.GetOrRegisterMeter("ads_cert_requests.ok", registry)
 	newMetrics.AdsCertRequestsFailure = metrics.GetOrRegisterMeter("ads_cert_requests.failed", registry)
@@ -863,6 +898,32 @@ func (me *Metrics) RecordSyncerSet(key string, status SyncerSetUidStatus) {
 	}
 }
 
+// RecordUIDCookieSize implements a part of the MetricsEngine interface. Records the size in bytes of a uids
+// cookie written in the given encoding
+func (me *Metrics) RecordUIDCookieSize(encoding CookieEncoding, size int) {
+	if histogram, exists := me.UIDCookieSize[encoding]; exists {
+		histogram.Update(int64(size))
+	}
+}
+
+// RecordSetUidCookieAttributes implements a part of the MetricsEngine interface. Records the attributes a uids
+// cookie was written with. Cohorts are defined by the host, so their meters are registered when first used
+func (me *Metrics) RecordSetUidCookieAttributes(labels CookieAttributesLabels) {
+	cohort := labels.Cohort
+	if cohort == "" {
+		cohort = CookieCohortNone
+	}
+	sameSite := labels.SameSite
+	if sameSite == "" {
+		sameSite = CookieSameSiteDefault
+	}
+	partitioned := "unpartitioned"
+	if labels.Partitioned {
+		partitioned = "partitioned"
+	}
+	metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_cookie_writes.%s.%s.%s", cohort, sameSite, partitioned), me.MetricsRegistry).Mark(1)
+}
+
 // RecordStoredReqCacheResult implements a part of the MetricsEngine interface. Records the
 // cache hits and misses when looking up stored requests
 func (me *Metrics) RecordStoredReqCacheResult(cacheResult CacheResult, inc int) {
@@ -926,11 +987,31 @@ func (me *Metrics) RecordRequestPrivacy(privacy PrivacyLabels) {
 		}
 	}
 
+	if privacy.GPCProvided {
+		me.PrivacyGPCRequest.Mark(1)
+		if privacy.GPCEnforced {
+			me.PrivacyGPCRequestOptOut.Mark(1)
+		}
+	}
+
 	if privacy.LMTEnforced {
 		me.PrivacyLMTRequest.Mark(1)
 	}
 }
 
+// RecordTCF2LegalBasis increments the meter of the legal basis evaluated for a TCF2 enforcement decision
+func (me *Metrics) RecordTCF2LegalBasis(labels TCF2LegalBasisLabels) {
+	meters, ok := me.PrivacyTCF2LegalBasis[labels.Activity][labels.LegalBasis]
+	if !ok {
+		return
+	}
+	if labels.Allowed {
+		meters.Allowed.Mark(1)
+	} else {
+		meters.Blocked.Mark(1)
+	}
+}
+
 func (me *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName) {
 	adapterStr := adapterName.String()
 	if me.MetricsDisabled.AdapterBuyerUIDScrubbed {
@@ -66,6 +66,8 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "privacy.request.ccpa.specified", m.PrivacyCCPARequest)
 	ensureContains(t, registry, "privacy.request.ccpa.opt-out", m.PrivacyCCPARequestOptOut)
 	ensureContains(t, registry, "privacy.request.coppa", m.PrivacyCOPPARequest)
+	ensureContains(t, registry, "privacy.request.gpc.specified", m.PrivacyGPCRequest)
+	ensureContains(t, registry, "privacy.request.gpc.opt-out", m.PrivacyGPCRequestOptOut)
 	ensureContains(t, registry, "privacy.request.lmt", m.PrivacyLMTRequest)
 	ensureContains(t, registry, "privacy.request.tcf.v2", m.PrivacyTCFRequestVersion[TCFVersionV2])
 	ensureContains(t, registry, "privacy.request.tcf.err", m.PrivacyTCFRequestVersion[TCFVersionErr])
@@ -767,6 +769,16 @@ func TestRecordRequestPrivacy(t *testing.T) {
 		COPPAEnforced: true,
 	})
 
+	// GPC
+	m.RecordRequestPrivacy(PrivacyLabels{
+		GPCEnforced: true,
+		GPCProvided: true,
+	})
+	m.RecordRequestPrivacy(PrivacyLabels{
+		GPCEnforced: false,
+		GPCProvided: true,
+	})
+
 	// LMT
 	m.RecordRequestPrivacy(PrivacyLabels{
 		LMTEnforced: true,
@@ -785,11 +797,28 @@ func TestRecordRequestPrivacy(t *testing.T) {
 	assert.Equal(t, m.PrivacyCCPARequest.Count(), int64(2), "CCPA")
 	assert.Equal(t, m.PrivacyCCPARequestOptOut.Count(), int64(1), "CCPA Opt Out")
 	assert.Equal(t, m.PrivacyCOPPARequest.Count(), int64(1), "COPPA")
+	assert.Equal(t, m.PrivacyGPCRequest.Count(), int64(2), "GPC")
+	assert.Equal(t, m.PrivacyGPCRequestOptOut.Count(), int64(1), "GPC Opt Out")
 	assert.Equal(t, m.PrivacyLMTRequest.Count(), int64(1), "LMT")
 	assert.Equal(t, m.PrivacyTCFRequestVersion[TCFVersionErr].Count(), int64(1), "TCF Err")
 	assert.Equal(t, m.PrivacyTCFRequestVersion[TCFVersionV2].Count(), int64(1), "TCF V2")
 }
 
+func TestRecordTCF2LegalBasis(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordTCF2LegalBasis(TCF2LegalBasisLabels{Activity: TCF2ActivitySync, LegalBasis: TCF2LegalBasisFlexible, Allowed: true})
+	m.RecordTCF2LegalBasis(TCF2LegalBasisLabels{Activity: TCF2ActivitySync, LegalBasis: TCF2LegalBasisPublisherRestriction, Allowed: false})
+	m.RecordTCF2LegalBasis(TCF2LegalBasisLabels{Activity: TCF2ActivitySync, LegalBasis: TCF2LegalBasisPublisherRestriction, Allowed: false})
+	m.RecordTCF2LegalBasis(TCF2LegalBasisLabels{Activity: "unknown", LegalBasis: TCF2LegalBasisConsent, Allowed: true})
+
+	assert.Equal(t, int64(1), m.PrivacyTCF2LegalBasis[TCF2ActivitySync][TCF2LegalBasisFlexible].Allowed.Count(), "flexible allowed")
+	assert.Equal(t, int64(0), m.PrivacyTCF2LegalBasis[TCF2ActivitySync][TCF2LegalBasisFlexible].Blocked.Count(), "flexible blocked")
+	assert.Equal(t, int64(2), m.PrivacyTCF2LegalBasis[TCF2ActivitySync][TCF2LegalBasisPublisherRestriction].Blocked.Count(), "publisher restriction blocked")
+	assert.Equal(t, int64(0), m.PrivacyTCF2LegalBasis[TCF2ActivityBidRequest][TCF2LegalBasisConsent].Allowed.Count(), "unknown activity")
+}
+
 func TestRecordAdapterBuyerUIDScrubbed(t *testing.T) {
 	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
 	adapter := "AnyName"
@@ -947,6 +976,17 @@ func TestRecordSyncerSet(t *testing.T) {
 	assert.Equal(t, m.SyncerSetsMeter["foo"][SyncerSetUidCleared].Count(), int64(1))
 }
 
+func TestRecordSetUidCookieAttributes(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Adapter1")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordSetUidCookieAttributes(CookieAttributesLabels{})
+	m.RecordSetUidCookieAttributes(CookieAttributesLabels{Cohort: "partitioned", SameSite: "none", Partitioned: true})
+
+	assert.Equal(t, int64(1), metrics.GetOrRegisterMeter("setuid_cookie_writes.none.default.unpartitioned", registry).Count())
+	assert.Equal(t, int64(1), metrics.GetOrRegisterMeter("setuid_cookie_writes.partitioned.none.partitioned", registry).Count())
+}
+
 func TestStoredResponses(t *testing.T) {
 	testCases := []struct {
 		description                           string
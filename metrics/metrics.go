@@ -66,9 +66,18 @@ type PrivacyLabels struct {
 	COPPAEnforced  bool
 	GDPREnforced   bool
 	GDPRTCFVersion TCFVersionValue
+	GPCEnforced    bool
+	GPCProvided    bool
 	LMTEnforced    bool
 }
 
+// TCF2LegalBasisLabels defines metrics describing the legal basis evaluated for a TCF2 enforcement decision.
+type TCF2LegalBasisLabels struct {
+	Activity   TCF2Activity
+	LegalBasis TCF2LegalBasis
+	Allowed    bool
+}
+
 type ModuleLabels struct {
 	Module    string
 	Stage     string
@@ -328,6 +337,58 @@ func TCFVersionToValue(version int) TCFVersionValue {
 	return TCFVersionErr
 }
 
+// TCF2Activity : The activities for which TCF2 enforcement decisions are explained
+type TCF2Activity string
+
+const (
+	TCF2ActivitySync       TCF2Activity = "sync"
+	TCF2ActivityBidRequest TCF2Activity = "bid_request"
+	TCF2ActivityPassID     TCF2Activity = "pass_id"
+	TCF2ActivityPassGeo    TCF2Activity = "pass_geo"
+)
+
+// TCF2Activities returns the possible values for the TCF2 explained activities
+func TCF2Activities() []TCF2Activity {
+	return []TCF2Activity{
+		TCF2ActivitySync,
+		TCF2ActivityBidRequest,
+		TCF2ActivityPassID,
+		TCF2ActivityPassGeo,
+	}
+}
+
+// TCF2LegalBasis : The legal basis on which a TCF2 enforcement decision was made
+type TCF2LegalBasis string
+
+const (
+	TCF2LegalBasisNotEnforced            TCF2LegalBasis = "not_enforced"
+	TCF2LegalBasisConsent                TCF2LegalBasis = "consent"
+	TCF2LegalBasisLegitimateInterest     TCF2LegalBasis = "legitimate_interest"
+	TCF2LegalBasisFlexible               TCF2LegalBasis = "flexible_basis"
+	TCF2LegalBasisPublisherRestriction   TCF2LegalBasis = "publisher_restriction"
+	TCF2LegalBasisVendorException        TCF2LegalBasis = "vendor_exception"
+	TCF2LegalBasisPurposeOneTreatment    TCF2LegalBasis = "purpose_one_treatment"
+	TCF2LegalBasisBasicEnforcementVendor TCF2LegalBasis = "basic_enforcement_vendor"
+	TCF2LegalBasisNoConsent              TCF2LegalBasis = "no_consent"
+	TCF2LegalBasisNone                   TCF2LegalBasis = "none"
+)
+
+// TCF2LegalBases returns the possible values for the TCF2 legal basis
+func TCF2LegalBases() []TCF2LegalBasis {
+	return []TCF2LegalBasis{
+		TCF2LegalBasisNotEnforced,
+		TCF2LegalBasisConsent,
+		TCF2LegalBasisLegitimateInterest,
+		TCF2LegalBasisFlexible,
+		TCF2LegalBasisPublisherRestriction,
+		TCF2LegalBasisVendorException,
+		TCF2LegalBasisPurposeOneTreatment,
+		TCF2LegalBasisBasicEnforcementVendor,
+		TCF2LegalBasisNoConsent,
+		TCF2LegalBasisNone,
+	}
+}
+
 // CookieSyncStatus is a status code resulting from a call to the /cookie_sync endpoint.
 type CookieSyncStatus string
 
@@ -339,6 +400,7 @@ const (
 	CookieSyncAccountBlocked         CookieSyncStatus = "acct_blocked"
 	CookieSyncAccountConfigMalformed CookieSyncStatus = "acct_config_malformed"
 	CookieSyncAccountInvalid         CookieSyncStatus = "acct_invalid"
+	CookieSyncRateLimited            CookieSyncStatus = "rate_limited"
 )
 
 // CookieSyncStatuses returns possible cookie sync statuses.
@@ -351,6 +413,7 @@ func CookieSyncStatuses() []CookieSyncStatus {
 		CookieSyncAccountBlocked,
 		CookieSyncAccountConfigMalformed,
 		CookieSyncAccountInvalid,
+		CookieSyncRateLimited,
 	}
 }
 
@@ -387,6 +450,8 @@ const (
 	SetUidAccountConfigMalformed SetUidStatus = "acct_config_malformed"
 	SetUidAccountInvalid         SetUidStatus = "acct_invalid"
 	SetUidSyncerUnknown          SetUidStatus = "syncer_unknown"
+	SetUidRejected               SetUidStatus = "rejected"
+	SetUidReplayCacheFull        SetUidStatus = "replay_cache_full"
 )
 
 // SetUidStatuses returns possible setuid statuses.
@@ -400,6 +465,8 @@ func SetUidStatuses() []SetUidStatus {
 		SetUidAccountConfigMalformed,
 		SetUidAccountInvalid,
 		SetUidSyncerUnknown,
+		SetUidRejected,
+		SetUidReplayCacheFull,
 	}
 }
 
@@ -419,6 +486,39 @@ func SyncerSetUidStatuses() []SyncerSetUidStatus {
 	}
 }
 
+// CookieAttributesLabels are the attributes a uids cookie was written with by the /setuid endpoint.
+type CookieAttributesLabels struct {
+	Cohort      string
+	SameSite    string
+	Partitioned bool
+}
+
+const (
+	// CookieCohortNone labels the uids cookies written outside of any experiment cohort.
+	CookieCohortNone = "none"
+	// CookieCohortOther labels the uids cookies written in a cohort the host didn't define, such as the cohorts of
+	// accounts, to bound the number of labels.
+	CookieCohortOther = "other"
+	// CookieSameSiteDefault labels the uids cookies written without the SameSite attribute.
+	CookieSameSiteDefault = "default"
+)
+
+// CookieEncoding is the encoding of the uids cookie.
+type CookieEncoding string
+
+const (
+	CookieEncodingJSON    CookieEncoding = "json"
+	CookieEncodingCompact CookieEncoding = "compact"
+)
+
+// CookieEncodings returns possible uids cookie encodings.
+func CookieEncodings() []CookieEncoding {
+	return []CookieEncoding{
+		CookieEncodingJSON,
+		CookieEncodingCompact,
+	}
+}
+
 // MetricsEngine is a generic interface to record PBS metrics into the desired backend
 // The first three metrics function fire off once per incoming request, so total metrics
 // will equal the total number of incoming requests. The remaining 5 fire off per outgoing
@@ -446,6 +546,8 @@ type MetricsEngine interface {
 	RecordSyncerRequest(key string, status SyncerCookieSyncStatus)
 	RecordSetUid(status SetUidStatus)
 	RecordSyncerSet(key string, status SyncerSetUidStatus)
+	RecordUIDCookieSize(encoding CookieEncoding, size int)
+	RecordSetUidCookieAttributes(labels CookieAttributesLabels)
 	RecordStoredReqCacheResult(cacheResult CacheResult, inc int)
 	RecordStoredImpCacheResult(cacheResult CacheResult, inc int)
 	RecordAccountCacheResult(cacheResult CacheResult, inc int)
@@ -457,6 +559,7 @@ type MetricsEngine interface {
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	RecordTCF2LegalBasis(labels TCF2LegalBasisLabels)
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordAdsCertReq(success bool)
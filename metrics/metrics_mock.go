@@ -121,6 +121,16 @@ func (me *MetricsEngineMock) RecordSyncerSet(key string, status SyncerSetUidStat
 	me.Called(key, status)
 }
 
+// RecordUIDCookieSize mock
+func (me *MetricsEngineMock) RecordUIDCookieSize(encoding CookieEncoding, size int) {
+	me.Called(encoding, size)
+}
+
+// RecordSetUidCookieAttributes mock
+func (me *MetricsEngineMock) RecordSetUidCookieAttributes(labels CookieAttributesLabels) {
+	me.Called(labels)
+}
+
 // RecordStoredReqCacheResult mock
 func (me *MetricsEngineMock) RecordStoredReqCacheResult(cacheResult CacheResult, inc int) {
 	me.Called(cacheResult, inc)
@@ -166,6 +176,11 @@ func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb
 	me.Called(adapterName)
 }
 
+// RecordTCF2LegalBasis mock
+func (me *MetricsEngineMock) RecordTCF2LegalBasis(labels TCF2LegalBasisLabels) {
+	me.Called(labels)
+}
+
 // RecordDebugRequest mock
 func (me *MetricsEngineMock) RecordDebugRequest(debugEnabled bool, pubId string) {
 	me.Called(debugEnabled, pubId)
@@ -13,6 +13,7 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		bidTypeValues             = []string{markupDeliveryAdm, markupDeliveryNurl}
 		boolValues                = boolValuesAsString()
 		cacheResultValues         = enumAsString(metrics.CacheResults())
+		cookieEncodingValues      = enumAsString(metrics.CookieEncodings())
 		connectionErrorValues     = []string{connectionAcceptError, connectionCloseError}
 		cookieSyncStatusValues    = enumAsString(metrics.CookieSyncStatuses())
 		cookieValues              = enumAsString(metrics.CookieTypes())
@@ -26,6 +27,8 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		syncerRequestStatusValues = enumAsString(metrics.SyncerRequestStatuses())
 		syncerSetsStatusValues    = enumAsString(metrics.SyncerSetUidStatuses())
 		tcfVersionValues          = enumAsString(metrics.TCFVersions())
+		tcf2ActivityValues        = enumAsString(metrics.TCF2Activities())
+		tcf2LegalBasisValues      = enumAsString(metrics.TCF2LegalBases())
 	)
 
 	preloadLabelValuesForCounter(m.connectionsError, map[string][]string{
@@ -40,6 +43,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		statusLabel: setUidStatusValues,
 	})
 
+	preloadLabelValuesForHistogram(m.uidCookieSize, map[string][]string{
+		encodingLabel: cookieEncodingValues,
+	})
+
 	preloadLabelValuesForCounter(m.impressions, map[string][]string{
 		isBannerLabel: boolValues,
 		isVideoLabel:  boolValues,
@@ -229,6 +236,12 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		versionLabel: tcfVersionValues,
 	})
 
+	preloadLabelValuesForCounter(m.privacyTCF2LegalBasis, map[string][]string{
+		activityLabel:   tcf2ActivityValues,
+		legalBasisLabel: tcf2LegalBasisValues,
+		allowedLabel:    boolValues,
+	})
+
 	if !m.metricsDisabled.AdapterBuyerUIDScrubbed {
 		preloadLabelValuesForCounter(m.adapterScrubbedBuyerUIDs, map[string][]string{
 			adapterLabel: adapterValues,
@@ -50,8 +50,10 @@ type Metrics struct {
 	tlsHandhakeTimer             prometheus.Histogram
 	privacyCCPA                  *prometheus.CounterVec
 	privacyCOPPA                 *prometheus.CounterVec
+	privacyGPC                   *prometheus.CounterVec
 	privacyLMT                   *prometheus.CounterVec
 	privacyTCF                   *prometheus.CounterVec
+	privacyTCF2LegalBasis        *prometheus.CounterVec
 	storedResponses              prometheus.Counter
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
@@ -80,6 +82,8 @@ type Metrics struct {
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
 	syncerSets     *prometheus.CounterVec
+	uidCookieSize  *prometheus.HistogramVec
+	setUidCookies  *prometheus.CounterVec
 
 	// Account Metrics
 	accountRequests                       *prometheus.CounterVec
@@ -106,23 +110,30 @@ type Metrics struct {
 const (
 	accountLabel         = "account"
 	actionLabel          = "action"
+	activityLabel        = "activity"
 	adapterErrorLabel    = "adapter_error"
 	adapterLabel         = "adapter"
+	allowedLabel         = "allowed"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
+	cohortLabel          = "cohort"
 	cookieLabel          = "cookie"
+	encodingLabel        = "encoding"
 	hasBidsLabel         = "has_bids"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
 	isNativeLabel        = "native"
 	isVideoLabel         = "video"
+	legalBasisLabel      = "legal_basis"
 	markupDeliveryLabel  = "delivery"
 	optOutLabel          = "opt_out"
 	overheadTypeLabel    = "overhead_type"
+	partitionedLabel     = "partitioned"
 	privacyBlockedLabel  = "privacy_blocked"
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
+	sameSiteLabel        = "same_site"
 	stageLabel           = "stage"
 	statusLabel          = "status"
 	successLabel         = "success"
@@ -166,6 +177,7 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 	cacheWriteTimeBuckets := []float64{0.001, 0.002, 0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 1}
 	priceBuckets := []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}
 	queuedRequestTimeBuckets := []float64{0, 1, 5, 30, 60, 120, 180, 240, 300}
+	cookieSizeBuckets := []float64{256, 512, 1024, 1536, 2048, 2560, 3072, 3584, 4096, 8192}
 	overheadTimeBuckets := []float64{0.05, 0.06, 0.07, 0.08, 0.09, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
 
 	metrics := Metrics{}
@@ -199,6 +211,17 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of set uid requests to Prebid Server.",
 		[]string{statusLabel})
 
+	metrics.uidCookieSize = newHistogramVec(cfg, reg,
+		"uid_cookie_size_bytes",
+		"Size in bytes of the uids cookies written by Prebid Server labeled by encoding.",
+		[]string{encodingLabel},
+		cookieSizeBuckets)
+
+	metrics.setUidCookies = newCounter(cfg, reg,
+		"setuid_cookie_writes",
+		"Count of uids cookies written by the set uid endpoint labeled by experiment cohort, SameSite and Partitioned attributes.",
+		[]string{cohortLabel, sameSiteLabel, partitionedLabel})
+
 	metrics.impressions = newCounter(cfg, reg,
 		"impressions_requests",
 		"Count of requested impressions to Prebid Server labeled by type.",
@@ -320,6 +343,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of total requests to Prebid Server where CCPA was provided by source and opt-out .",
 		[]string{sourceLabel, optOutLabel})
 
+	metrics.privacyGPC = newCounter(cfg, reg,
+		"privacy_gpc",
+		"Count of total requests to Prebid Server where the Global Privacy Control signal was provided by source and enforcement of the opt-out.",
+		[]string{sourceLabel, optOutLabel})
+
 	metrics.privacyCOPPA = newCounter(cfg, reg,
 		"privacy_coppa",
 		"Count of total requests to Prebid Server where the COPPA flag was set by source",
@@ -330,6 +358,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of TCF versions for requests where GDPR was enforced by source and version.",
 		[]string{versionLabel, sourceLabel})
 
+	metrics.privacyTCF2LegalBasis = newCounter(cfg, reg,
+		"privacy_tcf2_legal_basis",
+		"Count of TCF2 enforcement decisions by activity, legal basis evaluated and outcome.",
+		[]string{activityLabel, legalBasisLabel, allowedLabel})
+
 	metrics.privacyLMT = newCounter(cfg, reg,
 		"privacy_lmt",
 		"Count of total requests to Prebid Server where the LMT flag was set by source",
@@ -884,6 +917,28 @@ func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus)
 	}).Inc()
 }
 
+func (m *Metrics) RecordUIDCookieSize(encoding metrics.CookieEncoding, size int) {
+	m.uidCookieSize.With(prometheus.Labels{
+		encodingLabel: string(encoding),
+	}).Observe(float64(size))
+}
+
+func (m *Metrics) RecordSetUidCookieAttributes(labels metrics.CookieAttributesLabels) {
+	cohort := labels.Cohort
+	if cohort == "" {
+		cohort = metrics.CookieCohortNone
+	}
+	sameSite := labels.SameSite
+	if sameSite == "" {
+		sameSite = metrics.CookieSameSiteDefault
+	}
+	m.setUidCookies.With(prometheus.Labels{
+		cohortLabel:      cohort,
+		sameSiteLabel:    sameSite,
+		partitionedLabel: strconv.FormatBool(labels.Partitioned),
+	}).Inc()
+}
+
 func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
 	m.storedRequestCacheResult.With(prometheus.Labels{
 		cacheResultLabel: string(cacheResult),
@@ -952,6 +1007,13 @@ func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
 		}).Inc()
 	}
 
+	if privacy.GPCProvided {
+		m.privacyGPC.With(prometheus.Labels{
+			sourceLabel: sourceRequest,
+			optOutLabel: strconv.FormatBool(privacy.GPCEnforced),
+		}).Inc()
+	}
+
 	if privacy.LMTEnforced {
 		m.privacyLMT.With(prometheus.Labels{
 			sourceLabel: sourceRequest,
@@ -979,6 +1041,14 @@ func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidder
 	}).Inc()
 }
 
+func (m *Metrics) RecordTCF2LegalBasis(labels metrics.TCF2LegalBasisLabels) {
+	m.privacyTCF2LegalBasis.With(prometheus.Labels{
+		activityLabel:   string(labels.Activity),
+		legalBasisLabel: string(labels.LegalBasis),
+		allowedLabel:    strconv.FormatBool(labels.Allowed),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		m.adsCertRequests.With(prometheus.Labels{
@@ -1294,6 +1294,33 @@ func TestSetUidMetric(t *testing.T) {
 	}
 }
 
+func TestRecordSetUidCookieAttributes(t *testing.T) {
+	tests := []struct {
+		description string
+		labels      metrics.CookieAttributesLabels
+		expected    prometheus.Labels
+	}{
+		{
+			description: "default",
+			labels:      metrics.CookieAttributesLabels{},
+			expected:    prometheus.Labels{cohortLabel: "none", sameSiteLabel: "default", partitionedLabel: "false"},
+		},
+		{
+			description: "cohort",
+			labels:      metrics.CookieAttributesLabels{Cohort: "partitioned", SameSite: "none", Partitioned: true},
+			expected:    prometheus.Labels{cohortLabel: "partitioned", sameSiteLabel: "none", partitionedLabel: "true"},
+		},
+	}
+
+	for _, test := range tests {
+		m := createMetricsForTesting()
+
+		m.RecordSetUidCookieAttributes(test.labels)
+
+		assertCounterVecValue(t, test.description, "setuid_cookie_writes", m.setUidCookies, float64(1), test.expected)
+	}
+}
+
 func TestRecordSyncerSetMetric(t *testing.T) {
 	key := "anyKey"
 
@@ -1671,6 +1698,12 @@ func TestRecordRequestPrivacy(t *testing.T) {
 		COPPAEnforced: true,
 	})
 
+	// GPC
+	m.RecordRequestPrivacy(metrics.PrivacyLabels{
+		GPCEnforced: true,
+		GPCProvided: true,
+	})
+
 	// LMT
 	m.RecordRequestPrivacy(metrics.PrivacyLabels{
 		LMTEnforced: true,
@@ -1706,6 +1739,13 @@ func TestRecordRequestPrivacy(t *testing.T) {
 			sourceLabel: sourceRequest,
 		})
 
+	assertCounterVecValue(t, "", "privacy_gpc", m.privacyGPC,
+		float64(1),
+		prometheus.Labels{
+			sourceLabel: sourceRequest,
+			optOutLabel: "true",
+		})
+
 	assertCounterVecValue(t, "", "privacy_lmt", m.privacyLMT,
 		float64(1),
 		prometheus.Labels{
@@ -1846,6 +1886,29 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 		})
 }
 
+func TestRecordTCF2LegalBasis(t *testing.T) {
+	m := createMetricsForTesting()
+	m.RecordTCF2LegalBasis(metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityBidRequest, LegalBasis: metrics.TCF2LegalBasisConsent, Allowed: true})
+	m.RecordTCF2LegalBasis(metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityBidRequest, LegalBasis: metrics.TCF2LegalBasisConsent, Allowed: true})
+	m.RecordTCF2LegalBasis(metrics.TCF2LegalBasisLabels{Activity: metrics.TCF2ActivityPassGeo, LegalBasis: metrics.TCF2LegalBasisNone, Allowed: false})
+
+	assertCounterVecValue(t, "", "privacy_tcf2_legal_basis", m.privacyTCF2LegalBasis,
+		float64(2),
+		prometheus.Labels{
+			activityLabel:   string(metrics.TCF2ActivityBidRequest),
+			legalBasisLabel: string(metrics.TCF2LegalBasisConsent),
+			allowedLabel:    "true",
+		})
+
+	assertCounterVecValue(t, "", "privacy_tcf2_legal_basis", m.privacyTCF2LegalBasis,
+		float64(1),
+		prometheus.Labels{
+			activityLabel:   string(metrics.TCF2ActivityPassGeo),
+			legalBasisLabel: string(metrics.TCF2LegalBasisNone),
+			allowedLabel:    "false",
+		})
+}
+
 func TestStoredResponsesMetric(t *testing.T) {
 	testCases := []struct {
 		description                           string
@@ -1,7 +1,13 @@
 package modules
 
 import (
+	prebidCreativescanner "github.com/prebid/prebid-server/v2/modules/prebid/creativescanner"
+	prebidFrequencycapping "github.com/prebid/prebid-server/v2/modules/prebid/frequencycapping"
+	prebidIpgeolocation "github.com/prebid/prebid-server/v2/modules/prebid/ipgeolocation"
+	prebidIvtfiltering "github.com/prebid/prebid-server/v2/modules/prebid/ivtfiltering"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v2/modules/prebid/ortb2blocking"
+	prebidResponsecache "github.com/prebid/prebid-server/v2/modules/prebid/responsecache"
+	prebidUid2 "github.com/prebid/prebid-server/v2/modules/prebid/uid2"
 )
 
 // builders returns mapping between module name and its builder
@@ -9,7 +15,13 @@ import (
 func builders() ModuleBuilders {
 	return ModuleBuilders{
 		"prebid": {
-			"ortb2blocking": prebidOrtb2blocking.Builder,
+			"creativescanner":  prebidCreativescanner.Builder,
+			"frequencycapping": prebidFrequencycapping.Builder,
+			"ipgeolocation":    prebidIpgeolocation.Builder,
+			"ivtfiltering":     prebidIvtfiltering.Builder,
+			"ortb2blocking":    prebidOrtb2blocking.Builder,
+			"responsecache":    prebidResponsecache.Builder,
+			"uid2":             prebidUid2.Builder,
 		},
 	}
 }
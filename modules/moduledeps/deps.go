@@ -3,6 +3,7 @@ package moduledeps
 import (
 	"net/http"
 
+	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/currency"
 )
 
@@ -11,4 +12,6 @@ import (
 type ModuleDeps struct {
 	HTTPClient    *http.Client
 	RateConvertor *currency.RateConverter
+	BidderInfos   config.BidderInfos
+	EventNotifier *EventNotifier
 }
@@ -0,0 +1,40 @@
+package moduledeps
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+)
+
+// EventListener is invoked with every notification event received by the /event endpoint. The account of the
+// event is not resolved. Listeners are invoked on the request path and must not block.
+type EventListener func(event *analytics.NotificationEvent)
+
+// EventNotifier allows modules to subscribe to the notification events, e.g. to count impressions.
+// A nil EventNotifier has no listeners.
+type EventNotifier struct {
+	listeners []EventListener
+	mutex     sync.RWMutex
+}
+
+// Subscribe registers the listener for all subsequent notification events.
+func (n *EventNotifier) Subscribe(listener EventListener) {
+	if n == nil {
+		return
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.listeners = append(n.listeners, listener)
+}
+
+// Notify invokes all subscribed listeners with the event.
+func (n *EventNotifier) Notify(event *analytics.NotificationEvent) {
+	if n == nil {
+		return
+	}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	for _, listener := range n.listeners {
+		listener(event)
+	}
+}
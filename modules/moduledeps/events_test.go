@@ -0,0 +1,28 @@
+package moduledeps
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventNotifier(t *testing.T) {
+	event := &analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, BidID: "bid1"}}
+
+	notifier := &EventNotifier{}
+	notifier.Notify(event)
+
+	var received []*analytics.NotificationEvent
+	notifier.Subscribe(func(e *analytics.NotificationEvent) { received = append(received, e) })
+	notifier.Subscribe(func(e *analytics.NotificationEvent) { received = append(received, e) })
+
+	notifier.Notify(event)
+	assert.Equal(t, []*analytics.NotificationEvent{event, event}, received)
+}
+
+func TestNilEventNotifier(t *testing.T) {
+	var notifier *EventNotifier
+	notifier.Subscribe(func(e *analytics.NotificationEvent) {})
+	assert.NotPanics(t, func() { notifier.Notify(&analytics.NotificationEvent{}) })
+}
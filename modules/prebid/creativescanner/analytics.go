@@ -0,0 +1,52 @@
+package creativescanner
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+)
+
+const scanCreativesActivity = "scan_creatives"
+
+const (
+	creativeIDAnalyticKey = "crid"
+	adomainAnalyticKey    = "adomain"
+	violationsAnalyticKey = "violations"
+)
+
+func newScanTags() hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   scanCreativesActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+			},
+		},
+	}
+}
+
+func addAllowedAnalyticTag(result *hookstage.HookResult[hookstage.RawBidderResponsePayload], bidder, impID string) {
+	result.AnalyticsTags.Activities[0].Results = append(result.AnalyticsTags.Activities[0].Results, hookanalytics.Result{
+		Status: hookanalytics.ResultStatusAllow,
+		AppliedTo: hookanalytics.AppliedTo{
+			Bidder: bidder,
+			ImpIds: []string{impID},
+		},
+	})
+}
+
+func addBlockedAnalyticTag(result *hookstage.HookResult[hookstage.RawBidderResponsePayload], bidder string, bid *openrtb2.Bid, violations []string) {
+	result.AnalyticsTags.Activities[0].Results = append(result.AnalyticsTags.Activities[0].Results, hookanalytics.Result{
+		Status: hookanalytics.ResultStatusBlock,
+		Values: map[string]interface{}{
+			creativeIDAnalyticKey: bid.CrID,
+			adomainAnalyticKey:    bid.ADomain,
+			violationsAnalyticKey: violations,
+		},
+		AppliedTo: hookanalytics.AppliedTo{
+			Bidder: bidder,
+			ImpIds: []string{bid.ImpID},
+			BidIds: []string{bid.ID},
+		},
+	})
+}
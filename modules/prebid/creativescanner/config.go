@@ -0,0 +1,96 @@
+package creativescanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// config holds the rules applied to the accounts without their own and whether rejected bids are logged.
+type config struct {
+	// LogRejections enables logging of every rejected bid along with its creative ID.
+	LogRejections bool `json:"log_rejections"`
+	// Rules are applied to accounts which do not define their own rules.
+	Rules rules `json:"rules"`
+}
+
+// rules represents the scanning rules, they can be rewritten at the account-level.
+type rules struct {
+	// BlockedScriptDomains lists domains, including their subdomains, scripts must not be loaded from.
+	BlockedScriptDomains []string `json:"blocked_script_domains"`
+	// BlockAutoRedirects enables rejection of markup navigating the page without user interaction.
+	BlockAutoRedirects bool `json:"block_auto_redirects"`
+	// AutoRedirectPatterns lists additional regular expressions identifying auto-redirects.
+	AutoRedirectPatterns []string `json:"auto_redirect_patterns"`
+	// RequireHTTPS enables rejection of markup referencing resources over plain HTTP.
+	RequireHTTPS bool `json:"require_https"`
+	// MaxAdmSizeBytes is the maximum allowed markup size. Disabled if not positive.
+	MaxAdmSizeBytes int `json:"max_adm_size_bytes"`
+	// DisallowedVASTTags lists the case-insensitive names of elements not allowed in VAST markup.
+	DisallowedVASTTags []string `json:"disallowed_vast_tags"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if _, err := newScanner(cfg.Rules); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func newAccountRules(data json.RawMessage, defaultRules rules) (rules, error) {
+	if len(data) == 0 {
+		return defaultRules, nil
+	}
+
+	var accountCfg struct {
+		Rules *rules `json:"rules"`
+	}
+	if err := jsonutil.UnmarshalValid(data, &accountCfg); err != nil {
+		return defaultRules, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	if accountCfg.Rules == nil {
+		return defaultRules, nil
+	}
+	return *accountCfg.Rules, nil
+}
+
+// defaultAutoRedirectPatterns identify the most common ways of navigating the top level page from ad markup.
+var defaultAutoRedirectPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:window|top|self|parent|document)\.location(?:\.href)?\s*=[^=]`),
+	regexp.MustCompile(`(?i)\blocation\.(?:replace|assign)\s*\(`),
+	regexp.MustCompile(`(?i)<meta[^>]+http-equiv\s*=\s*["']?refresh`),
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto redirect pattern %q: %s", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func compileVASTTags(tags []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(tags))
+	for _, tag := range tags {
+		compiled = append(compiled, regexp.MustCompile(`(?i)<`+regexp.QuoteMeta(strings.TrimSpace(tag))+`[\s>/]`))
+	}
+	return compiled
+}
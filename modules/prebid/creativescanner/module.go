@@ -0,0 +1,121 @@
+package creativescanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+func Builder(data json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultScanner, err := newScanner(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return Module{cfg: cfg, defaultScanner: defaultScanner, accountScanners: newScannerCache()}, nil
+}
+
+type Module struct {
+	cfg             config
+	defaultScanner  scanner
+	accountScanners *scannerCache
+}
+
+// HandleRawBidderResponseHook rejects bids whose markup violates the scanning rules.
+// Rejected bids are reported as seat non-bids.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.RawBidderResponsePayload]{}
+
+	s := m.defaultScanner
+	if len(miCtx.AccountConfig) != 0 {
+		var err error
+		if s, err = m.accountScanners.get(miCtx.AccountConfig, m.cfg.Rules); err != nil {
+			return result, hookexecution.NewFailure("%s", err)
+		}
+	}
+
+	result.AnalyticsTags = newScanTags()
+	var seats []string
+	seatNonBids := make(map[string][]openrtb_ext.NonBid)
+	allowedBids := make([]*adapters.TypedBid, 0, len(payload.Bids))
+	for _, bid := range payload.Bids {
+		if bid == nil || bid.Bid == nil {
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		violations := s.scan(bid.Bid.AdM, bid.BidType)
+		if len(violations) == 0 {
+			addAllowedAnalyticTag(&result, payload.Bidder, bid.Bid.ImpID)
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		seat := payload.Bidder
+		if bid.Seat != "" {
+			seat = bid.Seat.String()
+		}
+		if _, ok := seatNonBids[seat]; !ok {
+			seats = append(seats, seat)
+		}
+		seatNonBids[seat] = append(seatNonBids[seat], newNonBid(bid, violations))
+
+		addBlockedAnalyticTag(&result, payload.Bidder, bid.Bid, violations)
+		message := fmt.Sprintf("Bid %s with creative %s from bidder %s has been rejected, violated rules: %s", bid.Bid.ID, bid.Bid.CrID, payload.Bidder, strings.Join(violations, ", "))
+		result.DebugMessages = append(result.DebugMessages, message)
+		if m.cfg.LogRejections {
+			glog.Info(message)
+		}
+	}
+
+	if len(allowedBids) != len(payload.Bids) {
+		result.ChangeSet.RawBidderResponse().Bids().Update(allowedBids)
+		for _, seat := range seats {
+			result.SeatNonBid = append(result.SeatNonBid, openrtb_ext.SeatNonBid{Seat: seat, NonBid: seatNonBids[seat]})
+		}
+	}
+
+	return result, nil
+}
+
+func newNonBid(bid *adapters.TypedBid, violations []string) openrtb_ext.NonBid {
+	reason := openrtb_ext.ResponseRejectedInvalidCreative
+	if len(violations) == 1 && violations[0] == violationNonHTTPSResource {
+		reason = openrtb_ext.ResponseRejectedCreativeNotSecure
+	}
+
+	return openrtb_ext.NonBid{
+		ImpId:      bid.Bid.ImpID,
+		StatusCode: int(reason),
+		Ext: openrtb_ext.NonBidExt{
+			Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+				Price:   bid.Bid.Price,
+				ADomain: bid.Bid.ADomain,
+				CatTax:  bid.Bid.CatTax,
+				Cat:     bid.Bid.Cat,
+				DealID:  bid.Bid.DealID,
+				W:       bid.Bid.W,
+				H:       bid.Bid.H,
+				Dur:     bid.Bid.Dur,
+				MType:   bid.Bid.MType,
+			}},
+		},
+	}
+}
@@ -0,0 +1,225 @@
+package creativescanner
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cleanAdm          = `<div><script src="https://cdn.safe.com/ad.js"></script></div>`
+	blockedScriptAdm  = `<script src="https://malware.com/ad.js"></script>`
+	insecureAdm       = `<img src="http://cdn.safe.com/1.png">`
+	insecureVideoAdm  = `<VAST version="3.0"><Ad><InLine><MediaFile><![CDATA[http://cdn.safe.com/v.mp4]]></MediaFile></InLine></Ad></VAST>`
+	testBidder        = "appnexus"
+	testHostRulesJSON = `{"enabled": true, "rules": {"blocked_script_domains": ["malware.com"], "require_https": true}}`
+)
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "Valid config",
+			config:      json.RawMessage(testHostRulesJSON),
+		},
+		{
+			description: "Empty config",
+			config:      nil,
+		},
+		{
+			description:   "Invalid auto redirect pattern",
+			config:        json.RawMessage(`{"rules": {"block_auto_redirects": true, "auto_redirect_patterns": ["("]}}`),
+			expectedError: "invalid auto redirect pattern",
+		},
+		{
+			description:   "Malformed config",
+			config:        json.RawMessage(`{"rules": []}`),
+			expectedError: "failed to parse config",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(test.config, moduledeps.ModuleDeps{})
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	cleanBid := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", CrID: "cr1", AdM: cleanAdm}}
+	blockedScriptBid := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", CrID: "cr2", Price: 1.5, ADomain: []string{"adv.com"}, W: 300, H: 250, AdM: blockedScriptAdm}}
+	insecureBid := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Seat: "alt-seat", Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp3", CrID: "cr3", AdM: insecureAdm}}
+	insecureVideoBid := &adapters.TypedBid{BidType: openrtb_ext.BidTypeVideo, Bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp4", CrID: "cr4", AdM: insecureVideoAdm}}
+
+	testCases := []struct {
+		description        string
+		accountConfig      json.RawMessage
+		bids               []*adapters.TypedBid
+		expectedBids       []*adapters.TypedBid
+		expectedHookResult hookstage.HookResult[hookstage.RawBidderResponsePayload]
+		expectedError      error
+	}{
+		{
+			description:  "All bids allowed",
+			bids:         []*adapters.TypedBid{cleanBid},
+			expectedBids: []*adapters.TypedBid{cleanBid},
+			expectedHookResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: analyticsTags(allowedResult("imp1")),
+			},
+		},
+		{
+			description:  "Violating bids rejected and reported as seat non-bids",
+			bids:         []*adapters.TypedBid{cleanBid, blockedScriptBid, insecureBid},
+			expectedBids: []*adapters.TypedBid{cleanBid},
+			expectedHookResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: analyticsTags(
+					allowedResult("imp1"),
+					blockedResult(blockedScriptBid.Bid, violationBlockedScriptDomain),
+					blockedResult(insecureBid.Bid, violationNonHTTPSResource),
+				),
+				DebugMessages: []string{
+					"Bid bid2 with creative cr2 from bidder appnexus has been rejected, violated rules: blocked_script_domain",
+					"Bid bid3 with creative cr3 from bidder appnexus has been rejected, violated rules: non_https_resource",
+				},
+				SeatNonBid: []openrtb_ext.SeatNonBid{
+					{
+						Seat: testBidder,
+						NonBid: []openrtb_ext.NonBid{{
+							ImpId:      "imp2",
+							StatusCode: int(openrtb_ext.ResponseRejectedInvalidCreative),
+							Ext: openrtb_ext.NonBidExt{Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+								Price:   1.5,
+								ADomain: []string{"adv.com"},
+								W:       300,
+								H:       250,
+							}}},
+						}},
+					},
+					{
+						Seat: "alt-seat",
+						NonBid: []openrtb_ext.NonBid{{
+							ImpId:      "imp3",
+							StatusCode: int(openrtb_ext.ResponseRejectedCreativeNotSecure),
+						}},
+					},
+				},
+			},
+		},
+		{
+			description:   "Account rules override host rules",
+			accountConfig: json.RawMessage(`{"rules": {"require_https": true}}`),
+			bids:          []*adapters.TypedBid{blockedScriptBid, insecureVideoBid},
+			expectedBids:  []*adapters.TypedBid{blockedScriptBid},
+			expectedHookResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: analyticsTags(
+					allowedResult("imp2"),
+					blockedResult(insecureVideoBid.Bid, violationNonHTTPSResource),
+				),
+				DebugMessages: []string{
+					"Bid bid4 with creative cr4 from bidder appnexus has been rejected, violated rules: non_https_resource",
+				},
+				SeatNonBid: []openrtb_ext.SeatNonBid{{
+					Seat: testBidder,
+					NonBid: []openrtb_ext.NonBid{{
+						ImpId:      "imp4",
+						StatusCode: int(openrtb_ext.ResponseRejectedCreativeNotSecure),
+					}},
+				}},
+			},
+		},
+		{
+			description:   "Account config without rules uses host rules",
+			accountConfig: json.RawMessage(`{}`),
+			bids:          []*adapters.TypedBid{cleanBid},
+			expectedBids:  []*adapters.TypedBid{cleanBid},
+			expectedHookResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: analyticsTags(allowedResult("imp1")),
+			},
+		},
+		{
+			description:        "Invalid account config",
+			accountConfig:      json.RawMessage(`{"rules": {"block_auto_redirects": true, "auto_redirect_patterns": ["("]}}`),
+			bids:               []*adapters.TypedBid{blockedScriptBid},
+			expectedBids:       []*adapters.TypedBid{blockedScriptBid},
+			expectedHookResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+			expectedError:      hookexecution.NewFailure(`invalid auto redirect pattern "(": error parsing regexp: missing closing ): ` + "`(`"),
+		},
+	}
+
+	result, err := Builder(json.RawMessage(testHostRulesJSON), moduledeps.ModuleDeps{})
+	require.NoError(t, err, "Failed to build module.")
+	module, ok := result.(Module)
+	require.True(t, ok, "Failed to cast module type.")
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.RawBidderResponsePayload{Bidder: testBidder, Bids: test.bids}
+
+			hookResult, err := module.HandleRawBidderResponseHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig, Endpoint: hookexecution.EndpointAuction},
+				payload,
+			)
+			assert.Equal(t, test.expectedError, err, "Invalid hook execution error.")
+
+			// test mutations separately
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				newPayload, err := mut.Apply(payload)
+				assert.NoError(t, err)
+				payload = newPayload
+			}
+			assert.Equal(t, test.expectedBids, payload.Bids, "Invalid bids after executing RawBidderResponseHook.")
+
+			// reset ChangeSet not to break hookResult assertion, we validated ChangeSet separately
+			hookResult.ChangeSet = hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+			assert.Equal(t, test.expectedHookResult, hookResult, "Invalid hook execution result.")
+		})
+	}
+}
+
+func analyticsTags(results ...hookanalytics.Result) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:    scanCreativesActivity,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: results,
+		}},
+	}
+}
+
+func allowedResult(impID string) hookanalytics.Result {
+	return hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusAllow,
+		AppliedTo: hookanalytics.AppliedTo{Bidder: testBidder, ImpIds: []string{impID}},
+	}
+}
+
+func blockedResult(bid *openrtb2.Bid, violations ...string) hookanalytics.Result {
+	return hookanalytics.Result{
+		Status: hookanalytics.ResultStatusBlock,
+		Values: map[string]interface{}{
+			creativeIDAnalyticKey: bid.CrID,
+			adomainAnalyticKey:    bid.ADomain,
+			violationsAnalyticKey: violations,
+		},
+		AppliedTo: hookanalytics.AppliedTo{Bidder: testBidder, ImpIds: []string{bid.ImpID}, BidIds: []string{bid.ID}},
+	}
+}
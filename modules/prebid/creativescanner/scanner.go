@@ -0,0 +1,162 @@
+package creativescanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// violation names reported in analytics tags and debug messages.
+const (
+	violationBlockedScriptDomain = "blocked_script_domain"
+	violationAutoRedirect        = "auto_redirect"
+	violationNonHTTPSResource    = "non_https_resource"
+	violationPayloadSize         = "payload_size"
+	violationVASTTag             = "disallowed_vast_tag"
+)
+
+type markupType int
+
+const (
+	markupHTML markupType = iota
+	markupVAST
+	markupNative
+)
+
+var (
+	scriptSrcRegex          = regexp.MustCompile(`(?is)<script[^>]*?\bsrc\s*=\s*["']?([^"'\s>]+)`)
+	javaScriptResourceRegex = regexp.MustCompile(`(?is)<JavaScriptResource[^>]*>\s*(?:<!\[CDATA\[)?\s*([^\s<\]]+)`)
+	insecureHTMLRegex       = regexp.MustCompile(`(?i)(?:\b(?:src|href|poster|background|action|data)\s*=\s*["']?|url\(\s*["']?|>\s*(?:<!\[CDATA\[)?\s*)http://`)
+	insecureNativeRegex     = regexp.MustCompile(`(?i)"\s*http:(?://|\\/\\/)`)
+	vastRegex               = regexp.MustCompile(`(?i)<VAST[\s>]`)
+	nativeUnescaper         = strings.NewReplacer(`\/`, `/`, `\"`, `"`)
+)
+
+// scanner checks ad markup against the compiled rules.
+type scanner struct {
+	rules                rules
+	autoRedirectPatterns []*regexp.Regexp
+	vastTags             []*regexp.Regexp
+}
+
+func newScanner(r rules) (scanner, error) {
+	s := scanner{rules: r}
+
+	if r.BlockAutoRedirects {
+		custom, err := compilePatterns(r.AutoRedirectPatterns)
+		if err != nil {
+			return s, err
+		}
+		s.autoRedirectPatterns = append(append(s.autoRedirectPatterns, defaultAutoRedirectPatterns...), custom...)
+	}
+
+	s.vastTags = compileVASTTags(r.DisallowedVASTTags)
+	return s, nil
+}
+
+// scan returns the names of the rules violated by the markup in the order the checks are performed.
+func (s scanner) scan(adm string, bidType openrtb_ext.BidType) []string {
+	var violations []string
+	if adm == "" {
+		return violations
+	}
+
+	if s.rules.MaxAdmSizeBytes > 0 && len(adm) > s.rules.MaxAdmSizeBytes {
+		violations = append(violations, violationPayloadSize)
+	}
+
+	markup := detectMarkupType(adm, bidType)
+	if markup == markupNative {
+		adm = nativeUnescaper.Replace(adm)
+	}
+
+	if len(s.rules.BlockedScriptDomains) > 0 && s.loadsBlockedScript(adm, markup) {
+		violations = append(violations, violationBlockedScriptDomain)
+	}
+
+	if markup != markupVAST && s.redirects(adm) {
+		violations = append(violations, violationAutoRedirect)
+	}
+
+	if s.rules.RequireHTTPS && isInsecure(adm, markup) {
+		violations = append(violations, violationNonHTTPSResource)
+	}
+
+	if markup == markupVAST && s.containsDisallowedVASTTag(adm) {
+		violations = append(violations, violationVASTTag)
+	}
+
+	return violations
+}
+
+func detectMarkupType(adm string, bidType openrtb_ext.BidType) markupType {
+	if bidType == openrtb_ext.BidTypeVideo || bidType == openrtb_ext.BidTypeAudio || vastRegex.MatchString(adm) {
+		return markupVAST
+	}
+	if bidType == openrtb_ext.BidTypeNative || strings.HasPrefix(strings.TrimSpace(adm), "{") {
+		return markupNative
+	}
+	return markupHTML
+}
+
+func (s scanner) loadsBlockedScript(adm string, markup markupType) bool {
+	matches := scriptSrcRegex.FindAllStringSubmatch(adm, -1)
+	if markup == markupVAST {
+		matches = append(matches, javaScriptResourceRegex.FindAllStringSubmatch(adm, -1)...)
+	}
+
+	for _, match := range matches {
+		if host := scriptHost(match[1]); host != "" && isBlockedHost(host, s.rules.BlockedScriptDomains) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s scanner) redirects(adm string) bool {
+	for _, pattern := range s.autoRedirectPatterns {
+		if pattern.MatchString(adm) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s scanner) containsDisallowedVASTTag(adm string) bool {
+	for _, tag := range s.vastTags {
+		if tag.MatchString(adm) {
+			return true
+		}
+	}
+	return false
+}
+
+func isInsecure(adm string, markup markupType) bool {
+	if markup == markupNative {
+		return insecureNativeRegex.MatchString(adm) || insecureHTMLRegex.MatchString(adm)
+	}
+	return insecureHTMLRegex.MatchString(adm)
+}
+
+func scriptHost(src string) string {
+	if strings.HasPrefix(src, "//") {
+		src = "https:" + src
+	}
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func isBlockedHost(host string, domains []string) bool {
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package creativescanner
+
+import "sync"
+
+// maxCachedScanners bounds the number of account scanners kept compiled. The cache starts over once full, which
+// only happens when account configs change often.
+const maxCachedScanners = 1000
+
+// scannerCache keeps the scanners compiled from the account configs, so their patterns are compiled once per
+// account config rather than for every bidder response.
+type scannerCache struct {
+	mutex    sync.RWMutex
+	scanners map[string]scanner
+}
+
+func newScannerCache() *scannerCache {
+	return &scannerCache{scanners: make(map[string]scanner)}
+}
+
+// get returns the scanner of the account config, compiling it from the host rules and the account config if it
+// isn't cached yet.
+func (c *scannerCache) get(accountConfig []byte, hostRules rules) (scanner, error) {
+	key := string(accountConfig)
+
+	c.mutex.RLock()
+	s, ok := c.scanners[key]
+	c.mutex.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	accountRules, err := newAccountRules(accountConfig, hostRules)
+	if err != nil {
+		return scanner{}, err
+	}
+	if s, err = newScanner(accountRules); err != nil {
+		return scanner{}, err
+	}
+
+	c.mutex.Lock()
+	if len(c.scanners) >= maxCachedScanners {
+		c.scanners = make(map[string]scanner)
+	}
+	c.scanners[key] = s
+	c.mutex.Unlock()
+	return s, nil
+}
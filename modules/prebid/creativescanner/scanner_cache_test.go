@@ -0,0 +1,40 @@
+package creativescanner
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerCache(t *testing.T) {
+	cache := newScannerCache()
+	hostRules := rules{BlockAutoRedirects: true}
+
+	s, err := cache.get([]byte(`{"rules": {"block_auto_redirects": true, "auto_redirect_patterns": ["top\\.location"]}}`), hostRules)
+	require.NoError(t, err)
+	require.Len(t, cache.scanners, 1)
+
+	cached, err := cache.get([]byte(`{"rules": {"block_auto_redirects": true, "auto_redirect_patterns": ["top\\.location"]}}`), hostRules)
+	require.NoError(t, err)
+	assert.Len(t, cache.scanners, 1, "Scanner of the same account config must be reused.")
+	assert.Same(t, s.autoRedirectPatterns[len(s.autoRedirectPatterns)-1], cached.autoRedirectPatterns[len(cached.autoRedirectPatterns)-1], "Patterns must not be compiled again.")
+
+	_, err = cache.get([]byte(`{"rules": {"block_auto_redirects": true, "auto_redirect_patterns": ["("]}}`), hostRules)
+	assert.Error(t, err)
+	assert.Len(t, cache.scanners, 1, "Invalid account configs must not be cached.")
+}
+
+func TestScannerCacheBounded(t *testing.T) {
+	cache := newScannerCache()
+	for i := 0; i < maxCachedScanners; i++ {
+		_, err := cache.get([]byte(`{"rules": {"max_adm_size_bytes": `+strconv.Itoa(i)+`}}`), rules{})
+		require.NoError(t, err)
+	}
+	require.Len(t, cache.scanners, maxCachedScanners)
+
+	_, err := cache.get([]byte(`{"rules": {}}`), rules{})
+	require.NoError(t, err)
+	assert.Len(t, cache.scanners, 1, "Cache must start over once full.")
+}
@@ -0,0 +1,135 @@
+package creativescanner
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	testRules := rules{
+		BlockedScriptDomains: []string{"malware.com"},
+		BlockAutoRedirects:   true,
+		AutoRedirectPatterns: []string{`(?i)forceRedirect\(`},
+		RequireHTTPS:         true,
+		MaxAdmSizeBytes:      200,
+		DisallowedVASTTags:   []string{"VPAIDJavaScript", "Extensions"},
+	}
+
+	testCases := []struct {
+		description        string
+		adm                string
+		bidType            openrtb_ext.BidType
+		expectedViolations []string
+	}{
+		{
+			description:        "Clean html markup",
+			adm:                `<div><script src="https://cdn.safe.com/ad.js"></script><img src="https://cdn.safe.com/1.png"></div>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: nil,
+		},
+		{
+			description:        "Empty markup",
+			adm:                "",
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: nil,
+		},
+		{
+			description:        "Script loaded from subdomain of blocked domain",
+			adm:                `<script type="text/javascript" src='//cdn.MALWARE.com/x.js'></script>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationBlockedScriptDomain},
+		},
+		{
+			description:        "Script loaded from domain sharing suffix with blocked domain",
+			adm:                `<script src="https://notmalware.com/x.js"></script>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: nil,
+		},
+		{
+			description:        "Auto redirect with top location",
+			adm:                `<script>top.location.href = "https://landing.com";</script>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationAutoRedirect},
+		},
+		{
+			description:        "Location comparison is not an auto redirect",
+			adm:                `<script>if (window.location == "x") {}</script>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: nil,
+		},
+		{
+			description:        "Auto redirect with meta refresh",
+			adm:                `<meta http-equiv="refresh" content="0;url=https://landing.com">`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationAutoRedirect},
+		},
+		{
+			description:        "Auto redirect with custom pattern",
+			adm:                `<script>forceRedirect("https://landing.com")</script>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationAutoRedirect},
+		},
+		{
+			description:        "Non https image",
+			adm:                `<img src="http://cdn.safe.com/1.png">`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationNonHTTPSResource},
+		},
+		{
+			description:        "Non https resource in css",
+			adm:                `<div style="background-image: url('http://cdn.safe.com/1.png')"></div>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationNonHTTPSResource},
+		},
+		{
+			description:        "Payload too large",
+			adm:                `<div>` + string(make([]byte, 200)) + `</div>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationPayloadSize},
+		},
+		{
+			description:        "Clean vast markup with xml namespace",
+			adm:                `<VAST version="3.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><Ad><InLine><Creatives/></InLine></Ad></VAST>`,
+			bidType:            openrtb_ext.BidTypeVideo,
+			expectedViolations: nil,
+		},
+		{
+			description:        "Vast with non https media file and disallowed tag",
+			adm:                `<VAST version="3.0"><Ad><InLine><Extensions/><MediaFile><![CDATA[http://cdn.safe.com/v.mp4]]></MediaFile></InLine></Ad></VAST>`,
+			bidType:            openrtb_ext.BidTypeVideo,
+			expectedViolations: []string{violationNonHTTPSResource, violationVASTTag},
+		},
+		{
+			description:        "Vast javascript resource from blocked domain",
+			adm:                `<VAST version="4.1"><Ad><InLine><Verification><JavaScriptResource apiFramework="omid"><![CDATA[https://malware.com/omid.js]]></JavaScriptResource></Verification></InLine></Ad></VAST>`,
+			bidType:            openrtb_ext.BidTypeBanner,
+			expectedViolations: []string{violationBlockedScriptDomain},
+		},
+		{
+			description:        "Native with escaped blocked script tracker and non https image",
+			adm:                `{"assets":[{"img":{"url":"http:\/\/cdn.safe.com\/1.png"}}],"jstracker":"<script src=\"https:\/\/malware.com\/t.js\"><\/script>"}`,
+			bidType:            openrtb_ext.BidTypeNative,
+			expectedViolations: []string{violationBlockedScriptDomain, violationNonHTTPSResource},
+		},
+	}
+
+	s, err := newScanner(testRules)
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedViolations, s.scan(test.adm, test.bidType))
+		})
+	}
+}
+
+func TestScanWithDisabledRules(t *testing.T) {
+	s, err := newScanner(rules{})
+	require.NoError(t, err)
+
+	adm := `<script src="https://malware.com/x.js"></script><script>top.location="https://a.com"</script><img src="http://a.com/1.png">`
+	assert.Empty(t, s.scan(adm, openrtb_ext.BidTypeBanner))
+}
@@ -0,0 +1,64 @@
+package frequencycapping
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+const frequencyCappingActivity = "frequency_capping"
+
+const (
+	capTypeAnalyticKey  = "cap_type"
+	capValueAnalyticKey = "cap_value"
+)
+
+// newCapTags reports the allowed bids by seat and every rejected bid along with the cap it reached.
+func newCapTags(responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, rejected map[*entities.PbsOrtbBid]cappedCounter) hookanalytics.Analytics {
+	var results []hookanalytics.Result
+	for _, seat := range sortedSeats(responses) {
+		var allowedBidIDs []string
+		for _, bid := range responses[openrtb_ext.BidderName(seat)].Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			c, ok := rejected[bid]
+			if !ok {
+				allowedBidIDs = append(allowedBidIDs, bid.Bid.ID)
+				continue
+			}
+			results = append(results, hookanalytics.Result{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{capTypeAnalyticKey: c.Type, capValueAnalyticKey: c.Value},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: seat, ImpIds: []string{bid.Bid.ImpID}, BidIds: []string{bid.Bid.ID}},
+			})
+		}
+		if len(allowedBidIDs) != 0 {
+			results = append(results, hookanalytics.Result{
+				Status:    hookanalytics.ResultStatusAllow,
+				AppliedTo: hookanalytics.AppliedTo{Bidder: seat, BidIds: allowedBidIDs},
+			})
+		}
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:    frequencyCappingActivity,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: results,
+		}},
+	}
+}
+
+func sortedSeats(responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []string {
+	seats := make([]string, 0, len(responses))
+	for seat, seatBid := range responses {
+		if seatBid != nil {
+			seats = append(seats, seat.String())
+		}
+	}
+	sort.Strings(seats)
+	return seats
+}
@@ -0,0 +1,137 @@
+package frequencycapping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	capTypeAdvertiser = "advertiser"
+	capTypeLineItem   = "line_item"
+	capTypeDeal       = "deal"
+	capTypeCreative   = "creative"
+)
+
+var supportedCapTypes = []string{capTypeAdvertiser, capTypeLineItem, capTypeDeal, capTypeCreative}
+
+const (
+	storeTypeMemory = "memory"
+	storeTypeRedis  = "redis"
+)
+
+const (
+	defaultImpressionTTLSeconds = 3600
+	defaultTimeoutMS            = 50
+	defaultMaxEntries           = 100000
+	defaultRedisPoolSize        = 10
+)
+
+// config identifies the users, bounds the wait for the counter store and selects where the impressions are counted.
+type config struct {
+	// HostCookieName is the name of the host cookie holding the user ID, the cookie is read at the entrypoint stage.
+	HostCookieName string `json:"host_cookie_name"`
+	// EIDSources lists the user.eids sources used as user key when neither the host cookie nor user.id is present.
+	EIDSources []string `json:"eid_sources"`
+	// ImpressionTTLSeconds specifies for how long after the auction impression events of the bids are counted.
+	ImpressionTTLSeconds int `json:"impression_ttl_seconds"`
+	// TimeoutMS is the timeout of the counter store operations.
+	TimeoutMS int         `json:"timeout_ms"`
+	Store     storeConfig `json:"store"`
+}
+
+type storeConfig struct {
+	// Type is either "memory" or "redis".
+	Type string `json:"type"`
+	// MaxEntries is the maximum number of counters and impressions kept by the in-memory store.
+	MaxEntries int         `json:"max_entries"`
+	Redis      redisConfig `json:"redis"`
+}
+
+// redisConfig configures the store backed by a server compatible with the Redis protocol.
+type redisConfig struct {
+	Address   string `json:"address"`
+	Password  string `json:"password"`
+	DB        int    `json:"db"`
+	KeyPrefix string `json:"key_prefix"`
+	PoolSize  int    `json:"pool_size"`
+}
+
+// accountConfig lists the frequency caps of an account, no bid is capped without caps.
+type accountConfig struct {
+	Caps []capConfig `json:"caps"`
+}
+
+// capConfig limits the number of impressions of the user within the window.
+type capConfig struct {
+	// Type is one of advertiser (bid.adomain), line_item (bid.cid), deal (bid.dealid) or creative (bid.crid).
+	Type string `json:"type"`
+	// Values lists the capped values, every value of the type is capped separately if empty.
+	Values         []string `json:"values"`
+	MaxImpressions int      `json:"max_impressions"`
+	WindowSeconds  int      `json:"window_seconds"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{
+		ImpressionTTLSeconds: defaultImpressionTTLSeconds,
+		TimeoutMS:            defaultTimeoutMS,
+		Store: storeConfig{
+			Type:       storeTypeMemory,
+			MaxEntries: defaultMaxEntries,
+			Redis:      redisConfig{PoolSize: defaultRedisPoolSize},
+		},
+	}
+	if len(data) != 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.ImpressionTTLSeconds <= 0 || cfg.TimeoutMS <= 0 {
+		return cfg, errors.New("impression_ttl_seconds and timeout_ms must be positive")
+	}
+
+	switch cfg.Store.Type {
+	case storeTypeMemory:
+		if cfg.Store.MaxEntries <= 0 {
+			return cfg, errors.New("store.max_entries must be positive")
+		}
+	case storeTypeRedis:
+		if cfg.Store.Redis.Address == "" {
+			return cfg, errors.New("store.redis.address is required")
+		}
+		if cfg.Store.Redis.PoolSize <= 0 {
+			return cfg, errors.New("store.redis.pool_size must be positive")
+		}
+	default:
+		return cfg, fmt.Errorf("unsupported store type: %s", cfg.Store.Type)
+	}
+
+	return cfg, nil
+}
+
+func newAccountConfig(data json.RawMessage) (accountConfig, error) {
+	var cfg accountConfig
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	for _, c := range cfg.Caps {
+		if !slices.Contains(supportedCapTypes, c.Type) {
+			return cfg, fmt.Errorf("unsupported cap type: %s", c.Type)
+		}
+		if c.MaxImpressions <= 0 || c.WindowSeconds <= 0 {
+			return cfg, fmt.Errorf("%s cap max_impressions and window_seconds must be positive", c.Type)
+		}
+	}
+
+	return cfg, nil
+}
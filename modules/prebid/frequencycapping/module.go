@@ -0,0 +1,378 @@
+package frequencycapping
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	hostCookieContextKey = "host_cookie"
+	userKeyContextKey    = "user_key"
+)
+
+const (
+	// impression events are counted in the background by a fixed number of workers,
+	// the events received while the queue is full are dropped
+	eventQueueSize = 1000
+	eventWorkers   = 4
+)
+
+func Builder(data json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var store counterStore
+	switch cfg.Store.Type {
+	case storeTypeRedis:
+		store = newRedisStore(cfg.Store.Redis)
+	default:
+		store = newMemoryStore(cfg.Store.MaxEntries)
+	}
+
+	module := Module{
+		cfg:     cfg,
+		store:   store,
+		events:  make(chan *analytics.EventRequest, eventQueueSize),
+		pending: &sync.WaitGroup{},
+	}
+	for i := 0; i < eventWorkers; i++ {
+		go module.countImpressions()
+	}
+	deps.EventNotifier.Subscribe(module.handleEvent)
+
+	return module, nil
+}
+
+type Module struct {
+	cfg     config
+	store   counterStore
+	events  chan *analytics.EventRequest
+	pending *sync.WaitGroup
+}
+
+// HandleEntrypointHook reads the user ID from the host cookie.
+func (m Module) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	result := hookstage.HookResult[hookstage.EntrypointPayload]{}
+
+	if m.cfg.HostCookieName == "" || payload.Request == nil {
+		return result, nil
+	}
+	if cookie, err := payload.Request.Cookie(m.cfg.HostCookieName); err == nil && cookie.Value != "" {
+		result.ModuleContext = hookstage.ModuleContext{hostCookieContextKey: cookie.Value}
+	}
+
+	return result, nil
+}
+
+// HandleProcessedAuctionHook determines the user key the impressions are counted for,
+// either the host cookie ID, user.id or the ID of one of the configured EID sources.
+// Only the hash of the ID is kept.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	userID, _ := miCtx.ModuleContext[hostCookieContextKey].(string)
+	if userID == "" && payload.Request != nil && payload.Request.BidRequest != nil {
+		userID = getUserID(payload.Request.User, m.cfg.EIDSources)
+	}
+	if userID == "" {
+		return result, nil
+	}
+
+	result.ModuleContext = hookstage.ModuleContext{userKeyContextKey: hashUserID(userID)}
+	return result, nil
+}
+
+// HandleAllProcessedBidResponsesHook rejects the bids reaching any of the account caps for the user
+// and records the counters to increment once the impression event of a remaining bid is received.
+func (m Module) HandleAllProcessedBidResponsesHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.AllProcessedBidResponsesPayload,
+) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	result := hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{}
+
+	accountCfg, err := newAccountConfig(miCtx.AccountConfig)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	userKey, _ := miCtx.ModuleContext[userKeyContextKey].(string)
+	if userKey == "" || len(accountCfg.Caps) == 0 {
+		return result, nil
+	}
+
+	bidCounters := make(map[*entities.PbsOrtbBid][]cappedCounter)
+	var keys []string
+	for _, seatBid := range payload.Responses {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			counters := getCounters(bid, accountCfg.Caps, miCtx.AccountID, userKey)
+			bidCounters[bid] = counters
+			for _, c := range counters {
+				keys = append(keys, c.Key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.cfg.TimeoutMS)*time.Millisecond)
+	defer cancel()
+
+	counts, err := m.store.get(ctx, keys)
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to get counters: %s", err)
+	}
+
+	rejected := make(map[*entities.PbsOrtbBid]cappedCounter)
+	impressions := make(map[string][]byte)
+	for bid, counters := range bidCounters {
+		if c, ok := findReachedCap(counters, counts); ok {
+			rejected[bid] = c
+			continue
+		}
+
+		value, err := jsonutil.Marshal(toCounters(counters))
+		if err != nil {
+			return result, hookexecution.NewFailure("failed to marshal counters: %s", err)
+		}
+		impressions[impressionKey(miCtx.AccountID, getEventBidID(bid))] = value
+	}
+
+	if len(impressions) != 0 {
+		ttl := time.Duration(m.cfg.ImpressionTTLSeconds) * time.Second
+		if err := m.store.put(ctx, impressions, ttl); err != nil {
+			return result, hookexecution.NewFailure("failed to store impressions: %s", err)
+		}
+	}
+
+	result.AnalyticsTags = newCapTags(payload.Responses, rejected)
+	if len(rejected) == 0 {
+		return result, nil
+	}
+
+	for _, seat := range sortedSeats(payload.Responses) {
+		var nonBids []openrtb_ext.NonBid
+		for _, bid := range payload.Responses[openrtb_ext.BidderName(seat)].Bids {
+			if c, ok := rejected[bid]; ok {
+				nonBids = append(nonBids, newNonBid(bid))
+				result.DebugMessages = append(result.DebugMessages, fmt.Sprintf("Bid %s from seat %s has been rejected, %s %s frequency cap of %d impressions reached", bid.Bid.ID, seat, c.Type, c.Value, c.MaxImpressions))
+			}
+		}
+		if len(nonBids) != 0 {
+			result.SeatNonBid = append(result.SeatNonBid, openrtb_ext.SeatNonBid{Seat: seat, NonBid: nonBids})
+		}
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.AllProcessedBidResponsesPayload) (hookstage.AllProcessedBidResponsesPayload, error) {
+		for _, seatBid := range payload.Responses {
+			if seatBid == nil {
+				continue
+			}
+			seatBid.Bids = slices.DeleteFunc(seatBid.Bids, func(bid *entities.PbsOrtbBid) bool {
+				_, ok := rejected[bid]
+				return ok
+			})
+		}
+		return payload, nil
+	}, hookstage.MutationDelete, "processedBidderResponses")
+
+	return result, nil
+}
+
+// handleEvent queues the impression events to be counted off the request path of the event endpoint.
+func (m Module) handleEvent(event *analytics.NotificationEvent) {
+	if event == nil || event.Request == nil || event.Request.Type != analytics.Imp || event.Request.BidID == "" {
+		return
+	}
+
+	m.pending.Add(1)
+	select {
+	case m.events <- event.Request:
+	default:
+		m.pending.Done()
+		glog.Warningf("Frequency capping dropped the impression event of bid %s, too many events pending", event.Request.BidID)
+	}
+}
+
+func (m Module) countImpressions() {
+	for event := range m.events {
+		m.countImpression(event)
+		m.pending.Done()
+	}
+}
+
+// countImpression increments the counters of the bid once its impression event is received.
+// Subsequent impression events of the same bid are not counted.
+func (m Module) countImpression(event *analytics.EventRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(m.cfg.TimeoutMS)*time.Millisecond)
+	defer cancel()
+
+	value, ok, err := m.store.take(ctx, impressionKey(event.AccountID, event.BidID))
+	if err != nil {
+		glog.Errorf("Frequency capping failed to get the counters of bid %s: %s", event.BidID, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	var counters []counter
+	if err := jsonutil.UnmarshalValid(value, &counters); err != nil {
+		glog.Errorf("Frequency capping failed to parse the counters of bid %s: %s", event.BidID, err)
+		return
+	}
+	if err := m.store.increment(ctx, counters); err != nil {
+		glog.Errorf("Frequency capping failed to increment the counters of bid %s: %s", event.BidID, err)
+	}
+}
+
+// cappedCounter is the counter of the bid for one of the account caps.
+type cappedCounter struct {
+	counter
+	Type           string
+	Value          string
+	MaxImpressions int
+}
+
+// getCounters returns the counters of the bid for every cap applying to it.
+func getCounters(bid *entities.PbsOrtbBid, caps []capConfig, accountID, userKey string) []cappedCounter {
+	var counters []cappedCounter
+	for _, c := range caps {
+		for _, value := range getCapValues(bid, c.Type) {
+			if value == "" || (len(c.Values) != 0 && !slices.Contains(c.Values, value)) {
+				continue
+			}
+			key := strings.Join([]string{"cnt", accountID, userKey, c.Type, value, strconv.Itoa(c.WindowSeconds)}, "|")
+			counters = append(counters, cappedCounter{
+				counter:        counter{Key: key, WindowSeconds: c.WindowSeconds},
+				Type:           c.Type,
+				Value:          value,
+				MaxImpressions: c.MaxImpressions,
+			})
+		}
+	}
+	return counters
+}
+
+func getCapValues(bid *entities.PbsOrtbBid, capType string) []string {
+	switch capType {
+	case capTypeAdvertiser:
+		return bid.Bid.ADomain
+	case capTypeLineItem:
+		return []string{bid.Bid.CID}
+	case capTypeDeal:
+		return []string{bid.Bid.DealID}
+	case capTypeCreative:
+		return []string{bid.Bid.CrID}
+	}
+	return nil
+}
+
+func findReachedCap(counters []cappedCounter, counts map[string]int64) (cappedCounter, bool) {
+	for _, c := range counters {
+		if counts[c.Key] >= int64(c.MaxImpressions) {
+			return c, true
+		}
+	}
+	return cappedCounter{}, false
+}
+
+func toCounters(counters []cappedCounter) []counter {
+	result := make([]counter, 0, len(counters))
+	for _, c := range counters {
+		// the same counter may be capped by several caps, e.g. a creative listed in two caps of different windows
+		if !slices.Contains(result, c.counter) {
+			result = append(result, c.counter)
+		}
+	}
+	return result
+}
+
+// getEventBidID returns the bid ID used by the event URLs of the bid.
+func getEventBidID(bid *entities.PbsOrtbBid) string {
+	if bid.GeneratedBidID != "" {
+		return bid.GeneratedBidID
+	}
+	return bid.Bid.ID
+}
+
+func impressionKey(accountID, bidID string) string {
+	return strings.Join([]string{"imp", accountID, bidID}, "|")
+}
+
+func getUserID(user *openrtb2.User, eidSources []string) string {
+	if user == nil {
+		return ""
+	}
+	if user.ID != "" {
+		return user.ID
+	}
+	for _, source := range eidSources {
+		for _, eid := range user.EIDs {
+			if eid.Source == source && len(eid.UIDs) > 0 && eid.UIDs[0].ID != "" {
+				return eid.UIDs[0].ID
+			}
+		}
+	}
+	return ""
+}
+
+func hashUserID(userID string) string {
+	hash := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(hash[:])
+}
+
+func newNonBid(bid *entities.PbsOrtbBid) openrtb_ext.NonBid {
+	return openrtb_ext.NonBid{
+		ImpId:      bid.Bid.ImpID,
+		StatusCode: int(openrtb_ext.ResponseRejectedGeneral),
+		Ext: openrtb_ext.NonBidExt{
+			Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+				Price:   bid.Bid.Price,
+				ADomain: bid.Bid.ADomain,
+				CatTax:  bid.Bid.CatTax,
+				Cat:     bid.Bid.Cat,
+				DealID:  bid.Bid.DealID,
+				W:       bid.Bid.W,
+				H:       bid.Bid.H,
+				Dur:     bid.Bid.Dur,
+				MType:   bid.Bid.MType,
+			}},
+		},
+	}
+}
@@ -0,0 +1,265 @@
+package frequencycapping
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccountConfig = `{"caps": [
+	{"type": "advertiser", "values": ["capped.com"], "max_impressions": 1, "window_seconds": 3600},
+	{"type": "creative", "max_impressions": 2, "window_seconds": 60}
+]}`
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "Memory store",
+			config:      json.RawMessage(`{"store": {"type": "memory", "max_entries": 100}}`),
+		},
+		{
+			description: "Redis store",
+			config:      json.RawMessage(`{"store": {"type": "redis", "redis": {"address": "localhost:6379"}}}`),
+		},
+		{
+			description: "Empty config",
+			config:      nil,
+		},
+		{
+			description:   "Missing redis address",
+			config:        json.RawMessage(`{"store": {"type": "redis"}}`),
+			expectedError: "store.redis.address is required",
+		},
+		{
+			description:   "Unsupported store",
+			config:        json.RawMessage(`{"store": {"type": "aerospike"}}`),
+			expectedError: "unsupported store type: aerospike",
+		},
+		{
+			description:   "Invalid timeout",
+			config:        json.RawMessage(`{"timeout_ms": 0}`),
+			expectedError: "impression_ttl_seconds and timeout_ms must be positive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(test.config, moduledeps.ModuleDeps{})
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHandleEntrypointHook(t *testing.T) {
+	module := Module{cfg: config{HostCookieName: "uids_host"}}
+
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.AddCookie(&http.Cookie{Name: "uids_host", Value: "host-user"})
+	result, err := module.HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.EntrypointPayload{Request: req})
+	require.NoError(t, err)
+	assert.Equal(t, hookstage.ModuleContext{hostCookieContextKey: "host-user"}, result.ModuleContext)
+
+	req = httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	result, err = module.HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.EntrypointPayload{Request: req})
+	require.NoError(t, err)
+	assert.Empty(t, result.ModuleContext, "Missing cookie.")
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	testCases := []struct {
+		description     string
+		moduleContext   hookstage.ModuleContext
+		user            *openrtb2.User
+		expectedUserKey string
+	}{
+		{
+			description:     "Host cookie takes precedence",
+			moduleContext:   hookstage.ModuleContext{hostCookieContextKey: "host-user"},
+			user:            &openrtb2.User{ID: "user"},
+			expectedUserKey: hashUserID("host-user"),
+		},
+		{
+			description:     "User ID",
+			user:            &openrtb2.User{ID: "user", EIDs: []openrtb2.EID{{Source: "example.com", UIDs: []openrtb2.UID{{ID: "eid-user"}}}}},
+			expectedUserKey: hashUserID("user"),
+		},
+		{
+			description:     "Configured EID source",
+			user:            &openrtb2.User{EIDs: []openrtb2.EID{{Source: "other.com", UIDs: []openrtb2.UID{{ID: "other"}}}, {Source: "example.com", UIDs: []openrtb2.UID{{ID: "eid-user"}}}}},
+			expectedUserKey: hashUserID("eid-user"),
+		},
+		{
+			description: "Unknown user",
+			user:        &openrtb2.User{EIDs: []openrtb2.EID{{Source: "other.com", UIDs: []openrtb2.UID{{ID: "other"}}}}},
+		},
+	}
+
+	module := Module{cfg: config{EIDSources: []string{"example.com"}}}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: test.user}}}
+			result, err := module.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{ModuleContext: test.moduleContext}, payload)
+			require.NoError(t, err)
+
+			userKey, _ := result.ModuleContext[userKeyContextKey].(string)
+			assert.Equal(t, test.expectedUserKey, userKey)
+			assert.NotContains(t, userKey, "user", "Raw user ID must not be kept.")
+		})
+	}
+}
+
+func TestHandleAllProcessedBidResponsesHook(t *testing.T) {
+	module := Module{
+		cfg:     config{ImpressionTTLSeconds: 3600, TimeoutMS: 50},
+		store:   newMemoryStore(100),
+		events:  make(chan *analytics.EventRequest, 10),
+		pending: &sync.WaitGroup{},
+	}
+	go module.countImpressions()
+	defer close(module.events)
+
+	notifier := &moduledeps.EventNotifier{}
+	notifier.Subscribe(module.handleEvent)
+	miCtx := hookstage.ModuleInvocationContext{
+		AccountID:     "acc",
+		AccountConfig: json.RawMessage(testAccountConfig),
+		ModuleContext: hookstage.ModuleContext{userKeyContextKey: hashUserID("user")},
+	}
+
+	auction := func() (hookstage.AllProcessedBidResponsesPayload, hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]) {
+		payload := hookstage.AllProcessedBidResponsesPayload{Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", ADomain: []string{"capped.com"}, CrID: "cr1"}, GeneratedBidID: "gen1"},
+				{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", ADomain: []string{"other.com"}, CrID: "cr2"}},
+			}},
+		}}
+		result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), miCtx, payload)
+		require.NoError(t, err)
+		for _, mutation := range result.ChangeSet.Mutations() {
+			payload, err = mutation.Apply(payload)
+			require.NoError(t, err)
+		}
+		return payload, result
+	}
+
+	payload, result := auction()
+	assert.Len(t, payload.Responses["appnexus"].Bids, 2, "Bids must be allowed before any impression.")
+	assert.Empty(t, result.SeatNonBid)
+
+	notifier.Notify(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Win, AccountID: "acc", BidID: "gen1"}})
+	module.pending.Wait()
+	payload, _ = auction()
+	assert.Len(t, payload.Responses["appnexus"].Bids, 2, "Win events must not be counted.")
+
+	// repeated impression events of the same bid are counted once
+	notifier.Notify(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, AccountID: "acc", BidID: "gen1"}})
+	notifier.Notify(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, AccountID: "acc", BidID: "gen1"}})
+	notifier.Notify(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, AccountID: "acc", BidID: "bid2"}})
+	module.pending.Wait()
+
+	payload, result = auction()
+	require.Len(t, payload.Responses["appnexus"].Bids, 1, "Bid of the capped advertiser must be rejected.")
+	assert.Equal(t, "bid2", payload.Responses["appnexus"].Bids[0].Bid.ID)
+	require.Len(t, result.SeatNonBid, 1)
+	assert.Equal(t, "appnexus", result.SeatNonBid[0].Seat)
+	assert.Equal(t, []openrtb_ext.NonBid{{
+		ImpId:      "imp1",
+		StatusCode: 300,
+		Ext:        openrtb_ext.NonBidExt{Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{ADomain: []string{"capped.com"}}}},
+	}}, result.SeatNonBid[0].NonBid)
+	assert.Equal(t, []string{"Bid bid1 from seat appnexus has been rejected, advertiser capped.com frequency cap of 1 impressions reached"}, result.DebugMessages)
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+		Name:   frequencyCappingActivity,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{
+			{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{capTypeAnalyticKey: capTypeAdvertiser, capValueAnalyticKey: "capped.com"},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", ImpIds: []string{"imp1"}, BidIds: []string{"bid1"}},
+			},
+			{
+				Status:    hookanalytics.ResultStatusAllow,
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid2"}},
+			},
+		},
+	}}}, result.AnalyticsTags)
+
+	notifier.Notify(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, AccountID: "acc", BidID: "bid2"}})
+	module.pending.Wait()
+	payload, _ = auction()
+	assert.Empty(t, payload.Responses["appnexus"].Bids, "Creative cap must be applied per creative.")
+
+	otherUser := miCtx
+	otherUser.ModuleContext = hookstage.ModuleContext{userKeyContextKey: hashUserID("other")}
+	result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), otherUser, hookstage.AllProcessedBidResponsesPayload{Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", ADomain: []string{"capped.com"}, CrID: "cr1"}}}},
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations(), "Counters must be kept per user.")
+}
+
+func TestHandleAllProcessedBidResponsesHookSkipped(t *testing.T) {
+	testCases := []struct {
+		description   string
+		miCtx         hookstage.ModuleInvocationContext
+		expectedError error
+	}{
+		{
+			description: "Unknown user",
+			miCtx:       hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(testAccountConfig)},
+		},
+		{
+			description: "No caps",
+			miCtx:       hookstage.ModuleInvocationContext{ModuleContext: hookstage.ModuleContext{userKeyContextKey: "key"}},
+		},
+		{
+			description:   "Invalid account config",
+			miCtx:         hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(`{"caps": [{"type": "site", "max_impressions": 1, "window_seconds": 1}]}`)},
+			expectedError: hookexecution.NewFailure("unsupported cap type: site"),
+		},
+	}
+
+	module := Module{cfg: config{TimeoutMS: 50}, store: newMemoryStore(100)}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.AllProcessedBidResponsesPayload{Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1", ADomain: []string{"capped.com"}}}}},
+			}}
+			result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), test.miCtx, payload)
+			assert.Equal(t, test.expectedError, err)
+			assert.Empty(t, result.ChangeSet.Mutations())
+			assert.Empty(t, result.AnalyticsTags)
+		})
+	}
+}
+
+func TestHandleEventQueueFull(t *testing.T) {
+	module := Module{events: make(chan *analytics.EventRequest), pending: &sync.WaitGroup{}}
+
+	module.handleEvent(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp, AccountID: "acc", BidID: "bid1"}})
+	module.pending.Wait()
+
+	assert.Empty(t, module.events, "Events must be dropped without blocking when no worker is available.")
+}
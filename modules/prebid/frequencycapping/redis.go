@@ -0,0 +1,104 @@
+package frequencycapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStore is a counter store backed by a server compatible with the Redis protocol,
+// shared by all Prebid Server instances.
+type redisStore struct {
+	keyPrefix string
+	client    *redis.Client
+}
+
+func newRedisStore(cfg redisConfig) *redisStore {
+	return &redisStore{
+		keyPrefix: cfg.KeyPrefix,
+		client: redis.NewClient(&redis.Options{
+			Addr:     cfg.Address,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+			PoolSize: cfg.PoolSize,
+		}),
+	}
+}
+
+func (s *redisStore) get(ctx context.Context, keys []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(keys))
+	if len(keys) == 0 {
+		return counts, nil
+	}
+
+	prefixedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixedKeys = append(prefixedKeys, s.keyPrefix+key)
+	}
+
+	values, err := s.client.MGet(ctx, prefixedKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(keys) {
+		return nil, errors.New("unexpected MGET reply")
+	}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(data, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid counter %s: %s", keys[i], err)
+		}
+		counts[keys[i]] = count
+	}
+	return counts, nil
+}
+
+func (s *redisStore) increment(ctx context.Context, counters []counter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, c := range counters {
+			key := s.keyPrefix + c.Key
+			// the counter window starts with the first impression
+			pipe.SetNX(ctx, key, 0, time.Duration(c.WindowSeconds)*time.Second)
+			pipe.Incr(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) put(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, s.keyPrefix+key, value, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) take(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.GetDel(ctx, s.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
@@ -0,0 +1,66 @@
+package frequencycapping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+	s := newRedisStore(redisConfig{Address: server.Addr(), Password: "secret", DB: 1, KeyPrefix: "fc:", PoolSize: 1})
+	ctx := context.Background()
+
+	counts, err := s.get(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Empty(t, counts, "Missing counters must be omitted.")
+
+	require.NoError(t, s.increment(ctx, []counter{{Key: "a", WindowSeconds: 10}, {Key: "b", WindowSeconds: 60}}))
+	require.NoError(t, s.increment(ctx, []counter{{Key: "a", WindowSeconds: 10}}))
+
+	counts, err = s.get(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1}, counts)
+
+	server.Select(1)
+	assert.Equal(t, 10*time.Second, server.TTL("fc:a"), "Counter must expire after the window.")
+
+	require.NoError(t, s.put(ctx, map[string][]byte{"imp": []byte(`[{"key":"a","window":10}]`)}, time.Minute))
+	assert.Equal(t, time.Minute, server.TTL("fc:imp"), "Value must expire after the ttl.")
+
+	value, ok, err := s.take(ctx, "imp")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`[{"key":"a","window":10}]`), value)
+
+	_, ok, err = s.take(ctx, "imp")
+	require.NoError(t, err)
+	assert.False(t, ok, "Taken value must be removed.")
+
+	server.FastForward(10 * time.Second)
+	counts, err = s.get(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"b": 1}, counts, "Counter must be reset after the window.")
+}
+
+func TestRedisStoreErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	s := newRedisStore(redisConfig{Address: server.Addr(), Password: "wrong", PoolSize: 1})
+	_, err := s.get(context.Background(), []string{"a"})
+	assert.Error(t, err, "Authentication failure must be returned.")
+
+	s = newRedisStore(redisConfig{Address: server.Addr(), Password: "secret", PoolSize: 1})
+	require.NoError(t, server.Set("a", "text"))
+	_, err = s.get(context.Background(), []string{"a"})
+	assert.ErrorContains(t, err, "invalid counter a", "Non numeric counter must be returned as error.")
+
+	server.Close()
+	assert.Error(t, s.increment(context.Background(), []counter{{Key: "a", WindowSeconds: 10}}), "Connection failure must be returned.")
+}
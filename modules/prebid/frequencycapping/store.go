@@ -0,0 +1,133 @@
+package frequencycapping
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// counter identifies an impression counter expiring after the window elapsed since its first impression.
+type counter struct {
+	Key           string `json:"key"`
+	WindowSeconds int    `json:"window"`
+}
+
+// counterStore keeps the impression counters and the counters to increment for each bid
+// which may be rendered. Implementations must be safe for concurrent use.
+type counterStore interface {
+	// get returns the current values of the counters, missing counters are omitted.
+	get(ctx context.Context, keys []string) (map[string]int64, error)
+	// increment increments the counters creating the missing ones.
+	increment(ctx context.Context, counters []counter) error
+	// put stores the values expiring after the ttl.
+	put(ctx context.Context, values map[string][]byte, ttl time.Duration) error
+	// take removes the value and returns it along with true if the value was found.
+	take(ctx context.Context, key string) ([]byte, bool, error)
+}
+
+type memoryItem struct {
+	key       string
+	count     int64
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryStore is an in-memory LRU counter store for single instance deployments.
+type memoryStore struct {
+	maxEntries int
+	items      map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+	mutex      sync.Mutex
+}
+
+func newMemoryStore(maxEntries int) *memoryStore {
+	return &memoryStore{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+func (s *memoryStore) get(_ context.Context, keys []string) (map[string]int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	counts := make(map[string]int64, len(keys))
+	for _, key := range keys {
+		if item, ok := s.lookup(key); ok {
+			counts[key] = item.count
+		}
+	}
+	return counts, nil
+}
+
+func (s *memoryStore) increment(_ context.Context, counters []counter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range counters {
+		if item, ok := s.lookup(c.Key); ok {
+			item.count++
+			continue
+		}
+		s.add(&memoryItem{key: c.Key, count: 1, expiresAt: s.now().Add(time.Duration(c.WindowSeconds) * time.Second)})
+	}
+	return nil
+}
+
+func (s *memoryStore) put(_ context.Context, values map[string][]byte, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, value := range values {
+		if element, ok := s.items[key]; ok {
+			s.remove(element)
+		}
+		s.add(&memoryItem{key: key, value: value, expiresAt: s.now().Add(ttl)})
+	}
+	return nil
+}
+
+func (s *memoryStore) take(_ context.Context, key string) ([]byte, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item, ok := s.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+	s.remove(s.items[key])
+	return item.value, true, nil
+}
+
+// lookup returns the item if it has not expired, expired items are removed.
+func (s *memoryStore) lookup(key string) (*memoryItem, bool) {
+	element, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := element.Value.(*memoryItem)
+	if !item.expiresAt.After(s.now()) {
+		s.remove(element)
+		return nil, false
+	}
+
+	s.lru.MoveToFront(element)
+	return item, true
+}
+
+func (s *memoryStore) add(item *memoryItem) {
+	if s.lru.Len() >= s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+	s.items[item.key] = s.lru.PushFront(item)
+}
+
+func (s *memoryStore) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.items, element.Value.(*memoryItem).key)
+}
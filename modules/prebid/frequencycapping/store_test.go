@@ -0,0 +1,57 @@
+package frequencycapping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreCounters(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := newMemoryStore(10)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.increment(context.Background(), []counter{{Key: "a", WindowSeconds: 10}, {Key: "b", WindowSeconds: 60}}))
+	require.NoError(t, s.increment(context.Background(), []counter{{Key: "a", WindowSeconds: 10}}))
+
+	counts, err := s.get(context.Background(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1}, counts, "Missing counters must be omitted.")
+
+	// the window starts with the first impression and is not extended by the subsequent ones
+	now = now.Add(10 * time.Second)
+	counts, err = s.get(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"b": 1}, counts, "Expired counters must be omitted.")
+}
+
+func TestMemoryStoreValues(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := newMemoryStore(2)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.put(context.Background(), map[string][]byte{"a": []byte("1")}, time.Minute))
+	require.NoError(t, s.put(context.Background(), map[string][]byte{"b": []byte("2")}, time.Second))
+	require.NoError(t, s.put(context.Background(), map[string][]byte{"c": []byte("3")}, time.Minute))
+
+	_, ok, err := s.take(context.Background(), "a")
+	require.NoError(t, err)
+	assert.False(t, ok, "Least recently used value must be evicted.")
+
+	value, ok, err := s.take(context.Background(), "c")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("3"), value)
+
+	_, ok, err = s.take(context.Background(), "c")
+	require.NoError(t, err)
+	assert.False(t, ok, "Taken value must be removed.")
+
+	now = now.Add(time.Second)
+	_, ok, err = s.take(context.Background(), "b")
+	require.NoError(t, err)
+	assert.False(t, ok, "Expired value must not be returned.")
+}
@@ -0,0 +1,90 @@
+package ipgeolocation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	fieldCountry   = "country"
+	fieldRegion    = "region"
+	fieldMetro     = "metro"
+	fieldCity      = "city"
+	fieldZip       = "zip"
+	fieldUTCOffset = "utcoffset"
+	fieldType      = "type"
+)
+
+var supportedFields = []string{fieldCountry, fieldRegion, fieldMetro, fieldCity, fieldZip, fieldUTCOffset, fieldType}
+
+// config locates the geolocation database and the device.geo fields written by default.
+type config struct {
+	// DatabasePath is the location of the MMDB file containing city level geolocation data.
+	DatabasePath string `json:"database_path"`
+	// RefreshIntervalSeconds specifies how often the database file is checked for changes.
+	// The file is loaded only once at startup if the value is not positive.
+	RefreshIntervalSeconds int `json:"refresh_interval_seconds"`
+	// Fields lists the device.geo fields written by default. All supported fields are written if empty.
+	Fields []string `json:"fields"`
+}
+
+// accountConfig narrows the device.geo fields written for an account and whether values sent by the publisher
+// are replaced.
+type accountConfig struct {
+	// Fields lists the device.geo fields the module is allowed to write for the account.
+	Fields []string `json:"fields"`
+	// Overwrite indicates whether values already present in device.geo should be replaced.
+	Overwrite bool `json:"overwrite"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if cfg.DatabasePath == "" {
+		return cfg, errors.New("database_path is required")
+	}
+
+	if len(cfg.Fields) == 0 {
+		cfg.Fields = supportedFields
+	}
+
+	if err := validateFields(cfg.Fields); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func newAccountConfig(data json.RawMessage, defaultFields []string) (accountConfig, error) {
+	var cfg accountConfig
+	if len(data) == 0 {
+		cfg.Fields = defaultFields
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	if cfg.Fields == nil {
+		cfg.Fields = defaultFields
+	}
+
+	return cfg, validateFields(cfg.Fields)
+}
+
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(supportedFields, field) {
+			return fmt.Errorf("unsupported device.geo field: %s", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package ipgeolocation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata" // time zone names from the database must resolve regardless of the host setup
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// cityRecord holds the subset of GeoIP2/GeoLite2 City fields used to populate device.geo.
+type cityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Location struct {
+		MetroCode uint   `maxminddb:"metro_code"`
+		TimeZone  string `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	Postal struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"postal"`
+	Subdivisions []struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"subdivisions"`
+}
+
+// location is the database lookup result converted to OpenRTB conventions.
+type location struct {
+	Country   string
+	Region    string
+	Metro     string
+	City      string
+	Zip       string
+	UTCOffset int64
+	HasOffset bool
+}
+
+// database wraps the MMDB reader allowing it to be replaced while lookups are in progress.
+type database struct {
+	reader atomic.Pointer[maxminddb.Reader]
+}
+
+// load parses data as an MMDB file and replaces the current reader if successful.
+func (db *database) load(data []byte) error {
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to load geolocation database: %s", err)
+	}
+	db.reader.Store(reader)
+	return nil
+}
+
+// lookup returns the location of the ip address. The second return value is false if
+// the database does not contain a record for the ip address.
+func (db *database) lookup(ip net.IP, now time.Time) (location, bool, error) {
+	reader := db.reader.Load()
+	if reader == nil {
+		return location{}, false, errors.New("geolocation database is not loaded")
+	}
+
+	if reader.Metadata.IPVersion == 4 && ip.To4() == nil {
+		return location{}, false, nil
+	}
+
+	var record cityRecord
+	offset, err := reader.LookupOffset(ip)
+	if err != nil {
+		return location{}, false, err
+	}
+	if offset == maxminddb.NotFound {
+		return location{}, false, nil
+	}
+	if err := reader.Decode(offset, &record); err != nil {
+		return location{}, false, err
+	}
+
+	return newLocation(record, now), true, nil
+}
+
+func newLocation(record cityRecord, now time.Time) location {
+	loc := location{
+		Country: alpha3CountryCode(record.Country.ISOCode),
+		City:    record.City.Names["en"],
+		Zip:     record.Postal.Code,
+	}
+
+	if len(record.Subdivisions) > 0 {
+		loc.Region = record.Subdivisions[0].ISOCode
+	}
+
+	if record.Location.MetroCode > 0 {
+		loc.Metro = fmt.Sprint(record.Location.MetroCode)
+	}
+
+	if record.Location.TimeZone != "" {
+		if tz, err := time.LoadLocation(record.Location.TimeZone); err == nil {
+			_, offsetSeconds := now.In(tz).Zone()
+			loc.UTCOffset = int64(offsetSeconds / 60)
+			loc.HasOffset = true
+		}
+	}
+
+	return loc
+}
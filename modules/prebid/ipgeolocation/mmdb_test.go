@@ -0,0 +1,135 @@
+package ipgeolocation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestDatabase writes a minimal IPv4 MMDB file containing the provided networks.
+// It supports only the value types required by the module tests.
+func writeTestDatabase(t *testing.T, path string, networks map[string]map[string]interface{}) {
+	type node struct{ children [2]int }
+	type leaf struct{ offset int }
+
+	nodes := []node{{children: [2]int{-1, -1}}}
+	leaves := map[[2]int]leaf{}
+
+	var data bytes.Buffer
+	cidrs := make([]string, 0, len(networks))
+	for cidr := range networks {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		ones, _ := network.Mask.Size()
+		ip := network.IP.To4()
+
+		offset := data.Len()
+		encodeValue(&data, networks[cidr])
+
+		current := 0
+		for i := 0; i < ones; i++ {
+			bit := int(ip[i/8]>>(7-uint(i%8))) & 1
+			if i == ones-1 {
+				leaves[[2]int{current, bit}] = leaf{offset: offset}
+				break
+			}
+			if nodes[current].children[bit] == -1 {
+				nodes = append(nodes, node{children: [2]int{-1, -1}})
+				nodes[current].children[bit] = len(nodes) - 1
+			}
+			current = nodes[current].children[bit]
+		}
+	}
+
+	nodeCount := len(nodes)
+	var out bytes.Buffer
+	for i, n := range nodes {
+		for bit, child := range n.children {
+			record := nodeCount
+			if l, ok := leaves[[2]int{i, bit}]; ok {
+				record = nodeCount + 16 + l.offset
+			} else if child != -1 {
+				record = child
+			}
+			out.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+	out.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encodeValue(&out, map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(1700000000),
+		"database_type":               "Test-City",
+		"description":                 map[string]interface{}{"en": "test database"},
+		"ip_version":                  uint16(4),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+	})
+
+	require.NoError(t, os.WriteFile(path, out.Bytes(), 0644))
+}
+
+func encodeValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		writeControl(buf, 2, len(v))
+		buf.WriteString(v)
+	case uint16:
+		writeUint(buf, 5, uint64(v))
+	case uint32:
+		writeUint(buf, 6, uint64(v))
+	case uint64:
+		writeUint(buf, 9, v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		writeControl(buf, 7, len(keys))
+		for _, key := range keys {
+			encodeValue(buf, key)
+			encodeValue(buf, v[key])
+		}
+	case []interface{}:
+		writeControl(buf, 11, len(v))
+		for _, item := range v {
+			encodeValue(buf, item)
+		}
+	default:
+		panic("unsupported value type")
+	}
+}
+
+func writeUint(buf *bytes.Buffer, dataType int, value uint64) {
+	raw := make([]byte, 8)
+	binary.BigEndian.PutUint64(raw, value)
+	raw = bytes.TrimLeft(raw, "\x00")
+	writeControl(buf, dataType, len(raw))
+	buf.Write(raw)
+}
+
+func writeControl(buf *bytes.Buffer, dataType, size int) {
+	if size >= 29 {
+		panic("unsupported value size")
+	}
+	if dataType <= 7 {
+		buf.WriteByte(byte(dataType<<5 | size))
+		return
+	}
+	buf.WriteByte(byte(size))
+	buf.WriteByte(byte(dataType - 7))
+}
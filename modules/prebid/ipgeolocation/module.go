@@ -0,0 +1,145 @@
+package ipgeolocation
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+const geolocationActivity = "device_geolocation"
+
+func Builder(data json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &database{}
+	reloader := task.NewFileRunner(cfg.DatabasePath, db.load)
+	if err := reloader.Run(); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshIntervalSeconds > 0 {
+		task.NewTickerTask(time.Duration(cfg.RefreshIntervalSeconds)*time.Second, reloader).Start()
+	}
+
+	return Module{db: db, defaultFields: cfg.Fields, now: time.Now}, nil
+}
+
+type Module struct {
+	db            *database
+	defaultFields []string
+	now           func() time.Time
+}
+
+// HandleProcessedAuctionHook populates device.geo based on the device ip address.
+// Only the fields allowed by the account config are written, values provided
+// with the request are kept unless the account config enables overwriting.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	cfg, err := newAccountConfig(miCtx.AccountConfig, m.defaultFields)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	if payload.Request == nil || payload.Request.Device == nil || len(cfg.Fields) == 0 {
+		return result, nil
+	}
+
+	ip := deviceIP(payload.Request.Device)
+	if ip == nil {
+		return result, nil
+	}
+
+	loc, found, err := m.db.lookup(ip, m.now())
+	if err != nil {
+		glog.Warningf("ipgeolocation: failed to look up ip address: %s", err)
+		result.Warnings = append(result.Warnings, "failed to look up device ip address")
+		return result, nil
+	}
+	if !found {
+		return result, nil
+	}
+
+	geo, updatedFields := mergeGeo(payload.Request.Device.Geo, loc, cfg)
+	if len(updatedFields) == 0 {
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+		payload.Request.Device.Geo = geo
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidRequest", "device.geo")
+
+	result.AnalyticsTags = hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   geolocationActivity,
+			Status: hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{{
+				Status: hookanalytics.ResultStatusModify,
+				Values: map[string]interface{}{"fields": updatedFields},
+			}},
+		}},
+	}
+
+	return result, nil
+}
+
+func deviceIP(device *openrtb2.Device) net.IP {
+	if ip := net.ParseIP(device.IP); ip != nil {
+		return ip
+	}
+	return net.ParseIP(device.IPv6)
+}
+
+// mergeGeo returns a copy of geo populated with the location fields enabled by the config
+// along with the list of fields which have been changed.
+func mergeGeo(geo *openrtb2.Geo, loc location, cfg accountConfig) (*openrtb2.Geo, []string) {
+	var merged openrtb2.Geo
+	if geo != nil {
+		merged = *geo
+	}
+
+	var updatedFields []string
+	setString := func(field string, target *string, value string) {
+		if value == "" || !slices.Contains(cfg.Fields, field) || (*target != "" && !cfg.Overwrite) || *target == value {
+			return
+		}
+		*target = value
+		updatedFields = append(updatedFields, field)
+	}
+
+	setString(fieldCountry, &merged.Country, loc.Country)
+	setString(fieldRegion, &merged.Region, loc.Region)
+	setString(fieldMetro, &merged.Metro, loc.Metro)
+	setString(fieldCity, &merged.City, loc.City)
+	setString(fieldZip, &merged.ZIP, loc.Zip)
+
+	if loc.HasOffset && slices.Contains(cfg.Fields, fieldUTCOffset) && (merged.UTCOffset == 0 || cfg.Overwrite) && merged.UTCOffset != loc.UTCOffset {
+		merged.UTCOffset = loc.UTCOffset
+		updatedFields = append(updatedFields, fieldUTCOffset)
+	}
+
+	if len(updatedFields) > 0 && slices.Contains(cfg.Fields, fieldType) && (merged.Type == 0 || cfg.Overwrite) && merged.Type != adcom1.LocationIP {
+		merged.Type = adcom1.LocationIP
+		updatedFields = append(updatedFields, fieldType)
+	}
+
+	return &merged, updatedFields
+}
@@ -0,0 +1,254 @@
+package ipgeolocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNetworks = map[string]map[string]interface{}{
+	"1.2.3.0/24": {
+		"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Los Angeles"}},
+		"country":      map[string]interface{}{"iso_code": "US"},
+		"location":     map[string]interface{}{"metro_code": uint16(803), "time_zone": "America/Los_Angeles"},
+		"postal":       map[string]interface{}{"code": "90001"},
+		"subdivisions": []interface{}{map[string]interface{}{"iso_code": "CA"}},
+	},
+	"5.6.0.0/16": {
+		"country": map[string]interface{}{"iso_code": "DE"},
+	},
+}
+
+func buildTestModule(t *testing.T) Module {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, testNetworks)
+
+	result, err := Builder(json.RawMessage(`{"enabled": true, "database_path": "`+path+`"}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err, "Failed to build module.")
+
+	module, ok := result.(Module)
+	require.True(t, ok, "Failed to cast module type.")
+
+	module.now = func() time.Time { return time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC) }
+	return module
+}
+
+func TestBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, testNetworks)
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.mmdb")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("invalid"), 0644))
+
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "Valid config",
+			config:      json.RawMessage(`{"database_path": "` + path + `", "fields": ["country"]}`),
+		},
+		{
+			description:   "Missing database path",
+			config:        json.RawMessage(`{}`),
+			expectedError: "database_path is required",
+		},
+		{
+			description:   "Unsupported field",
+			config:        json.RawMessage(`{"database_path": "` + path + `", "fields": ["lat"]}`),
+			expectedError: "unsupported device.geo field: lat",
+		},
+		{
+			description:   "Missing database file",
+			config:        json.RawMessage(`{"database_path": "` + filepath.Join(t.TempDir(), "missing.mmdb") + `"}`),
+			expectedError: "no such file or directory",
+		},
+		{
+			description:   "Invalid database file",
+			config:        json.RawMessage(`{"database_path": "` + invalidPath + `"}`),
+			expectedError: "failed to load geolocation database",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(test.config, moduledeps.ModuleDeps{})
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	testCases := []struct {
+		description        string
+		accountConfig      json.RawMessage
+		bidRequest         *openrtb2.BidRequest
+		expectedBidRequest *openrtb2.BidRequest
+		expectedHookResult hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]
+		expectedError      error
+	}{
+		{
+			description: "All fields populated for ipv4 address",
+			bidRequest:  &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4"}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4", Geo: &openrtb2.Geo{
+				Country:   "USA",
+				Region:    "CA",
+				Metro:     "803",
+				City:      "Los Angeles",
+				ZIP:       "90001",
+				UTCOffset: -480,
+				Type:      adcom1.LocationIP,
+			}}},
+			expectedHookResult: modifiedResult("country", "region", "metro", "city", "zip", "utcoffset", "type"),
+		},
+		{
+			description:   "Only account fields populated",
+			accountConfig: json.RawMessage(`{"fields": ["country", "region"]}`),
+			bidRequest:    &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4"}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4", Geo: &openrtb2.Geo{
+				Country: "USA",
+				Region:  "CA",
+			}}},
+			expectedHookResult: modifiedResult("country", "region"),
+		},
+		{
+			description: "Existing values kept",
+			bidRequest:  &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "5.6.7.8", Geo: &openrtb2.Geo{Country: "FRA", City: "Paris"}}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "5.6.7.8", Geo: &openrtb2.Geo{
+				Country: "FRA",
+				City:    "Paris",
+			}}},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:   "Existing values overwritten",
+			accountConfig: json.RawMessage(`{"overwrite": true}`),
+			bidRequest:    &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "5.6.7.8", Geo: &openrtb2.Geo{Country: "FRA", City: "Paris", Type: adcom1.LocationGPS}}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "5.6.7.8", Geo: &openrtb2.Geo{
+				Country: "DEU",
+				City:    "Paris",
+				Type:    adcom1.LocationIP,
+			}}},
+			expectedHookResult: modifiedResult("country", "type"),
+		},
+		{
+			description:        "Unknown ip address",
+			bidRequest:         &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "9.9.9.9"}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "9.9.9.9"}},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:        "Ipv6 address not present in ipv4 database",
+			bidRequest:         &openrtb2.BidRequest{Device: &openrtb2.Device{IPv6: "2001:db8::1"}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IPv6: "2001:db8::1"}},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:        "Missing device",
+			bidRequest:         &openrtb2.BidRequest{},
+			expectedBidRequest: &openrtb2.BidRequest{},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:        "Invalid account config",
+			accountConfig:      json.RawMessage(`{"fields": ["lat"]}`),
+			bidRequest:         &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4"}},
+			expectedBidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4"}},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+			expectedError:      hookexecution.NewFailure("unsupported device.geo field: lat"),
+		},
+	}
+
+	module := buildTestModule(t)
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.bidRequest}}
+
+			hookResult, err := module.HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig, Endpoint: hookexecution.EndpointAuction},
+				payload,
+			)
+			assert.Equal(t, test.expectedError, err, "Invalid hook execution error.")
+
+			// test mutations separately
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBidRequest, payload.Request.BidRequest, "Invalid BidRequest after executing ProcessedAuctionHook.")
+
+			// reset ChangeSet not to break hookResult assertion, we validated ChangeSet separately
+			hookResult.ChangeSet = hookstage.ChangeSet[hookstage.ProcessedAuctionRequestPayload]{}
+			assert.Equal(t, test.expectedHookResult, hookResult, "Invalid hook execution result.")
+		})
+	}
+}
+
+func TestDatabaseReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, map[string]map[string]interface{}{
+		"1.2.3.0/24": {"country": map[string]interface{}{"iso_code": "US"}},
+	})
+
+	result, err := Builder(json.RawMessage(`{"database_path": "`+path+`", "refresh_interval_seconds": 1}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module := result.(Module)
+
+	loc, found, err := module.db.lookup(net.IP{1, 2, 3, 4}, time.Now())
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "USA", loc.Country)
+
+	writeTestDatabase(t, path, map[string]map[string]interface{}{
+		"1.2.3.0/24": {"country": map[string]interface{}{"iso_code": "CA"}},
+	})
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	assert.Eventually(t, func() bool {
+		loc, _, _ := module.db.lookup(net.IP{1, 2, 3, 4}, time.Now())
+		return loc.Country == "CAN"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestDatabaseNotLoaded(t *testing.T) {
+	_, _, err := (&database{}).lookup(net.IP{1, 2, 3, 4}, time.Now())
+	assert.Equal(t, errors.New("geolocation database is not loaded"), err)
+}
+
+func modifiedResult(fields ...string) hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload] {
+	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+		AnalyticsTags: hookanalytics.Analytics{
+			Activities: []hookanalytics.Activity{{
+				Name:   geolocationActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{{
+					Status: hookanalytics.ResultStatusModify,
+					Values: map[string]interface{}{"fields": fields},
+				}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package ivtfiltering
+
+import (
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+)
+
+const ivtFilteringActivity = "ivt_filtering"
+
+const (
+	scoreAnalyticKey   = "score"
+	signalsAnalyticKey = "signals"
+)
+
+func newAllowedTags() hookanalytics.Analytics {
+	return newTags(hookanalytics.Result{Status: hookanalytics.ResultStatusAllow})
+}
+
+// newFlaggedTags reports traffic which matched some lists but did not reach any of the account thresholds.
+func newFlaggedTags(score int, matched []scoredList) hookanalytics.Analytics {
+	return newTags(hookanalytics.Result{
+		Status: hookanalytics.ResultStatusAllow,
+		Values: signalValues(score, matched),
+	})
+}
+
+func newRejectedTags(score int, matched []scoredList) hookanalytics.Analytics {
+	return newTags(hookanalytics.Result{
+		Status: hookanalytics.ResultStatusBlock,
+		Values: signalValues(score, matched),
+	})
+}
+
+func newStrippedTags(score int, matched []scoredList, bidders []string) hookanalytics.Analytics {
+	analytics := hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{Name: ivtFilteringActivity, Status: hookanalytics.ActivityStatusSuccess}},
+	}
+	for _, bidder := range bidders {
+		analytics.Activities[0].Results = append(analytics.Activities[0].Results, hookanalytics.Result{
+			Status:    hookanalytics.ResultStatusBlock,
+			Values:    signalValues(score, matched),
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder},
+		})
+	}
+	return analytics
+}
+
+func newTags(result hookanalytics.Result) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:    ivtFilteringActivity,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{result},
+		}},
+	}
+}
+
+func signalValues(score int, matched []scoredList) map[string]interface{} {
+	signals := make([]string, 0, len(matched))
+	for _, list := range matched {
+		signals = append(signals, list.Name)
+	}
+	return map[string]interface{}{
+		scoreAnalyticKey:   score,
+		signalsAnalyticKey: signals,
+	}
+}
@@ -0,0 +1,94 @@
+package ivtfiltering
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// config lists the files of known invalid traffic sources and how often they are reloaded.
+type config struct {
+	// RefreshIntervalSeconds specifies how often the list files are checked for changes.
+	// The files are loaded only once at startup if the value is not positive.
+	RefreshIntervalSeconds int `json:"refresh_interval_seconds"`
+	// IPRanges lists files of IPv4/IPv6 addresses and CIDR ranges, one entry per line.
+	IPRanges []listConfig `json:"ip_ranges"`
+	// UserAgents lists files of case-insensitive regular expressions matched against device.ua, one per line.
+	UserAgents []listConfig `json:"user_agents"`
+	// Mismatches lists JSON files with rules restricting which site domains and app bundles
+	// may be claimed by traffic originating from the given ip ranges.
+	Mismatches []listConfig `json:"mismatches"`
+}
+
+// listConfig describes a single hot-reloadable list and the signal raised when a request matches it.
+type listConfig struct {
+	Name  string               `json:"name"`
+	Path  string               `json:"path"`
+	Score int                  `json:"score"`
+	NBR   openrtb3.NoBidReason `json:"nbr"`
+}
+
+// accountConfig sets the scores at which an account rejects the auction or strips bidders from it.
+type accountConfig struct {
+	// RejectThreshold is the minimum score at which the auction is rejected. Disabled if not positive.
+	RejectThreshold int `json:"reject_threshold"`
+	// StripThreshold is the minimum score at which StripBidders are removed from the auction. Disabled if not positive.
+	StripThreshold int `json:"strip_threshold"`
+	// StripBidders lists the bidders which are not called for traffic scoring at or above StripThreshold.
+	StripBidders []string `json:"strip_bidders"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	defaultNBRs := map[*[]listConfig]openrtb3.NoBidReason{
+		&cfg.IPRanges:   openrtb3.NoBidProxy,
+		&cfg.UserAgents: openrtb3.NoBidCrawler,
+		&cfg.Mismatches: openrtb3.NoBidNonHuman,
+	}
+	for lists, defaultNBR := range defaultNBRs {
+		for i := range *lists {
+			list := &(*lists)[i]
+			if err := list.validate(); err != nil {
+				return cfg, err
+			}
+			if list.NBR == 0 {
+				list.NBR = defaultNBR
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c listConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("list name is required")
+	}
+	if c.Path == "" {
+		return fmt.Errorf("path is required for list %s", c.Name)
+	}
+	if c.Score < 0 {
+		return fmt.Errorf("score must not be negative for list %s", c.Name)
+	}
+	return nil
+}
+
+func newAccountConfig(data json.RawMessage) (accountConfig, error) {
+	var cfg accountConfig
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,177 @@
+package ivtfiltering
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/sliceutil"
+)
+
+// matcher is implemented by the hot-reloadable lists evaluated against every request.
+type matcher interface {
+	load(data []byte) error
+	match(req requestSignals) bool
+}
+
+// requestSignals holds the request attributes relevant for traffic scoring.
+type requestSignals struct {
+	ip        netip.Addr
+	userAgent string
+	domain    string
+	bundle    string
+}
+
+// ipRangeList matches requests originating from any of the listed addresses or ranges.
+type ipRangeList struct {
+	prefixes atomic.Pointer[[]netip.Prefix]
+}
+
+func (l *ipRangeList) load(data []byte) error {
+	var prefixes []netip.Prefix
+	for _, line := range readLines(data) {
+		prefix, err := parsePrefix(line)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	l.prefixes.Store(&prefixes)
+	return nil
+}
+
+func (l *ipRangeList) match(req requestSignals) bool {
+	prefixes := l.prefixes.Load()
+	if prefixes == nil || !req.ip.IsValid() {
+		return false
+	}
+	return containsAddr(*prefixes, req.ip)
+}
+
+// userAgentList matches requests with a user agent matching any of the listed patterns.
+type userAgentList struct {
+	patterns atomic.Pointer[[]*regexp.Regexp]
+}
+
+func (l *userAgentList) load(data []byte) error {
+	var patterns []*regexp.Regexp
+	for _, line := range readLines(data) {
+		pattern, err := regexp.Compile("(?i)" + line)
+		if err != nil {
+			return fmt.Errorf("invalid user agent pattern %q: %s", line, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	l.patterns.Store(&patterns)
+	return nil
+}
+
+func (l *userAgentList) match(req requestSignals) bool {
+	patterns := l.patterns.Load()
+	if patterns == nil || req.userAgent == "" {
+		return false
+	}
+	for _, pattern := range *patterns {
+		if pattern.MatchString(req.userAgent) {
+			return true
+		}
+	}
+	return false
+}
+
+// mismatchRule restricts traffic from the listed ranges to the listed domains and bundles.
+type mismatchRule struct {
+	IPRanges []string `json:"ip_ranges"`
+	Domains  []string `json:"domains"`
+	Bundles  []string `json:"bundles"`
+
+	prefixes []netip.Prefix
+}
+
+// mismatchList matches requests originating from a rule's ranges which claim
+// a site domain or app bundle not allowed by the rule.
+type mismatchList struct {
+	rules atomic.Pointer[[]mismatchRule]
+}
+
+func (l *mismatchList) load(data []byte) error {
+	var rules []mismatchRule
+	if err := jsonutil.UnmarshalValid(data, &rules); err != nil {
+		return fmt.Errorf("invalid mismatch rules: %s", err)
+	}
+	for i := range rules {
+		for _, ipRange := range rules[i].IPRanges {
+			prefix, err := parsePrefix(ipRange)
+			if err != nil {
+				return err
+			}
+			rules[i].prefixes = append(rules[i].prefixes, prefix)
+		}
+	}
+	l.rules.Store(&rules)
+	return nil
+}
+
+func (l *mismatchList) match(req requestSignals) bool {
+	rules := l.rules.Load()
+	if rules == nil || !req.ip.IsValid() {
+		return false
+	}
+	for _, rule := range *rules {
+		if !containsAddr(rule.prefixes, req.ip) {
+			continue
+		}
+		if req.domain != "" && !sliceutil.ContainsStringIgnoreCase(rule.Domains, req.domain) {
+			return true
+		}
+		if req.bundle != "" && !sliceutil.ContainsStringIgnoreCase(rule.Bundles, req.bundle) {
+			return true
+		}
+	}
+	return false
+}
+
+// readLines returns the trimmed non-empty lines of data skipping the ones starting with #.
+func readLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return prefix, fmt.Errorf("invalid ip range %q: %s", value, err)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid ip address %q: %s", value, err)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package ivtfiltering
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/sliceutil"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+func Builder(data json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []scoredList
+	add := func(listCfg listConfig, m matcher) error {
+		runner := task.NewFileRunner(listCfg.Path, m.load)
+		if err := runner.Run(); err != nil {
+			return fmt.Errorf("failed to load list %s: %s", listCfg.Name, err)
+		}
+		if cfg.RefreshIntervalSeconds > 0 {
+			task.NewTickerTask(time.Duration(cfg.RefreshIntervalSeconds)*time.Second, runner).Start()
+		}
+		lists = append(lists, scoredList{listConfig: listCfg, matcher: m})
+		return nil
+	}
+
+	for _, listCfg := range cfg.IPRanges {
+		if err := add(listCfg, &ipRangeList{}); err != nil {
+			return nil, err
+		}
+	}
+	for _, listCfg := range cfg.UserAgents {
+		if err := add(listCfg, &userAgentList{}); err != nil {
+			return nil, err
+		}
+	}
+	for _, listCfg := range cfg.Mismatches {
+		if err := add(listCfg, &mismatchList{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return Module{lists: lists}, nil
+}
+
+type Module struct {
+	lists []scoredList
+}
+
+type scoredList struct {
+	listConfig
+	matcher matcher
+}
+
+// HandleProcessedAuctionHook scores the request against the configured lists.
+// Depending on the account thresholds the auction is either rejected or
+// the configured bidders are removed from all impressions.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	cfg, err := newAccountConfig(miCtx.AccountConfig)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, nil
+	}
+
+	score, matched := m.evaluate(newRequestSignals(payload.Request))
+	if len(matched) == 0 {
+		result.AnalyticsTags = newAllowedTags()
+		return result, nil
+	}
+
+	if cfg.RejectThreshold > 0 && score >= cfg.RejectThreshold {
+		result.Reject = true
+		result.NbrCode = int(rejectionReason(matched))
+		result.AnalyticsTags = newRejectedTags(score, matched)
+		return result, nil
+	}
+
+	if cfg.StripThreshold > 0 && score >= cfg.StripThreshold && len(cfg.StripBidders) > 0 {
+		result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+			return payload, stripBidders(payload.Request, cfg.StripBidders)
+		}, hookstage.MutationDelete, "bidRequest", "imp.ext.prebid.bidder")
+		result.AnalyticsTags = newStrippedTags(score, matched, cfg.StripBidders)
+		return result, nil
+	}
+
+	result.AnalyticsTags = newFlaggedTags(score, matched)
+	return result, nil
+}
+
+// evaluate returns the total score of the request capped at 100 along with the lists the request matched.
+func (m Module) evaluate(req requestSignals) (int, []scoredList) {
+	var score int
+	var matched []scoredList
+	for _, list := range m.lists {
+		if list.matcher.match(req) {
+			score += list.Score
+			matched = append(matched, list)
+		}
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, matched
+}
+
+// rejectionReason returns the no bid reason of the highest scoring matched list.
+func rejectionReason(matched []scoredList) openrtb3.NoBidReason {
+	top := matched[0]
+	for _, list := range matched[1:] {
+		if list.Score > top.Score {
+			top = list
+		}
+	}
+	return top.NBR
+}
+
+func newRequestSignals(req *openrtb_ext.RequestWrapper) requestSignals {
+	var signals requestSignals
+	if req.Device != nil {
+		signals.userAgent = req.Device.UA
+		if ip, err := netip.ParseAddr(req.Device.IP); err == nil {
+			signals.ip = ip
+		} else if ip, err := netip.ParseAddr(req.Device.IPv6); err == nil {
+			signals.ip = ip
+		}
+	}
+	if req.Site != nil {
+		signals.domain = req.Site.Domain
+		if signals.domain == "" && req.Site.Publisher != nil {
+			signals.domain = req.Site.Publisher.Domain
+		}
+	}
+	if req.App != nil {
+		signals.bundle = req.App.Bundle
+	}
+	return signals
+}
+
+func stripBidders(req *openrtb_ext.RequestWrapper, bidders []string) error {
+	for _, imp := range req.GetImp() {
+		impExt, err := imp.GetImpExt()
+		if err != nil {
+			return err
+		}
+		prebid := impExt.GetPrebid()
+		if prebid == nil {
+			continue
+		}
+
+		changed := false
+		for bidder := range prebid.Bidder {
+			if sliceutil.ContainsStringIgnoreCase(bidders, bidder) {
+				delete(prebid.Bidder, bidder)
+				changed = true
+			}
+		}
+		if changed {
+			impExt.SetPrebid(prebid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package ivtfiltering
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// loadedList returns a list matching the given contents, as if they were read from its file.
+func loadedList(t *testing.T, listCfg listConfig, m matcher, contents string) scoredList {
+	require.NoError(t, m.load([]byte(contents)))
+	return scoredList{listConfig: listCfg, matcher: m}
+}
+
+func TestBuilder(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeTestFile(t, dir, "valid.txt", "10.0.0.1\n")
+	invalidIP := writeTestFile(t, dir, "invalid_ip.txt", "10.0.0.300\n")
+	invalidPattern := writeTestFile(t, dir, "invalid_pattern.txt", "bot(\n")
+	invalidRules := writeTestFile(t, dir, "invalid_rules.json", "{")
+
+	testCases := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "Valid config",
+			config:      `{"ip_ranges": [{"name": "list", "path": "` + valid + `", "score": 10}]}`,
+		},
+		{
+			description:   "Missing list name",
+			config:        `{"ip_ranges": [{"path": "` + valid + `"}]}`,
+			expectedError: "list name is required",
+		},
+		{
+			description:   "Missing list path",
+			config:        `{"user_agents": [{"name": "list"}]}`,
+			expectedError: "path is required for list list",
+		},
+		{
+			description:   "Negative score",
+			config:        `{"ip_ranges": [{"name": "list", "path": "` + valid + `", "score": -1}]}`,
+			expectedError: "score must not be negative for list list",
+		},
+		{
+			description:   "Invalid ip address",
+			config:        `{"ip_ranges": [{"name": "list", "path": "` + invalidIP + `"}]}`,
+			expectedError: `failed to load list list: invalid ip address "10.0.0.300"`,
+		},
+		{
+			description:   "Invalid user agent pattern",
+			config:        `{"user_agents": [{"name": "list", "path": "` + invalidPattern + `"}]}`,
+			expectedError: `failed to load list list: invalid user agent pattern "bot("`,
+		},
+		{
+			description:   "Invalid mismatch rules",
+			config:        `{"mismatches": [{"name": "list", "path": "` + invalidRules + `"}]}`,
+			expectedError: "failed to load list list: invalid mismatch rules",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(json.RawMessage(test.config), moduledeps.ModuleDeps{})
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	accountConfig := json.RawMessage(`{"reject_threshold": 100, "strip_threshold": 50, "strip_bidders": ["appnexus"]}`)
+	imps := func() []openrtb2.Imp {
+		return []openrtb2.Imp{{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1},"rubicon":{}}}}`)}}
+	}
+
+	testCases := []struct {
+		description        string
+		accountConfig      json.RawMessage
+		bidRequest         *openrtb2.BidRequest
+		expectedImps       []openrtb2.Imp
+		expectedHookResult hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]
+		expectedError      string
+	}{
+		{
+			description:   "Clean traffic allowed",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "1.1.1.1", UA: "Mozilla/5.0"}},
+			expectedImps:  imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				AnalyticsTags: newAllowedTags(),
+			},
+		},
+		{
+			description:   "Bot traffic from data center rejected with the reason of the highest scoring signal",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "10.1.2.3", UA: "Googlebot/2.1"}},
+			expectedImps:  imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				Reject:        true,
+				NbrCode:       int(openrtb3.NoBidCrawler),
+				AnalyticsTags: newTestTags(hookanalytics.ResultStatusBlock, 100, []string{"datacenter", "bot_user_agent"}, ""),
+			},
+		},
+		{
+			description:   "Known bad ip rejected with configured reason",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "192.168.1.1"}},
+			expectedImps:  imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				Reject:        true,
+				NbrCode:       int(openrtb3.NoBidNonHuman),
+				AnalyticsTags: newTestTags(hookanalytics.ResultStatusBlock, 100, []string{"known_bad_ip"}, ""),
+			},
+		},
+		{
+			description:   "Data center ipv6 traffic strips bidders",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IPv6: "2001:db8::1"}},
+			expectedImps:  []openrtb2.Imp{{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"rubicon":{}}}}`)}},
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				AnalyticsTags: newTestTags(hookanalytics.ResultStatusBlock, 60, []string{"datacenter"}, "appnexus"),
+			},
+		},
+		{
+			description:   "Domain mismatch flagged below thresholds",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "172.16.5.5"}, Site: &openrtb2.Site{Domain: "other.com"}},
+			expectedImps:  imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				AnalyticsTags: newTestTags(hookanalytics.ResultStatusAllow, 30, []string{"ip_mismatch"}, ""),
+			},
+		},
+		{
+			description:   "Matching bundle allowed",
+			accountConfig: accountConfig,
+			bidRequest:    &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "172.16.5.5"}, App: &openrtb2.App{Bundle: "com.publisher.app"}},
+			expectedImps:  imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				AnalyticsTags: newAllowedTags(),
+			},
+		},
+		{
+			description:  "Flagged traffic allowed without account thresholds",
+			bidRequest:   &openrtb2.BidRequest{Imp: imps(), Device: &openrtb2.Device{IP: "192.168.1.1"}},
+			expectedImps: imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				AnalyticsTags: newTestTags(hookanalytics.ResultStatusAllow, 100, []string{"known_bad_ip"}, ""),
+			},
+		},
+		{
+			description:        "Invalid account config",
+			accountConfig:      json.RawMessage(`{"reject_threshold": "high"}`),
+			bidRequest:         &openrtb2.BidRequest{Imp: imps()},
+			expectedImps:       imps(),
+			expectedHookResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+			expectedError:      "failed to parse account config",
+		},
+	}
+
+	module := Module{lists: []scoredList{
+		loadedList(t, listConfig{Name: "datacenter", Score: 60, NBR: openrtb3.NoBidProxy}, &ipRangeList{}, "# cloud providers\n10.0.0.0/8\n2001:db8::/32\n"),
+		loadedList(t, listConfig{Name: "known_bad_ip", Score: 100, NBR: openrtb3.NoBidNonHuman}, &ipRangeList{}, "192.168.1.1\n"),
+		loadedList(t, listConfig{Name: "bot_user_agent", Score: 70, NBR: openrtb3.NoBidCrawler}, &userAgentList{}, "bot\nheadlesschrome\n"),
+		loadedList(t, listConfig{Name: "ip_mismatch", Score: 30, NBR: openrtb3.NoBidNonHuman}, &mismatchList{}, `[{"ip_ranges": ["172.16.0.0/12"], "domains": ["publisher.com"], "bundles": ["com.publisher.app"]}]`),
+	}}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.bidRequest}}
+
+			hookResult, err := module.HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig, Endpoint: hookexecution.EndpointAuction},
+				payload,
+			)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError, "Invalid hook execution error.")
+			} else {
+				assert.NoError(t, err, "Invalid hook execution error.")
+			}
+
+			// test mutations separately
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			require.NoError(t, payload.Request.RebuildRequest())
+			assert.Equal(t, test.expectedImps, payload.Request.Imp, "Invalid imps after executing ProcessedAuctionHook.")
+
+			// reset ChangeSet not to break hookResult assertion, we validated ChangeSet separately
+			hookResult.ChangeSet = hookstage.ChangeSet[hookstage.ProcessedAuctionRequestPayload]{}
+			assert.Equal(t, test.expectedHookResult, hookResult, "Invalid hook execution result.")
+		})
+	}
+}
+
+func TestListReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "bots.txt", "bot\n")
+
+	result, err := Builder(json.RawMessage(`{"refresh_interval_seconds": 1, "user_agents": [{"name": "bots", "path": "`+path+`", "score": 10}]}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module := result.(Module)
+
+	score, _ := module.evaluate(requestSignals{userAgent: "crawler"})
+	assert.Equal(t, 0, score)
+
+	writeTestFile(t, dir, "bots.txt", "bot\ncrawler\n")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	assert.Eventually(t, func() bool {
+		score, _ := module.evaluate(requestSignals{userAgent: "crawler"})
+		return score == 10
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func newTestTags(status hookanalytics.ResultStatus, score int, signals []string, bidder string) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   ivtFilteringActivity,
+			Status: hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{{
+				Status:    status,
+				Values:    map[string]interface{}{scoreAnalyticKey: score, signalsAnalyticKey: signals},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: bidder},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,89 @@
+package responsecache
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+)
+
+const responseCacheActivity = "response_cache"
+
+const (
+	cacheHitAnalyticKey = "cache_hit"
+	storedAnalyticKey   = "stored"
+)
+
+// newLookupTags reports for every bidder whether its bids were served from the cache.
+func newLookupTags(hits []cachedBid) hookanalytics.Analytics {
+	if len(hits) == 0 {
+		return newTags(hookanalytics.Result{
+			Status: hookanalytics.ResultStatusAllow,
+			Values: map[string]interface{}{cacheHitAnalyticKey: false},
+		})
+	}
+
+	impIDs := make(map[string][]string)
+	for _, hit := range hits {
+		impIDs[hit.bidder.String()] = append(impIDs[hit.bidder.String()], hit.bid.Bid.ImpID)
+	}
+
+	results := make([]hookanalytics.Result, 0, len(impIDs))
+	for _, bidder := range sortedKeys(impIDs) {
+		results = append(results, hookanalytics.Result{
+			Status:    hookanalytics.ResultStatusModify,
+			Values:    map[string]interface{}{cacheHitAnalyticKey: true},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: impIDs[bidder]},
+		})
+	}
+	return newTags(results...)
+}
+
+// newStoreTags reports the bids stored for subsequent requests.
+func newStoreTags(stored []cachedBid) hookanalytics.Analytics {
+	bidIDs := make(map[string][]string)
+	for _, bid := range stored {
+		bidIDs[bid.bidder.String()] = append(bidIDs[bid.bidder.String()], bid.bid.Bid.ID)
+	}
+
+	results := make([]hookanalytics.Result, 0, len(bidIDs))
+	for _, bidder := range sortedKeys(bidIDs) {
+		results = append(results, hookanalytics.Result{
+			Status:    hookanalytics.ResultStatusAllow,
+			Values:    map[string]interface{}{storedAnalyticKey: true},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, BidIds: bidIDs[bidder]},
+		})
+	}
+	return newTags(results...)
+}
+
+// newHitTags reports the bids served from the cache which made it to the auction response.
+func newHitTags(bidIDs map[string][]string) hookanalytics.Analytics {
+	results := make([]hookanalytics.Result, 0, len(bidIDs))
+	for _, seat := range sortedKeys(bidIDs) {
+		results = append(results, hookanalytics.Result{
+			Status:    hookanalytics.ResultStatusModify,
+			Values:    map[string]interface{}{cacheHitAnalyticKey: true},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: seat, BidIds: bidIDs[seat]},
+		})
+	}
+	return newTags(results...)
+}
+
+func newTags(results ...hookanalytics.Result) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:    responseCacheActivity,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: results,
+		}},
+	}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
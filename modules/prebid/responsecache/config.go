@@ -0,0 +1,95 @@
+package responsecache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	fieldImpSizes  = "imp_sizes"
+	fieldPlacement = "placement"
+	fieldUser      = "user"
+	fieldDevice    = "device"
+)
+
+var supportedFields = []string{fieldImpSizes, fieldPlacement, fieldUser, fieldDevice}
+
+const (
+	defaultMaxEntries        = 10000
+	defaultDefaultTTLSeconds = 30
+	defaultMaxTTLSeconds     = 300
+)
+
+// config sizes the bid cache, bounds the time-to-live of the cached bids and selects the request fields
+// identifying identical requests.
+type config struct {
+	// MaxEntries is the maximum number of request fingerprints kept in the cache,
+	// the least recently used fingerprints are evicted first.
+	MaxEntries int `json:"max_entries"`
+	// DefaultTTLSeconds is the time-to-live of bids which do not specify bid.exp.
+	DefaultTTLSeconds int `json:"default_ttl_seconds"`
+	// MaxTTLSeconds caps the time-to-live of every cached bid.
+	MaxTTLSeconds int `json:"max_ttl_seconds"`
+	// FingerprintFields lists the request fields the fingerprint is built from. All supported fields are used if empty.
+	FingerprintFields []string `json:"fingerprint_fields"`
+}
+
+// accountConfig overrides the request fields identifying identical requests of an account.
+type accountConfig struct {
+	// FingerprintFields lists the request fields the fingerprint is built from for the account.
+	FingerprintFields []string `json:"fingerprint_fields"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{
+		MaxEntries:        defaultMaxEntries,
+		DefaultTTLSeconds: defaultDefaultTTLSeconds,
+		MaxTTLSeconds:     defaultMaxTTLSeconds,
+	}
+	if len(data) != 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.MaxEntries <= 0 {
+		return cfg, errors.New("max_entries must be positive")
+	}
+	if cfg.DefaultTTLSeconds <= 0 || cfg.MaxTTLSeconds <= 0 {
+		return cfg, errors.New("default_ttl_seconds and max_ttl_seconds must be positive")
+	}
+
+	if len(cfg.FingerprintFields) == 0 {
+		cfg.FingerprintFields = supportedFields
+	}
+
+	return cfg, validateFields(cfg.FingerprintFields)
+}
+
+func newAccountConfig(data json.RawMessage, defaultFields []string) (accountConfig, error) {
+	var cfg accountConfig
+	if len(data) != 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse account config: %s", err)
+		}
+	}
+
+	if len(cfg.FingerprintFields) == 0 {
+		cfg.FingerprintFields = defaultFields
+	}
+
+	return cfg, validateFields(cfg.FingerprintFields)
+}
+
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(supportedFields, field) {
+			return fmt.Errorf("unsupported fingerprint field: %s", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package responsecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"slices"
+	"strconv"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// fingerprint returns a digest of the request fields identifying repeated requests.
+// The account is always part of the fingerprint so bids are never served across accounts.
+// The number of impressions and the currencies are always part of the fingerprint
+// as cached bids are matched to the impressions by their position.
+func fingerprint(req *openrtb2.BidRequest, accountID string, fields []string) string {
+	h := sha256.New()
+	write(h, "account", accountID)
+	write(h, "imps", strconv.Itoa(len(req.Imp)))
+	write(h, "cur")
+	write(h, req.Cur...)
+
+	if slices.Contains(fields, fieldImpSizes) {
+		for _, imp := range req.Imp {
+			writeImpSizes(h, imp)
+		}
+	}
+
+	if slices.Contains(fields, fieldPlacement) {
+		for _, imp := range req.Imp {
+			write(h, "tagid", imp.TagID)
+		}
+		if req.Site != nil {
+			write(h, "site", req.Site.Domain, req.Site.Page)
+		}
+		if req.App != nil {
+			write(h, "app", req.App.Bundle)
+		}
+	}
+
+	if slices.Contains(fields, fieldUser) && req.User != nil {
+		write(h, "user", req.User.ID, req.User.BuyerUID)
+		for _, eid := range req.User.EIDs {
+			write(h, "eid", eid.Source)
+			for _, uid := range eid.UIDs {
+				write(h, uid.ID)
+			}
+		}
+	}
+
+	if slices.Contains(fields, fieldDevice) && req.Device != nil {
+		write(h, "device", req.Device.UA, req.Device.IP, req.Device.IPv6, req.Device.IFA, req.Device.OS, req.Device.Make, req.Device.Model, strconv.Itoa(int(req.Device.DeviceType)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeImpSizes(h hash.Hash, imp openrtb2.Imp) {
+	write(h, "imp")
+	if imp.Banner != nil {
+		write(h, "banner", size(imp.Banner.W, imp.Banner.H))
+		for _, format := range imp.Banner.Format {
+			write(h, size(&format.W, &format.H))
+		}
+	}
+	if imp.Video != nil {
+		write(h, "video", size(imp.Video.W, imp.Video.H))
+	}
+	if imp.Audio != nil {
+		write(h, "audio")
+	}
+	if imp.Native != nil {
+		write(h, "native")
+	}
+}
+
+func size(w, h *int64) string {
+	var width, height int64
+	if w != nil {
+		width = *w
+	}
+	if h != nil {
+		height = *h
+	}
+	return strconv.FormatInt(width, 10) + "x" + strconv.FormatInt(height, 10)
+}
+
+// write adds the values to the digest separated to avoid ambiguous concatenations.
+func write(h hash.Hash, values ...string) {
+	for _, value := range values {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+}
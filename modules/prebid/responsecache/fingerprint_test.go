@@ -0,0 +1,103 @@
+package responsecache
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	w, h := int64(300), int64(250)
+	baseRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			ID:     "request1",
+			Imp:    []openrtb2.Imp{{ID: "imp1", TagID: "top", Banner: &openrtb2.Banner{W: &w, H: &h}}},
+			App:    &openrtb2.App{Bundle: "com.app"},
+			Device: &openrtb2.Device{IFA: "ifa", UA: "ua"},
+			User:   &openrtb2.User{ID: "user1"},
+			Cur:    []string{"USD"},
+		}
+	}
+	base := fingerprint(baseRequest(), "account1", supportedFields)
+
+	testCases := []struct {
+		description   string
+		modify        func(req *openrtb2.BidRequest)
+		accountID     string
+		fields        []string
+		expectedEqual bool
+	}{
+		{
+			description:   "Request and imp IDs are ignored",
+			modify:        func(req *openrtb2.BidRequest) { req.ID = "request2"; req.Imp[0].ID = "imp2" },
+			accountID:     "account1",
+			fields:        supportedFields,
+			expectedEqual: true,
+		},
+		{
+			description: "Different sizes",
+			modify:      func(req *openrtb2.BidRequest) { req.Imp[0].Banner.Format = []openrtb2.Format{{W: 320, H: 50}} },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different placement",
+			modify:      func(req *openrtb2.BidRequest) { req.Imp[0].TagID = "bottom" },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different user",
+			modify:      func(req *openrtb2.BidRequest) { req.User.ID = "user2" },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different device",
+			modify:      func(req *openrtb2.BidRequest) { req.Device.IFA = "ifa2" },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different account",
+			modify:      func(req *openrtb2.BidRequest) {},
+			accountID:   "account2",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different currency",
+			modify:      func(req *openrtb2.BidRequest) { req.Cur = []string{"EUR"} },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+		{
+			description: "Different number of imps",
+			modify:      func(req *openrtb2.BidRequest) { req.Imp = append(req.Imp, req.Imp[0]) },
+			accountID:   "account1",
+			fields:      supportedFields,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := baseRequest()
+			test.modify(req)
+			assert.Equal(t, test.expectedEqual, base == fingerprint(req, test.accountID, test.fields))
+		})
+	}
+}
+
+func TestFingerprintIgnoredFields(t *testing.T) {
+	fields := []string{fieldImpSizes}
+	first := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{TagID: "top"}}, User: &openrtb2.User{ID: "user1"}, Device: &openrtb2.Device{IFA: "ifa1"}}
+	second := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{TagID: "bottom"}}, User: &openrtb2.User{ID: "user2"}, Device: &openrtb2.Device{IFA: "ifa2"}}
+
+	assert.Equal(t, fingerprint(first, "account1", fields), fingerprint(second, "account1", fields))
+}
+
+func TestFingerprintAlwaysIncludesAccount(t *testing.T) {
+	req := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{TagID: "top"}}}
+
+	assert.NotEqual(t, fingerprint(req, "account1", nil), fingerprint(req, "account2", nil))
+}
@@ -0,0 +1,391 @@
+package responsecache
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+const (
+	fingerprintContextKey = "fingerprint"
+	impIDsContextKey      = "imp_ids"
+	hitsContextKey        = "hits"
+)
+
+func Builder(data json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedBidders := make(map[string]bool)
+	vastModifyingBidders := make(map[string]bool)
+	for bidder, info := range deps.BidderInfos {
+		if info.ResponseCachingAllowed {
+			allowedBidders[bidder] = true
+		}
+		if info.ModifyingVastXmlAllowed {
+			vastModifyingBidders[bidder] = true
+		}
+	}
+
+	return Module{
+		cfg:                  cfg,
+		allowedBidders:       allowedBidders,
+		vastModifyingBidders: vastModifyingBidders,
+		store:                newStore(cfg.MaxEntries),
+		now:                  time.Now,
+	}, nil
+}
+
+type Module struct {
+	cfg            config
+	allowedBidders map[string]bool
+	// vastModifyingBidders are the bidders whose VAST markup gets the event tracking of the auction injected.
+	vastModifyingBidders map[string]bool
+	store                *store
+	now                  func() time.Time
+}
+
+// HandleProcessedAuctionHook looks up unused bids of previous auctions with the same request fingerprint.
+// Bidders with cached bids for an impression are not called for that impression, their cached bids
+// are supplied to the exchange instead and added to the bid responses.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	accountCfg, err := newAccountConfig(miCtx.AccountConfig, m.cfg.FingerprintFields)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	req := payload.Request
+	if req == nil || req.BidRequest == nil {
+		return result, nil
+	}
+
+	cacheable, err := isCacheable(req)
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to get request ext: %s", err)
+	}
+	if !cacheable {
+		return result, nil
+	}
+
+	impBidders, err := getImpBidders(req)
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to get imp ext: %s", err)
+	}
+
+	fp := fingerprint(req.BidRequest, miCtx.AccountID, accountCfg.FingerprintFields)
+	now := m.now()
+	hits := m.store.take(fp, now, func(bidder openrtb_ext.BidderName, impIndex int) bool {
+		return impIndex < len(impBidders) && containsBidder(impBidders[impIndex], bidder)
+	})
+
+	impIDs := make([]string, 0, len(req.Imp))
+	for _, imp := range req.Imp {
+		impIDs = append(impIDs, imp.ID)
+	}
+
+	// cached bids are matched to the impressions by position as identical requests may use different impression IDs
+	for i := range hits {
+		hits[i].bid = copyBid(hits[i].bid)
+		hits[i].bid.Bid.ImpID = impIDs[hits[i].impIndex]
+		hits[i].bid.Bid.Exp = int64(math.Ceil(hits[i].expiresAt.Sub(now).Seconds()))
+	}
+
+	result.ModuleContext = hookstage.ModuleContext{
+		fingerprintContextKey: fp,
+		impIDsContextKey:      impIDs,
+		hitsContextKey:        hits,
+	}
+	result.AnalyticsTags = newLookupTags(hits)
+
+	if len(hits) > 0 {
+		result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+			return payload, removeCachedBidders(payload.Request, hits)
+		}, hookstage.MutationDelete, "bidRequest", "imp.ext.prebid.bidder")
+		result.SeatBids = makeSeatBids(hits)
+	}
+
+	return result, nil
+}
+
+// HandleAllProcessedBidResponsesHook stores the bids of the opted-in bidders which did not win the auction
+// for their impression, including the cached bids served again. The bid IDs, floors and events set by the
+// auction are removed as the exchange sets them again for the auction the bid is served to. Video bids
+// of the bidders whose VAST markup carries the event tracking of the auction are not stored.
+func (m Module) HandleAllProcessedBidResponsesHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.AllProcessedBidResponsesPayload,
+) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	result := hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{}
+
+	fp, ok := miCtx.ModuleContext[fingerprintContextKey].(string)
+	if !ok {
+		return result, nil
+	}
+	impIDs, _ := miCtx.ModuleContext[impIDsContextKey].([]string)
+	hits, _ := miCtx.ModuleContext[hitsContextKey].([]cachedBid)
+
+	impIndexes := make(map[string]int, len(impIDs))
+	for i, impID := range impIDs {
+		impIndexes[impID] = i
+	}
+
+	served := make(map[*entities.PbsOrtbBid]bool, len(hits))
+	for _, hit := range hits {
+		served[hit.bid] = true
+	}
+
+	// the cached bids served were added to the bidder responses by the exchange
+	winners := make(map[string]*entities.PbsOrtbBid, len(impIDs))
+	for _, seatBid := range payload.Responses {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			if winner, ok := winners[bid.Bid.ImpID]; !ok || bid.Bid.Price > winner.Bid.Price {
+				winners[bid.Bid.ImpID] = bid
+			}
+		}
+	}
+
+	now := m.now()
+	var unused []cachedBid
+	for bidder, seatBid := range payload.Responses {
+		if seatBid == nil || !m.isAllowed(bidder) {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil || served[bid] || winners[bid.Bid.ImpID] == bid || m.hasEventMarkup(bidder, bid) {
+				continue
+			}
+			impIndex, ok := impIndexes[bid.Bid.ImpID]
+			if !ok {
+				continue
+			}
+			unused = append(unused, cachedBid{
+				bidder:    bidder,
+				impIndex:  impIndex,
+				currency:  seatBid.Currency,
+				bid:       unprocessedBid(bid),
+				expiresAt: now.Add(m.ttl(bid)),
+			})
+		}
+	}
+	for _, hit := range hits {
+		if winners[hit.bid.Bid.ImpID] != hit.bid && !m.hasEventMarkup(hit.bidder, hit.bid) {
+			hit.bid = unprocessedBid(hit.bid)
+			unused = append(unused, hit)
+		}
+	}
+	m.store.put(fp, now, unused)
+	result.AnalyticsTags = newStoreTags(unused)
+
+	return result, nil
+}
+
+// HandleAuctionResponseHook marks the bids served from the cache in the response extension.
+func (m Module) HandleAuctionResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.AuctionResponsePayload,
+) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.AuctionResponsePayload]{}
+
+	hits, _ := miCtx.ModuleContext[hitsContextKey].([]cachedBid)
+	if len(hits) == 0 || payload.BidResponse == nil {
+		return result, nil
+	}
+
+	cachedBidIDs := make(map[string]map[string]bool)
+	for _, hit := range hits {
+		seat := hit.bidder.String()
+		if cachedBidIDs[seat] == nil {
+			cachedBidIDs[seat] = make(map[string]bool)
+		}
+		cachedBidIDs[seat][hit.bid.Bid.ID] = true
+	}
+
+	servedBidIDs := make(map[string][]string)
+	for _, seatBid := range payload.BidResponse.SeatBid {
+		for _, bid := range seatBid.Bid {
+			if cachedBidIDs[seatBid.Seat][bid.ID] {
+				servedBidIDs[seatBid.Seat] = append(servedBidIDs[seatBid.Seat], bid.ID)
+			}
+		}
+	}
+	if len(servedBidIDs) == 0 {
+		return result, nil
+	}
+
+	ext, err := jsonutil.Marshal(responseExt{ResponseCache: responseCacheExt{Hits: servedBidIDs}})
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to marshal response ext: %s", err)
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.AuctionResponsePayload) (hookstage.AuctionResponsePayload, error) {
+		original := payload.BidResponse.Ext
+		if len(original) == 0 {
+			original = json.RawMessage(`{}`)
+		}
+		merged, err := jsonpatch.MergePatch(original, ext)
+		if err != nil {
+			return payload, err
+		}
+		payload.BidResponse.Ext = merged
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidResponse", "ext.responsecache")
+	result.AnalyticsTags = newHitTags(servedBidIDs)
+
+	return result, nil
+}
+
+type responseExt struct {
+	ResponseCache responseCacheExt `json:"responsecache"`
+}
+
+type responseCacheExt struct {
+	// Hits lists the IDs of the bids served from the cache by seat.
+	Hits map[string][]string `json:"hits"`
+}
+
+func (m Module) isAllowed(bidder openrtb_ext.BidderName) bool {
+	normalized, ok := openrtb_ext.NormalizeBidderName(bidder.String())
+	return ok && m.allowedBidders[normalized.String()]
+}
+
+// hasEventMarkup returns true if the VAST markup of the bid may include the event tracking of the auction.
+func (m Module) hasEventMarkup(bidder openrtb_ext.BidderName, bid *entities.PbsOrtbBid) bool {
+	if bid.BidType != openrtb_ext.BidTypeVideo {
+		return false
+	}
+	normalized, ok := openrtb_ext.NormalizeBidderName(bidder.String())
+	return ok && m.vastModifyingBidders[normalized.String()]
+}
+
+func (m Module) ttl(bid *entities.PbsOrtbBid) time.Duration {
+	ttl := int64(m.cfg.DefaultTTLSeconds)
+	if bid.Bid.Exp > 0 {
+		ttl = bid.Bid.Exp
+	}
+	if ttl > int64(m.cfg.MaxTTLSeconds) {
+		ttl = int64(m.cfg.MaxTTLSeconds)
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// isCacheable returns false for requests with brand category targeting as the category mapping
+// of the original auction is not available to subsequent auctions.
+func isCacheable(req *openrtb_ext.RequestWrapper) (bool, error) {
+	reqExt, err := req.GetRequestExt()
+	if err != nil {
+		return false, err
+	}
+	prebid := reqExt.GetPrebid()
+	return prebid == nil || prebid.Targeting == nil || prebid.Targeting.IncludeBrandCategory == nil, nil
+}
+
+// getImpBidders returns the names of the bidders requested for each impression.
+func getImpBidders(req *openrtb_ext.RequestWrapper) ([][]string, error) {
+	impBidders := make([][]string, 0, len(req.Imp))
+	for _, imp := range req.GetImp() {
+		impExt, err := imp.GetImpExt()
+		if err != nil {
+			return nil, err
+		}
+
+		var bidders []string
+		if prebid := impExt.GetPrebid(); prebid != nil {
+			for bidder := range prebid.Bidder {
+				bidders = append(bidders, bidder)
+			}
+		}
+		impBidders = append(impBidders, bidders)
+	}
+	return impBidders, nil
+}
+
+func containsBidder(bidders []string, bidder openrtb_ext.BidderName) bool {
+	for _, b := range bidders {
+		if strings.EqualFold(b, bidder.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeCachedBidders(req *openrtb_ext.RequestWrapper, hits []cachedBid) error {
+	imps := req.GetImp()
+	for _, hit := range hits {
+		impExt, err := imps[hit.impIndex].GetImpExt()
+		if err != nil {
+			return err
+		}
+		prebid := impExt.GetPrebid()
+		if prebid == nil {
+			continue
+		}
+		for bidder := range prebid.Bidder {
+			if strings.EqualFold(bidder, hit.bidder.String()) {
+				delete(prebid.Bidder, bidder)
+				impExt.SetPrebid(prebid)
+			}
+		}
+	}
+	return nil
+}
+
+// makeSeatBids groups the cached bids by seat to be supplied to the exchange in place of calling the bidders.
+func makeSeatBids(hits []cachedBid) []entities.PbsOrtbSeatBid {
+	var seatBids []entities.PbsOrtbSeatBid
+	seatIndexes := make(map[openrtb_ext.BidderName]int)
+	for _, hit := range hits {
+		i, ok := seatIndexes[hit.bidder]
+		if !ok {
+			i = len(seatBids)
+			seatIndexes[hit.bidder] = i
+			seatBids = append(seatBids, entities.PbsOrtbSeatBid{Seat: hit.bidder.String(), Currency: hit.currency})
+		}
+		seatBids[i].Bids = append(seatBids[i].Bids, hit.bid)
+	}
+	return seatBids
+}
+
+// unprocessedBid copies the bid without the values set by the exchange for the auction.
+func unprocessedBid(bid *entities.PbsOrtbBid) *entities.PbsOrtbBid {
+	bidCopy := copyBid(bid)
+	bidCopy.GeneratedBidID = ""
+	bidCopy.BidEvents = nil
+	bidCopy.BidFloors = nil
+	return bidCopy
+}
+
+// copyBid prevents changes made to the bid by the rest of the auction from affecting cached bids.
+func copyBid(bid *entities.PbsOrtbBid) *entities.PbsOrtbBid {
+	bidCopy := *bid
+	if bid.Bid != nil {
+		ortbBid := *bid.Bid
+		bidCopy.Bid = &ortbBid
+	}
+	return &bidCopy
+}
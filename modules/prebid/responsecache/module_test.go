@@ -0,0 +1,365 @@
+package responsecache
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	pbsconfig "github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDeps = moduledeps.ModuleDeps{BidderInfos: pbsconfig.BidderInfos{
+	"appnexus": {ResponseCachingAllowed: true},
+	"rubicon":  {},
+}}
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "Valid config",
+			config:      json.RawMessage(`{"max_entries": 100, "fingerprint_fields": ["imp_sizes", "placement"]}`),
+		},
+		{
+			description: "Empty config",
+			config:      nil,
+		},
+		{
+			description:   "Invalid max entries",
+			config:        json.RawMessage(`{"max_entries": -1}`),
+			expectedError: "max_entries must be positive",
+		},
+		{
+			description:   "Invalid ttl",
+			config:        json.RawMessage(`{"default_ttl_seconds": 0, "max_ttl_seconds": 0}`),
+			expectedError: "default_ttl_seconds and max_ttl_seconds must be positive",
+		},
+		{
+			description:   "Unsupported field",
+			config:        json.RawMessage(`{"fingerprint_fields": ["site"]}`),
+			expectedError: "unsupported fingerprint field: site",
+		},
+		{
+			description:   "Account field always used",
+			config:        json.RawMessage(`{"fingerprint_fields": ["account"]}`),
+			expectedError: "unsupported fingerprint field: account",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(test.config, testDeps)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestResponseCaching(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	module := Module{
+		cfg:            config{MaxEntries: 10, DefaultTTLSeconds: 60, MaxTTLSeconds: 120, FingerprintFields: supportedFields},
+		allowedBidders: map[string]bool{"appnexus": true},
+		store:          newStore(10),
+		now:            func() time.Time { return now },
+	}
+	miCtx := hookstage.ModuleInvocationContext{AccountID: "account1", Endpoint: hookexecution.EndpointAuction}
+
+	// first auction, appnexus loses imp1 and wins imp2
+	request := newTestRequest("imp1", "imp2")
+	lookupResult := processRequest(t, module, miCtx, request)
+	assert.Equal(t, newTags(hookanalytics.Result{
+		Status: hookanalytics.ResultStatusAllow,
+		Values: map[string]interface{}{cacheHitAnalyticKey: false},
+	}), lookupResult.AnalyticsTags, "Cache miss expected for first request.")
+	assert.Equal(t, []string{"appnexus", "rubicon"}, impBidderNames(t, request, 0))
+
+	miCtx.ModuleContext = lookupResult.ModuleContext
+	responses := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": newTestSeatBid("appnexus", testBid("an-1", "imp1", 1.0, 0), testBid("an-2", "imp2", 5.0, 0)),
+		"rubicon":  newTestSeatBid("rubicon", testBid("rb-1", "imp1", 2.0, 0), testBid("rb-2", "imp2", 1.0, 0)),
+	}
+	storeResult := processResponses(t, module, miCtx, responses)
+	assert.Equal(t, newTags(hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusAllow,
+		Values:    map[string]interface{}{storedAnalyticKey: true},
+		AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"an-1"}},
+	}), storeResult.AnalyticsTags, "Only the non-winning bid of the opted-in bidder must be stored.")
+
+	// identical request of another account, bids are never served across accounts
+	otherAccountCtx := hookstage.ModuleInvocationContext{AccountID: "account2", Endpoint: hookexecution.EndpointAuction}
+	otherAccountResult := processRequest(t, module, otherAccountCtx, newTestRequest("imp1", "imp2"))
+	assert.Equal(t, false, otherAccountResult.AnalyticsTags.Activities[0].Results[0].Values[cacheHitAnalyticKey], "Cache miss expected for other account.")
+	assert.Empty(t, otherAccountResult.SeatBids)
+
+	// identical request with different impression IDs, appnexus is not called for the first impression
+	now = now.Add(20 * time.Second)
+	request = newTestRequest("imp3", "imp4")
+	lookupResult = processRequest(t, module, miCtx, request)
+	assert.Equal(t, newTags(hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusModify,
+		Values:    map[string]interface{}{cacheHitAnalyticKey: true},
+		AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", ImpIds: []string{"imp3"}},
+	}), lookupResult.AnalyticsTags, "Cache hit expected for identical request.")
+	assert.Equal(t, []string{"rubicon"}, impBidderNames(t, request, 0))
+	assert.Equal(t, []string{"appnexus", "rubicon"}, impBidderNames(t, request, 1))
+
+	assert.Equal(t, []entities.PbsOrtbSeatBid{*newTestSeatBid("appnexus", testBid("an-1", "imp3", 1.0, 40))}, lookupResult.SeatBids, "Cached bid must be supplied with the remaining ttl.")
+
+	miCtx.ModuleContext = lookupResult.ModuleContext
+	responses = map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": newTestSeatBid("appnexus", testBid("an-3", "imp4", 5.0, 0)),
+		"rubicon":  newTestSeatBid("rubicon", testBid("rb-3", "imp3", 0.5, 0)),
+	}
+	supplySeatBids(responses, lookupResult.SeatBids)
+	storeResult = processResponses(t, module, miCtx, responses)
+	assert.Empty(t, storeResult.AnalyticsTags.Activities[0].Results, "The served cached bid won its impression, nothing must be stored.")
+
+	response := &openrtb2.BidResponse{
+		SeatBid: []openrtb2.SeatBid{
+			{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "an-1", ImpID: "imp3"}, {ID: "an-3", ImpID: "imp4"}}},
+			{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "rb-3", ImpID: "imp3"}}},
+		},
+		Ext: json.RawMessage(`{"responsetimemillis":{"rubicon":5}}`),
+	}
+	responseResult := processAuctionResponse(t, module, miCtx, response)
+	assert.JSONEq(t, `{"responsetimemillis":{"rubicon":5},"responsecache":{"hits":{"appnexus":["an-1"]}}}`, string(response.Ext))
+	assert.Equal(t, newTags(hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusModify,
+		Values:    map[string]interface{}{cacheHitAnalyticKey: true},
+		AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"an-1"}},
+	}), responseResult.AnalyticsTags)
+
+	// the cached bid won the previous auction, it can't be served again
+	miCtx.ModuleContext = nil
+	lookupResult = processRequest(t, module, miCtx, newTestRequest("imp5", "imp6"))
+	assert.Equal(t, false, lookupResult.AnalyticsTags.Activities[0].Results[0].Values[cacheHitAnalyticKey])
+}
+
+func TestResponseCachingExpiration(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	module := Module{
+		cfg:            config{MaxEntries: 10, DefaultTTLSeconds: 60, MaxTTLSeconds: 120, FingerprintFields: supportedFields},
+		allowedBidders: map[string]bool{"appnexus": true},
+		store:          newStore(10),
+		now:            func() time.Time { return now },
+	}
+	miCtx := hookstage.ModuleInvocationContext{Endpoint: hookexecution.EndpointAuction}
+
+	miCtx.ModuleContext = processRequest(t, module, miCtx, newTestRequest("imp1")).ModuleContext
+	processResponses(t, module, miCtx, map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": newTestSeatBid("appnexus", testBid("an-1", "imp1", 1.0, 10), testBid("an-2", "imp1", 0.5, 1000), testBid("an-3", "imp1", 2.0, 0)),
+	})
+
+	// an-1 expired, an-2 ttl capped at the max ttl
+	now = now.Add(30 * time.Second)
+	lookupResult := processRequest(t, module, miCtx, newTestRequest("imp1"))
+	assert.Equal(t, []entities.PbsOrtbSeatBid{{
+		Seat:     "appnexus",
+		Currency: "USD",
+		Bids:     []*entities.PbsOrtbBid{testBid("an-2", "imp1", 0.5, 90)},
+	}}, lookupResult.SeatBids)
+}
+
+func TestHandleAllProcessedBidResponsesHookStoredBids(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description  string
+		bid          *entities.PbsOrtbBid
+		expectedBids []cachedBid
+	}{
+		{
+			description: "Values of the auction removed",
+			bid: &entities.PbsOrtbBid{
+				Bid:            &openrtb2.Bid{ID: "an-1", ImpID: "imp1", Price: 1},
+				BidType:        openrtb_ext.BidTypeBanner,
+				GeneratedBidID: "generated-id",
+				BidEvents:      &openrtb_ext.ExtBidPrebidEvents{Win: "win-url", Imp: "imp-url"},
+				BidFloors:      &openrtb_ext.ExtBidPrebidFloors{FloorValue: 0.5, FloorCurrency: "USD"},
+			},
+			expectedBids: []cachedBid{{
+				bidder:    "appnexus",
+				currency:  "USD",
+				bid:       &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "an-1", ImpID: "imp1", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+				expiresAt: now.Add(60 * time.Second),
+			}},
+		},
+		{
+			description: "Video bid with event tracking in the VAST markup not stored",
+			bid: &entities.PbsOrtbBid{
+				Bid:     &openrtb2.Bid{ID: "an-1", ImpID: "imp1", Price: 1, AdM: "<VAST></VAST>"},
+				BidType: openrtb_ext.BidTypeVideo,
+			},
+			expectedBids: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module := Module{
+				cfg:                  config{MaxEntries: 10, DefaultTTLSeconds: 60, MaxTTLSeconds: 120, FingerprintFields: supportedFields},
+				allowedBidders:       map[string]bool{"appnexus": true},
+				vastModifyingBidders: map[string]bool{"appnexus": true},
+				store:                newStore(10),
+				now:                  func() time.Time { return now },
+			}
+			miCtx := hookstage.ModuleInvocationContext{ModuleContext: hookstage.ModuleContext{
+				fingerprintContextKey: "fingerprint",
+				impIDsContextKey:      []string{"imp1"},
+			}}
+
+			processResponses(t, module, miCtx, map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": newTestSeatBid("appnexus", testBid("an-2", "imp1", 5.0, 0), test.bid),
+			})
+
+			storedBids := module.store.take("fingerprint", now, func(openrtb_ext.BidderName, int) bool { return true })
+			assert.Equal(t, test.expectedBids, storedBids)
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHookNotCached(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	module := Module{
+		cfg:            config{MaxEntries: 10, DefaultTTLSeconds: 60, MaxTTLSeconds: 120, FingerprintFields: supportedFields},
+		allowedBidders: map[string]bool{"appnexus": true},
+		store:          newStore(10),
+		now:            func() time.Time { return now },
+	}
+
+	testCases := []struct {
+		description   string
+		accountConfig json.RawMessage
+		request       *openrtb2.BidRequest
+		expectedError error
+	}{
+		{
+			description: "Brand category targeting",
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "imp1"}},
+				Ext: json.RawMessage(`{"prebid":{"targeting":{"includebrandcategory":{"primaryadserver":1}}}}`),
+			},
+		},
+		{
+			description:   "Invalid account config",
+			accountConfig: json.RawMessage(`{"fingerprint_fields": ["site"]}`),
+			request:       &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}},
+			expectedError: hookexecution.NewFailure("unsupported fingerprint field: site"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := module.HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig},
+				hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.request}},
+			)
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}, result)
+		})
+	}
+}
+
+func processRequest(t *testing.T, module Module, miCtx hookstage.ModuleInvocationContext, request *openrtb_ext.RequestWrapper) hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload] {
+	payload := hookstage.ProcessedAuctionRequestPayload{Request: request}
+	result, err := module.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+	require.NoError(t, err)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	return result
+}
+
+func processResponses(t *testing.T, module Module, miCtx hookstage.ModuleInvocationContext, responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload] {
+	payload := hookstage.AllProcessedBidResponsesPayload{Responses: responses}
+	result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), miCtx, payload)
+	require.NoError(t, err)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	return result
+}
+
+// supplySeatBids adds the bids supplied by the hook to the bidder responses as the exchange does.
+func supplySeatBids(responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatBids []entities.PbsOrtbSeatBid) {
+	for _, seatBid := range seatBids {
+		bidder := openrtb_ext.BidderName(seatBid.Seat)
+		if existing, ok := responses[bidder]; ok {
+			existing.Bids = append(existing.Bids, seatBid.Bids...)
+		} else {
+			seatBidCopy := seatBid
+			responses[bidder] = &seatBidCopy
+		}
+	}
+}
+
+func processAuctionResponse(t *testing.T, module Module, miCtx hookstage.ModuleInvocationContext, response *openrtb2.BidResponse) hookstage.HookResult[hookstage.AuctionResponsePayload] {
+	payload := hookstage.AuctionResponsePayload{BidResponse: response}
+	result, err := module.HandleAuctionResponseHook(context.Background(), miCtx, payload)
+	require.NoError(t, err)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	return result
+}
+
+func newTestRequest(impIDs ...string) *openrtb_ext.RequestWrapper {
+	w, h := int64(300), int64(250)
+	request := &openrtb2.BidRequest{
+		ID:     "request",
+		App:    &openrtb2.App{Bundle: "com.app"},
+		Device: &openrtb2.Device{IFA: "ifa"},
+		Cur:    []string{"USD"},
+	}
+	for _, impID := range impIDs {
+		request.Imp = append(request.Imp, openrtb2.Imp{
+			ID:     impID,
+			Banner: &openrtb2.Banner{W: &w, H: &h},
+			Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1},"rubicon":{"accountId":1}}}}`),
+		})
+	}
+	return &openrtb_ext.RequestWrapper{BidRequest: request}
+}
+
+func newTestSeatBid(seat string, bids ...*entities.PbsOrtbBid) *entities.PbsOrtbSeatBid {
+	return &entities.PbsOrtbSeatBid{Seat: seat, Currency: "USD", Bids: bids}
+}
+
+func testBid(id, impID string, price float64, exp int64) *entities.PbsOrtbBid {
+	return &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: id, ImpID: impID, Price: price, Exp: exp}}
+}
+
+func impBidderNames(t *testing.T, request *openrtb_ext.RequestWrapper, impIndex int) []string {
+	require.NoError(t, request.RebuildRequest())
+	impBidders, err := getImpBidders(&openrtb_ext.RequestWrapper{BidRequest: request.BidRequest})
+	require.NoError(t, err)
+
+	names := impBidders[impIndex]
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+package responsecache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// cachedBid is an unused bid kept for a subsequent request with the same fingerprint.
+type cachedBid struct {
+	bidder    openrtb_ext.BidderName
+	impIndex  int
+	currency  string
+	bid       *entities.PbsOrtbBid
+	expiresAt time.Time
+}
+
+type entry struct {
+	fingerprint string
+	bids        []cachedBid
+}
+
+// store is an in-memory LRU cache of unused bids grouped by request fingerprint.
+type store struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	mutex      sync.Mutex
+}
+
+func newStore(maxEntries int) *store {
+	return &store{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// take removes and returns the valid bids of the fingerprint accepted by the accept function.
+// Expired bids are discarded.
+func (s *store) take(fingerprint string, now time.Time, accept func(bidder openrtb_ext.BidderName, impIndex int) bool) []cachedBid {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[fingerprint]
+	if !ok {
+		return nil
+	}
+
+	var taken, kept []cachedBid
+	for _, bid := range element.Value.(*entry).bids {
+		if !bid.expiresAt.After(now) {
+			continue
+		}
+		if accept(bid.bidder, bid.impIndex) {
+			taken = append(taken, bid)
+		} else {
+			kept = append(kept, bid)
+		}
+	}
+
+	if len(kept) == 0 {
+		s.remove(element)
+	} else {
+		element.Value.(*entry).bids = kept
+		s.lru.MoveToFront(element)
+	}
+	return taken
+}
+
+// put adds the bids to the fingerprint evicting the least recently used fingerprint when the cache is full.
+func (s *store) put(fingerprint string, now time.Time, bids []cachedBid) {
+	if len(bids) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.entries[fingerprint]; ok {
+		e := element.Value.(*entry)
+		for _, bid := range e.bids {
+			if bid.expiresAt.After(now) {
+				bids = append(bids, bid)
+			}
+		}
+		e.bids = bids
+		s.lru.MoveToFront(element)
+		return
+	}
+
+	if s.lru.Len() >= s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+	s.entries[fingerprint] = s.lru.PushFront(&entry{fingerprint: fingerprint, bids: bids})
+}
+
+func (s *store) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*entry).fingerprint)
+}
@@ -0,0 +1,72 @@
+package responsecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreTake(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	acceptAll := func(openrtb_ext.BidderName, int) bool { return true }
+
+	s := newStore(10)
+	s.put("fp", now, []cachedBid{
+		testCachedBid("appnexus", 0, "bid1", now.Add(time.Minute)),
+		testCachedBid("appnexus", 1, "bid2", now.Add(time.Minute)),
+		testCachedBid("rubicon", 0, "bid3", now.Add(time.Second)),
+	})
+
+	assert.Empty(t, s.take("unknown", now, acceptAll), "Unknown fingerprint.")
+
+	taken := s.take("fp", now.Add(2*time.Second), func(bidder openrtb_ext.BidderName, impIndex int) bool {
+		return impIndex == 0
+	})
+	assert.Equal(t, []string{"bid1"}, bidIDs(taken), "Expired and not accepted bids must not be taken.")
+
+	taken = s.take("fp", now.Add(2*time.Second), acceptAll)
+	assert.Equal(t, []string{"bid2"}, bidIDs(taken), "Not accepted bids must be kept.")
+
+	assert.Empty(t, s.take("fp", now.Add(2*time.Second), acceptAll), "Taken bids must be removed.")
+	assert.Empty(t, s.entries, "Empty entries must be removed.")
+}
+
+func TestStorePut(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	acceptAll := func(openrtb_ext.BidderName, int) bool { return true }
+
+	s := newStore(2)
+	s.put("fp1", now, []cachedBid{testCachedBid("appnexus", 0, "bid1", now.Add(time.Minute))})
+	s.put("fp2", now, []cachedBid{testCachedBid("appnexus", 0, "bid2", now.Add(time.Second))})
+	s.put("fp2", now.Add(2*time.Second), []cachedBid{testCachedBid("appnexus", 0, "bid3", now.Add(time.Minute))})
+	s.put("fp1", now, nil)
+
+	// fp2 was used most recently, fp1 is evicted
+	s.put("fp3", now, []cachedBid{testCachedBid("appnexus", 0, "bid4", now.Add(time.Minute))})
+
+	assert.Empty(t, s.take("fp1", now, acceptAll), "Least recently used fingerprint must be evicted.")
+	assert.Equal(t, []string{"bid3"}, bidIDs(s.take("fp2", now.Add(2*time.Second), acceptAll)), "Expired bids must be dropped when adding bids.")
+	assert.Equal(t, []string{"bid4"}, bidIDs(s.take("fp3", now, acceptAll)))
+}
+
+func testCachedBid(bidder string, impIndex int, id string, expiresAt time.Time) cachedBid {
+	return cachedBid{
+		bidder:    openrtb_ext.BidderName(bidder),
+		impIndex:  impIndex,
+		currency:  "USD",
+		bid:       &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: id}},
+		expiresAt: expiresAt,
+	}
+}
+
+func bidIDs(bids []cachedBid) []string {
+	var ids []string
+	for _, bid := range bids {
+		ids = append(ids, bid.bid.Bid.ID)
+	}
+	return ids
+}
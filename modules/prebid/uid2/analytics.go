@@ -0,0 +1,36 @@
+package uid2
+
+import "github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+
+const tokenActivity = "uid_token"
+
+// token statuses report where the token of a request came from.
+const (
+	statusCached    = "cached"
+	statusGenerated = "generated"
+	statusRefreshed = "refreshed"
+	statusError     = "error"
+)
+
+func newTags(service, status string) hookanalytics.Analytics {
+	resultStatus := hookanalytics.ResultStatusModify
+	activityStatus := hookanalytics.ActivityStatusSuccess
+	switch status {
+	case statusOptOut:
+		resultStatus = hookanalytics.ResultStatusAllow
+	case statusError:
+		resultStatus = hookanalytics.ResultStatusError
+		activityStatus = hookanalytics.ActivityStatusError
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   tokenActivity,
+			Status: activityStatus,
+			Results: []hookanalytics.Result{{
+				Status: resultStatus,
+				Values: map[string]interface{}{"service": service, "token": status},
+			}},
+		}},
+	}
+}
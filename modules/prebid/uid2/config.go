@@ -0,0 +1,131 @@
+package uid2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	serviceUID2 = "uid2"
+	serviceEUID = "euid"
+)
+
+const (
+	defaultUID2OperatorURL  = "https://prod.uidapi.com"
+	defaultEUIDOperatorURL  = "https://prod.euid.eu"
+	defaultTimeoutMS        = 200
+	defaultMaxEntries       = 100000
+	defaultOptOutTTLSeconds = 86400
+)
+
+// config holds the UID2 and EUID operators tokens are generated with and the limits of the token cache, which
+// are shared by all the accounts.
+type config struct {
+	// UID2OperatorURL is the base URL of the UID2 operator tokens are generated and refreshed with.
+	UID2OperatorURL string `json:"uid2_operator_url"`
+	// EUIDOperatorURL is the base URL of the EUID operator tokens are generated and refreshed with.
+	EUIDOperatorURL string `json:"euid_operator_url"`
+	// TimeoutMS is the maximum time to wait for the operator to generate or refresh a token.
+	TimeoutMS int `json:"timeout_ms"`
+	// MaxEntries is the maximum number of identities whose tokens are cached, the least recently used
+	// identities are evicted first.
+	MaxEntries int `json:"max_entries"`
+	// OptOutTTLSeconds is how long an identity which opted out isn't sent to the operator again.
+	OptOutTTLSeconds int `json:"optout_ttl_seconds"`
+}
+
+// accountConfig holds the service and the operator keys an account generates tokens with. Accounts without keys
+// don't get tokens.
+type accountConfig struct {
+	// Service is either uid2 or euid, uid2 by default.
+	Service string `json:"service"`
+	// APIKey is the operator API key of the account.
+	APIKey string `json:"api_key"`
+	// ClientSecret is the base64 encoded operator secret of the account, requests and responses are encrypted with.
+	ClientSecret string `json:"client_secret"`
+
+	secret []byte
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{
+		UID2OperatorURL:  defaultUID2OperatorURL,
+		EUIDOperatorURL:  defaultEUIDOperatorURL,
+		TimeoutMS:        defaultTimeoutMS,
+		MaxEntries:       defaultMaxEntries,
+		OptOutTTLSeconds: defaultOptOutTTLSeconds,
+	}
+	if len(data) != 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.UID2OperatorURL == "" && cfg.EUIDOperatorURL == "" {
+		return cfg, errors.New("at least one of uid2_operator_url or euid_operator_url is required")
+	}
+	if cfg.TimeoutMS <= 0 {
+		return cfg, errors.New("timeout_ms must be positive")
+	}
+	if cfg.MaxEntries <= 0 {
+		return cfg, errors.New("max_entries must be positive")
+	}
+	if cfg.OptOutTTLSeconds < 0 {
+		return cfg, errors.New("optout_ttl_seconds must not be negative")
+	}
+
+	return cfg, nil
+}
+
+func (cfg config) operatorURL(service string) string {
+	if service == serviceEUID {
+		return cfg.EUIDOperatorURL
+	}
+	return cfg.UID2OperatorURL
+}
+
+func (cfg config) timeout() time.Duration {
+	return time.Duration(cfg.TimeoutMS) * time.Millisecond
+}
+
+func (cfg config) optOutTTL() time.Duration {
+	return time.Duration(cfg.OptOutTTLSeconds) * time.Second
+}
+
+// newAccountConfig parses the account config, returning false if the account has no operator keys.
+func newAccountConfig(data json.RawMessage, hostCfg config) (accountConfig, bool, error) {
+	cfg := accountConfig{Service: serviceUID2}
+	if len(data) == 0 {
+		return cfg, false, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, false, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	if cfg.APIKey == "" && cfg.ClientSecret == "" {
+		return cfg, false, nil
+	}
+
+	if cfg.Service != serviceUID2 && cfg.Service != serviceEUID {
+		return cfg, false, fmt.Errorf("unsupported service: %s", cfg.Service)
+	}
+	if hostCfg.operatorURL(cfg.Service) == "" {
+		return cfg, false, fmt.Errorf("no operator is configured for service: %s", cfg.Service)
+	}
+	if cfg.APIKey == "" {
+		return cfg, false, errors.New("api_key is required")
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(cfg.ClientSecret)
+	if err != nil || len(secret) != 32 {
+		return cfg, false, errors.New("client_secret must be a base64 encoded 32 bytes key")
+	}
+	cfg.secret = secret
+
+	return cfg, true, nil
+}
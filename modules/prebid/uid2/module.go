@@ -0,0 +1,230 @@
+package uid2
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// eidSources are the user.eids sources the tokens of each service are added with.
+var eidSources = map[string]string{
+	serviceUID2: "uidapi.com",
+	serviceEUID: "euid.eu",
+}
+
+func Builder(data json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := deps.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return Module{
+		cfg:      cfg,
+		store:    newStore(cfg.MaxEntries),
+		operator: &operatorClient{httpClient: httpClient},
+		now:      time.Now,
+	}, nil
+}
+
+type Module struct {
+	cfg      config
+	store    *store
+	operator *operatorClient
+	now      func() time.Time
+}
+
+// HandleProcessedAuctionHook adds the UID2 or EUID token of the user to user.eids. The token is generated from
+// the hashed email or phone found in user.ext.data, using the operator keys of the account. Tokens are cached
+// and refreshed ahead of their expiry, users who opted out are not sent to the operator again for a while.
+//
+// The hashes are user first party data, so they are removed from the payload by the hook executor when the
+// transmitUfpd activity isn't allowed for the module.
+func (m Module) HandleProcessedAuctionHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	accountCfg, enabled, err := newAccountConfig(miCtx.AccountConfig, m.cfg)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	req := payload.Request
+	if !enabled || req == nil || req.BidRequest == nil || req.User == nil {
+		return result, nil
+	}
+	if req.Regs != nil && req.Regs.COPPA == 1 {
+		return result, nil
+	}
+
+	source := eidSources[accountCfg.Service]
+	if hasEID(req.User.EIDs, source) {
+		return result, nil
+	}
+
+	id, found, err := userIdentity(req.User)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+		return result, nil
+	}
+	if !found {
+		return result, nil
+	}
+
+	key := tokenKey{account: miCtx.AccountID, service: accountCfg.Service, identity: id}
+	t, status, err := m.token(ctx, key, accountCfg)
+	if err != nil {
+		glog.Warningf("uid2: failed to get %s token: %s", accountCfg.Service, err)
+		result.Warnings = append(result.Warnings, "failed to get "+accountCfg.Service+" token")
+		result.AnalyticsTags = newTags(accountCfg.Service, statusError)
+		return result, nil
+	}
+
+	result.AnalyticsTags = newTags(accountCfg.Service, status)
+	if t.optOut {
+		return result, nil
+	}
+
+	eid := openrtb2.EID{Source: source, UIDs: []openrtb2.UID{{ID: t.advertisingToken, AType: adcom1.AgentTypePerson}}}
+	result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+		user := *payload.Request.User
+		user.EIDs = append(append(make([]openrtb2.EID, 0, len(user.EIDs)+1), user.EIDs...), eid)
+		payload.Request.User = &user
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidRequest", "user.eids")
+
+	return result, nil
+}
+
+// token returns the cached token of the key, refreshing it in the background once it may be refreshed. A token
+// is generated when there is none, or when it has expired and can no longer be refreshed.
+func (m Module) token(ctx context.Context, key tokenKey, accountCfg accountConfig) (token, string, error) {
+	now := m.now()
+	operatorURL := m.cfg.operatorURL(accountCfg.Service)
+
+	if cached, optOutExpires, found := m.store.get(key); found {
+		switch {
+		case cached.optOut && now.Before(optOutExpires):
+			return cached, statusOptOut, nil
+		case !cached.optOut && now.Before(cached.identityExpires):
+			if !now.Before(cached.refreshFrom) && now.Before(cached.refreshExpires) && m.store.startRefresh(key) {
+				go m.refreshInBackground(key, accountCfg, cached)
+			}
+			return cached, statusCached, nil
+		case !cached.optOut && now.Before(cached.refreshExpires):
+			if t, err := m.refresh(ctx, key, accountCfg, cached); err == nil {
+				return t, tokenStatus(t, statusRefreshed), nil
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.timeout())
+	defer cancel()
+
+	t, err := m.operator.generate(ctx, operatorURL, accountCfg, key.identity, now)
+	if err != nil {
+		return token{}, "", err
+	}
+	m.store.set(key, t, now.Add(m.cfg.optOutTTL()))
+	return t, tokenStatus(t, statusGenerated), nil
+}
+
+func (m Module) refresh(ctx context.Context, key tokenKey, accountCfg accountConfig, cached token) (token, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.timeout())
+	defer cancel()
+
+	t, err := m.operator.refresh(ctx, m.cfg.operatorURL(accountCfg.Service), accountCfg, cached)
+	if err != nil {
+		return token{}, err
+	}
+	m.store.set(key, t, m.now().Add(m.cfg.optOutTTL()))
+	return t, nil
+}
+
+func (m Module) refreshInBackground(key tokenKey, accountCfg accountConfig, cached token) {
+	if _, err := m.refresh(context.Background(), key, accountCfg, cached); err != nil {
+		glog.Warningf("uid2: failed to refresh %s token: %s", accountCfg.Service, err)
+		m.store.endRefresh(key)
+	}
+}
+
+func tokenStatus(t token, status string) string {
+	if t.optOut {
+		return statusOptOut
+	}
+	return status
+}
+
+func hasEID(eids []openrtb2.EID, source string) bool {
+	for _, eid := range eids {
+		if eid.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+type userExt struct {
+	Data struct {
+		EmailHash string `json:"email_hash"`
+		PhoneHash string `json:"phone_hash"`
+	} `json:"data"`
+}
+
+// userIdentity returns the hashed email of the user, or the hashed phone if there is no email. The hashes are
+// SHA-256 digests of the normalized email or phone, either hex or base64 encoded.
+func userIdentity(user *openrtb2.User) (identity, bool, error) {
+	if len(user.Ext) == 0 {
+		return identity{}, false, nil
+	}
+
+	var ext userExt
+	if err := jsonutil.Unmarshal(user.Ext, &ext); err != nil {
+		return identity{}, false, errors.New("failed to parse user.ext")
+	}
+
+	kind, value := identityEmail, ext.Data.EmailHash
+	if value == "" {
+		kind, value = identityPhone, ext.Data.PhoneHash
+	}
+	if value == "" {
+		return identity{}, false, nil
+	}
+
+	hash, err := normalizeHash(value)
+	if err != nil {
+		return identity{}, false, errors.New("invalid user.ext.data." + kind)
+	}
+	return identity{kind: kind, hash: hash}, true, nil
+}
+
+// normalizeHash returns the base64 encoding the operator expects of a hex or base64 encoded SHA-256 digest.
+func normalizeHash(value string) (string, error) {
+	digest, err := hex.DecodeString(value)
+	if err != nil {
+		digest, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil || len(digest) != 32 {
+		return "", errors.New("invalid SHA-256 digest")
+	}
+	return base64.StdEncoding.EncodeToString(digest), nil
+}
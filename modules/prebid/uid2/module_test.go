@@ -0,0 +1,317 @@
+package uid2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	emailHashHex    = "b4c9a289323b21a01c3e940f150eb9b8c542587f1abfd8f0e1cc1ffc5e475514"
+	emailHashBase64 = "tMmiiTI7IaAcPpQPFQ65uMVCWH8av9jw4cwf/F5HVRQ="
+	phoneHashBase64 = "wdN1alhrbw1Bmz49GzKGdPX1TAvFYRj2E4AcbN8gqhw="
+	optOutHash      = "YdPNOsDqYuCHEIA9sD6sRZM0NLq9jXUSIpTgVSu2OQ0="
+)
+
+var accountKeys = json.RawMessage(`{"api_key":"` + testAPIKey + `","client_secret":"` + testClientSecret + `"}`)
+
+// testClock is a clock shared by a module and the stand-in operator.
+type testClock struct {
+	now atomic.Pointer[time.Time]
+}
+
+func newTestClock() *testClock {
+	clock := &testClock{}
+	clock.set(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	return clock
+}
+
+func (c *testClock) get() time.Time {
+	return *c.now.Load()
+}
+
+func (c *testClock) set(now time.Time) {
+	c.now.Store(&now)
+}
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "Default config",
+			config:      json.RawMessage(`{"enabled": true}`),
+		},
+		{
+			description: "EUID only",
+			config:      json.RawMessage(`{"uid2_operator_url": "", "euid_operator_url": "https://euid.example.com"}`),
+		},
+		{
+			description:   "No operator",
+			config:        json.RawMessage(`{"uid2_operator_url": "", "euid_operator_url": ""}`),
+			expectedError: "at least one of uid2_operator_url or euid_operator_url is required",
+		},
+		{
+			description:   "Invalid timeout",
+			config:        json.RawMessage(`{"timeout_ms": 0}`),
+			expectedError: "timeout_ms must be positive",
+		},
+		{
+			description:   "Invalid max entries",
+			config:        json.RawMessage(`{"max_entries": -1}`),
+			expectedError: "max_entries must be positive",
+		},
+		{
+			description:   "Invalid opt-out TTL",
+			config:        json.RawMessage(`{"optout_ttl_seconds": -1}`),
+			expectedError: "optout_ttl_seconds must not be negative",
+		},
+		{
+			description:   "Malformed",
+			config:        json.RawMessage(`{"timeout_ms": "1"}`),
+			expectedError: "failed to parse config",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Builder(test.config, moduledeps.ModuleDeps{})
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	testCases := []struct {
+		description      string
+		accountConfig    json.RawMessage
+		bidRequest       *openrtb2.BidRequest
+		expectedEIDs     []openrtb2.EID
+		expectedWarnings []string
+		expectedTags     hookanalytics.Analytics
+		expectedError    error
+	}{
+		{
+			description:   "Token generated from hex email hash",
+			accountConfig: accountKeys,
+			bidRequest: &openrtb2.BidRequest{User: &openrtb2.User{
+				EIDs: []openrtb2.EID{{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "pubcid"}}}},
+				Ext:  json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`),
+			}},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "pubcid"}}},
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "generated-1", AType: adcom1.AgentTypePerson}}},
+			},
+			expectedTags: newTags(serviceUID2, statusGenerated),
+		},
+		{
+			description:   "Token generated from base64 phone hash",
+			accountConfig: accountKeys,
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"phone_hash":"` + phoneHashBase64 + `"}}`)}},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "generated-1", AType: adcom1.AgentTypePerson}}},
+			},
+			expectedTags: newTags(serviceUID2, statusGenerated),
+		},
+		{
+			description:   "EUID token",
+			accountConfig: json.RawMessage(`{"service":"euid","api_key":"` + testAPIKey + `","client_secret":"` + testClientSecret + `"}`),
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashBase64 + `"}}`)}},
+			expectedEIDs: []openrtb2.EID{
+				{Source: "euid.eu", UIDs: []openrtb2.UID{{ID: "generated-1", AType: adcom1.AgentTypePerson}}},
+			},
+			expectedTags: newTags(serviceEUID, statusGenerated),
+		},
+		{
+			description:   "Opted out",
+			accountConfig: accountKeys,
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + optOutHash + `"}}`)}},
+			expectedTags:  newTags(serviceUID2, statusOptOut),
+		},
+		{
+			description: "No account keys",
+			bidRequest:  &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`)}},
+		},
+		{
+			description:   "Token already present",
+			accountConfig: accountKeys,
+			bidRequest: &openrtb2.BidRequest{User: &openrtb2.User{
+				EIDs: []openrtb2.EID{{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token"}}}},
+				Ext:  json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`),
+			}},
+			expectedEIDs: []openrtb2.EID{{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "token"}}}},
+		},
+		{
+			description:   "COPPA",
+			accountConfig: accountKeys,
+			bidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1},
+				User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`)},
+			},
+		},
+		{
+			description:   "No hashes",
+			accountConfig: accountKeys,
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{}}`)}},
+		},
+		{
+			description:   "No user",
+			accountConfig: accountKeys,
+			bidRequest:    &openrtb2.BidRequest{},
+		},
+		{
+			description:      "Invalid hash",
+			accountConfig:    accountKeys,
+			bidRequest:       &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"user@example.com"}}`)}},
+			expectedWarnings: []string{"invalid user.ext.data.email_hash"},
+		},
+		{
+			description:      "Operator error",
+			accountConfig:    json.RawMessage(`{"api_key":"invalid","client_secret":"` + testClientSecret + `"}`),
+			bidRequest:       &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`)}},
+			expectedWarnings: []string{"failed to get uid2 token"},
+			expectedTags:     newTags(serviceUID2, statusError),
+		},
+		{
+			description:   "Unsupported service",
+			accountConfig: json.RawMessage(`{"service":"id5","api_key":"` + testAPIKey + `","client_secret":"` + testClientSecret + `"}`),
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`)}},
+			expectedError: hookexecution.NewFailure("unsupported service: id5"),
+		},
+		{
+			description:   "Invalid client secret",
+			accountConfig: json.RawMessage(`{"api_key":"` + testAPIKey + `","client_secret":"c2VjcmV0"}`),
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{Ext: json.RawMessage(`{"data":{"email_hash":"` + emailHashHex + `"}}`)}},
+			expectedError: hookexecution.NewFailure("client_secret must be a base64 encoded 32 bytes key"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			clock := newTestClock()
+			_, server := newTestOperator(t, clock.get, optOutHash)
+			module := Module{
+				cfg:      config{UID2OperatorURL: server.URL, EUIDOperatorURL: server.URL, TimeoutMS: defaultTimeoutMS},
+				store:    newStore(defaultMaxEntries),
+				operator: &operatorClient{httpClient: http.DefaultClient},
+				now:      clock.get,
+			}
+
+			if test.bidRequest.User != nil && test.expectedEIDs == nil {
+				test.expectedEIDs = test.bidRequest.User.EIDs
+			}
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.bidRequest}}
+
+			hookResult, err := module.HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig, AccountID: "1001", Endpoint: hookexecution.EndpointAuction},
+				payload,
+			)
+			assert.Equal(t, test.expectedError, err, "Invalid hook execution error.")
+			assert.Equal(t, test.expectedWarnings, hookResult.Warnings, "Invalid hook warnings.")
+			assert.Equal(t, test.expectedTags, hookResult.AnalyticsTags, "Invalid analytics tags.")
+
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			if payload.Request.User != nil {
+				assert.Equal(t, test.expectedEIDs, payload.Request.User.EIDs, "Invalid user.eids.")
+			}
+		})
+	}
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	clock := newTestClock()
+	operator, server := newTestOperator(t, clock.get)
+	module := Module{
+		cfg:      config{UID2OperatorURL: server.URL, TimeoutMS: defaultTimeoutMS},
+		store:    newStore(defaultMaxEntries),
+		operator: &operatorClient{httpClient: http.DefaultClient},
+		now:      clock.get,
+	}
+	start := clock.get()
+
+	accountCfg, _, err := newAccountConfig(accountKeys, module.cfg)
+	require.NoError(t, err)
+	key := tokenKey{account: "1001", service: serviceUID2, identity: identity{kind: identityEmail, hash: emailHashBase64}}
+
+	assertToken := func(description, expectedToken, expectedStatus string) {
+		t.Helper()
+		resolved, status, err := module.token(context.Background(), key, accountCfg)
+		assert.NoError(t, err, description)
+		assert.Equal(t, expectedToken, resolved.advertisingToken, description)
+		assert.Equal(t, expectedStatus, status, description)
+	}
+
+	assertToken("generated", "generated-1", statusGenerated)
+	assertToken("cached", "generated-1", statusCached)
+
+	clock.set(start.Add(45 * time.Minute))
+	assertToken("refresh due", "generated-1", statusCached)
+	assert.Eventually(t, func() bool {
+		_, refreshed := operator.counts()
+		return refreshed == 1
+	}, time.Second, 10*time.Millisecond, "refreshed in background")
+	assert.Eventually(t, func() bool {
+		cached, _, _ := module.store.get(key)
+		return cached.advertisingToken == "refreshed-1"
+	}, time.Second, 10*time.Millisecond, "refreshed token cached")
+
+	clock.set(start.Add(3 * time.Hour))
+	assertToken("expired", "refreshed-2", statusRefreshed)
+
+	clock.set(start.Add(48 * time.Hour))
+	assertToken("refresh expired", "generated-2", statusGenerated)
+
+	generated, refreshed := operator.counts()
+	assert.Equal(t, 2, generated)
+	assert.Equal(t, 2, refreshed)
+}
+
+func TestTokenOptOut(t *testing.T) {
+	clock := newTestClock()
+	operator, server := newTestOperator(t, clock.get, emailHashBase64)
+	module := Module{
+		cfg:      config{UID2OperatorURL: server.URL, TimeoutMS: defaultTimeoutMS, OptOutTTLSeconds: 24 * 60 * 60},
+		store:    newStore(defaultMaxEntries),
+		operator: &operatorClient{httpClient: http.DefaultClient},
+		now:      clock.get,
+	}
+	start := clock.get()
+
+	accountCfg, _, err := newAccountConfig(accountKeys, module.cfg)
+	require.NoError(t, err)
+	key := tokenKey{account: "1001", service: serviceUID2, identity: identity{kind: identityEmail, hash: emailHashBase64}}
+
+	for _, now := range []time.Time{start, start.Add(time.Hour), start.Add(25 * time.Hour)} {
+		clock.set(now)
+		resolved, status, err := module.token(context.Background(), key, accountCfg)
+		assert.NoError(t, err)
+		assert.True(t, resolved.optOut)
+		assert.Equal(t, statusOptOut, status)
+	}
+
+	generated, _ := operator.counts()
+	assert.Equal(t, 2, generated, "opt-out is checked again once forgotten")
+}
@@ -0,0 +1,218 @@
+package uid2
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	generatePath = "/v2/token/generate"
+	refreshPath  = "/v2/token/refresh"
+
+	envelopeVersion = 1
+	nonceSize       = 12
+	requestNonceLen = 8
+	timestampLen    = 8
+
+	statusSuccess = "success"
+	statusOptOut  = "optout"
+)
+
+// identity is the hashed email or phone of a user, base64 encoded.
+type identity struct {
+	kind string
+	hash string
+}
+
+// identity kinds are the request fields the operator expects the hashes in.
+const (
+	identityEmail = "email_hash"
+	identityPhone = "phone_hash"
+)
+
+// token is the identity of a user as returned by the operator. A user who opted out has no token.
+type token struct {
+	optOut             bool
+	advertisingToken   string
+	refreshToken       string
+	refreshResponseKey []byte
+	identityExpires    time.Time
+	refreshFrom        time.Time
+	refreshExpires     time.Time
+}
+
+type operatorResponse struct {
+	Status string            `json:"status"`
+	Body   operatorTokenBody `json:"body"`
+}
+
+type operatorTokenBody struct {
+	AdvertisingToken   string `json:"advertising_token"`
+	RefreshToken       string `json:"refresh_token"`
+	IdentityExpires    int64  `json:"identity_expires"`
+	RefreshFrom        int64  `json:"refresh_from"`
+	RefreshExpires     int64  `json:"refresh_expires"`
+	RefreshResponseKey string `json:"refresh_response_key"`
+}
+
+// operatorClient generates and refreshes tokens with the v2 operator API, whose request and response bodies are
+// encrypted with AES-GCM.
+type operatorClient struct {
+	httpClient *http.Client
+}
+
+// generate exchanges an identity for a token, using the operator keys of the account.
+func (c *operatorClient) generate(ctx context.Context, operatorURL string, account accountConfig, id identity, now time.Time) (token, error) {
+	requestNonce := make([]byte, requestNonceLen)
+	if _, err := rand.Read(requestNonce); err != nil {
+		return token{}, err
+	}
+
+	body, err := jsonutil.Marshal(map[string]interface{}{id.kind: id.hash, "optout_check": 1})
+	if err != nil {
+		return token{}, err
+	}
+	payload := make([]byte, 0, timestampLen+requestNonceLen+len(body))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(now.UnixMilli()))
+	payload = append(payload, requestNonce...)
+	payload = append(payload, body...)
+
+	envelope, err := seal(account.secret, payload)
+	if err != nil {
+		return token{}, err
+	}
+	envelope = append([]byte{envelopeVersion}, envelope...)
+
+	decrypted, err := c.post(ctx, operatorURL+generatePath, account.APIKey, base64.StdEncoding.EncodeToString(envelope), account.secret)
+	if err != nil {
+		return token{}, err
+	}
+	if len(decrypted) < timestampLen+requestNonceLen {
+		return token{}, errors.New("operator response is too short")
+	}
+	if !bytes.Equal(decrypted[timestampLen:timestampLen+requestNonceLen], requestNonce) {
+		return token{}, errors.New("operator response nonce does not match the request")
+	}
+	return parseOperatorResponse(decrypted[timestampLen+requestNonceLen:])
+}
+
+// refresh exchanges the refresh token of a token for a new one. Refresh responses are encrypted with the key
+// returned along with the refresh token.
+func (c *operatorClient) refresh(ctx context.Context, operatorURL string, account accountConfig, t token) (token, error) {
+	if t.refreshToken == "" || len(t.refreshResponseKey) == 0 {
+		return token{}, errors.New("token cannot be refreshed")
+	}
+	decrypted, err := c.post(ctx, operatorURL+refreshPath, account.APIKey, t.refreshToken, t.refreshResponseKey)
+	if err != nil {
+		return token{}, err
+	}
+	return parseOperatorResponse(decrypted)
+}
+
+func (c *operatorClient) post(ctx context.Context, url, apiKey, body string, key []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+apiKey)
+	request.Header.Set("Content-Type", "text/plain")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected operator status code %d", response.StatusCode)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(responseBody)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode operator response: %s", err)
+	}
+	return open(key, sealed)
+}
+
+func parseOperatorResponse(data []byte) (token, error) {
+	var response operatorResponse
+	if err := jsonutil.Unmarshal(data, &response); err != nil {
+		return token{}, fmt.Errorf("failed to parse operator response: %s", err)
+	}
+
+	switch response.Status {
+	case statusOptOut:
+		return token{optOut: true}, nil
+	case statusSuccess:
+	default:
+		return token{}, fmt.Errorf("unexpected operator status: %s", response.Status)
+	}
+
+	body := response.Body
+	if body.AdvertisingToken == "" {
+		return token{}, errors.New("operator response has no advertising token")
+	}
+	refreshResponseKey, err := base64.StdEncoding.DecodeString(body.RefreshResponseKey)
+	if err != nil {
+		return token{}, fmt.Errorf("failed to decode refresh response key: %s", err)
+	}
+
+	return token{
+		advertisingToken:   body.AdvertisingToken,
+		refreshToken:       body.RefreshToken,
+		refreshResponseKey: refreshResponseKey,
+		identityExpires:    time.UnixMilli(body.IdentityExpires),
+		refreshFrom:        time.UnixMilli(body.RefreshFrom),
+		refreshExpires:     time.UnixMilli(body.RefreshExpires),
+	}, nil
+}
+
+// seal encrypts data with AES-GCM, prefixing the ciphertext with the random nonce.
+func seal(key, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts data sealed with AES-GCM.
+func open(key, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < nonceSize {
+		return nil, errors.New("encrypted data is too short")
+	}
+	return aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
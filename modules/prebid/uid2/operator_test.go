@@ -0,0 +1,262 @@
+package uid2
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey       = "test-api-key"
+	testClientSecret = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+)
+
+// testOperator stands in for a UID2 operator. Tokens are valid for an hour, they may be refreshed after half an
+// hour and until a day has passed.
+type testOperator struct {
+	t      *testing.T
+	secret []byte
+	now    func() time.Time
+	optOut map[string]bool
+
+	mutex        sync.Mutex
+	generated    int
+	refreshed    int
+	responseKeys map[string][]byte
+}
+
+func newTestOperator(t *testing.T, now func() time.Time, optOutHashes ...string) (*testOperator, *httptest.Server) {
+	secret, err := base64.StdEncoding.DecodeString(testClientSecret)
+	require.NoError(t, err)
+
+	operator := &testOperator{t: t, secret: secret, now: now, optOut: map[string]bool{}, responseKeys: map[string][]byte{}}
+	for _, hash := range optOutHashes {
+		operator.optOut[hash] = true
+	}
+	server := httptest.NewServer(operator)
+	t.Cleanup(server.Close)
+	return operator, server
+}
+
+func (o *testOperator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+testAPIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	require.NoError(o.t, err)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	switch r.URL.Path {
+	case generatePath:
+		o.generate(w, body)
+	case refreshPath:
+		o.refresh(w, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (o *testOperator) generate(w http.ResponseWriter, body []byte) {
+	envelope, err := base64.StdEncoding.DecodeString(string(body))
+	require.NoError(o.t, err)
+	require.Equal(o.t, byte(envelopeVersion), envelope[0])
+	payload, err := open(o.secret, envelope[1:])
+	require.NoError(o.t, err)
+
+	var request map[string]interface{}
+	require.NoError(o.t, jsonutil.Unmarshal(payload[timestampLen+requestNonceLen:], &request))
+	assert.Equal(o.t, float64(1), request["optout_check"])
+
+	o.generated++
+	var response []byte
+	for _, kind := range []string{identityEmail, identityPhone} {
+		if hash, ok := request[kind].(string); ok && o.optOut[hash] {
+			response = []byte(`{"status":"optout"}`)
+		}
+	}
+	if response == nil {
+		response = o.newToken("generated-" + strconv.Itoa(o.generated))
+	}
+
+	plaintext := append(append([]byte{}, payload[:timestampLen+requestNonceLen]...), response...)
+	o.write(w, o.secret, plaintext)
+}
+
+func (o *testOperator) refresh(w http.ResponseWriter, body []byte) {
+	key, ok := o.responseKeys[string(body)]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	delete(o.responseKeys, string(body))
+
+	o.refreshed++
+	o.write(w, key, o.newToken("refreshed-"+strconv.Itoa(o.refreshed)))
+}
+
+func (o *testOperator) newToken(advertisingToken string) []byte {
+	now := o.now()
+	refreshToken := "refresh-" + advertisingToken
+	responseKey := make([]byte, 32)
+	_, err := rand.Read(responseKey)
+	require.NoError(o.t, err)
+	o.responseKeys[refreshToken] = responseKey
+
+	response, err := jsonutil.Marshal(operatorResponse{
+		Status: statusSuccess,
+		Body: operatorTokenBody{
+			AdvertisingToken:   advertisingToken,
+			RefreshToken:       refreshToken,
+			IdentityExpires:    now.Add(time.Hour).UnixMilli(),
+			RefreshFrom:        now.Add(30 * time.Minute).UnixMilli(),
+			RefreshExpires:     now.Add(24 * time.Hour).UnixMilli(),
+			RefreshResponseKey: base64.StdEncoding.EncodeToString(responseKey),
+		},
+	})
+	require.NoError(o.t, err)
+	return response
+}
+
+func (o *testOperator) write(w http.ResponseWriter, key, plaintext []byte) {
+	sealed, err := seal(key, plaintext)
+	require.NoError(o.t, err)
+	w.Write([]byte(base64.StdEncoding.EncodeToString(sealed)))
+}
+
+func (o *testOperator) counts() (int, int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.generated, o.refreshed
+}
+
+func TestOperatorClientGenerate(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	_, server := newTestOperator(t, func() time.Time { return now })
+	account, _, err := newAccountConfig([]byte(`{"api_key":"`+testAPIKey+`","client_secret":"`+testClientSecret+`"}`), config{UID2OperatorURL: server.URL})
+	require.NoError(t, err)
+
+	client := &operatorClient{httpClient: server.Client()}
+	generated, err := client.generate(context.Background(), server.URL, account, identity{kind: identityEmail, hash: "hash"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "generated-1", generated.advertisingToken)
+	assert.Equal(t, now.Add(time.Hour), generated.identityExpires.UTC())
+	assert.Equal(t, now.Add(30*time.Minute), generated.refreshFrom.UTC())
+	assert.Equal(t, now.Add(24*time.Hour), generated.refreshExpires.UTC())
+
+	refreshed, err := client.refresh(context.Background(), server.URL, account, generated)
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed-1", refreshed.advertisingToken)
+
+	_, err = client.refresh(context.Background(), server.URL, account, generated)
+	assert.EqualError(t, err, "unexpected operator status code 400", "refresh token already used")
+
+	_, err = client.refresh(context.Background(), server.URL, account, token{})
+	assert.EqualError(t, err, "token cannot be refreshed")
+}
+
+func TestOperatorClientGenerateNonceMismatch(t *testing.T) {
+	secret, err := base64.StdEncoding.DecodeString(testClientSecret)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plaintext := binary.BigEndian.AppendUint64(nil, 0)
+		plaintext = append(plaintext, bytes.Repeat([]byte{1}, requestNonceLen)...)
+		plaintext = append(plaintext, `{"status":"optout"}`...)
+		sealed, err := seal(secret, plaintext)
+		require.NoError(t, err)
+		w.Write([]byte(base64.StdEncoding.EncodeToString(sealed)))
+	}))
+	defer server.Close()
+
+	client := &operatorClient{httpClient: server.Client()}
+	_, err = client.generate(context.Background(), server.URL, accountConfig{APIKey: testAPIKey, secret: secret}, identity{kind: identityEmail, hash: "hash"}, time.Now())
+	assert.EqualError(t, err, "operator response nonce does not match the request")
+}
+
+func TestParseOperatorResponse(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          string
+		expectedToken token
+		expectedError string
+	}{
+		{
+			description:   "Opt-out",
+			data:          `{"status":"optout"}`,
+			expectedToken: token{optOut: true},
+		},
+		{
+			description: "Success",
+			data:        `{"status":"success","body":{"advertising_token":"ad","refresh_token":"refresh","identity_expires":1000,"refresh_from":500,"refresh_expires":2000,"refresh_response_key":"AQID"}}`,
+			expectedToken: token{
+				advertisingToken:   "ad",
+				refreshToken:       "refresh",
+				refreshResponseKey: []byte{1, 2, 3},
+				identityExpires:    time.UnixMilli(1000),
+				refreshFrom:        time.UnixMilli(500),
+				refreshExpires:     time.UnixMilli(2000),
+			},
+		},
+		{
+			description:   "Missing advertising token",
+			data:          `{"status":"success","body":{}}`,
+			expectedError: "operator response has no advertising token",
+		},
+		{
+			description:   "Client error",
+			data:          `{"status":"client_error"}`,
+			expectedError: "unexpected operator status: client_error",
+		},
+		{
+			description:   "Malformed",
+			data:          `malformed`,
+			expectedError: "failed to parse operator response",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			parsed, err := parseOperatorResponse([]byte(test.data))
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedToken, parsed)
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	sealed, err := seal(key, []byte("data"))
+	require.NoError(t, err)
+
+	opened, err := open(key, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(opened))
+
+	_, err = open(bytes.Repeat([]byte{8}, 32), sealed)
+	assert.Error(t, err, "wrong key")
+
+	_, err = open(key, []byte("short"))
+	assert.Error(t, err, "too short")
+	assert.False(t, strings.Contains(string(sealed), "data"))
+}
@@ -0,0 +1,95 @@
+package uid2
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// tokenKey identifies the token of a user for an account, accounts have their own operator keys.
+type tokenKey struct {
+	account string
+	service string
+	identity
+}
+
+type storeEntry struct {
+	key   tokenKey
+	token token
+	// expires is when an opt-out is forgotten, tokens expire by themselves.
+	expires    time.Time
+	refreshing bool
+}
+
+// store is an in-memory LRU cache of the tokens generated by the operator.
+type store struct {
+	maxEntries int
+	entries    map[tokenKey]*list.Element
+	lru        *list.List
+	mutex      sync.Mutex
+}
+
+func newStore(maxEntries int) *store {
+	return &store{
+		maxEntries: maxEntries,
+		entries:    make(map[tokenKey]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// get returns the cached token of the key along with when an opt-out expires.
+func (s *store) get(key tokenKey) (token, time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return token{}, time.Time{}, false
+	}
+	s.lru.MoveToFront(element)
+	entry := element.Value.(*storeEntry)
+	return entry.token, entry.expires, true
+}
+
+// set caches the token of the key, evicting the least recently used keys when the store is full.
+func (s *store) set(key tokenKey, t token, expires time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value = &storeEntry{key: key, token: t, expires: expires}
+		s.lru.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.lru.PushFront(&storeEntry{key: key, token: t, expires: expires})
+	for s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*storeEntry).key)
+	}
+}
+
+// startRefresh marks the token of the key as being refreshed, returning false if it already is.
+func (s *store) startRefresh(key tokenKey) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok || element.Value.(*storeEntry).refreshing {
+		return false
+	}
+	element.Value.(*storeEntry).refreshing = true
+	return true
+}
+
+// endRefresh marks the token of the key as no longer being refreshed. A successful refresh replaces the token
+// beforehand, which clears the mark by itself.
+func (s *store) endRefresh(key tokenKey) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*storeEntry).refreshing = false
+	}
+}
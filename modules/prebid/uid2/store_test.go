@@ -0,0 +1,49 @@
+package uid2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreEviction(t *testing.T) {
+	s := newStore(2)
+	keyA := tokenKey{account: "a"}
+	keyB := tokenKey{account: "b"}
+	keyC := tokenKey{account: "c"}
+
+	s.set(keyA, token{advertisingToken: "a"}, time.Time{})
+	s.set(keyB, token{advertisingToken: "b"}, time.Time{})
+	s.get(keyA)
+	s.set(keyC, token{advertisingToken: "c"}, time.Time{})
+
+	_, _, found := s.get(keyB)
+	assert.False(t, found, "least recently used evicted")
+
+	cached, _, found := s.get(keyA)
+	assert.True(t, found)
+	assert.Equal(t, "a", cached.advertisingToken)
+
+	s.set(keyA, token{advertisingToken: "a2"}, time.Time{})
+	cached, _, _ = s.get(keyA)
+	assert.Equal(t, "a2", cached.advertisingToken, "replaced")
+	assert.Equal(t, 2, s.lru.Len())
+}
+
+func TestStoreRefresh(t *testing.T) {
+	s := newStore(10)
+	key := tokenKey{account: "a"}
+
+	assert.False(t, s.startRefresh(key), "not cached")
+
+	s.set(key, token{advertisingToken: "a"}, time.Time{})
+	assert.True(t, s.startRefresh(key))
+	assert.False(t, s.startRefresh(key), "already refreshing")
+
+	s.endRefresh(key)
+	assert.True(t, s.startRefresh(key), "refresh ended")
+
+	s.set(key, token{advertisingToken: "refreshed"}, time.Time{})
+	assert.True(t, s.startRefresh(key), "replaced token")
+}
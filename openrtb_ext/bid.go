@@ -86,9 +86,10 @@ type ExtBidPrebidEvents struct {
 
 // ExtBidDSA defines the contract for bidresponse.seatbid.bid[i].ext.dsa
 type ExtBidDSA struct {
-	AdRender *int8  `json:"adrender,omitempty"`
-	Behalf   string `json:"behalf,omitempty"`
-	Paid     string `json:"paid,omitempty"`
+	AdRender     *int8                   `json:"adrender,omitempty"`
+	Behalf       string                  `json:"behalf,omitempty"`
+	Paid         string                  `json:"paid,omitempty"`
+	Transparency []ExtBidDSATransparency `json:"transparency,omitempty"`
 }
 
 // BidType describes the allowed values for bidresponse.seatbid.bid[i].ext.prebid.type
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"maps"
 	"slices"
+	"strconv"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
@@ -56,6 +57,7 @@ const (
 	dsaKey                              = "dsa"
 	eidsKey                             = "eids"
 	gdprKey                             = "gdpr"
+	gpcKey                              = "gpc"
 	prebidKey                           = "prebid"
 	dataKey                             = "data"
 	schainKey                           = "schain"
@@ -1201,6 +1203,8 @@ type RegExt struct {
 	dsaDirty       bool
 	gdpr           *int8
 	gdprDirty      bool
+	gpc            string
+	gpcDirty       bool
 	usPrivacy      string
 	usPrivacyDirty bool
 }
@@ -1237,6 +1241,19 @@ func (re *RegExt) unmarshal(extJson json.RawMessage) error {
 		}
 	}
 
+	gpcJson, hasGPC := re.ext[gpcKey]
+	if hasGPC && gpcJson != nil {
+		// the signal is specified as a string, integers are tolerated as several clients send them. Other
+		// values are ignored rather than rejected as the field was passed through unvalidated historically.
+		var gpcInt int
+		if err := jsonutil.Unmarshal(gpcJson, &re.gpc); err != nil {
+			re.gpc = ""
+			if err := jsonutil.Unmarshal(gpcJson, &gpcInt); err == nil {
+				re.gpc = strconv.Itoa(gpcInt)
+			}
+		}
+	}
+
 	uspJson, hasUsp := re.ext[us_privacyKey]
 	if hasUsp && uspJson != nil {
 		if err := jsonutil.Unmarshal(uspJson, &re.usPrivacy); err != nil {
@@ -1274,6 +1291,19 @@ func (re *RegExt) marshal() (json.RawMessage, error) {
 		re.gdprDirty = false
 	}
 
+	if re.gpcDirty {
+		if len(re.gpc) > 0 {
+			rawjson, err := jsonutil.Marshal(re.gpc)
+			if err != nil {
+				return nil, err
+			}
+			re.ext[gpcKey] = rawjson
+		} else {
+			delete(re.ext, gpcKey)
+		}
+		re.gpcDirty = false
+	}
+
 	if re.usPrivacyDirty {
 		if len(re.usPrivacy) > 0 {
 			rawjson, err := jsonutil.Marshal(re.usPrivacy)
@@ -1295,7 +1325,7 @@ func (re *RegExt) marshal() (json.RawMessage, error) {
 }
 
 func (re *RegExt) Dirty() bool {
-	return re.extDirty || re.dsaDirty || re.gdprDirty || re.usPrivacyDirty
+	return re.extDirty || re.dsaDirty || re.gdprDirty || re.gpcDirty || re.usPrivacyDirty
 }
 
 func (re *RegExt) GetExt() map[string]json.RawMessage {
@@ -1337,6 +1367,15 @@ func (re *RegExt) SetGDPR(gdpr *int8) {
 	re.gdprDirty = true
 }
 
+func (re *RegExt) GetGPC() string {
+	return re.gpc
+}
+
+func (re *RegExt) SetGPC(gpc string) {
+	re.gpc = gpc
+	re.gpcDirty = true
+}
+
 func (re *RegExt) GetUSPrivacy() string {
 	uSPrivacy := re.usPrivacy
 	return uSPrivacy
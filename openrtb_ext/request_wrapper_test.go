@@ -2150,6 +2150,18 @@ func TestRebuildRegExt(t *testing.T) {
 			regExt:          RegExt{gdpr: nil, gdprDirty: true},
 			expectedRequest: openrtb2.BidRequest{Regs: &openrtb2.Regs{}},
 		},
+		{
+			name:            "req_regs_gpc_populated_-_dirty_and_different-_change",
+			request:         openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"0"}`)}},
+			regExt:          RegExt{gpc: "1", gpcDirty: true},
+			expectedRequest: openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+		},
+		{
+			name:            "req_regs_gpc_populated_-_dirty_and_empty_-_cleared",
+			request:         openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+			regExt:          RegExt{gpc: "", gpcDirty: true},
+			expectedRequest: openrtb2.BidRequest{Regs: &openrtb2.Regs{}},
+		},
 		{
 			name:            "req_regs_usprivacy_populated_-_not_dirty_-_no_change",
 			request:         openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"a"}`)}},
@@ -2194,6 +2206,7 @@ func TestRegExtUnmarshal(t *testing.T) {
 		extJson         json.RawMessage
 		expectDSA       *ExtRegsDSA
 		expectGDPR      *int8
+		expectGPC       string
 		expectUSPrivacy string
 		expectError     bool
 	}{
@@ -2253,6 +2266,27 @@ func TestRegExtUnmarshal(t *testing.T) {
 			expectGDPR:  ptrutil.ToPtr[int8](0),
 			expectError: true,
 		},
+		// gpc
+		{
+			name:        "valid_gpc_json",
+			regExt:      &RegExt{},
+			extJson:     json.RawMessage(`{"gpc":"1"}`),
+			expectGPC:   "1",
+			expectError: false,
+		},
+		{
+			name:        "integer_gpc_json",
+			regExt:      &RegExt{},
+			extJson:     json.RawMessage(`{"gpc":1}`),
+			expectGPC:   "1",
+			expectError: false,
+		},
+		{
+			name:        "unsupported_gpc_json_ignored",
+			regExt:      &RegExt{},
+			extJson:     json.RawMessage(`{"gpc":true}`),
+			expectError: false,
+		},
 		// us_privacy
 		{
 			name:            "valid_usprivacy_json",
@@ -2278,6 +2312,7 @@ func TestRegExtUnmarshal(t *testing.T) {
 			}
 			assert.Equal(t, tt.expectDSA, tt.regExt.dsa)
 			assert.Equal(t, tt.expectGDPR, tt.regExt.gdpr)
+			assert.Equal(t, tt.expectGPC, tt.regExt.gpc)
 			assert.Equal(t, tt.expectUSPrivacy, tt.regExt.usPrivacy)
 		})
 	}
@@ -2334,6 +2369,16 @@ func TestRegExtGetUSPrivacySetUSPrivacy(t *testing.T) {
 	assert.NotSame(t, regExtUSPrivacy, usprivacy)
 }
 
+func TestRegExtGetGPCSetGPC(t *testing.T) {
+	regExt := &RegExt{}
+	assert.Equal(t, "", regExt.GetGPC())
+	assert.False(t, regExt.Dirty())
+
+	regExt.SetGPC("1")
+	assert.True(t, regExt.Dirty())
+	assert.Equal(t, "1", regExt.GetGPC())
+}
+
 func TestRegExtGetGDPRSetGDPR(t *testing.T) {
 	regExt := &RegExt{}
 	regExtGDPR := regExt.GetGDPR()
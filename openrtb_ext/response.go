@@ -31,6 +31,55 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest json.RawMessage `json:"resolvedrequest,omitempty"`
+	// Privacy defines the contract for bidresponse.ext.debug.privacy
+	Privacy *ExtResponseDebugPrivacy `json:"privacy,omitempty"`
+}
+
+// ExtResponseDebugPrivacy reports the privacy signals of the request and their enforcement
+type ExtResponseDebugPrivacy struct {
+	GPC  *ExtResponseDebugGPC  `json:"gpc,omitempty"`
+	TCF2 *ExtResponseDebugTCF2 `json:"tcf2,omitempty"`
+	// DataMinimization lists the request paths stripped for each bidder by its data minimization profile
+	DataMinimization []ExtResponseDebugDataMinimization `json:"dataminimization,omitempty"`
+}
+
+// ExtResponseDebugGPC defines the contract for bidresponse.ext.debug.privacy.gpc
+type ExtResponseDebugGPC struct {
+	Signal string `json:"signal"`
+	// Source is either "request" if the signal is read from regs.ext.gpc or "header" if read from the Sec-GPC header
+	Source   string `json:"source"`
+	Enforced bool   `json:"enforced"`
+}
+
+// ExtResponseDebugTCF2 defines the contract for bidresponse.ext.debug.privacy.tcf2
+type ExtResponseDebugTCF2 struct {
+	Bidders []ExtResponseDebugTCF2Bidder `json:"bidders"`
+}
+
+// ExtResponseDebugTCF2Bidder reports the TCF2 enforcement decisions made for a bidder
+type ExtResponseDebugTCF2Bidder struct {
+	Bidder    string                         `json:"bidder"`
+	VendorID  uint16                         `json:"vendorid,omitempty"`
+	Decisions []ExtResponseDebugTCF2Decision `json:"decisions"`
+}
+
+// ExtResponseDebugTCF2Decision reports the legal basis evaluated for an activity under a purpose or special feature
+type ExtResponseDebugTCF2Decision struct {
+	// Activity is one of "sync", "bid_request", "pass_id" or "pass_geo"
+	Activity       string `json:"activity"`
+	Purpose        int    `json:"purpose,omitempty"`
+	SpecialFeature int    `json:"specialfeature,omitempty"`
+	// Enforcement is the enforcement algorithm applied, either "full" or "basic"
+	Enforcement          string `json:"enforcement,omitempty"`
+	LegalBasis           string `json:"legalbasis"`
+	PublisherRestriction string `json:"pubrestriction,omitempty"`
+	Allowed              bool   `json:"allowed"`
+}
+
+// ExtResponseDebugDataMinimization defines the contract for bidresponse.ext.debug.privacy.dataminimization
+type ExtResponseDebugDataMinimization struct {
+	Bidder   string   `json:"bidder"`
+	Stripped []string `json:"stripped"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
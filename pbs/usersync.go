@@ -1,6 +1,7 @@
 package pbs
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -10,8 +11,11 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
+	accountService "github.com/prebid/prebid-server/v2/account"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/server/ssl"
+	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/usersync"
 )
 
@@ -23,6 +27,13 @@ type UserSyncDeps struct {
 	RecaptchaSecret  string
 	HostCookieConfig *config.HostCookie
 	PriorityGroups   [][]string
+	UIDStore         usersync.UIDStore
+	UIDStoreConfig   *config.UIDStore
+	// Config, AccountsFetcher and MetricsEngine resolve the account the opt out is written for, so the cookie is
+	// written with the same attributes as by /setuid.
+	Config          *config.Configuration
+	AccountsFetcher stored_requests.AccountFetcher
+	MetricsEngine   metrics.MetricsEngine
 }
 
 // Struct for parsing json in google's response
@@ -59,11 +70,16 @@ func (deps *UserSyncDeps) VerifyRecaptcha(response string) error {
 func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	optout := r.FormValue("optout")
 	rr := r.FormValue("g-recaptcha-response")
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+	accountID := r.FormValue("account")
+	encoder := usersync.NewEncoder(deps.HostCookieConfig)
+	decoder := usersync.VersionedDecoder{}
 
 	if rr == "" {
-		http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), http.StatusMovedPermanently)
+		optOutPage := fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl)
+		if accountID != "" {
+			optOutPage += "?account=" + url.QueryEscape(accountID)
+		}
+		http.Redirect(w, r, optOutPage, http.StatusMovedPermanently)
 		return
 	}
 
@@ -77,21 +93,60 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 	}
 
 	// Read Cookie
-	pc := usersync.ReadCookie(r, decoder, deps.HostCookieConfig)
-	usersync.SyncHostCookie(r, pc, deps.HostCookieConfig)
+	hostCookie := usersync.ResolveHostCookie(r, deps.HostCookieConfig, accountID)
+	pc := usersync.ReadCookie(r, decoder, hostCookie)
+	usersync.SyncHostCookie(r, pc, hostCookie)
+	if optout != "" {
+		deps.deleteStoredUIDs(r, pc, hostCookie)
+	}
 	pc.SetOptOut(optout != "")
 
 	// Write Cookie
+	cookieAttributes := usersync.NewCookieAttributes(r, pc, hostCookie, deps.accountCookie(accountID), false)
 	encodedCookie, err := encoder.Encode(pc)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	usersync.WriteCookie(w, encodedCookie, deps.HostCookieConfig, false)
+	usersync.WriteCookieWithAttributes(w, encodedCookie, hostCookie, cookieAttributes)
 
 	if optout == "" {
-		http.Redirect(w, r, deps.HostCookieConfig.OptInURL, http.StatusMovedPermanently)
+		http.Redirect(w, r, hostCookie.OptInURL, http.StatusMovedPermanently)
 	} else {
-		http.Redirect(w, r, deps.HostCookieConfig.OptOutURL, http.StatusMovedPermanently)
+		http.Redirect(w, r, hostCookie.OptOutURL, http.StatusMovedPermanently)
+	}
+}
+
+// deleteStoredUIDs removes the UIDs kept in the server-side UID store for the user opting out.
+// accountCookie returns the cookie configuration of the account, or nil if there is no such account. The opt out
+// is then written with the host attributes.
+func (deps *UserSyncDeps) accountCookie(accountID string) *config.AccountCookie {
+	if accountID == "" || deps.Config == nil || deps.AccountsFetcher == nil {
+		return nil
+	}
+	account, errs := accountService.GetAccount(context.Background(), deps.Config, deps.AccountsFetcher, accountID, deps.MetricsEngine)
+	if len(errs) > 0 {
+		return nil
+	}
+	return &account.Cookie
+}
+
+func (deps *UserSyncDeps) deleteStoredUIDs(r *http.Request, pc *usersync.Cookie, hostCookie *config.HostCookie) {
+	if deps.UIDStore == nil {
+		return
+	}
+	id := usersync.StoreID(r, pc, hostCookie, deps.UIDStoreConfig)
+	if id == "" {
+		return
+	}
+
+	ctx := context.Background()
+	if timeout := deps.UIDStoreConfig.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := deps.UIDStore.Delete(ctx, id, ""); err != nil {
+		glog.Errorf("UIDs of an opted out user could not be deleted from the UID store: %v", err)
 	}
 }
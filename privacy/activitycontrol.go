@@ -26,6 +26,8 @@ func NewRequestFromBidRequest(r openrtb_ext.RequestWrapper) ActivityRequest {
 type ActivityRequest struct {
 	policies   *Policies
 	bidRequest *openrtb_ext.RequestWrapper
+	// gpcHeader is the value of the Sec-GPC header of the bid request, set by the activity control
+	gpcHeader string
 }
 
 func (r ActivityRequest) IsPolicies() bool {
@@ -36,8 +38,15 @@ func (r ActivityRequest) IsBidRequest() bool {
 	return r.bidRequest != nil
 }
 
+// ActivityRecorder is notified of each activity decision made by an ActivityControl
+type ActivityRecorder interface {
+	RecordActivity(activity Activity, target Component, allowed bool)
+}
+
 type ActivityControl struct {
 	plans      map[Activity]ActivityPlan
+	recorder   ActivityRecorder
+	gpcHeader  string
 	IPv6Config config.IPv6
 	IPv4Config config.IPv4
 }
@@ -45,19 +54,35 @@ type ActivityControl struct {
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil || (cfg.AllowActivities == nil && !cfg.USNat.Enabled) {
 		return ac
 	}
 
-	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
+	var allowActivities config.AllowActivities
+	if cfg.AllowActivities != nil {
+		allowActivities = *cfg.AllowActivities
+	}
+
+	plans := make(map[Activity]ActivityPlan, 9)
+	plans[ActivitySyncUser] = buildPlan(allowActivities.SyncUser)
+	plans[ActivityFetchBids] = buildPlan(allowActivities.FetchBids)
+	plans[ActivityEnrichUserFPD] = buildPlan(allowActivities.EnrichUserFPD)
+	plans[ActivityReportAnalytics] = buildPlan(allowActivities.ReportAnalytics)
+	plans[ActivityTransmitUserFPD] = buildPlan(allowActivities.TransmitUserFPD)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(allowActivities.TransmitPreciseGeo)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(allowActivities.TransmitUniqueRequestIds)
+	plans[ActivityTransmitTIDs] = buildPlan(allowActivities.TransmitTids)
+	plans[ActivityTransmitEIDs] = buildPlan(allowActivities.TransmitEids)
+
+	// the GPP US sections are enforced before the account rules
+	if cfg.USNat.Enabled {
+		sections := newUSNatSections(cfg.USNat)
+		for _, activity := range usnatActivities {
+			plan := plans[activity]
+			plan.rules = append([]Rule{USNatRule{activity: activity, sections: sections}}, plan.rules...)
+			plans[activity] = plan
+		}
+	}
 	ac.plans = plans
 
 	ac.IPv4Config = cfg.IPv4Config
@@ -86,6 +111,12 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 			result:        result,
 			componentName: r.Condition.ComponentName,
 			componentType: r.Condition.ComponentType,
+			gppSID:        r.Condition.GPPSID,
+			geo:           r.Condition.Geo,
+			gdpr:          r.Condition.GDPR,
+			coppa:         r.Condition.COPPA,
+			gpc:           r.Condition.GPC,
+			channel:       r.Condition.Channel,
 		}
 		enfRules = append(enfRules, er)
 	}
@@ -100,7 +131,30 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 }
 
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
+	allowed := e.evaluate(activity, target, request)
+
+	if e.recorder != nil {
+		e.recorder.RecordActivity(activity, target, allowed)
+	}
+	return allowed
+}
+
+// WithRecorder returns a copy of the activity control which notifies the recorder of each decision made
+func (e ActivityControl) WithRecorder(recorder ActivityRecorder) ActivityControl {
+	e.recorder = recorder
+	return e
+}
+
+// WithGPCHeader returns a copy of the activity control which matches the gpc conditions of the bid request rules
+// against the Sec-GPC header when the request does not specify regs.ext.gpc
+func (e ActivityControl) WithGPCHeader(header string) ActivityControl {
+	e.gpcHeader = header
+	return e
+}
+
+func (e ActivityControl) evaluate(activity Activity, target Component, request ActivityRequest) bool {
 	plan, planDefined := e.plans[activity]
+	request.gpcHeader = e.gpcHeader
 
 	if !planDefined {
 		return defaultActivityResult
@@ -1,8 +1,11 @@
 package privacy
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
@@ -32,6 +35,7 @@ func TestNewActivityControl(t *testing.T) {
 					TransmitPreciseGeo:       getTestActivityConfig(false),
 					TransmitUniqueRequestIds: getTestActivityConfig(true),
 					TransmitTids:             getTestActivityConfig(true),
+					TransmitEids:             getTestActivityConfig(true),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
@@ -46,6 +50,7 @@ func TestNewActivityControl(t *testing.T) {
 					ActivityTransmitPreciseGeo:       getTestActivityPlan(ActivityDeny),
 					ActivityTransmitUniqueRequestIDs: getTestActivityPlan(ActivityAllow),
 					ActivityTransmitTIDs:             getTestActivityPlan(ActivityAllow),
+					ActivityTransmitEIDs:             getTestActivityPlan(ActivityAllow),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
@@ -142,6 +147,47 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+type activityRecorderMock struct {
+	decisions []string
+}
+
+func (r *activityRecorderMock) RecordActivity(activity Activity, target Component, allowed bool) {
+	r.decisions = append(r.decisions, fmt.Sprintf("%s:%s.%s:%t", activity, target.Type, target.Name, allowed))
+}
+
+func TestActivityControlWithRecorder(t *testing.T) {
+	recorder := &activityRecorderMock{}
+	activityControl := ActivityControl{plans: map[Activity]ActivityPlan{
+		ActivityFetchBids: getTestActivityPlan(ActivityDeny)}}
+
+	recordedControl := activityControl.WithRecorder(recorder)
+
+	assert.False(t, recordedControl.Allow(ActivityFetchBids, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{}))
+	assert.True(t, recordedControl.Allow(ActivityTransmitEIDs, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{}))
+	assert.Equal(t, []string{"fetchBids:bidder.bidderA:false", "transmitEids:bidder.bidderA:true"}, recorder.decisions)
+
+	// original activity control is left unchanged
+	activityControl.Allow(ActivityFetchBids, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{})
+	assert.Len(t, recorder.decisions, 2)
+}
+
+func TestActivityControlWithGPCHeader(t *testing.T) {
+	activityControl := ActivityControl{plans: map[Activity]ActivityPlan{
+		ActivityTransmitUserFPD: {
+			defaultResult: true,
+			rules:         []Rule{ConditionRule{result: ActivityDeny, gpc: ptrutil.ToPtr[int8](1)}},
+		},
+	}}
+	target := Component{Type: "bidder", Name: "bidderA"}
+	request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
+
+	assert.True(t, activityControl.Allow(ActivityTransmitUserFPD, target, request), "no signal")
+	assert.False(t, activityControl.WithGPCHeader("1").Allow(ActivityTransmitUserFPD, target, request), "header signal")
+
+	requestSignal := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"0"}`)}}})
+	assert.True(t, activityControl.WithGPCHeader("1").Allow(ActivityTransmitUserFPD, target, requestSignal), "request signal overrides header")
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
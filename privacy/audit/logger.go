@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	cglog "github.com/chasex/glog"
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/randomutil"
+)
+
+// Sink receives the sampled audit records once the privacy decisions of the auction have been made.
+type Sink interface {
+	Write(record *Record)
+}
+
+// Logger samples auctions for the privacy audit and writes their records to the configured sink. A nil
+// Logger samples nothing.
+type Logger struct {
+	sampleRate      float64
+	sink            Sink
+	analytics       bool
+	randomGenerator randomutil.RandomGenerator
+}
+
+// NewLogger builds the privacy audit logger from the host config. It returns nil when the audit is disabled.
+func NewLogger(cfg config.PrivacyAudit) (*Logger, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var sink Sink
+	switch cfg.Sink {
+	case config.PrivacyAuditSinkFile:
+		fileSink, err := NewFileSink(cfg.File.Filename)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	case config.PrivacyAuditSinkAnalytics:
+		sink = AnalyticsSink{}
+	default:
+		return nil, fmt.Errorf("unknown privacy audit sink %s", cfg.Sink)
+	}
+
+	return &Logger{
+		sampleRate:      cfg.SampleRate,
+		sink:            sink,
+		analytics:       cfg.Sink == config.PrivacyAuditSinkAnalytics,
+		randomGenerator: randomutil.RandomNumberGenerator{},
+	}, nil
+}
+
+// Sample decides if the auction is audited and, if so, returns a new record for it. Otherwise it returns nil,
+// which is safe to record against.
+func (l *Logger) Sample(timestamp time.Time, requestID, accountID string) *Record {
+	if l == nil || l.sampleRate <= 0 {
+		return nil
+	}
+	if l.sampleRate < 1 && float64(l.randomGenerator.GenerateInt63())/math.MaxInt64 >= l.sampleRate {
+		return nil
+	}
+	return NewRecord(timestamp, requestID, accountID)
+}
+
+// Write hands a sampled record to the sink. Records which were not sampled are ignored.
+func (l *Logger) Write(record *Record) {
+	if l == nil || record == nil {
+		return
+	}
+	l.sink.Write(record)
+}
+
+// AnalyticsRecord returns the record to hand to the analytics modules, which is nil unless they are the
+// configured sink.
+func (l *Logger) AnalyticsRecord(record *Record) *Record {
+	if l == nil || !l.analytics {
+		return nil
+	}
+	return record
+}
+
+// AnalyticsSink leaves the delivery of records to the analytics modules, which receive the record of each
+// sampled auction with the auction, amp and video objects.
+type AnalyticsSink struct{}
+
+func (AnalyticsSink) Write(record *Record) {}
+
+type fileWriter interface {
+	Debug(v ...interface{})
+	Flush()
+}
+
+// FileSink writes each record as a single line of JSON to a daily rotated file. Records are buffered and
+// flushed to the file every 30 seconds by the writer, so writes don't block the auction on disk I/O.
+type FileSink struct {
+	writer fileWriter
+}
+
+func NewFileSink(filename string) (*FileSink, error) {
+	options := cglog.LogOptions{
+		File:  filename,
+		Flag:  cglog.LstdFlags,
+		Level: cglog.Ldebug,
+		Mode:  cglog.R_Day,
+	}
+	writer, err := cglog.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{writer: writer}, nil
+}
+
+func (s *FileSink) Write(record *Record) {
+	record.mutex.Lock()
+	b, err := jsonutil.Marshal(record)
+	record.mutex.Unlock()
+
+	if err != nil {
+		glog.Errorf("Privacy audit record for request %s could not be written: %v", record.RequestID, err)
+		return
+	}
+	s.writer.Debug(string(b))
+}
+
+// Flush writes the buffered records to the file.
+func (s *FileSink) Flush() {
+	s.writer.Flush()
+}
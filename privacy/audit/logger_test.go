@@ -0,0 +1,197 @@
+package audit
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRandomGenerator struct {
+	value int64
+}
+
+func (g fakeRandomGenerator) GenerateInt63() int64 {
+	return g.value
+}
+
+type fakeSink struct {
+	records []*Record
+}
+
+func (s *fakeSink) Write(record *Record) {
+	s.records = append(s.records, record)
+}
+
+func TestNewLogger(t *testing.T) {
+	testCases := []struct {
+		description   string
+		cfg           config.PrivacyAudit
+		expectNil     bool
+		expectedError string
+	}{
+		{
+			description: "disabled",
+			cfg:         config.PrivacyAudit{Enabled: false, SampleRate: 1, Sink: config.PrivacyAuditSinkAnalytics},
+			expectNil:   true,
+		},
+		{
+			description: "analytics",
+			cfg:         config.PrivacyAudit{Enabled: true, SampleRate: 1, Sink: config.PrivacyAuditSinkAnalytics},
+		},
+		{
+			description: "file",
+			cfg:         config.PrivacyAudit{Enabled: true, SampleRate: 1, Sink: config.PrivacyAuditSinkFile, File: config.PrivacyAuditFile{Filename: filepath.Join(t.TempDir(), "audit.log")}},
+		},
+		{
+			description:   "unknown-sink",
+			cfg:           config.PrivacyAudit{Enabled: true, SampleRate: 1, Sink: "other"},
+			expectNil:     true,
+			expectedError: "unknown privacy audit sink other",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			logger, err := NewLogger(test.cfg)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			if test.expectNil {
+				assert.Nil(t, logger)
+			} else {
+				assert.NotNil(t, logger)
+			}
+		})
+	}
+}
+
+func TestLoggerSample(t *testing.T) {
+	testCases := []struct {
+		description string
+		sampleRate  float64
+		random      int64
+		expected    bool
+	}{
+		{
+			description: "rate-zero",
+			sampleRate:  0,
+			random:      0,
+			expected:    false,
+		},
+		{
+			description: "rate-one",
+			sampleRate:  1,
+			random:      math.MaxInt64,
+			expected:    true,
+		},
+		{
+			description: "below-rate",
+			sampleRate:  0.5,
+			random:      math.MaxInt64 / 4,
+			expected:    true,
+		},
+		{
+			description: "above-rate",
+			sampleRate:  0.5,
+			random:      math.MaxInt64 / 4 * 3,
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			logger := &Logger{sampleRate: test.sampleRate, sink: &fakeSink{}, randomGenerator: fakeRandomGenerator{value: test.random}}
+			record := logger.Sample(time.Time{}, "req", "acct")
+			if test.expected {
+				assert.NotNil(t, record)
+				assert.Equal(t, "req", record.RequestID)
+				assert.Equal(t, "acct", record.AccountID)
+			} else {
+				assert.Nil(t, record)
+			}
+		})
+	}
+}
+
+func TestLoggerWrite(t *testing.T) {
+	sink := &fakeSink{}
+	logger := &Logger{sampleRate: 1, sink: sink, randomGenerator: fakeRandomGenerator{}}
+
+	logger.Write(nil)
+	assert.Empty(t, sink.records)
+
+	record := NewRecord(time.Time{}, "req", "acct")
+	logger.Write(record)
+	assert.Equal(t, []*Record{record}, sink.records)
+}
+
+func TestLoggerAnalyticsRecord(t *testing.T) {
+	record := NewRecord(time.Time{}, "req", "acct")
+
+	analyticsLogger, err := NewLogger(config.PrivacyAudit{Enabled: true, SampleRate: 1, Sink: config.PrivacyAuditSinkAnalytics})
+	assert.NoError(t, err)
+	assert.Equal(t, record, analyticsLogger.AnalyticsRecord(record))
+
+	fileLogger, err := NewLogger(config.PrivacyAudit{Enabled: true, SampleRate: 1, Sink: config.PrivacyAuditSinkFile, File: config.PrivacyAuditFile{Filename: filepath.Join(t.TempDir(), "audit.log")}})
+	assert.NoError(t, err)
+	assert.Nil(t, fileLogger.AnalyticsRecord(record), "The record must only be written to the file")
+}
+
+func TestLoggerNil(t *testing.T) {
+	var logger *Logger
+	assert.NotPanics(t, func() {
+		record := logger.Sample(time.Time{}, "req", "acct")
+		assert.Nil(t, record)
+		logger.Write(NewRecord(time.Time{}, "req", "acct"))
+		assert.Nil(t, logger.AnalyticsRecord(NewRecord(time.Time{}, "req", "acct")))
+	})
+}
+
+type fakeFileWriter struct {
+	lines   []string
+	flushes int
+}
+
+func (w *fakeFileWriter) Debug(v ...interface{}) {
+	w.lines = append(w.lines, v[0].(string))
+}
+
+func (w *fakeFileWriter) Flush() {
+	w.flushes++
+}
+
+func TestFileSinkBuffered(t *testing.T) {
+	writer := &fakeFileWriter{}
+	sink := &FileSink{writer: writer}
+
+	sink.Write(NewRecord(time.Time{}, "req-1", "acct"))
+	sink.Write(NewRecord(time.Time{}, "req-2", "acct"))
+
+	assert.Len(t, writer.lines, 2)
+	assert.Zero(t, writer.flushes, "Records must be buffered")
+}
+
+func TestFileSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(filename)
+	assert.NoError(t, err)
+
+	sink.Write(NewRecord(time.Time{}, "req-1", "acct"))
+	sink.Flush()
+
+	// files are rotated daily and carry a date suffix
+	files, err := filepath.Glob(filename + "*")
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	contents, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), `"requestid":"req-1"`)
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/privacy"
+)
+
+// Scrub identifies a scrub function from the privacy package applied to a bidder request.
+type Scrub string
+
+const (
+	ScrubUserFPD                 Scrub = "ScrubUserFPD"
+	ScrubGdprID                  Scrub = "ScrubGdprID"
+	ScrubDeviceIDsIPsUserDemoExt Scrub = "ScrubDeviceIDsIPsUserDemoExt"
+	ScrubEIDs                    Scrub = "ScrubEIDs"
+	ScrubGeoAndDeviceIP          Scrub = "ScrubGeoAndDeviceIP"
+	ScrubTID                     Scrub = "ScrubTID"
+	ScrubChildDirected           Scrub = "ScrubChildDirected"
+	ScrubUserSegments            Scrub = "ScrubUserSegments"
+)
+
+// Scrub reasons which are not activities. Scrubs caused by a denied activity use the activity name as the reason.
+const (
+	ReasonGDPR  = "gdpr"
+	ReasonCCPA  = "ccpa"
+	ReasonGPC   = "gpc"
+	ReasonLMT   = "lmt"
+	ReasonCOPPA = "coppa"
+	// ReasonKAnonymity is the reason of the user segments removed by the account k-anonymity settings.
+	ReasonKAnonymity = "kanonymity"
+)
+
+// fingerprintLength is the number of hex characters of the SHA-256 digest kept for a fingerprint. It's long
+// enough to match a record against a consent string held elsewhere without storing the string itself.
+const fingerprintLength = 16
+
+// Record is the privacy audit of a single auction. It holds how the privacy signals of the request were
+// interpreted, the activity decisions made for each component and the scrub functions applied to each
+// bidder request. It never holds the raw consent strings or any of the scrubbed values.
+type Record struct {
+	Timestamp  time.Time          `json:"timestamp"`
+	RequestID  string             `json:"requestid"`
+	AccountID  string             `json:"accountid"`
+	Signals    Signals            `json:"signals"`
+	Components []*ComponentRecord `json:"components,omitempty"`
+
+	mutex      sync.Mutex
+	components map[privacy.Component]*ComponentRecord
+}
+
+// Signals describes the privacy signals received with the request as parsed by Prebid Server.
+type Signals struct {
+	GDPR      GDPRSignals `json:"gdpr"`
+	GPP       *GPPSignals `json:"gpp,omitempty"`
+	USPrivacy string      `json:"usprivacy,omitempty"`
+	GPC       bool        `json:"gpc"`
+	COPPA     bool        `json:"coppa"`
+	LMT       bool        `json:"lmt"`
+}
+
+type GDPRSignals struct {
+	Signal             string `json:"signal"`
+	Enforced           bool   `json:"enforced"`
+	ConsentProvided    bool   `json:"consentprovided"`
+	ConsentValid       bool   `json:"consentvalid"`
+	ConsentFingerprint string `json:"consentfingerprint,omitempty"`
+	TCFVersion         int    `json:"tcfversion,omitempty"`
+	CMPID              int    `json:"cmpid,omitempty"`
+	VendorListVersion  int    `json:"vendorlistversion,omitempty"`
+	PurposesConsented  []int  `json:"purposesconsented,omitempty"`
+}
+
+type GPPSignals struct {
+	Fingerprint    string `json:"fingerprint"`
+	Valid          bool   `json:"valid"`
+	SectionIDs     []int  `json:"sectionids,omitempty"`
+	ApplicableSIDs []int  `json:"applicablesids,omitempty"`
+}
+
+// ComponentRecord holds the decisions made for a single component, in the order they were first made.
+type ComponentRecord struct {
+	Type       string             `json:"type"`
+	Name       string             `json:"name"`
+	Activities []ActivityDecision `json:"activities,omitempty"`
+	Scrubs     []ScrubRecord      `json:"scrubs,omitempty"`
+}
+
+type ActivityDecision struct {
+	Activity string `json:"activity"`
+	Allowed  bool   `json:"allowed"`
+}
+
+type ScrubRecord struct {
+	Function Scrub  `json:"function"`
+	Reason   string `json:"reason"`
+}
+
+// NewRecord creates an empty audit record for the auction.
+func NewRecord(timestamp time.Time, requestID, accountID string) *Record {
+	return &Record{
+		Timestamp:  timestamp,
+		RequestID:  requestID,
+		AccountID:  accountID,
+		components: make(map[privacy.Component]*ComponentRecord),
+	}
+}
+
+// SetSignals stores the parsed privacy signals of the request. Safe to call on a nil record.
+func (r *Record) SetSignals(signals Signals) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Signals = signals
+}
+
+// RecordActivity implements privacy.ActivityRecorder. A repeated decision for the same activity and component
+// replaces the previous one. Safe to call on a nil record.
+func (r *Record) RecordActivity(activity privacy.Activity, target privacy.Component, allowed bool) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	component := r.component(target)
+	for i := range component.Activities {
+		if component.Activities[i].Activity == activity.String() {
+			component.Activities[i].Allowed = allowed
+			return
+		}
+	}
+	component.Activities = append(component.Activities, ActivityDecision{Activity: activity.String(), Allowed: allowed})
+}
+
+// RecordScrub notes a scrub function applied to the request sent to the component. A scrub function applied
+// more than once for the same reason is recorded once. Safe to call on a nil record.
+func (r *Record) RecordScrub(target privacy.Component, function Scrub, reason string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	scrub := ScrubRecord{Function: function, Reason: reason}
+	component := r.component(target)
+	for _, s := range component.Scrubs {
+		if s == scrub {
+			return
+		}
+	}
+	component.Scrubs = append(component.Scrubs, scrub)
+}
+
+func (r *Record) component(target privacy.Component) *ComponentRecord {
+	if component, ok := r.components[target]; ok {
+		return component
+	}
+	component := &ComponentRecord{Type: target.Type, Name: target.Name}
+	r.components[target] = component
+	r.Components = append(r.Components, component)
+	return component
+}
+
+// Fingerprint returns a short SHA-256 digest of a privacy string, or an empty string if there is none.
+func Fingerprint(value string) string {
+	if value == "" {
+		return ""
+	}
+	digest := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(digest[:])[:fingerprintLength]
+}
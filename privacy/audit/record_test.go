@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordActivity(t *testing.T) {
+	bidderA := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderA"}
+	bidderB := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderB"}
+
+	record := NewRecord(time.Time{}, "req", "acct")
+	record.RecordActivity(privacy.ActivityFetchBids, bidderA, true)
+	record.RecordActivity(privacy.ActivityFetchBids, bidderB, false)
+	record.RecordActivity(privacy.ActivityTransmitUserFPD, bidderA, true)
+	record.RecordActivity(privacy.ActivityTransmitUserFPD, bidderA, false)
+
+	expected := []*ComponentRecord{
+		{
+			Type: "bidder",
+			Name: "bidderA",
+			Activities: []ActivityDecision{
+				{Activity: "fetchBids", Allowed: true},
+				{Activity: "transmitUfpd", Allowed: false},
+			},
+		},
+		{
+			Type:       "bidder",
+			Name:       "bidderB",
+			Activities: []ActivityDecision{{Activity: "fetchBids", Allowed: false}},
+		},
+	}
+	assert.Equal(t, expected, record.Components)
+}
+
+func TestRecordScrub(t *testing.T) {
+	bidder := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderA"}
+
+	record := NewRecord(time.Time{}, "req", "acct")
+	record.RecordScrub(bidder, ScrubDeviceIDsIPsUserDemoExt, ReasonCCPA)
+	record.RecordScrub(bidder, ScrubDeviceIDsIPsUserDemoExt, ReasonCCPA)
+	record.RecordScrub(bidder, ScrubDeviceIDsIPsUserDemoExt, ReasonLMT)
+	record.RecordScrub(bidder, ScrubTID, privacy.ActivityTransmitTIDs.String())
+
+	expected := []ScrubRecord{
+		{Function: ScrubDeviceIDsIPsUserDemoExt, Reason: "ccpa"},
+		{Function: ScrubDeviceIDsIPsUserDemoExt, Reason: "lmt"},
+		{Function: ScrubTID, Reason: "transmitTid"},
+	}
+	assert.Len(t, record.Components, 1)
+	assert.Equal(t, expected, record.Components[0].Scrubs)
+}
+
+func TestRecordNil(t *testing.T) {
+	var record *Record
+	bidder := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderA"}
+
+	assert.NotPanics(t, func() {
+		record.SetSignals(Signals{COPPA: true})
+		record.RecordActivity(privacy.ActivityFetchBids, bidder, true)
+		record.RecordScrub(bidder, ScrubTID, ReasonGDPR)
+	})
+}
+
+func TestRecordWithActivityControl(t *testing.T) {
+	record := NewRecord(time.Time{}, "req", "acct")
+	activityControl := privacy.ActivityControl{}.WithRecorder(record)
+
+	bidder := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderA"}
+	activityControl.Allow(privacy.ActivityTransmitEIDs, bidder, privacy.ActivityRequest{})
+
+	assert.Equal(t, []*ComponentRecord{
+		{Type: "bidder", Name: "bidderA", Activities: []ActivityDecision{{Activity: "transmitEids", Allowed: true}}},
+	}, record.Components)
+}
+
+func TestRecordMarshal(t *testing.T) {
+	record := NewRecord(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "req", "acct")
+	record.SetSignals(Signals{
+		GDPR: GDPRSignals{
+			Signal:             "yes",
+			Enforced:           true,
+			ConsentProvided:    true,
+			ConsentValid:       true,
+			ConsentFingerprint: "0123456789abcdef",
+			TCFVersion:         2,
+			CMPID:              7,
+			VendorListVersion:  150,
+			PurposesConsented:  []int{1, 2},
+		},
+		USPrivacy: "1YNN",
+		GPC:       true,
+	})
+	record.RecordScrub(privacy.Component{Type: privacy.ComponentTypeBidder, Name: "bidderA"}, ScrubGdprID, ReasonGDPR)
+
+	b, err := jsonutil.Marshal(record)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"timestamp": "2024-03-01T12:00:00Z",
+		"requestid": "req",
+		"accountid": "acct",
+		"signals": {
+			"gdpr": {
+				"signal": "yes",
+				"enforced": true,
+				"consentprovided": true,
+				"consentvalid": true,
+				"consentfingerprint": "0123456789abcdef",
+				"tcfversion": 2,
+				"cmpid": 7,
+				"vendorlistversion": 150,
+				"purposesconsented": [1, 2]
+			},
+			"usprivacy": "1YNN",
+			"gpc": true,
+			"coppa": false,
+			"lmt": false
+		},
+		"components": [
+			{"type": "bidder", "name": "bidderA", "scrubs": [{"function": "ScrubGdprID", "reason": "gdpr"}]}
+		]
+	}`, string(b))
+}
+
+func TestFingerprint(t *testing.T) {
+	testCases := []struct {
+		description string
+		value       string
+		expected    string
+	}{
+		{
+			description: "empty",
+			value:       "",
+			expected:    "",
+		},
+		{
+			description: "value",
+			value:       "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+			expected:    Fingerprint("CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result := Fingerprint(test.value)
+			assert.Equal(t, test.expected, result)
+			if test.value != "" {
+				assert.Len(t, result, fingerprintLength)
+				assert.NotContains(t, result, test.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package coppa
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// ChildDirected reports if the request is for inventory the account lists as child-directed, either by its app
+// bundle, its site or app domain or the stored request it was built from.
+func ChildDirected(cfg config.AccountChildDirected, req *openrtb2.BidRequest, storedRequestID string) bool {
+	if storedRequestID != "" && contains(cfg.StoredRequestIDs, storedRequestID) {
+		return true
+	}
+	if req == nil {
+		return false
+	}
+
+	if req.App != nil {
+		if req.App.Bundle != "" && contains(cfg.Bundles, req.App.Bundle) {
+			return true
+		}
+		return DomainChildDirected(cfg, req.App.Domain)
+	}
+
+	if req.Site != nil {
+		if DomainChildDirected(cfg, req.Site.Domain) {
+			return true
+		}
+		if page, err := url.Parse(req.Site.Page); err == nil {
+			return DomainChildDirected(cfg, page.Hostname())
+		}
+	}
+	return false
+}
+
+// DomainChildDirected reports if the domain, or any domain it belongs to, is listed as child-directed.
+func DomainChildDirected(cfg config.AccountChildDirected, domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" {
+		return false
+	}
+	for _, listed := range cfg.Domains {
+		listed = strings.TrimSuffix(strings.ToLower(listed), ".")
+		if listed == "" {
+			continue
+		}
+		if domain == listed || strings.HasSuffix(domain, "."+listed) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package coppa
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChildDirected(t *testing.T) {
+	cfg := config.AccountChildDirected{
+		Bundles:          []string{"com.kids.app"},
+		Domains:          []string{"kids.com"},
+		StoredRequestIDs: []string{"kids-stored-request"},
+	}
+
+	testCases := []struct {
+		name            string
+		request         *openrtb2.BidRequest
+		storedRequestID string
+		expected        bool
+	}{
+		{
+			name:     "nil-request",
+			request:  nil,
+			expected: false,
+		},
+		{
+			name:            "stored-request",
+			request:         &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "news.com"}},
+			storedRequestID: "kids-stored-request",
+			expected:        true,
+		},
+		{
+			name:            "other-stored-request",
+			request:         &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "news.com"}},
+			storedRequestID: "stored-request",
+			expected:        false,
+		},
+		{
+			name:     "app-bundle",
+			request:  &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "COM.KIDS.APP"}},
+			expected: true,
+		},
+		{
+			name:     "app-domain",
+			request:  &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.news.app", Domain: "kids.com"}},
+			expected: true,
+		},
+		{
+			name:     "other-app",
+			request:  &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.news.app", Domain: "news.com"}},
+			expected: false,
+		},
+		{
+			name:     "site-domain",
+			request:  &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "kids.com"}},
+			expected: true,
+		},
+		{
+			name:     "site-subdomain",
+			request:  &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "games.kids.com"}},
+			expected: true,
+		},
+		{
+			name:     "site-page",
+			request:  &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://www.kids.com/games"}},
+			expected: true,
+		},
+		{
+			name:     "other-site",
+			request:  &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "notkids.com", Page: "https://notkids.com/games"}},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ChildDirected(cfg, test.request, test.storedRequestID))
+		})
+	}
+}
+
+func TestDomainChildDirected(t *testing.T) {
+	cfg := config.AccountChildDirected{Domains: []string{"Kids.com.", ""}}
+
+	testCases := []struct {
+		name     string
+		domain   string
+		expected bool
+	}{
+		{
+			name:     "empty",
+			domain:   "",
+			expected: false,
+		},
+		{
+			name:     "domain",
+			domain:   "kids.com",
+			expected: true,
+		},
+		{
+			name:     "subdomain",
+			domain:   "www.KIDS.com",
+			expected: true,
+		},
+		{
+			name:     "same-suffix",
+			domain:   "notkids.com",
+			expected: false,
+		},
+		{
+			name:     "other",
+			domain:   "news.com",
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DomainChildDirected(cfg, test.domain))
+		})
+	}
+}
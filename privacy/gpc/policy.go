@@ -0,0 +1,76 @@
+package gpc
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// SignalOptOut is the value of the Global Privacy Control signal when the user does not consent
+// to the sale or sharing of their personal information. It is the only value defined by the specification.
+const SignalOptOut = "1"
+
+// Signal sources.
+const (
+	SourceRequest = "request"
+	SourceHeader  = "header"
+)
+
+// Policy represents the Global Privacy Control (GPC) signal of a request.
+type Policy struct {
+	Signal string
+	// Source is the origin of the signal, either SourceRequest or SourceHeader. Empty if no signal is provided.
+	Source string
+}
+
+// ReadFromRequestWrapper extracts the GPC signal from request.regs.ext.gpc, falling back to the
+// value of the Sec-GPC header if the request does not specify it.
+func ReadFromRequestWrapper(req *openrtb_ext.RequestWrapper, header string) (Policy, error) {
+	if req != nil && req.BidRequest != nil {
+		regsExt, err := req.GetRegExt()
+		if err != nil {
+			return ReadFromHeader(header), fmt.Errorf("error reading request.regs.ext: %s", err)
+		}
+		if signal := regsExt.GetGPC(); signal != "" {
+			return Policy{Signal: signal, Source: SourceRequest}, nil
+		}
+	}
+	return ReadFromHeader(header), nil
+}
+
+// ReadFromHeader extracts the GPC signal from the value of the Sec-GPC header.
+// Values other than SignalOptOut are ignored.
+func ReadFromHeader(header string) Policy {
+	if header != SignalOptOut {
+		return Policy{}
+	}
+	return Policy{Signal: header, Source: SourceHeader}
+}
+
+// Write sets request.regs.ext.gpc to the signal read from the Sec-GPC header, so the signal is
+// forwarded to the bidders in the same way as a signal sent in the request. It is only written when the
+// signal is enforced, the activity control reads the header otherwise.
+func (p Policy) Write(req *openrtb_ext.RequestWrapper) error {
+	if p.Source != SourceHeader || req == nil || req.BidRequest == nil {
+		return nil
+	}
+
+	regsExt, err := req.GetRegExt()
+	if err != nil {
+		return err
+	}
+	if regsExt.GetGPC() == "" {
+		regsExt.SetGPC(p.Signal)
+	}
+	return nil
+}
+
+// CanEnforce returns true when the GPC signal is provided by the publisher or the user agent.
+func (p Policy) CanEnforce() bool {
+	return p.Signal != ""
+}
+
+// ShouldEnforce returns true when the GPC signal opts the user out of the sale or sharing of their personal information.
+func (p Policy) ShouldEnforce(bidder string) bool {
+	return p.Signal == SignalOptOut
+}
@@ -0,0 +1,162 @@
+package gpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFromRequestWrapper(t *testing.T) {
+	testCases := []struct {
+		description    string
+		request        *openrtb2.BidRequest
+		header         string
+		expectedPolicy Policy
+		expectedError  bool
+	}{
+		{
+			description:    "Nil Request",
+			request:        nil,
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Nil Request - Header",
+			request:        nil,
+			header:         "1",
+			expectedPolicy: Policy{Signal: "1", Source: SourceHeader},
+		},
+		{
+			description:    "Nil Regs",
+			request:        &openrtb2.BidRequest{},
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Request Signal",
+			request:        &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+			expectedPolicy: Policy{Signal: "1", Source: SourceRequest},
+		},
+		{
+			description:    "Request Signal - Precedence Over Header",
+			request:        &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"0"}`)}},
+			header:         "1",
+			expectedPolicy: Policy{Signal: "0", Source: SourceRequest},
+		},
+		{
+			description:    "Header Signal",
+			request:        &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN"}`)}},
+			header:         "1",
+			expectedPolicy: Policy{Signal: "1", Source: SourceHeader},
+		},
+		{
+			description:    "Header Signal - Unknown Value",
+			request:        &openrtb2.BidRequest{},
+			header:         "true",
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Malformed Regs Ext",
+			request:        &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`malformed`)}},
+			header:         "1",
+			expectedPolicy: Policy{Signal: "1", Source: SourceHeader},
+			expectedError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var req *openrtb_ext.RequestWrapper
+			if test.request != nil {
+				req = &openrtb_ext.RequestWrapper{BidRequest: test.request}
+			}
+
+			policy, err := ReadFromRequestWrapper(req, test.header)
+			assert.Equal(t, test.expectedPolicy, policy)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		description     string
+		policy          Policy
+		request         *openrtb2.BidRequest
+		expectedRequest *openrtb2.BidRequest
+	}{
+		{
+			description:     "Header Signal",
+			policy:          Policy{Signal: "1", Source: SourceHeader},
+			request:         &openrtb2.BidRequest{},
+			expectedRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+		},
+		{
+			description:     "Header Signal - Existing Regs Ext",
+			policy:          Policy{Signal: "1", Source: SourceHeader},
+			request:         &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN"}`)}},
+			expectedRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1","us_privacy":"1YNN"}`)}},
+		},
+		{
+			description:     "Request Signal",
+			policy:          Policy{Signal: "1", Source: SourceRequest},
+			request:         &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+			expectedRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}},
+		},
+		{
+			description:     "No Signal",
+			policy:          Policy{},
+			request:         &openrtb2.BidRequest{},
+			expectedRequest: &openrtb2.BidRequest{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: test.request}
+			assert.NoError(t, test.policy.Write(req))
+			assert.NoError(t, req.RebuildRequest())
+			assert.Equal(t, test.expectedRequest, req.BidRequest)
+		})
+	}
+}
+
+func TestShouldEnforce(t *testing.T) {
+	testCases := []struct {
+		description        string
+		policy             Policy
+		expectedCanEnforce bool
+		expectedEnforce    bool
+	}{
+		{
+			description:        "No Signal",
+			policy:             Policy{},
+			expectedCanEnforce: false,
+			expectedEnforce:    false,
+		},
+		{
+			description:        "Opt-Out",
+			policy:             Policy{Signal: "1", Source: SourceRequest},
+			expectedCanEnforce: true,
+			expectedEnforce:    true,
+		},
+		{
+			description:        "No Opt-Out",
+			policy:             Policy{Signal: "0", Source: SourceRequest},
+			expectedCanEnforce: true,
+			expectedEnforce:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedCanEnforce, test.policy.CanEnforce())
+			assert.Equal(t, test.expectedEnforce, test.policy.ShouldEnforce("anyBidder"))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// Values of the US section fields. Fields which do not apply are set to zero.
+const (
+	// USNoticeProvided and USNoticeNotProvided are the values of the notice fields.
+	USNoticeProvided    byte = 1
+	USNoticeNotProvided byte = 2
+	// USOptedOut and USDidNotOptOut are the values of the opt-out fields.
+	USOptedOut     byte = 1
+	USDidNotOptOut byte = 2
+	// USNoConsent and USConsent are the values of the sensitive data processing and consent fields.
+	USNoConsent byte = 1
+	USConsent   byte = 2
+	// USServiceProviderMode is the value of MspaServiceProviderMode if the transaction is in service provider mode.
+	USServiceProviderMode byte = 1
+)
+
+// USNatPreciseGeolocation is the index of the precise geolocation category in USNat.SensitiveDataProcessing.
+const USNatPreciseGeolocation = 7
+
+// usnatSensitiveDataIndexes maps the sensitive data categories of the state sections onto the US national ones.
+var usnatSensitiveDataIndexes = map[gppConstants.SectionID][]int{
+	// identification documents, account login, precise geolocation, racial or ethnic origin, mail contents,
+	// genetic, biometric, health, sex life or sexual orientation
+	gppConstants.SectionUSPCA: {8, 9, 7, 0, 11, 5, 6, 2, 3},
+	gppConstants.SectionUSPVA: {0, 1, 2, 3, 4, 5, 6, 7},
+	gppConstants.SectionUSPCO: {0, 1, 2, 3, 4, 5, 6},
+	// racial or ethnic origin, religious beliefs, sexual orientation, citizenship, health, genetic, biometric, precise geolocation
+	gppConstants.SectionUSPUT: {0, 1, 3, 4, 2, 5, 6, 7},
+	gppConstants.SectionUSPCT: {0, 1, 2, 3, 4, 5, 6, 7},
+}
+
+// USNat holds the fields of a US national or state section mapped onto the US national section.
+type USNat struct {
+	SectionID                           gppConstants.SectionID
+	SharingNotice                       byte
+	SaleOptOutNotice                    byte
+	SharingOptOutNotice                 byte
+	TargetedAdvertisingOptOutNotice     byte
+	SensitiveDataProcessingOptOutNotice byte
+	SensitiveDataLimitUseNotice         byte
+	SaleOptOut                          byte
+	SharingOptOut                       byte
+	TargetedAdvertisingOptOut           byte
+	SensitiveDataProcessing             [12]byte
+	// KnownChildSensitiveDataConsents holds the consents for consumers from 13 to 16 and younger than 13 years of age.
+	KnownChildSensitiveDataConsents [2]byte
+	PersonalDataConsents            byte
+	MspaServiceProviderMode         byte
+	GPC                             bool
+}
+
+// IsUSSection returns true if the section is the US national or one of the US state sections.
+func IsUSSection(sid gppConstants.SectionID) bool {
+	return sid >= gppConstants.SectionUSPNAT && sid <= gppConstants.SectionUSPCT
+}
+
+// ReadUSNat maps the US section onto the US national section. If normalize is true, the US national
+// fields missing from a state section are derived from their closest state equivalent, e.g. the targeted
+// advertising opt-out of California from its sharing opt-out. Returns false if the section is not a US section.
+func ReadUSNat(section gpplib.Section, normalize bool) (USNat, bool) {
+	var usnat USNat
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		core := s.CoreSegment
+		usnat = USNat{
+			SharingNotice:                       core.SharingNotice,
+			SaleOptOutNotice:                    core.SaleOptOutNotice,
+			SharingOptOutNotice:                 core.SharingOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     core.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: core.SensitiveDataProcessingOptOutNotice,
+			SensitiveDataLimitUseNotice:         core.SensitiveDataLimitUseNotice,
+			SaleOptOut:                          core.SaleOptOut,
+			SharingOptOut:                       core.SharingOptOut,
+			TargetedAdvertisingOptOut:           core.TargetedAdvertisingOptOut,
+			PersonalDataConsents:                core.PersonalDataConsents,
+			MspaServiceProviderMode:             core.MspaServiceProviderMode,
+			GPC:                                 s.GPCSegment.Gpc,
+		}
+		copy(usnat.SensitiveDataProcessing[:], core.SensitiveDataProcessing)
+		copy(usnat.KnownChildSensitiveDataConsents[:], core.KnownChildSensitiveDataConsents)
+	case uspca.USPCA:
+		core := s.CoreSegment
+		usnat = USNat{
+			SaleOptOutNotice:            core.SaleOptOutNotice,
+			SharingOptOutNotice:         core.SharingOptOutNotice,
+			SensitiveDataLimitUseNotice: core.SensitiveDataLimitUseNotice,
+			SaleOptOut:                  core.SaleOptOut,
+			SharingOptOut:               core.SharingOptOut,
+			PersonalDataConsents:        core.PersonalDataConsents,
+			MspaServiceProviderMode:     core.MspaServiceProviderMode,
+			GPC:                         s.GPCSegment.Gpc,
+		}
+		copy(usnat.KnownChildSensitiveDataConsents[:], core.KnownChildSensitiveDataConsents)
+		if normalize {
+			usnat.TargetedAdvertisingOptOut = core.SharingOptOut
+			usnat.TargetedAdvertisingOptOutNotice = core.SharingOptOutNotice
+		}
+		mapSensitiveData(&usnat, gppConstants.SectionUSPCA, core.SensitiveDataProcessing)
+	case uspva.USPVA:
+		usnat = readCommonUSCore(s.CoreSegment.SharingNotice, s.CoreSegment.SaleOptOutNotice, s.CoreSegment.TargetedAdvertisingOptOutNotice,
+			s.CoreSegment.SaleOptOut, s.CoreSegment.TargetedAdvertisingOptOut, s.CoreSegment.MspaServiceProviderMode, normalize)
+		mapSensitiveData(&usnat, gppConstants.SectionUSPVA, s.CoreSegment.SensitiveDataProcessing)
+		mapKnownChildConsents(&usnat, nil, s.CoreSegment.KnownChildSensitiveDataConsents)
+	case uspco.USPCO:
+		usnat = readCommonUSCore(s.CoreSegment.SharingNotice, s.CoreSegment.SaleOptOutNotice, s.CoreSegment.TargetedAdvertisingOptOutNotice,
+			s.CoreSegment.SaleOptOut, s.CoreSegment.TargetedAdvertisingOptOut, s.CoreSegment.MspaServiceProviderMode, normalize)
+		usnat.GPC = s.GPCSegment.Gpc
+		mapSensitiveData(&usnat, gppConstants.SectionUSPCO, s.CoreSegment.SensitiveDataProcessing)
+		mapKnownChildConsents(&usnat, nil, s.CoreSegment.KnownChildSensitiveDataConsents)
+	case usput.USPUT:
+		usnat = readCommonUSCore(s.CoreSegment.SharingNotice, s.CoreSegment.SaleOptOutNotice, s.CoreSegment.TargetedAdvertisingOptOutNotice,
+			s.CoreSegment.SaleOptOut, s.CoreSegment.TargetedAdvertisingOptOut, s.CoreSegment.MspaServiceProviderMode, normalize)
+		usnat.SensitiveDataProcessingOptOutNotice = s.CoreSegment.SensitiveDataProcessingOptOutNotice
+		mapSensitiveData(&usnat, gppConstants.SectionUSPUT, s.CoreSegment.SensitiveDataProcessing)
+		mapKnownChildConsents(&usnat, nil, []byte{s.CoreSegment.KnownChildSensitiveDataConsents})
+	case uspct.USPCT:
+		usnat = readCommonUSCore(s.CoreSegment.SharingNotice, s.CoreSegment.SaleOptOutNotice, s.CoreSegment.TargetedAdvertisingOptOutNotice,
+			s.CoreSegment.SaleOptOut, s.CoreSegment.TargetedAdvertisingOptOut, s.CoreSegment.MspaServiceProviderMode, normalize)
+		usnat.GPC = s.GPCSegment.Gpc
+		mapSensitiveData(&usnat, gppConstants.SectionUSPCT, s.CoreSegment.SensitiveDataProcessing)
+		// targeted advertising and sale consents for consumers from 13 to 16, sensitive data consent for younger consumers
+		if consents := s.CoreSegment.KnownChildSensitiveDataConsents; len(consents) == 3 {
+			mapKnownChildConsents(&usnat, []byte{consents[0], consents[2]}, consents[1:2])
+		}
+	default:
+		return usnat, false
+	}
+
+	usnat.SectionID = section.GetID()
+	return usnat, true
+}
+
+// readCommonUSCore maps the fields shared by the Virginia, Colorado, Utah and Connecticut sections. These states
+// consider sharing as targeted advertising, their sharing opt-out is derived from it if normalize is true.
+func readCommonUSCore(sharingNotice, saleOptOutNotice, targetedAdvertisingOptOutNotice, saleOptOut, targetedAdvertisingOptOut, serviceProviderMode byte, normalize bool) USNat {
+	usnat := USNat{
+		SharingNotice:                   sharingNotice,
+		SaleOptOutNotice:                saleOptOutNotice,
+		TargetedAdvertisingOptOutNotice: targetedAdvertisingOptOutNotice,
+		SaleOptOut:                      saleOptOut,
+		TargetedAdvertisingOptOut:       targetedAdvertisingOptOut,
+		MspaServiceProviderMode:         serviceProviderMode,
+	}
+	if normalize {
+		usnat.SharingOptOut = targetedAdvertisingOptOut
+		usnat.SharingOptOutNotice = targetedAdvertisingOptOutNotice
+	}
+	return usnat
+}
+
+func mapSensitiveData(usnat *USNat, sid gppConstants.SectionID, values []byte) {
+	indexes := usnatSensitiveDataIndexes[sid]
+	for i, value := range values {
+		if i < len(indexes) {
+			usnat.SensitiveDataProcessing[indexes[i]] = value
+		}
+	}
+	// California combines racial or ethnic origin, religious beliefs and union membership in a single category
+	if sid == gppConstants.SectionUSPCA && len(values) > 3 {
+		usnat.SensitiveDataProcessing[1] = values[3]
+		usnat.SensitiveDataProcessing[10] = values[3]
+	}
+}
+
+// mapKnownChildConsents maps the consents for consumers from 13 to 16 and younger than 13 years of age,
+// the most restrictive value of each group is used.
+func mapKnownChildConsents(usnat *USNat, from13To16, under13 []byte) {
+	usnat.KnownChildSensitiveDataConsents[0] = mostRestrictiveConsent(from13To16)
+	usnat.KnownChildSensitiveDataConsents[1] = mostRestrictiveConsent(under13)
+}
+
+func mostRestrictiveConsent(values []byte) byte {
+	var result byte
+	for _, value := range values {
+		if value == USNoConsent {
+			return USNoConsent
+		}
+		if value == USConsent {
+			result = USConsent
+		}
+	}
+	return result
+}
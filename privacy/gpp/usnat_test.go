@@ -0,0 +1,168 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadUSNat(t *testing.T) {
+	testCases := []struct {
+		description string
+		section     gpplib.Section
+		normalize   bool
+		expected    USNat
+		expectedOK  bool
+	}{
+		{
+			description: "US national",
+			section: uspnat.USPNAT{
+				SectionID: gppConstants.SectionUSPNAT,
+				CoreSegment: uspnat.USPNATCoreSegment{
+					SharingNotice:                   USNoticeProvided,
+					SaleOptOut:                      USOptedOut,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, USNoConsent, 0, 0, 0, 0},
+					KnownChildSensitiveDataConsents: []byte{USConsent, USNoConsent},
+					PersonalDataConsents:            USConsent,
+					MspaServiceProviderMode:         USServiceProviderMode,
+				},
+				GPCSegment: sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expected: USNat{
+				SectionID:                       gppConstants.SectionUSPNAT,
+				SharingNotice:                   USNoticeProvided,
+				SaleOptOut:                      USOptedOut,
+				SensitiveDataProcessing:         [12]byte{USNatPreciseGeolocation: USNoConsent},
+				KnownChildSensitiveDataConsents: [2]byte{USConsent, USNoConsent},
+				PersonalDataConsents:            USConsent,
+				MspaServiceProviderMode:         USServiceProviderMode,
+				GPC:                             true,
+			},
+			expectedOK: true,
+		},
+		{
+			description: "California",
+			section: uspca.USPCA{
+				SectionID: gppConstants.SectionUSPCA,
+				CoreSegment: uspca.USPCACoreSegment{
+					SharingOptOutNotice:             USNoticeProvided,
+					SharingOptOut:                   USOptedOut,
+					SensitiveDataProcessing:         []byte{0, 0, USNoConsent, USConsent, 0, 0, 0, 0, 0},
+					KnownChildSensitiveDataConsents: []byte{USNoConsent, 0},
+				},
+			},
+			expected: USNat{
+				SectionID:                       gppConstants.SectionUSPCA,
+				SharingOptOutNotice:             USNoticeProvided,
+				SharingOptOut:                   USOptedOut,
+				SensitiveDataProcessing:         [12]byte{0: USConsent, 1: USConsent, 10: USConsent, USNatPreciseGeolocation: USNoConsent},
+				KnownChildSensitiveDataConsents: [2]byte{USNoConsent, 0},
+			},
+			expectedOK: true,
+		},
+		{
+			description: "California normalized",
+			section: uspca.USPCA{
+				SectionID: gppConstants.SectionUSPCA,
+				CoreSegment: uspca.USPCACoreSegment{
+					SharingOptOutNotice: USNoticeProvided,
+					SharingOptOut:       USOptedOut,
+				},
+			},
+			normalize: true,
+			expected: USNat{
+				SectionID:                       gppConstants.SectionUSPCA,
+				SharingOptOutNotice:             USNoticeProvided,
+				SharingOptOut:                   USOptedOut,
+				TargetedAdvertisingOptOutNotice: USNoticeProvided,
+				TargetedAdvertisingOptOut:       USOptedOut,
+			},
+			expectedOK: true,
+		},
+		{
+			description: "Virginia normalized",
+			section: uspva.USPVA{
+				SectionID: gppConstants.SectionUSPVA,
+				CoreSegment: sections.CommonUSCoreSegment{
+					TargetedAdvertisingOptOutNotice: USNoticeProvided,
+					TargetedAdvertisingOptOut:       USDidNotOptOut,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, USNoConsent},
+					KnownChildSensitiveDataConsents: []byte{USNoConsent},
+				},
+			},
+			normalize: true,
+			expected: USNat{
+				SectionID:                       gppConstants.SectionUSPVA,
+				TargetedAdvertisingOptOutNotice: USNoticeProvided,
+				TargetedAdvertisingOptOut:       USDidNotOptOut,
+				SharingOptOutNotice:             USNoticeProvided,
+				SharingOptOut:                   USDidNotOptOut,
+				SensitiveDataProcessing:         [12]byte{USNatPreciseGeolocation: USNoConsent},
+				KnownChildSensitiveDataConsents: [2]byte{0, USNoConsent},
+			},
+			expectedOK: true,
+		},
+		{
+			description: "Utah",
+			section: usput.USPUT{
+				SectionID: gppConstants.SectionUSPUT,
+				CoreSegment: usput.USPUTCoreSegment{
+					SensitiveDataProcessingOptOutNotice: USNoticeNotProvided,
+					SensitiveDataProcessing:             []byte{0, 0, USNoConsent, 0, USConsent, 0, 0, 0},
+					KnownChildSensitiveDataConsents:     USConsent,
+				},
+			},
+			expected: USNat{
+				SectionID:                           gppConstants.SectionUSPUT,
+				SensitiveDataProcessingOptOutNotice: USNoticeNotProvided,
+				SensitiveDataProcessing:             [12]byte{2: USConsent, 3: USNoConsent},
+				KnownChildSensitiveDataConsents:     [2]byte{0, USConsent},
+			},
+			expectedOK: true,
+		},
+		{
+			description: "Connecticut",
+			section: uspct.USPCT{
+				SectionID: gppConstants.SectionUSPCT,
+				CoreSegment: sections.CommonUSCoreSegment{
+					KnownChildSensitiveDataConsents: []byte{USConsent, USConsent, USNoConsent},
+				},
+				GPCSegment: sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expected: USNat{
+				SectionID:                       gppConstants.SectionUSPCT,
+				KnownChildSensitiveDataConsents: [2]byte{USNoConsent, USConsent},
+				GPC:                             true,
+			},
+			expectedOK: true,
+		},
+		{
+			description: "Not a US section",
+			section:     gpplib.GenericSection{},
+			expectedOK:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			actual, ok := ReadUSNat(test.section, test.normalize)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestIsUSSection(t *testing.T) {
+	assert.True(t, IsUSSection(gppConstants.SectionUSPNAT))
+	assert.True(t, IsUSSection(gppConstants.SectionUSPCT))
+	assert.False(t, IsUSSection(gppConstants.SectionUSPV1))
+	assert.False(t, IsUSSection(gppConstants.SectionTCFEU2))
+}
@@ -0,0 +1,120 @@
+package kanonymity
+
+import (
+	"sort"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Policy limits the user.data segments and user.eids sources sent to a bidder to those which are not
+// individually identifying. Zero disables either limit.
+type Policy struct {
+	// MinPopulation is the smallest audience population of a segment or EID source which may be sent.
+	// Segments and sources missing from the populations are treated as having no audience.
+	MinPopulation int
+	// MaxSegments is the largest number of segments which may be sent. The segments with the largest
+	// audiences are kept.
+	MaxSegments int
+}
+
+type segmentRef struct {
+	data       int
+	segment    int
+	population int
+}
+
+// Minimize returns the user with the segments and EID sources which don't meet the policy removed. The
+// given user is never modified, a copy is returned if anything is removed.
+func (p Policy) Minimize(user *openrtb2.User, populations *Populations) (*openrtb2.User, bool) {
+	if user == nil || (len(user.Data) == 0 && len(user.EIDs) == 0) {
+		return user, false
+	}
+
+	data, dataChanged := p.minimizeData(user.Data, populations)
+	eids, eidsChanged := p.minimizeEIDs(user.EIDs, populations)
+	if !dataChanged && !eidsChanged {
+		return user, false
+	}
+
+	userCopy := *user
+	userCopy.Data = data
+	userCopy.EIDs = eids
+	return &userCopy, true
+}
+
+func (p Policy) minimizeData(data []openrtb2.Data, populations *Populations) ([]openrtb2.Data, bool) {
+	var candidates []segmentRef
+	total := 0
+	for i, d := range data {
+		provider := d.ID
+		if provider == "" {
+			provider = d.Name
+		}
+		for j, segment := range d.Segment {
+			total++
+			population := populations.Segment(provider, segment.ID)
+			if p.MinPopulation > 0 && population < p.MinPopulation {
+				continue
+			}
+			candidates = append(candidates, segmentRef{data: i, segment: j, population: population})
+		}
+	}
+
+	if p.MaxSegments > 0 && len(candidates) > p.MaxSegments {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].population > candidates[j].population
+		})
+		candidates = candidates[:p.MaxSegments]
+	}
+
+	if len(candidates) == total {
+		return data, false
+	}
+
+	kept := make(map[int]map[int]struct{}, len(data))
+	for _, c := range candidates {
+		if kept[c.data] == nil {
+			kept[c.data] = make(map[int]struct{})
+		}
+		kept[c.data][c.segment] = struct{}{}
+	}
+
+	var result []openrtb2.Data
+	for i, d := range data {
+		if len(d.Segment) == 0 {
+			result = append(result, d)
+			continue
+		}
+
+		var segments []openrtb2.Segment
+		for j, segment := range d.Segment {
+			if _, ok := kept[i][j]; ok {
+				segments = append(segments, segment)
+			}
+		}
+		if len(segments) == 0 {
+			continue
+		}
+		d.Segment = segments
+		result = append(result, d)
+	}
+	return result, true
+}
+
+func (p Policy) minimizeEIDs(eids []openrtb2.EID, populations *Populations) ([]openrtb2.EID, bool) {
+	if p.MinPopulation <= 0 || len(eids) == 0 {
+		return eids, false
+	}
+
+	var result []openrtb2.EID
+	for _, eid := range eids {
+		if populations.EID(eid.Source) >= p.MinPopulation {
+			result = append(result, eid)
+		}
+	}
+
+	if len(result) == len(eids) {
+		return eids, false
+	}
+	return result, true
+}
@@ -0,0 +1,159 @@
+package kanonymity
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyMinimize(t *testing.T) {
+	populations := &Populations{
+		Segments: map[string]map[string]int{
+			"providerA": {"a1": 50000, "a2": 200, "a3": 9000},
+			"providerB": {"b1": 100000, "b2": 300},
+		},
+		EIDs: map[string]int{
+			"adserver.org": 5000000,
+			"niche.com":    40,
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		policy          Policy
+		user            *openrtb2.User
+		expectedUser    *openrtb2.User
+		expectedChanged bool
+	}{
+		{
+			description:     "nil-user",
+			policy:          Policy{MinPopulation: 1000},
+			user:            nil,
+			expectedUser:    nil,
+			expectedChanged: false,
+		},
+		{
+			description: "no-limits",
+			policy:      Policy{},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a2"}}}},
+				EIDs: []openrtb2.EID{{Source: "niche.com"}},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a2"}}}},
+				EIDs: []openrtb2.EID{{Source: "niche.com"}},
+			},
+			expectedChanged: false,
+		},
+		{
+			description: "all-above-threshold",
+			policy:      Policy{MinPopulation: 1000},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}, {ID: "a3"}}}},
+				EIDs: []openrtb2.EID{{Source: "adserver.org"}},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}, {ID: "a3"}}}},
+				EIDs: []openrtb2.EID{{Source: "adserver.org"}},
+			},
+			expectedChanged: false,
+		},
+		{
+			description: "below-threshold-dropped",
+			policy:      Policy{MinPopulation: 1000},
+			user: &openrtb2.User{
+				ID: "user",
+				Data: []openrtb2.Data{
+					{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}, {ID: "a2"}}},
+					{ID: "providerB", Segment: []openrtb2.Segment{{ID: "b2"}}},
+				},
+				EIDs: []openrtb2.EID{{Source: "adserver.org"}, {Source: "niche.com"}},
+			},
+			expectedUser: &openrtb2.User{
+				ID:   "user",
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}}}},
+				EIDs: []openrtb2.EID{{Source: "adserver.org"}},
+			},
+			expectedChanged: true,
+		},
+		{
+			description: "unknown-segments-and-sources-dropped",
+			policy:      Policy{MinPopulation: 1},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerC", Segment: []openrtb2.Segment{{ID: "c1"}}}},
+				EIDs: []openrtb2.EID{{Source: "unknown.com"}},
+			},
+			expectedUser:    &openrtb2.User{},
+			expectedChanged: true,
+		},
+		{
+			description: "provider-matched-by-name",
+			policy:      Policy{MinPopulation: 1000},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{{Name: "providerB", Segment: []openrtb2.Segment{{ID: "b1"}, {ID: "b2"}}}},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{{Name: "providerB", Segment: []openrtb2.Segment{{ID: "b1"}}}},
+			},
+			expectedChanged: true,
+		},
+		{
+			description: "data-without-segments-kept",
+			policy:      Policy{MinPopulation: 1000},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA"}, {ID: "providerA", Segment: []openrtb2.Segment{{ID: "a2"}}}},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA"}},
+			},
+			expectedChanged: true,
+		},
+		{
+			description: "max-segments-keeps-largest-audiences-in-order",
+			policy:      Policy{MaxSegments: 2},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{
+					{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}}},
+					{ID: "providerB", Segment: []openrtb2.Segment{{ID: "b1"}}},
+				},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{
+					{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a1"}}},
+					{ID: "providerB", Segment: []openrtb2.Segment{{ID: "b1"}}},
+				},
+			},
+			expectedChanged: true,
+		},
+		{
+			description: "threshold-and-max-segments",
+			policy:      Policy{MinPopulation: 1000, MaxSegments: 1},
+			user: &openrtb2.User{
+				Data: []openrtb2.Data{
+					{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a2"}, {ID: "a3"}}},
+					{ID: "providerB", Segment: []openrtb2.Segment{{ID: "b2"}}},
+				},
+			},
+			expectedUser: &openrtb2.User{
+				Data: []openrtb2.Data{{ID: "providerA", Segment: []openrtb2.Segment{{ID: "a3"}}}},
+			},
+			expectedChanged: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var original *openrtb2.User
+			if test.user != nil {
+				userCopy := *test.user
+				original = &userCopy
+			}
+
+			result, changed := test.policy.Minimize(test.user, populations)
+			assert.Equal(t, test.expectedUser, result)
+			assert.Equal(t, test.expectedChanged, changed)
+			assert.Equal(t, original, test.user, "user must not be modified")
+		})
+	}
+}
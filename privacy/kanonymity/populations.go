@@ -0,0 +1,81 @@
+package kanonymity
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// Populations holds the audience population of the user.data segments and user.eids sources, as loaded
+// from a local populations file of the form:
+//
+//	{
+//	  "segments": {"<user.data id or name>": {"<segment id>": 120000}},
+//	  "eids": {"<eid source>": 3500000}
+//	}
+type Populations struct {
+	Segments map[string]map[string]int `json:"segments"`
+	EIDs     map[string]int            `json:"eids"`
+}
+
+// FilePopulations are the populations read from the configured populations file, which is reloaded whenever
+// it changes. A failed reload keeps the populations loaded previously.
+type FilePopulations struct {
+	populations atomic.Pointer[Populations]
+}
+
+// NewFilePopulations loads the configured populations file and reloads it periodically. It returns nil if no
+// file is configured, and an error if the file cannot be loaded.
+func NewFilePopulations(cfg config.KAnonymity) (*FilePopulations, error) {
+	if cfg.PopulationsFile == "" {
+		return nil, nil
+	}
+
+	p := &FilePopulations{}
+	reloader := task.NewFileRunner(cfg.PopulationsFile, p.load)
+	if err := reloader.Run(); err != nil {
+		return nil, fmt.Errorf("unable to load k-anonymity populations file %s: %v", cfg.PopulationsFile, err)
+	}
+	if cfg.RefreshIntervalSeconds > 0 {
+		task.NewTickerTask(cfg.RefreshInterval(), reloader).Start()
+	}
+	return p, nil
+}
+
+// Load returns the populations most recently loaded. Safe to call on nil file populations.
+func (p *FilePopulations) Load() *Populations {
+	if p == nil {
+		return nil
+	}
+	return p.populations.Load()
+}
+
+func (p *FilePopulations) load(data []byte) error {
+	var populations Populations
+	if err := jsonutil.UnmarshalValid(data, &populations); err != nil {
+		return err
+	}
+	p.populations.Store(&populations)
+	return nil
+}
+
+// Segment returns the audience population of a segment from a user.data provider. Unknown segments have
+// a population of zero. Safe to call on nil populations.
+func (p *Populations) Segment(provider, segment string) int {
+	if p == nil {
+		return 0
+	}
+	return p.Segments[provider][segment]
+}
+
+// EID returns the audience population of an EID source. Unknown sources have a population of zero. Safe to
+// call on nil populations.
+func (p *Populations) EID(source string) int {
+	if p == nil {
+		return 0
+	}
+	return p.EIDs[source]
+}
@@ -0,0 +1,98 @@
+package kanonymity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilePopulations(t *testing.T) {
+	dir := t.TempDir()
+
+	validFile := filepath.Join(dir, "valid.json")
+	os.WriteFile(validFile, []byte(`{"segments":{"provider":{"seg1":1500}},"eids":{"adserver.org":20000}}`), 0644)
+
+	malformedFile := filepath.Join(dir, "malformed.json")
+	os.WriteFile(malformedFile, []byte(`{"segments":`), 0644)
+
+	testCases := []struct {
+		description   string
+		filename      string
+		expected      *Populations
+		expectedError bool
+	}{
+		{
+			description: "valid",
+			filename:    validFile,
+			expected: &Populations{
+				Segments: map[string]map[string]int{"provider": {"seg1": 1500}},
+				EIDs:     map[string]int{"adserver.org": 20000},
+			},
+		},
+		{
+			description: "not-configured",
+			filename:    "",
+			expected:    nil,
+		},
+		{
+			description:   "missing",
+			filename:      filepath.Join(dir, "missing.json"),
+			expectedError: true,
+		},
+		{
+			description:   "malformed",
+			filename:      malformedFile,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			populations, err := NewFilePopulations(config.KAnonymity{PopulationsFile: test.filename})
+			if test.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, populations)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, populations.Load())
+			}
+		})
+	}
+}
+
+func TestFilePopulationsReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "populations.json")
+	os.WriteFile(filename, []byte(`{"eids":{"adserver.org":20000}}`), 0644)
+
+	populations, err := NewFilePopulations(config.KAnonymity{PopulationsFile: filename, RefreshIntervalSeconds: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 20000, populations.Load().EID("adserver.org"))
+
+	os.WriteFile(filename, []byte(`{"eids":{"adserver.org":30000}}`), 0644)
+	os.Chtimes(filename, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	assert.Eventually(t, func() bool {
+		return populations.Load().EID("adserver.org") == 30000
+	}, 3*time.Second, 100*time.Millisecond, "Populations must be reloaded when the file changes")
+}
+
+func TestPopulationsLookup(t *testing.T) {
+	populations := &Populations{
+		Segments: map[string]map[string]int{"provider": {"seg1": 1500}},
+		EIDs:     map[string]int{"adserver.org": 20000},
+	}
+
+	assert.Equal(t, 1500, populations.Segment("provider", "seg1"))
+	assert.Equal(t, 0, populations.Segment("provider", "unknown"))
+	assert.Equal(t, 0, populations.Segment("unknown", "seg1"))
+	assert.Equal(t, 20000, populations.EID("adserver.org"))
+	assert.Equal(t, 0, populations.EID("unknown"))
+
+	var nilPopulations *Populations
+	assert.Equal(t, 0, nilPopulations.Segment("provider", "seg1"))
+	assert.Equal(t, 0, nilPopulations.EID("adserver.org"))
+}
@@ -0,0 +1,351 @@
+package minimization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// requiredPaths are always sent to bidders regardless of their profile. They identify the auction and
+// the impressions on offer, carry the bidder params and the privacy signals bidders must honor.
+var requiredPaths = []string{
+	"id",
+	"imp.id",
+	"imp.banner",
+	"imp.video",
+	"imp.audio",
+	"imp.native",
+	"imp.pmp",
+	"imp.tagid",
+	"imp.bidfloor",
+	"imp.bidfloorcur",
+	"imp.secure",
+	"imp.instl",
+	"imp.ext.bidder",
+	"imp.ext.prebid",
+	"at",
+	"tmax",
+	"cur",
+	"test",
+	"regs",
+	"user.consent",
+	"ext.prebid",
+}
+
+// Profile is a compiled allowlist of ORTB paths. Paths are dot separated field names relative to the bid
+// request, for example "site.page" or "device.ua". A path allows the whole value found at it. Arrays are
+// transparent, so "imp.ext.gpid" applies to every imp. Array elements can be selected by the value of one
+// of their fields, as in "user.eids[source=adserver.org]", and "*" matches any field name.
+type Profile struct {
+	root *node
+}
+
+type node struct {
+	all       bool
+	children  map[string]*node
+	selectors []*selector
+}
+
+type selector struct {
+	field string
+	value string
+	node  *node
+}
+
+type segment struct {
+	name     string
+	selector *selector
+}
+
+// NewProfile compiles the allowed paths, adding the paths which are always sent.
+func NewProfile(allowedPaths []string) (*Profile, error) {
+	root := &node{}
+	for _, path := range allowedPaths {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		root.add(segments)
+	}
+	for _, path := range requiredPaths {
+		segments, _ := parsePath(path)
+		root.add(segments)
+	}
+	return &Profile{root: root}, nil
+}
+
+// ValidatePaths reports the first allowed path which is malformed.
+func ValidatePaths(allowedPaths []string) error {
+	for _, path := range allowedPaths {
+		if _, err := parsePath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parsePath(path string) ([]segment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("data minimization path must not be empty")
+	}
+
+	var segments []segment
+	var current strings.Builder
+	inSelector := false
+	for _, r := range path {
+		switch {
+		case r == '[' && !inSelector:
+			inSelector = true
+			current.WriteRune(r)
+		case r == ']' && inSelector:
+			inSelector = false
+			current.WriteRune(r)
+		case r == '.' && !inSelector:
+			s, err := parseSegment(current.String(), path)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, s)
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inSelector {
+		return nil, fmt.Errorf("data minimization path %s has an unterminated selector", path)
+	}
+
+	s, err := parseSegment(current.String(), path)
+	if err != nil {
+		return nil, err
+	}
+	return append(segments, s), nil
+}
+
+func parseSegment(text, path string) (segment, error) {
+	name, rest, hasSelector := strings.Cut(text, "[")
+	if name == "" || strings.ContainsAny(name, "]=") {
+		return segment{}, fmt.Errorf("data minimization path %s has an invalid field name", path)
+	}
+	if !hasSelector {
+		return segment{name: name}, nil
+	}
+
+	if !strings.HasSuffix(rest, "]") {
+		return segment{}, fmt.Errorf("data minimization path %s must end a selector with ]", path)
+	}
+	field, value, hasValue := strings.Cut(strings.TrimSuffix(rest, "]"), "=")
+	if !hasValue || field == "" || value == "" {
+		return segment{}, fmt.Errorf("data minimization path %s must use selectors of the form [field=value]", path)
+	}
+	return segment{name: name, selector: &selector{field: field, value: value}}, nil
+}
+
+func (n *node) add(segments []segment) {
+	current := n
+	for _, s := range segments {
+		if current.children == nil {
+			current.children = make(map[string]*node)
+		}
+		child, ok := current.children[s.name]
+		if !ok {
+			child = &node{}
+			current.children[s.name] = child
+		}
+		current = child
+
+		if s.selector != nil {
+			current = current.selectorNode(s.selector.field, s.selector.value)
+		}
+	}
+	current.all = true
+}
+
+func (n *node) selectorNode(field, value string) *node {
+	for _, s := range n.selectors {
+		if s.field == field && s.value == value {
+			return s.node
+		}
+	}
+	s := &selector{field: field, value: value, node: &node{}}
+	n.selectors = append(n.selectors, s)
+	return s.node
+}
+
+// Apply returns the request holding only the allowed paths and the sorted paths which were stripped. The
+// given request is never modified, it's returned as is when nothing is stripped. Safe to call on a nil
+// profile, which allows everything.
+func (p *Profile) Apply(request *openrtb2.BidRequest) (*openrtb2.BidRequest, []string, error) {
+	if p == nil || request == nil {
+		return request, nil, nil
+	}
+
+	requestJSON, err := jsonutil.Marshal(request)
+	if err != nil {
+		return request, nil, err
+	}
+
+	stripped := make(map[string]struct{})
+	minimizedJSON, _, err := p.root.filter(requestJSON, "", stripped)
+	if err != nil {
+		return request, nil, err
+	}
+	if len(stripped) == 0 {
+		return request, nil, nil
+	}
+
+	minimized := &openrtb2.BidRequest{}
+	if err := jsonutil.Unmarshal(minimizedJSON, minimized); err != nil {
+		return request, nil, err
+	}
+
+	paths := make([]string, 0, len(stripped))
+	for path := range stripped {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return minimized, paths, nil
+}
+
+func (n *node) filter(value json.RawMessage, path string, stripped map[string]struct{}) (json.RawMessage, bool, error) {
+	if n.all {
+		return value, true, nil
+	}
+
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return nil, false, nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		return n.filterObject(trimmed, path, stripped)
+	case '[':
+		return n.filterArray(trimmed, path, stripped)
+	default:
+		stripped[path] = struct{}{}
+		return nil, false, nil
+	}
+}
+
+func (n *node) filterObject(value json.RawMessage, path string, stripped map[string]struct{}) (json.RawMessage, bool, error) {
+	var object map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(value, &object); err != nil {
+		return nil, false, err
+	}
+
+	kept := make(map[string]json.RawMessage, len(object))
+	for key, child := range object {
+		childPath := joinPath(path, key)
+		childNode, ok := n.children[key]
+		if !ok {
+			childNode, ok = n.children["*"]
+		}
+		if !ok {
+			stripped[childPath] = struct{}{}
+			continue
+		}
+
+		filtered, keep, err := childNode.filter(child, childPath, stripped)
+		if err != nil {
+			return nil, false, err
+		}
+		if keep {
+			kept[key] = filtered
+		}
+	}
+
+	if len(kept) == 0 {
+		return nil, false, nil
+	}
+
+	b, err := jsonutil.Marshal(kept)
+	return b, err == nil, err
+}
+
+func (n *node) filterArray(value json.RawMessage, path string, stripped map[string]struct{}) (json.RawMessage, bool, error) {
+	var elements []json.RawMessage
+	if err := jsonutil.Unmarshal(value, &elements); err != nil {
+		return nil, false, err
+	}
+
+	kept := make([]json.RawMessage, 0, len(elements))
+	for _, element := range elements {
+		var (
+			filtered json.RawMessage
+			keep     bool
+			err      error
+		)
+
+		if s := n.matchSelector(element); s != nil {
+			filtered, keep, err = s.node.filter(element, fmt.Sprintf("%s[%s=%s]", path, s.field, s.value), stripped)
+		} else if len(n.children) > 0 {
+			filtered, keep, err = n.filter(element, path, stripped)
+		} else {
+			stripped[n.elementPath(element, path)] = struct{}{}
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+		if keep {
+			kept = append(kept, filtered)
+		}
+	}
+
+	if len(kept) == 0 {
+		return nil, false, nil
+	}
+
+	b, err := jsonutil.Marshal(kept)
+	return b, err == nil, err
+}
+
+func (n *node) matchSelector(element json.RawMessage) *selector {
+	for _, s := range n.selectors {
+		if value, ok := elementField(element, s.field); ok && value == s.value {
+			return s
+		}
+	}
+	return nil
+}
+
+// elementPath names a stripped array element by the field used to select its siblings, if any.
+func (n *node) elementPath(element json.RawMessage, path string) string {
+	if len(n.selectors) > 0 {
+		field := n.selectors[0].field
+		if value, ok := elementField(element, field); ok {
+			return fmt.Sprintf("%s[%s=%s]", path, field, value)
+		}
+	}
+	return path
+}
+
+func elementField(element json.RawMessage, field string) (string, bool) {
+	var object map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(element, &object); err != nil {
+		return "", false
+	}
+	raw, ok := object[field]
+	if !ok {
+		return "", false
+	}
+
+	var s string
+	if err := jsonutil.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	return string(bytes.TrimSpace(raw)), true
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
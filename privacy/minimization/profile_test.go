@@ -0,0 +1,194 @@
+package minimization
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePaths(t *testing.T) {
+	testCases := []struct {
+		description   string
+		paths         []string
+		expectedError string
+	}{
+		{
+			description: "valid",
+			paths:       []string{"site.page", "device.ua", "user.ext.eids[source=adserver.org]", "user.eids[source=x].uids", "imp.*"},
+		},
+		{
+			description:   "empty",
+			paths:         []string{""},
+			expectedError: "data minimization path must not be empty",
+		},
+		{
+			description:   "empty-segment",
+			paths:         []string{"site..page"},
+			expectedError: "data minimization path site..page has an invalid field name",
+		},
+		{
+			description:   "unterminated-selector",
+			paths:         []string{"user.eids[source=x"},
+			expectedError: "data minimization path user.eids[source=x has an unterminated selector",
+		},
+		{
+			description:   "selector-without-value",
+			paths:         []string{"user.eids[source]"},
+			expectedError: "data minimization path user.eids[source] must use selectors of the form [field=value]",
+		},
+		{
+			description:   "text-after-selector",
+			paths:         []string{"user.eids[source=x]uids"},
+			expectedError: "data minimization path user.eids[source=x]uids must end a selector with ]",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidatePaths(test.paths)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+
+			profile, err := NewProfile(test.paths)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.NotNil(t, profile)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, profile)
+			}
+		})
+	}
+}
+
+func TestProfileApply(t *testing.T) {
+	newRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			ID:   "req",
+			TMax: 500,
+			Imp: []openrtb2.Imp{
+				{ID: "imp1", Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300)}, Ext: json.RawMessage(`{"bidder":{"placement":1},"gpid":"/1/home","data":{"pbadslot":"x"}}`)},
+				{ID: "imp2", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}, Ext: json.RawMessage(`{"bidder":{"placement":2}}`)},
+			},
+			Site: &openrtb2.Site{Page: "https://example.com/page", Domain: "example.com", Keywords: "sports"},
+			Device: &openrtb2.Device{
+				UA:  "Mozilla",
+				IP:  "1.2.3.4",
+				Geo: &openrtb2.Geo{Country: "USA", Lat: ptrutil.ToPtr(1.5)},
+			},
+			User: &openrtb2.User{
+				ID:      "user",
+				Consent: "consent",
+				EIDs: []openrtb2.EID{
+					{Source: "adserver.org", UIDs: []openrtb2.UID{{ID: "a", AType: 1}}},
+					{Source: "other.com", UIDs: []openrtb2.UID{{ID: "b"}}},
+				},
+			},
+			Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+		}
+	}
+
+	testCases := []struct {
+		description      string
+		paths            []string
+		expectedRequest  func() *openrtb2.BidRequest
+		expectedStripped []string
+	}{
+		{
+			description: "allow-everything-present",
+			paths:       []string{"imp", "site", "device", "user"},
+			expectedRequest: func() *openrtb2.BidRequest {
+				return newRequest()
+			},
+			expectedStripped: nil,
+		},
+		{
+			description: "leaf-paths",
+			paths:       []string{"site.page", "device.ua", "device.geo.country", "imp.ext.gpid"},
+			expectedRequest: func() *openrtb2.BidRequest {
+				r := newRequest()
+				r.Imp[0].Ext = json.RawMessage(`{"bidder":{"placement":1},"gpid":"/1/home"}`)
+				r.Site = &openrtb2.Site{Page: "https://example.com/page"}
+				r.Device = &openrtb2.Device{UA: "Mozilla", Geo: &openrtb2.Geo{Country: "USA"}}
+				r.User = &openrtb2.User{Consent: "consent"}
+				return r
+			},
+			expectedStripped: []string{
+				"device.geo.lat",
+				"device.ip",
+				"imp.ext.data",
+				"site.domain",
+				"site.keywords",
+				"user.eids",
+				"user.id",
+			},
+		},
+		{
+			description: "eid-selector",
+			paths:       []string{"user.eids[source=adserver.org]"},
+			expectedRequest: func() *openrtb2.BidRequest {
+				r := newRequest()
+				r.Imp[0].Ext = json.RawMessage(`{"bidder":{"placement":1}}`)
+				r.Site = nil
+				r.Device = nil
+				r.User = &openrtb2.User{
+					Consent: "consent",
+					EIDs:    []openrtb2.EID{{Source: "adserver.org", UIDs: []openrtb2.UID{{ID: "a", AType: 1}}}},
+				}
+				return r
+			},
+			expectedStripped: []string{"device", "imp.ext.data", "imp.ext.gpid", "site", "user.eids[source=other.com]", "user.id"},
+		},
+		{
+			description: "selector-with-nested-path",
+			paths:       []string{"user.eids[source=adserver.org].uids.id", "user.eids[source=adserver.org].source", "site", "device", "imp"},
+			expectedRequest: func() *openrtb2.BidRequest {
+				r := newRequest()
+				r.User = &openrtb2.User{
+					Consent: "consent",
+					EIDs:    []openrtb2.EID{{Source: "adserver.org", UIDs: []openrtb2.UID{{ID: "a"}}}},
+				}
+				return r
+			},
+			expectedStripped: []string{"user.eids[source=adserver.org].uids.atype", "user.eids[source=other.com]", "user.id"},
+		},
+		{
+			description: "wildcard",
+			paths:       []string{"device.*", "site", "imp", "user"},
+			expectedRequest: func() *openrtb2.BidRequest {
+				return newRequest()
+			},
+			expectedStripped: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			profile, err := NewProfile(test.paths)
+			assert.NoError(t, err)
+
+			request := newRequest()
+			result, stripped, err := profile.Apply(request)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRequest(), result)
+			assert.Equal(t, test.expectedStripped, stripped)
+			assert.Equal(t, newRequest(), request, "request must not be modified")
+		})
+	}
+}
+
+func TestProfileApplyNil(t *testing.T) {
+	var profile *Profile
+	request := &openrtb2.BidRequest{ID: "req", Site: &openrtb2.Site{Page: "page"}}
+
+	result, stripped, err := profile.Apply(request)
+	assert.NoError(t, err)
+	assert.Nil(t, stripped)
+	assert.Same(t, request, result)
+}
@@ -3,4 +3,13 @@ package privacy
 // Policies contains privacy signals and consent for non-OpenRTB activities.
 type Policies struct {
 	GPPSID []int8
+	GPP    string
+	// GDPR, COPPA and GPC are nil if the signal is unknown.
+	GDPR    *int8
+	COPPA   *int8
+	GPC     *int8
+	Channel string
+	// Country is an ISO-3166-1 alpha-3 country code, Region an ISO-3166-2 region code.
+	Country string
+	Region  string
 }
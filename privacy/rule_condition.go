@@ -1,5 +1,13 @@
 package privacy
 
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/privacy/gpc"
+)
+
 // noClausesDefinedResult represents the default return when there is no matching criteria specified.
 const noClausesDefinedResult = true
 
@@ -8,6 +16,11 @@ type ConditionRule struct {
 	componentName []string
 	componentType []string
 	gppSID        []int8
+	geo           []string
+	gdpr          *int8
+	coppa         *int8
+	gpc           *int8
+	channel       []string
 }
 
 func (r ConditionRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
@@ -23,6 +36,26 @@ func (r ConditionRule) Evaluate(target Component, request ActivityRequest) Activ
 		return ActivityAbstain
 	}
 
+	if matched := evaluateGeo(r.geo, request); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.gdpr, getGDPR(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.coppa, getCOPPA(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.gpc, getGPC(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateChannel(r.channel, request); !matched {
+		return ActivityAbstain
+	}
+
 	return r.result
 }
 
@@ -82,3 +115,154 @@ func getGPPSID(request ActivityRequest) []int8 {
 
 	return nil
 }
+
+// evaluateGeo matches the request country or, for conditions in the "country.region" format,
+// the request country and region. Requests of unknown location do not match.
+func evaluateGeo(geo []string, request ActivityRequest) bool {
+	if len(geo) == 0 {
+		return noClausesDefinedResult
+	}
+
+	country, region := getGeo(request)
+	if country == "" {
+		return false
+	}
+
+	for _, g := range geo {
+		conditionCountry, conditionRegion, hasRegion := strings.Cut(g, ".")
+		if !strings.EqualFold(conditionCountry, country) {
+			continue
+		}
+		if !hasRegion || strings.EqualFold(conditionRegion, region) {
+			return true
+		}
+	}
+	return false
+}
+
+func getGeo(request ActivityRequest) (string, string) {
+	if request.IsPolicies() {
+		return request.policies.Country, normalizeRegion(request.policies.Region)
+	}
+
+	if request.IsBidRequest() {
+		if device := request.bidRequest.Device; device != nil && device.Geo != nil && device.Geo.Country != "" {
+			return device.Geo.Country, normalizeRegion(device.Geo.Region)
+		}
+		if user := request.bidRequest.User; user != nil && user.Geo != nil {
+			return user.Geo.Country, normalizeRegion(user.Geo.Region)
+		}
+	}
+
+	return "", ""
+}
+
+// normalizeRegion removes the country prefix of ISO-3166-2 region codes, e.g. "US-CA".
+func normalizeRegion(region string) string {
+	if i := strings.LastIndex(region, "-"); i >= 0 {
+		return region[i+1:]
+	}
+	return region
+}
+
+// evaluateSignal matches the request signal, unknown signals do not match.
+func evaluateSignal(condition *int8, signal *int8) bool {
+	if condition == nil {
+		return noClausesDefinedResult
+	}
+
+	return signal != nil && *signal == *condition
+}
+
+func getGDPR(request ActivityRequest) *int8 {
+	if request.IsPolicies() {
+		return request.policies.GDPR
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.GDPR
+	}
+
+	return nil
+}
+
+func getCOPPA(request ActivityRequest) *int8 {
+	if request.IsPolicies() {
+		return request.policies.COPPA
+	}
+
+	if request.IsBidRequest() {
+		var coppa int8
+		if request.bidRequest.Regs != nil {
+			coppa = request.bidRequest.Regs.COPPA
+		}
+		return &coppa
+	}
+
+	return nil
+}
+
+func getGPC(request ActivityRequest) *int8 {
+	if request.IsPolicies() {
+		return request.policies.GPC
+	}
+
+	if request.IsBidRequest() {
+		policy, err := gpc.ReadFromRequestWrapper(request.bidRequest, request.gpcHeader)
+		if err != nil {
+			return nil
+		}
+		if signal, err := strconv.ParseInt(policy.Signal, 10, 8); err == nil {
+			gpcSignal := int8(signal)
+			return &gpcSignal
+		}
+	}
+
+	return nil
+}
+
+func evaluateChannel(channels []string, request ActivityRequest) bool {
+	if len(channels) == 0 {
+		return noClausesDefinedResult
+	}
+
+	channel := getChannel(request)
+	if channel == "" {
+		return false
+	}
+
+	for _, c := range channels {
+		if strings.EqualFold(c, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// getChannel returns the ext.prebid.channel.name of the request or, if not specified,
+// the channel derived from the distribution channel object of the request.
+func getChannel(request ActivityRequest) string {
+	if request.IsPolicies() {
+		return request.policies.Channel
+	}
+
+	if !request.IsBidRequest() {
+		return ""
+	}
+
+	if reqExt, err := request.bidRequest.GetRequestExt(); err == nil {
+		if prebid := reqExt.GetPrebid(); prebid != nil && prebid.Channel != nil && prebid.Channel.Name != "" {
+			return prebid.Channel.Name
+		}
+	}
+
+	switch {
+	case request.bidRequest.App != nil:
+		return string(config.ChannelApp)
+	case request.bidRequest.DOOH != nil:
+		return string(config.ChannelDOOH)
+	case request.bidRequest.Site != nil:
+		return string(config.ChannelWeb)
+	}
+	return ""
+}
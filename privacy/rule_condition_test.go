@@ -1,10 +1,12 @@
 package privacy
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -276,3 +278,225 @@ func TestGetGPPSID(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateGeo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		condition []string
+		request   ActivityRequest
+		expected  bool
+	}{
+		{
+			name:      "no-condition",
+			condition: nil,
+			request:   ActivityRequest{},
+			expected:  true,
+		},
+		{
+			name:      "country-match",
+			condition: []string{"CAN", "usa"},
+			request:   NewRequestFromPolicies(Policies{Country: "USA", Region: "NY"}),
+			expected:  true,
+		},
+		{
+			name:      "country-nomatch",
+			condition: []string{"CAN"},
+			request:   NewRequestFromPolicies(Policies{Country: "USA"}),
+			expected:  false,
+		},
+		{
+			name:      "region-match",
+			condition: []string{"USA.CA"},
+			request:   NewRequestFromPolicies(Policies{Country: "USA", Region: "ca"}),
+			expected:  true,
+		},
+		{
+			name:      "region-nomatch",
+			condition: []string{"USA.CA"},
+			request:   NewRequestFromPolicies(Policies{Country: "USA", Region: "NY"}),
+			expected:  false,
+		},
+		{
+			name:      "unknown-geo",
+			condition: []string{"USA"},
+			request:   NewRequestFromPolicies(Policies{}),
+			expected:  false,
+		},
+		{
+			name:      "request-device-geo",
+			condition: []string{"USA.CA"},
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "US-CA"}},
+				User:   &openrtb2.User{Geo: &openrtb2.Geo{Country: "CAN"}},
+			}}),
+			expected: true,
+		},
+		{
+			name:      "request-user-geo",
+			condition: []string{"CAN"},
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				User: &openrtb2.User{Geo: &openrtb2.Geo{Country: "CAN"}},
+			}}),
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			actualResult := evaluateGeo(test.condition, test.request)
+			assert.Equal(t, test.expected, actualResult)
+		})
+	}
+}
+
+func TestEvaluateRegs(t *testing.T) {
+	gdprApplies := ptrutil.ToPtr[int8](1)
+	gdprNotApplies := ptrutil.ToPtr[int8](0)
+
+	testCases := []struct {
+		name     string
+		gdpr     *int8
+		coppa    *int8
+		gpc      *int8
+		request  ActivityRequest
+		expected ActivityResult
+	}{
+		{
+			name:     "gdpr-match",
+			gdpr:     gdprApplies,
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)}}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "gdpr-nomatch",
+			gdpr:     gdprNotApplies,
+			request:  NewRequestFromPolicies(Policies{GDPR: ptrutil.ToPtr[int8](1)}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "gdpr-unknown",
+			gdpr:     gdprNotApplies,
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "coppa-not-set-in-request",
+			coppa:    ptrutil.ToPtr[int8](0),
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "coppa-match",
+			coppa:    ptrutil.ToPtr[int8](1),
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{COPPA: 1}}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "coppa-unknown-in-policies",
+			coppa:    ptrutil.ToPtr[int8](0),
+			request:  NewRequestFromPolicies(Policies{}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "gpc-request-match",
+			gpc:      ptrutil.ToPtr[int8](1),
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"1"}`)}}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "gpc-request-nomatch",
+			gpc:      ptrutil.ToPtr[int8](1),
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpc":"0"}`)}}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "gpc-request-unknown",
+			gpc:      ptrutil.ToPtr[int8](0),
+			request:  NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "gpc-policies-match",
+			gpc:      ptrutil.ToPtr[int8](1),
+			request:  NewRequestFromPolicies(Policies{GPC: ptrutil.ToPtr[int8](1)}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "gdpr-and-coppa-both-required",
+			gdpr:     gdprApplies,
+			coppa:    ptrutil.ToPtr[int8](1),
+			request:  NewRequestFromPolicies(Policies{GDPR: ptrutil.ToPtr[int8](1), COPPA: ptrutil.ToPtr[int8](0)}),
+			expected: ActivityAbstain,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule := ConditionRule{result: ActivityDeny, gdpr: test.gdpr, coppa: test.coppa, gpc: test.gpc}
+			actualResult := rule.Evaluate(Component{Type: "bidder", Name: "bidderA"}, test.request)
+			assert.Equal(t, test.expected, actualResult)
+		})
+	}
+}
+
+func TestEvaluateChannel(t *testing.T) {
+	testCases := []struct {
+		name      string
+		condition []string
+		request   ActivityRequest
+		expected  bool
+	}{
+		{
+			name:      "no-condition",
+			condition: nil,
+			request:   ActivityRequest{},
+			expected:  true,
+		},
+		{
+			name:      "request-ext-channel",
+			condition: []string{"AMP"},
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Site: &openrtb2.Site{},
+				Ext:  json.RawMessage(`{"prebid":{"channel":{"name":"amp"}}}`),
+			}}),
+			expected: true,
+		},
+		{
+			name:      "request-app",
+			condition: []string{"app"},
+			request:   NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{App: &openrtb2.App{}}}),
+			expected:  true,
+		},
+		{
+			name:      "request-site",
+			condition: []string{"app", "dooh"},
+			request:   NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Site: &openrtb2.Site{}}}),
+			expected:  false,
+		},
+		{
+			name:      "request-dooh",
+			condition: []string{"dooh"},
+			request:   NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{DOOH: &openrtb2.DOOH{}}}),
+			expected:  true,
+		},
+		{
+			name:      "policies-unknown-channel",
+			condition: []string{"web"},
+			request:   NewRequestFromPolicies(Policies{}),
+			expected:  false,
+		},
+		{
+			name:      "policies-channel",
+			condition: []string{"web"},
+			request:   NewRequestFromPolicies(Policies{Channel: "web"}),
+			expected:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			actualResult := evaluateChannel(test.condition, test.request)
+			assert.Equal(t, test.expected, actualResult)
+		})
+	}
+}
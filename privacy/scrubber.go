@@ -74,6 +74,23 @@ func ScrubEIDs(reqWrapper *openrtb_ext.RequestWrapper) error {
 	return scrubUserExt(reqWrapper, "eids")
 }
 
+// ScrubChildDirected removes the eids, user data and geo of the request, on top of the COPPA scrubbing, for
+// the stricter child-directed profile
+func ScrubChildDirected(reqWrapper *openrtb_ext.RequestWrapper) error {
+	if reqWrapper.User != nil {
+		reqWrapper.User.EIDs = nil
+		reqWrapper.User.Data = nil
+	}
+	if err := scrubUserExt(reqWrapper, "eids"); err != nil {
+		return err
+	}
+	if err := scrubUserExt(reqWrapper, "data"); err != nil {
+		return err
+	}
+	scrubGeoFull(reqWrapper)
+	return nil
+}
+
 func ScrubTID(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.Source != nil {
 		reqWrapper.Source.TID = ""
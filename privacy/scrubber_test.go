@@ -173,6 +173,50 @@ func TestScrubEids(t *testing.T) {
 	}
 }
 
+func TestScrubChildDirected(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userIn         *openrtb2.User
+		deviceIn       *openrtb2.Device
+		expectedUser   *openrtb2.User
+		expectedDevice *openrtb2.Device
+	}{
+		{
+			name:           "nil",
+			userIn:         nil,
+			deviceIn:       nil,
+			expectedUser:   nil,
+			expectedDevice: nil,
+		},
+		{
+			name: "eids_data_and_geo",
+			userIn: &openrtb2.User{
+				ID:   "ID",
+				EIDs: []openrtb2.EID{{Source: "source"}},
+				Data: []openrtb2.Data{{ID: "data"}},
+				Geo:  &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Country: "US"},
+				Ext:  json.RawMessage(`{"eids":[{"source":"source"}],"data":{"key":"value"},"test":1}`),
+			},
+			deviceIn: &openrtb2.Device{UA: "UA", Geo: &openrtb2.Geo{Lon: ptrutil.ToPtr(678.89)}},
+			expectedUser: &openrtb2.User{
+				ID:  "ID",
+				Geo: &openrtb2.Geo{},
+				Ext: json.RawMessage(`{"test":1}`),
+			},
+			expectedDevice: &openrtb2.Device{UA: "UA", Geo: &openrtb2.Geo{}},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: test.userIn, Device: test.deviceIn}}
+			assert.NoError(t, ScrubChildDirected(brw))
+			brw.RebuildRequest()
+			assert.Equal(t, test.expectedUser, brw.User)
+			assert.Equal(t, test.expectedDevice, brw.Device)
+		})
+	}
+}
+
 func TestScrubTID(t *testing.T) {
 	testCases := []struct {
 		name           string
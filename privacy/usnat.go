@@ -0,0 +1,158 @@
+package privacy
+
+import (
+	"slices"
+	"sync"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v2/config"
+	gppPolicy "github.com/prebid/prebid-server/v2/privacy/gpp"
+)
+
+// usnatActivities lists the activities governed by the GPP US national and state sections.
+var usnatActivities = []Activity{
+	ActivitySyncUser,
+	ActivityTransmitUserFPD,
+	ActivityTransmitPreciseGeo,
+	ActivityTransmitTIDs,
+	ActivityTransmitEIDs,
+}
+
+// USNatRule denies the activity if the applicable GPP US sections of the request do not permit it,
+// it abstains otherwise.
+type USNatRule struct {
+	activity Activity
+	sections *usnatSections
+}
+
+func (r USNatRule) Evaluate(_ Component, request ActivityRequest) ActivityResult {
+	for _, section := range r.sections.read(request) {
+		if !allowedByUSNat(r.activity, section) {
+			return ActivityDeny
+		}
+	}
+	return ActivityAbstain
+}
+
+func allowedByUSNat(activity Activity, s gppPolicy.USNat) bool {
+	switch activity {
+	case ActivitySyncUser:
+		return !optedOutOfSaleOrSharing(s) && !knownChildWithoutConsent(s)
+	case ActivityTransmitUserFPD:
+		return !optedOutOfSaleOrSharing(s) && !knownChildWithoutConsent(s) && !sensitiveDataRestricted(s) &&
+			s.PersonalDataConsents != gppPolicy.USNoConsent
+	case ActivityTransmitEIDs:
+		return !optedOutOfSaleOrSharing(s) && !knownChildWithoutConsent(s) && s.PersonalDataConsents != gppPolicy.USNoConsent
+	case ActivityTransmitPreciseGeo:
+		return s.MspaServiceProviderMode != gppPolicy.USServiceProviderMode && !knownChildWithoutConsent(s) &&
+			s.SensitiveDataProcessingOptOutNotice != gppPolicy.USNoticeNotProvided &&
+			s.SensitiveDataLimitUseNotice != gppPolicy.USNoticeNotProvided &&
+			s.SensitiveDataProcessing[gppPolicy.USNatPreciseGeolocation] != gppPolicy.USNoConsent
+	case ActivityTransmitTIDs:
+		return !optedOutOfSaleOrSharing(s)
+	}
+	return true
+}
+
+// optedOutOfSaleOrSharing returns true if the user opted out of the sale, sharing or targeted advertising,
+// was not given the notice of these opt-outs, signaled the Global Privacy Control or if the transaction is
+// in service provider mode.
+func optedOutOfSaleOrSharing(s gppPolicy.USNat) bool {
+	return s.MspaServiceProviderMode == gppPolicy.USServiceProviderMode ||
+		s.GPC ||
+		s.SaleOptOut == gppPolicy.USOptedOut ||
+		s.SharingOptOut == gppPolicy.USOptedOut ||
+		s.TargetedAdvertisingOptOut == gppPolicy.USOptedOut ||
+		s.SharingNotice == gppPolicy.USNoticeNotProvided ||
+		s.SaleOptOutNotice == gppPolicy.USNoticeNotProvided ||
+		s.SharingOptOutNotice == gppPolicy.USNoticeNotProvided ||
+		s.TargetedAdvertisingOptOutNotice == gppPolicy.USNoticeNotProvided
+}
+
+func knownChildWithoutConsent(s gppPolicy.USNat) bool {
+	return slices.Contains(s.KnownChildSensitiveDataConsents[:], gppPolicy.USNoConsent)
+}
+
+// sensitiveDataRestricted returns true if the processing of any sensitive data category other than
+// precise geolocation is restricted, the latter is governed by the transmitPreciseGeo activity.
+func sensitiveDataRestricted(s gppPolicy.USNat) bool {
+	if s.SensitiveDataProcessingOptOutNotice == gppPolicy.USNoticeNotProvided || s.SensitiveDataLimitUseNotice == gppPolicy.USNoticeNotProvided {
+		return true
+	}
+	for i, value := range s.SensitiveDataProcessing {
+		if i != gppPolicy.USNatPreciseGeolocation && value == gppPolicy.USNoConsent {
+			return true
+		}
+	}
+	return false
+}
+
+// usnatSections reads the applicable GPP US sections of the requests. The sections of the most recent
+// GPP string are kept as the activities of a request are usually evaluated one after another.
+type usnatSections struct {
+	skipSIDs  []int8
+	normalize bool
+
+	mutex    sync.Mutex
+	gpp      string
+	sections []gppPolicy.USNat
+}
+
+func newUSNatSections(cfg config.AccountUSNat) *usnatSections {
+	return &usnatSections{skipSIDs: cfg.SkipSIDs, normalize: cfg.NormalizeStates}
+}
+
+func (u *usnatSections) read(request ActivityRequest) []gppPolicy.USNat {
+	gpp, sids := getGPP(request)
+	if gpp == "" {
+		return nil
+	}
+
+	u.mutex.Lock()
+	if gpp != u.gpp || u.sections == nil {
+		u.gpp = gpp
+		u.sections = u.parse(gpp)
+	}
+	sections := u.sections
+	u.mutex.Unlock()
+
+	// the sections listed in the GPP string only apply if they are listed in the gpp_sid as well
+	if len(sids) == 0 {
+		return sections
+	}
+	applicable := make([]gppPolicy.USNat, 0, len(sections))
+	for _, section := range sections {
+		if gppPolicy.IsSIDInList(sids, section.SectionID) {
+			applicable = append(applicable, section)
+		}
+	}
+	return applicable
+}
+
+// parse returns the US sections of the GPP string which are not skipped. Sections which could not be parsed are ignored.
+func (u *usnatSections) parse(gpp string) []gppPolicy.USNat {
+	container, _ := gpplib.Parse(gpp)
+
+	sections := make([]gppPolicy.USNat, 0, len(container.Sections))
+	for _, section := range container.Sections {
+		if section == nil || !gppPolicy.IsUSSection(section.GetID()) || gppPolicy.IsSIDInList(u.skipSIDs, section.GetID()) {
+			continue
+		}
+		if usnat, ok := gppPolicy.ReadUSNat(section, u.normalize); ok {
+			sections = append(sections, usnat)
+		}
+	}
+	return sections
+}
+
+func getGPP(request ActivityRequest) (string, []int8) {
+	if request.IsPolicies() {
+		return request.policies.GPP, request.policies.GPPSID
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.GPP, request.bidRequest.Regs.GPPSID
+	}
+
+	return "", nil
+}
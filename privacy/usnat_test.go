@@ -0,0 +1,196 @@
+package privacy
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSNatActivityControl(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usnat    config.AccountUSNat
+		gpp      string
+		gppSID   []int8
+		expected map[Activity]bool
+	}{
+		{
+			name:  "no_opt_out",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(*uspnat.USPNATCoreSegment) {})),
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "sale_opt_out",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.SaleOptOut = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: false, ActivityTransmitUserFPD: false, ActivityTransmitEIDs: false, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: false,
+			},
+		},
+		{
+			name:  "precise_geolocation_restricted",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.SensitiveDataProcessing[7] = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: false, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "health_data_restricted",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.SensitiveDataProcessing[2] = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: false, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "known_child_without_consent",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.KnownChildSensitiveDataConsents[1] = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: false, ActivityTransmitUserFPD: false, ActivityTransmitEIDs: false, ActivityTransmitPreciseGeo: false, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "service_provider_mode",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.MspaServiceProviderMode = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: false, ActivityTransmitUserFPD: false, ActivityTransmitEIDs: false, ActivityTransmitPreciseGeo: false, ActivityTransmitTIDs: false,
+			},
+		},
+		{
+			name:  "disabled",
+			usnat: config.AccountUSNat{},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.MspaServiceProviderMode = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "section_skipped",
+			usnat: config.AccountUSNat{Enabled: true, SkipSIDs: []int8{7}},
+			gpp:   encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.MspaServiceProviderMode = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:   "section_not_in_gpp_sid",
+			usnat:  config.AccountUSNat{Enabled: true},
+			gpp:    encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.MspaServiceProviderMode = 1 })),
+			gppSID: []int8{8},
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "california_sharing_opt_out",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   encodeTestGPP(t, testUSCA(func(c *uspca.USPCACoreSegment) { c.SharingOptOut = 1 })),
+			expected: map[Activity]bool{
+				ActivitySyncUser: false, ActivityTransmitUserFPD: false, ActivityTransmitEIDs: false, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: false,
+			},
+		},
+		{
+			name:   "california_precise_geolocation_restricted",
+			usnat:  config.AccountUSNat{Enabled: true, NormalizeStates: true},
+			gpp:    encodeTestGPP(t, testUSCA(func(c *uspca.USPCACoreSegment) { c.SensitiveDataProcessing[2] = 1 })),
+			gppSID: []int8{8},
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: false, ActivityTransmitTIDs: true,
+			},
+		},
+		{
+			name:  "invalid_gpp",
+			usnat: config.AccountUSNat{Enabled: true},
+			gpp:   "invalid",
+			expected: map[Activity]bool{
+				ActivitySyncUser: true, ActivityTransmitUserFPD: true, ActivityTransmitEIDs: true, ActivityTransmitPreciseGeo: true, ActivityTransmitTIDs: true,
+			},
+		},
+	}
+
+	component := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ac := NewActivityControl(&config.AccountPrivacy{USNat: test.usnat})
+
+			policiesRequest := NewRequestFromPolicies(Policies{GPP: test.gpp, GPPSID: test.gppSID})
+			bidRequest := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: test.gpp, GPPSID: test.gppSID},
+			}})
+			for activity, expected := range test.expected {
+				assert.Equal(t, expected, ac.Allow(activity, component, policiesRequest), "policies %s", activity)
+				assert.Equal(t, expected, ac.Allow(activity, component, bidRequest), "bid request %s", activity)
+			}
+			assert.True(t, ac.Allow(ActivityFetchBids, component, bidRequest), "fetchBids is not governed by the US sections")
+		})
+	}
+}
+
+func TestUSNatRuleBeforeAccountRules(t *testing.T) {
+	ac := NewActivityControl(&config.AccountPrivacy{
+		USNat: config.AccountUSNat{Enabled: true},
+		AllowActivities: &config.AllowActivities{
+			SyncUser: config.Activity{Rules: []config.ActivityRule{{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}}}},
+		},
+	})
+
+	optedOut := NewRequestFromPolicies(Policies{GPP: encodeTestGPP(t, testUSNat(func(c *uspnat.USPNATCoreSegment) { c.SaleOptOut = 1 }))})
+	assert.False(t, ac.Allow(ActivitySyncUser, Component{Type: ComponentTypeBidder, Name: "bidderA"}, optedOut), "US opt-out must take precedence over account rules")
+
+	notOptedOut := NewRequestFromPolicies(Policies{GPP: encodeTestGPP(t, testUSNat(func(*uspnat.USPNATCoreSegment) {}))})
+	assert.True(t, ac.Allow(ActivitySyncUser, Component{Type: ComponentTypeBidder, Name: "bidderA"}, notOptedOut))
+}
+
+func testUSNat(modify func(*uspnat.USPNATCoreSegment)) gpplib.Section {
+	core := uspnat.USPNATCoreSegment{
+		Version:                         1,
+		SharingNotice:                   1,
+		SaleOptOutNotice:                1,
+		SharingOptOutNotice:             1,
+		TargetedAdvertisingOptOutNotice: 1,
+		SaleOptOut:                      2,
+		SharingOptOut:                   2,
+		TargetedAdvertisingOptOut:       2,
+		SensitiveDataProcessing:         make([]byte, 12),
+		KnownChildSensitiveDataConsents: make([]byte, 2),
+		MspaServiceProviderMode:         2,
+	}
+	modify(&core)
+	return uspnat.USPNAT{SectionID: gppConstants.SectionUSPNAT, CoreSegment: core, GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1}}
+}
+
+func testUSCA(modify func(*uspca.USPCACoreSegment)) gpplib.Section {
+	core := uspca.USPCACoreSegment{
+		Version:                         1,
+		SaleOptOutNotice:                1,
+		SharingOptOutNotice:             1,
+		SaleOptOut:                      2,
+		SharingOptOut:                   2,
+		SensitiveDataProcessing:         make([]byte, 9),
+		KnownChildSensitiveDataConsents: make([]byte, 2),
+		MspaServiceProviderMode:         2,
+	}
+	modify(&core)
+	return uspca.USPCA{SectionID: gppConstants.SectionUSPCA, CoreSegment: core, GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1}}
+}
+
+func encodeTestGPP(t *testing.T, sections ...gpplib.Section) string {
+	gpp, err := gpplib.Encode(sections)
+	require.NoError(t, err)
+	return gpp
+}
@@ -1,5 +1,7 @@
 package usersync
 
+import "sort"
+
 // bidderChooser determines which bidders to consider for user syncing.
 type bidderChooser interface {
 	// choose returns an ordered collection of potentially non-unique bidders.
@@ -58,3 +60,49 @@ func (c standardBidderChooser) shuffledAppend(a, b []string) []string {
 	c.shuffler.shuffle(a[startIndex:])
 	return a
 }
+
+// yieldBidderChooser orders the bidders by the value of a synced UID to each of them, highest first. Bidders of
+// unknown value follow in random order. Requested bidders are still considered first, but the priority groups
+// of cooperative syncing are replaced by the values.
+type yieldBidderChooser struct {
+	shuffler shuffler
+	values   SyncValues
+	account  string
+	country  string
+}
+
+func (c yieldBidderChooser) choose(requested, available []string, cooperative Cooperative) []string {
+	if cooperative.Enabled {
+		bidders := make([]string, 0, len(requested)+len(available))
+		bidders = c.sortedAppend(bidders, requested)
+		return c.sortedAppend(bidders, available)
+	}
+
+	if len(requested) == 0 {
+		return c.sortedAppend(make([]string, 0, len(available)), available)
+	}
+
+	return c.sortedAppend(make([]string, 0, len(requested)), requested)
+}
+
+func (c yieldBidderChooser) sortedAppend(a, b []string) []string {
+	startIndex := len(a)
+	a = append(a, b...)
+	bidders := a[startIndex:]
+
+	// shuffle first so bidders of equal or unknown value keep a random order
+	c.shuffler.shuffle(bidders)
+
+	values := make(map[string]float64, len(bidders))
+	known := make(map[string]bool, len(bidders))
+	for _, bidder := range bidders {
+		values[bidder], known[bidder] = c.values.Value(c.account, c.country, bidder)
+	}
+	sort.SliceStable(bidders, func(i, j int) bool {
+		if known[bidders[i]] != known[bidders[j]] {
+			return known[bidders[i]]
+		}
+		return values[bidders[i]] > values[bidders[j]]
+	})
+	return a
+}
@@ -259,6 +259,51 @@ func TestBidderChooserShuffledAppend(t *testing.T) {
 }
 
 // copySlice returns a cloned a slice or nil.
+func TestYieldBidderChooserChoose(t *testing.T) {
+	values := fakeSyncValues{"a1": 1, "a2": 3, "r1": 0.5, "r2": 2}
+	available := []string{"a1", "a2", "a3", "a4"}
+
+	testCases := []struct {
+		description      string
+		givenRequested   []string
+		givenCooperative Cooperative
+		expected         []string
+	}{
+		{
+			description:      "No Coop - Available By Value",
+			givenRequested:   nil,
+			givenCooperative: Cooperative{Enabled: false},
+			expected:         []string{"a2", "a1", "a4", "a3"},
+		},
+		{
+			description:      "No Coop - Requested By Value",
+			givenRequested:   []string{"r1", "r2", "r3"},
+			givenCooperative: Cooperative{Enabled: false},
+			expected:         []string{"r2", "r1", "r3"},
+		},
+		{
+			description:      "Coop - Requested First, Priority Groups Ignored",
+			givenRequested:   []string{"r1", "r2"},
+			givenCooperative: Cooperative{Enabled: true, PriorityGroups: [][]string{{"a4"}}},
+			expected:         []string{"r2", "r1", "a2", "a1", "a4", "a3"},
+		},
+	}
+
+	for _, test := range testCases {
+		chooser := yieldBidderChooser{shuffler: reverseShuffler{}, values: values, account: "account", country: "USA"}
+		result := chooser.choose(test.givenRequested, available, test.givenCooperative)
+		assert.Equal(t, test.expected, result, test.description)
+	}
+}
+
+// fakeSyncValues holds the same value of each bidder for any account and country.
+type fakeSyncValues map[string]float64
+
+func (v fakeSyncValues) Value(account, country, bidder string) (float64, bool) {
+	value, ok := v[bidder]
+	return value, ok
+}
+
 func copySlice(a []string) []string {
 	var aCopy []string
 	if a != nil {
@@ -14,8 +14,9 @@ type Chooser interface {
 	Choose(request Request, cookie *Cookie) Result
 }
 
-// NewChooser returns a new instance of the standard chooser implementation.
-func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]struct{}, bidderInfo map[string]config.BidderInfo) Chooser {
+// NewChooser returns a new instance of the standard chooser implementation. The sync values, which may be nil,
+// order the bidders of requests using the yield prioritization.
+func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]struct{}, bidderInfo map[string]config.BidderInfo, syncValues SyncValues) Chooser {
 	bidders := make([]string, 0, len(bidderSyncerLookup))
 
 	for k := range bidderSyncerLookup {
@@ -26,6 +27,7 @@ func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]st
 		bidderSyncerLookup:       bidderSyncerLookup,
 		biddersAvailable:         bidders,
 		bidderChooser:            standardBidderChooser{shuffler: randomShuffler{}},
+		syncValues:               syncValues,
 		normalizeValidBidderName: openrtb_ext.NormalizeBidderName,
 		biddersKnown:             biddersKnown,
 		bidderInfo:               bidderInfo,
@@ -41,6 +43,16 @@ type Request struct {
 	SyncTypeFilter SyncTypeFilter
 	GPPSID         string
 	Debug          bool
+	Prioritization Prioritization
+}
+
+// Prioritization specifies how the bidders to sync are ordered for a given request.
+type Prioritization struct {
+	// Yield orders the bidders by the value of a synced UID to each of them for the account and the country,
+	// instead of by the priority groups and at random.
+	Yield   bool
+	Account string
+	Country string
 }
 
 // Cooperative specifies the settings for cooperative syncing for a given request, where bidders
@@ -103,6 +115,10 @@ const (
 
 	// StatusBlockedByDisabledUsersync refers to a bidder who won't be synced because it's been disabled in its config by the host
 	StatusBlockedByDisabledUsersync
+
+	// StatusRateLimited refers to a bidder who won't be synced because the user has already been offered as many syncs as
+	// its config allows without any succeeding
+	StatusRateLimited
 )
 
 // Privacy determines which privacy policies will be enforced for a user sync request.
@@ -119,6 +135,7 @@ type standardChooser struct {
 	bidderSyncerLookup       map[string]Syncer
 	biddersAvailable         []string
 	bidderChooser            bidderChooser
+	syncValues               SyncValues
 	normalizeValidBidderName func(name string) (openrtb_ext.BidderName, bool)
 	biddersKnown             map[string]struct{}
 	bidderInfo               map[string]config.BidderInfo
@@ -142,7 +159,7 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	biddersEvaluated := make([]BidderEvaluation, 0)
 	syncersChosen := make([]SyncerChoice, 0)
 
-	bidders := c.bidderChooser.choose(request.Bidders, c.biddersAvailable, request.Cooperative)
+	bidders := c.bidderChooserFor(request.Prioritization).choose(request.Bidders, c.biddersAvailable, request.Cooperative)
 	for i := 0; i < len(bidders) && (limitDisabled || len(syncersChosen) < request.Limit); i++ {
 		if _, ok := biddersSeen[bidders[i]]; ok {
 			continue
@@ -159,6 +176,20 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	return Result{Status: StatusOK, BiddersEvaluated: biddersEvaluated, SyncersChosen: syncersChosen}
 }
 
+// bidderChooserFor returns the bidder chooser implementing the prioritization of the request. The standard one
+// is used if no sync values are available.
+func (c standardChooser) bidderChooserFor(prioritization Prioritization) bidderChooser {
+	if !prioritization.Yield || c.syncValues == nil {
+		return c.bidderChooser
+	}
+	return yieldBidderChooser{
+		shuffler: randomShuffler{},
+		values:   c.syncValues,
+		account:  prioritization.Account,
+		country:  prioritization.Country,
+	}
+}
+
 func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie, GPPSID string) (Syncer, BidderEvaluation) {
 	bidderNormalized, exists := c.normalizeValidBidderName(bidder)
 	if !exists {
@@ -184,7 +215,7 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 		return nil, BidderEvaluation{Status: StatusTypeNotSupported, Bidder: bidder, SyncerKey: syncer.Key()}
 	}
 
-	if cookie.HasLiveSync(syncer.Key()) {
+	if cookie.HasLiveSync(syncer.Key()) && !c.isResyncDue(bidder, syncer.Key(), cookie) {
 		return nil, BidderEvaluation{Status: StatusAlreadySynced, Bidder: bidder, SyncerKey: syncer.Key()}
 	}
 
@@ -213,5 +244,17 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 		}
 	}
 
+	if syncerConfig := c.bidderInfo[bidder].Syncer; syncerConfig.MaxSyncAttempts() > 0 {
+		if cookie.SyncAttemptCount(syncer.Key(), syncerConfig.SyncAttemptWindow()) >= syncerConfig.MaxSyncAttempts() {
+			return nil, BidderEvaluation{Status: StatusRateLimited, Bidder: bidder, SyncerKey: syncer.Key()}
+		}
+	}
+
 	return syncer, BidderEvaluation{Status: StatusOK, Bidder: bidder, SyncerKey: syncer.Key()}
 }
+
+// isResyncDue returns true if the live UID of the syncer key is older than the bidder allows.
+func (c standardChooser) isResyncDue(bidder string, syncerKey string, cookie *Cookie) bool {
+	maxAge := c.bidderInfo[bidder].Syncer.ResyncMaxAge()
+	return maxAge > 0 && cookie.IsSyncOlderThan(syncerKey, maxAge)
+}
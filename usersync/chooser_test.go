@@ -43,7 +43,7 @@ func TestNewChooser(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		chooser, _ := NewChooser(test.bidderSyncerLookup, make(map[string]struct{}), test.bidderInfo).(standardChooser)
+		chooser, _ := NewChooser(test.bidderSyncerLookup, make(map[string]struct{}), test.bidderInfo, nil).(standardChooser)
 		assert.ElementsMatch(t, test.expectedBiddersAvailable, chooser.biddersAvailable, test.description)
 	}
 }
@@ -451,6 +451,42 @@ func TestChooserChoose(t *testing.T) {
 	}
 }
 
+func TestChooserBidderChooserFor(t *testing.T) {
+	values := fakeSyncValues{"a": 1}
+	standard := standardBidderChooser{shuffler: randomShuffler{}}
+
+	testCases := []struct {
+		description         string
+		givenSyncValues     SyncValues
+		givenPrioritization Prioritization
+		expected            bidderChooser
+	}{
+		{
+			description:         "Random",
+			givenSyncValues:     values,
+			givenPrioritization: Prioritization{Account: "account"},
+			expected:            standard,
+		},
+		{
+			description:         "Yield",
+			givenSyncValues:     values,
+			givenPrioritization: Prioritization{Yield: true, Account: "account", Country: "USA"},
+			expected:            yieldBidderChooser{shuffler: randomShuffler{}, values: values, account: "account", country: "USA"},
+		},
+		{
+			description:         "Yield Without Values",
+			givenSyncValues:     nil,
+			givenPrioritization: Prioritization{Yield: true, Account: "account"},
+			expected:            standard,
+		},
+	}
+
+	for _, test := range testCases {
+		chooser, _ := NewChooser(map[string]Syncer{}, map[string]struct{}{}, nil, test.givenSyncValues).(standardChooser)
+		assert.Equal(t, test.expected, chooser.bidderChooserFor(test.givenPrioritization), test.description)
+	}
+}
+
 func TestChooserEvaluate(t *testing.T) {
 	fakeSyncerA := fakeSyncer{key: "keyA", supportsIFrame: true}
 	fakeSyncerB := fakeSyncer{key: "keyB", supportsIFrame: false}
@@ -468,6 +504,7 @@ func TestChooserEvaluate(t *testing.T) {
 	cookieNeedsSync := Cookie{}
 	cookieAlreadyHasSyncForA := Cookie{uids: map[string]UIDEntry{"keyA": {Expires: time.Now().Add(time.Duration(24) * time.Hour)}}}
 	cookieAlreadyHasSyncForB := Cookie{uids: map[string]UIDEntry{"keyB": {Expires: time.Now().Add(time.Duration(24) * time.Hour)}}}
+	cookieAttemptedSyncForA := Cookie{attempts: map[string]SyncAttempts{"keyA": {Count: 2, First: time.Now().Add(-time.Hour)}}}
 
 	usersyncDisabled := ptrutil.ToPtr(false)
 
@@ -692,11 +729,76 @@ func TestChooserEvaluate(t *testing.T) {
 			expectedSyncer:              nil,
 			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusBlockedByRegulationScope},
 		},
+		{
+			description:                 "Already Synced - Resync Not Due",
+			givenBidder:                 "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAlreadyHasSyncForA,
+			givenBidderInfo:             map[string]config.BidderInfo{"a": {Syncer: &config.Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(24 * 30)}}},
+			givenSyncTypeFilter:         syncTypeFilter,
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			normalisedBidderName:        "",
+			expectedSyncer:              nil,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusAlreadySynced},
+		},
+		{
+			description:                 "Already Synced - Resync Due",
+			givenBidder:                 "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAlreadyHasSyncForA,
+			givenBidderInfo:             map[string]config.BidderInfo{"a": {Syncer: &config.Syncer{ResyncMaxAgeHours: ptrutil.ToPtr(24)}}},
+			givenSyncTypeFilter:         syncTypeFilter,
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			normalisedBidderName:        "a",
+			expectedSyncer:              fakeSyncerA,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusOK},
+		},
+		{
+			description:                 "Attempts Under Limit",
+			givenBidder:                 "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenBidderInfo:             map[string]config.BidderInfo{"a": {Syncer: &config.Syncer{MaxAttempts: ptrutil.ToPtr(3)}}},
+			givenSyncTypeFilter:         syncTypeFilter,
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			normalisedBidderName:        "a",
+			expectedSyncer:              fakeSyncerA,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusOK},
+		},
+		{
+			description:                 "Attempts Limit Reached",
+			givenBidder:                 "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenBidderInfo:             map[string]config.BidderInfo{"a": {Syncer: &config.Syncer{MaxAttempts: ptrutil.ToPtr(2)}}},
+			givenSyncTypeFilter:         syncTypeFilter,
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			normalisedBidderName:        "a",
+			expectedSyncer:              nil,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusRateLimited},
+		},
+		{
+			description:                 "Attempts Limit Reached - Window Passed",
+			givenBidder:                 "a",
+			givenSyncersSeen:            map[string]struct{}{},
+			givenPrivacy:                fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+			givenCookie:                 cookieAttemptedSyncForA,
+			givenBidderInfo:             map[string]config.BidderInfo{"a": {Syncer: &config.Syncer{MaxAttempts: ptrutil.ToPtr(2), AttemptWindowHours: ptrutil.ToPtr(1)}}},
+			givenSyncTypeFilter:         syncTypeFilter,
+			normalizedBidderNamesLookup: normalizedBidderNamesLookup,
+			normalisedBidderName:        "a",
+			expectedSyncer:              fakeSyncerA,
+			expectedEvaluation:          BidderEvaluation{Bidder: "a", SyncerKey: "keyA", Status: StatusOK},
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			chooser, _ := NewChooser(bidderSyncerLookup, biddersKnown, test.givenBidderInfo).(standardChooser)
+			chooser, _ := NewChooser(bidderSyncerLookup, biddersKnown, test.givenBidderInfo, nil).(standardChooser)
 			chooser.normalizeValidBidderName = test.normalizedBidderNamesLookup
 			sync, evaluation := chooser.evaluate(test.givenBidder, test.givenSyncersSeen, test.givenSyncTypeFilter, &test.givenPrivacy, &test.givenCookie, test.givenGPPSID)
 
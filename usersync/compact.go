@@ -0,0 +1,297 @@
+package usersync
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// compactPrefix marks a cookie value written in version 1 of the compact encoding. Legacy cookies are base64
+// encoded JSON objects, which never contain a '.'.
+const compactPrefix = "c1."
+
+const (
+	compactFlagOptOut     byte = 1 << 0
+	compactFlagCompressed byte = 1 << 1
+)
+
+// maxCompactLength bounds the strings and the number of entries read from a compact cookie, well above what
+// fits in a browser cookie, so a malformed value can't force large allocations.
+const maxCompactLength = 4096
+
+var compactKeyIndex = func() map[string]int {
+	index := make(map[string]int, len(compactKeys))
+	for i, key := range compactKeys {
+		index[key] = i
+	}
+	return index
+}()
+
+var errCompactMalformed = errors.New("malformed compact cookie")
+
+// CompactEncoder writes the cookie in the compact binary encoding. Syncer keys are interned, expirations are
+// stored as varint second offsets from the earliest expiration and the whole cookie is optionally compressed.
+// Sync attempts and the cohort bucket, if any, follow the UIDs so decoders unaware of them still read the UIDs.
+type CompactEncoder struct {
+	Compress bool
+}
+
+func (e CompactEncoder) Encode(c *Cookie) (string, error) {
+	if c == nil {
+		c = NewCookie()
+	}
+
+	var flags byte
+	if c.optOut {
+		flags |= compactFlagOptOut
+	}
+	body := encodeCompactBody(c)
+
+	if e.Compress {
+		if compressed, err := compress(body); err == nil && len(compressed) < len(body) {
+			flags |= compactFlagCompressed
+			body = compressed
+		}
+	}
+
+	payload := append([]byte{flags}, body...)
+	return compactPrefix + base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func encodeCompactBody(c *Cookie) []byte {
+	keys := make([]string, 0, len(c.uids))
+	for key := range c.uids {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var base int64
+	for i, key := range keys {
+		if expires := c.uids[key].Expires.Unix(); i == 0 || expires < base {
+			base = expires
+		}
+	}
+
+	body := binary.AppendUvarint(nil, uint64(len(keys)))
+	body = binary.AppendVarint(body, base)
+	for _, key := range keys {
+		entry := c.uids[key]
+		body = appendCompactKey(body, key)
+		body = appendCompactString(body, entry.UID)
+		body = binary.AppendUvarint(body, uint64(entry.Expires.Unix()-base))
+	}
+
+	if len(c.attempts) == 0 && c.bucket == nil {
+		return body
+	}
+	attemptKeys := make([]string, 0, len(c.attempts))
+	for key := range c.attempts {
+		attemptKeys = append(attemptKeys, key)
+	}
+	sort.Strings(attemptKeys)
+
+	body = binary.AppendUvarint(body, uint64(len(attemptKeys)))
+	for _, key := range attemptKeys {
+		attempts := c.attempts[key]
+		body = appendCompactKey(body, key)
+		body = binary.AppendUvarint(body, uint64(attempts.Count))
+		body = binary.AppendVarint(body, attempts.First.Unix())
+	}
+
+	if c.bucket != nil {
+		body = binary.AppendUvarint(body, uint64(*c.bucket))
+	}
+	return body
+}
+
+func appendCompactKey(b []byte, key string) []byte {
+	if index, ok := compactKeyIndex[key]; ok {
+		return binary.AppendUvarint(b, uint64(index)+1)
+	}
+	b = binary.AppendUvarint(b, 0)
+	return appendCompactString(b, key)
+}
+
+func appendCompactString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// VersionedDecoder reads cookies written in the compact encoding as well as legacy base64 JSON cookies, so
+// cookies keep working while the host switches between encodings.
+type VersionedDecoder struct{}
+
+func (d VersionedDecoder) Decode(encodedValue string) *Cookie {
+	if !strings.HasPrefix(encodedValue, compactPrefix) {
+		return Base64Decoder{}.Decode(encodedValue)
+	}
+
+	cookie, err := decodeCompact(strings.TrimPrefix(encodedValue, compactPrefix))
+	if err != nil {
+		return NewCookie()
+	}
+	return cookie
+}
+
+func decodeCompact(value string) (*Cookie, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) == 0 {
+		return nil, errCompactMalformed
+	}
+
+	flags, body := payload[0], payload[1:]
+	if flags&compactFlagCompressed != 0 {
+		if body, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(body)), maxCompactLength*4)); err != nil {
+			return nil, err
+		}
+	}
+
+	cookie, err := decodeCompactBody(body)
+	if flags&compactFlagOptOut != 0 {
+		// the opt out holds regardless of the body, which only keeps the cohort bucket of the user
+		optOut := NewCookie()
+		optOut.optOut = true
+		if err == nil {
+			optOut.bucket = cookie.bucket
+		}
+		return optOut, nil
+	}
+	return cookie, err
+}
+
+func decodeCompactBody(body []byte) (*Cookie, error) {
+	cookie := NewCookie()
+	r := bytes.NewReader(body)
+	count, err := binary.ReadUvarint(r)
+	if err != nil || count > maxCompactLength {
+		return nil, errCompactMalformed
+	}
+	base, err := binary.ReadVarint(r)
+	if err != nil {
+		return nil, errCompactMalformed
+	}
+
+	for i := uint64(0); i < count; i++ {
+		key, err := readCompactKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := readCompactString(r)
+		if err != nil {
+			return nil, err
+		}
+		offset, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errCompactMalformed
+		}
+
+		cookie.uids[key] = UIDEntry{
+			UID:     uid,
+			Expires: time.Unix(base+int64(offset), 0).UTC(),
+		}
+	}
+
+	// Audience Network Handling
+	if id, ok := cookie.uids[string(openrtb_ext.BidderAudienceNetwork)]; ok && id.UID == "0" {
+		delete(cookie.uids, string(openrtb_ext.BidderAudienceNetwork))
+	}
+
+	if r.Len() == 0 {
+		return cookie, nil
+	}
+	if cookie.attempts, err = readCompactAttempts(r); err != nil {
+		return nil, err
+	}
+	if len(cookie.attempts) == 0 {
+		cookie.attempts = nil
+	}
+
+	if r.Len() == 0 {
+		return cookie, nil
+	}
+	bucket, err := binary.ReadUvarint(r)
+	if err != nil || bucket >= 100 {
+		return nil, errCompactMalformed
+	}
+	b := int(bucket)
+	cookie.bucket = &b
+	return cookie, nil
+}
+
+func readCompactAttempts(r *bytes.Reader) (map[string]SyncAttempts, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil || count > maxCompactLength {
+		return nil, errCompactMalformed
+	}
+
+	attempts := make(map[string]SyncAttempts, count)
+	for i := uint64(0); i < count; i++ {
+		key, err := readCompactKey(r)
+		if err != nil {
+			return nil, err
+		}
+		attemptCount, err := binary.ReadUvarint(r)
+		if err != nil || attemptCount > maxCompactLength {
+			return nil, errCompactMalformed
+		}
+		first, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, errCompactMalformed
+		}
+		attempts[key] = SyncAttempts{Count: int(attemptCount), First: time.Unix(first, 0).UTC()}
+	}
+	return attempts, nil
+}
+
+func readCompactKey(r *bytes.Reader) (string, error) {
+	keyRef, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", errCompactMalformed
+	}
+	if keyRef == 0 {
+		return readCompactString(r)
+	}
+	if keyRef > uint64(len(compactKeys)) {
+		return "", errCompactMalformed
+	}
+	return compactKeys[keyRef-1], nil
+}
+
+func readCompactString(r *bytes.Reader) (string, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil || length > maxCompactLength || length > uint64(r.Len()) {
+		return "", errCompactMalformed
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", errCompactMalformed
+	}
+	return string(b), nil
+}
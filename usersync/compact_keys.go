@@ -0,0 +1,27 @@
+package usersync
+
+// compactKeys is the interned syncer key table of the compact cookie encoding. A syncer key in the table is
+// written as its position, any other key is written in full. The table is append only: removing or reordering
+// keys would change the keys of the cookies already written.
+var compactKeys = []string{
+	"33across", "Beintoo", "aax", "acuityads", "adf", "adform", "adkernel", "adkernelAdn", "adman", "admatic",
+	"admixer", "adnxs", "adocean", "adot", "adpone", "adprime", "adquery", "adsinteractive", "adtarget",
+	"adtelligent", "advangelists", "adxcg", "adyoulike", "aidem", "aja", "alkimi", "amx", "apacdex", "aso",
+	"audienceNetwork", "avocet", "axis", "axonix", "bcmint", "beachfront", "bematterfull", "between",
+	"beyondmedia", "bidgency", "bidmyadz", "bliink", "blue", "bmtm", "boldwin", "bwx", "cadent_aperture_mx",
+	"ccx", "colossus", "compass", "connectad", "consumable", "conversant", "copper6", "cpmstar", "criteo",
+	"datablocks", "deepintent", "dianomi", "dmx", "driftpixel", "dxkulture", "e_volution", "emtv",
+	"emx_digital", "eplanning", "epsilon", "evtech", "freewheel-ssp", "freewheelssp", "frvradn", "gamma",
+	"gamoshi", "globalsun", "grid", "gumgum", "imds", "impactify", "improvedigital", "indicue", "inmobi",
+	"invibes", "iqx", "ix", "janet", "jdpmedia", "jixie", "kargo", "kiviads", "krushmedia", "lm_kiviads",
+	"lockerdome", "logan", "logicad", "lunamedia", "markapp", "marsmedia", "mediago", "medianet", "mgid",
+	"mgidX", "minutemedia", "nextmillennium", "nobid", "onetag", "openweb", "openx", "operaads", "orbidder",
+	"outbrain", "ownadx", "pgam", "pgamssp", "playdigo", "pubmatic", "pubrise", "pulsepoint", "pwbid", "qt",
+	"quantumdex", "richaudience", "rise", "rtbhouse", "rubicon", "sa_lunamedia", "seedingAlliance",
+	"sharethrough", "smaato", "smartadserver", "smarthub", "smartrtb", "smartyads", "smilewanted", "sonobi",
+	"sovrn", "sspBC", "streamkey", "stroeerCore", "suntContent", "taboola", "tappx", "telaria", "theadx",
+	"thetradedesk", "tpmn", "tredio", "triplelift", "triplelift_native", "trustedstack", "trustx", "ucfunnel",
+	"undertone", "unruly", "valueimpression", "vidazoo", "videobyte", "vidoomy", "viewdeos", "visiblemeasures",
+	"visx", "vox", "vrtcal", "xeworks", "yahooAds", "yahooAdvertising", "yahoossp", "yandex", "yieldlab",
+	"yieldmo", "yieldone", "zeroclickfraud", "zeta_global_ssp",
+}
@@ -0,0 +1,283 @@
+package usersync
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactKeysSortedAndUnique(t *testing.T) {
+	assert.True(t, sort.StringsAreSorted(compactKeys), "compact keys must be sorted")
+	assert.Len(t, compactKeyIndex, len(compactKeys), "compact keys must be unique")
+}
+
+func TestCompactEncoderDecoder(t *testing.T) {
+	expires := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		givenCookie    *Cookie
+		expectedCookie *Cookie
+	}{
+		{
+			name: "interned-keys",
+			givenCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs":    {UID: "UID", Expires: expires},
+					"pubmatic": {UID: "12345", Expires: expires.Add(time.Hour)},
+				},
+			},
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs":    {UID: "UID", Expires: expires},
+					"pubmatic": {UID: "12345", Expires: expires.Add(time.Hour)},
+				},
+			},
+		},
+		{
+			name: "unknown-key",
+			givenCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"custom-syncer": {UID: "UID", Expires: expires},
+				},
+			},
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"custom-syncer": {UID: "UID", Expires: expires},
+				},
+			},
+		},
+		{
+			name: "zero-expiry",
+			givenCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs": {UID: "UID"},
+				},
+			},
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs": {UID: "UID"},
+				},
+			},
+		},
+		{
+			name: "sync-attempts",
+			givenCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs": {UID: "UID", Expires: expires},
+				},
+				attempts: map[string]SyncAttempts{
+					"pubmatic":      {Count: 2, First: expires},
+					"custom-syncer": {Count: 1, First: expires.Add(time.Minute)},
+				},
+			},
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"adnxs": {UID: "UID", Expires: expires},
+				},
+				attempts: map[string]SyncAttempts{
+					"pubmatic":      {Count: 2, First: expires},
+					"custom-syncer": {Count: 1, First: expires.Add(time.Minute)},
+				},
+			},
+		},
+		{
+			name: "bucket",
+			givenCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}},
+				bucket: ptrutil.ToPtr(42),
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}},
+				bucket: ptrutil.ToPtr(42),
+			},
+		},
+		{
+			name: "bucket-with-sync-attempts",
+			givenCookie: &Cookie{
+				uids:     map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}},
+				attempts: map[string]SyncAttempts{"pubmatic": {Count: 2, First: expires}},
+				bucket:   ptrutil.ToPtr(0),
+			},
+			expectedCookie: &Cookie{
+				uids:     map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}},
+				attempts: map[string]SyncAttempts{"pubmatic": {Count: 2, First: expires}},
+				bucket:   ptrutil.ToPtr(0),
+			},
+		},
+		{
+			name:           "opt-out",
+			givenCookie:    &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}}, optOut: true},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}, optOut: true},
+		},
+		{
+			name:           "opt-out-keeps-bucket",
+			givenCookie:    &Cookie{uids: map[string]UIDEntry{}, optOut: true, bucket: ptrutil.ToPtr(99)},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}, optOut: true, bucket: ptrutil.ToPtr(99)},
+		},
+		{
+			name:           "empty-cookie",
+			givenCookie:    &Cookie{},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "nil-cookie",
+			givenCookie:    nil,
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name: "audience-network-zero-uid",
+			givenCookie: &Cookie{
+				uids: map[string]UIDEntry{
+					"audienceNetwork": {UID: "0", Expires: expires},
+				},
+			},
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+	}
+
+	for _, compress := range []bool{false, true} {
+		encoder := CompactEncoder{Compress: compress}
+		for _, test := range testCases {
+			t.Run(test.name, func(t *testing.T) {
+				encodedCookie, err := encoder.Encode(test.givenCookie)
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(encodedCookie, compactPrefix))
+
+				decodedCookie := VersionedDecoder{}.Decode(encodedCookie)
+				assert.Equal(t, test.expectedCookie.uids, decodedCookie.uids)
+				assert.Equal(t, test.expectedCookie.attempts, decodedCookie.attempts)
+				assert.Equal(t, test.expectedCookie.optOut, decodedCookie.optOut)
+				assert.Equal(t, test.expectedCookie.bucket, decodedCookie.bucket)
+			})
+		}
+	}
+}
+
+func TestCompactEncoderSize(t *testing.T) {
+	expires := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cookie := NewCookie()
+	for i, key := range compactKeys[:20] {
+		cookie.uids[key] = UIDEntry{UID: strings.Repeat("a", 20), Expires: expires.Add(time.Duration(i) * time.Minute)}
+	}
+
+	jsonCookie, err := Base64Encoder{}.Encode(cookie)
+	assert.NoError(t, err)
+	compactCookie, err := CompactEncoder{}.Encode(cookie)
+	assert.NoError(t, err)
+	compressedCookie, err := CompactEncoder{Compress: true}.Encode(cookie)
+	assert.NoError(t, err)
+
+	assert.Less(t, len(compactCookie), len(jsonCookie)/2)
+	assert.Less(t, len(compressedCookie), len(compactCookie))
+	assert.Equal(t, cookie.uids, VersionedDecoder{}.Decode(compressedCookie).uids)
+}
+
+func TestVersionedDecoder(t *testing.T) {
+	expires := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	legacyCookie, err := Base64Encoder{}.Encode(&Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}}})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		givenValue     string
+		expectedCookie *Cookie
+	}{
+		{
+			name:       "legacy-json",
+			givenValue: legacyCookie,
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{"adnxs": {UID: "UID", Expires: expires}},
+			},
+		},
+		{
+			name:           "legacy-malformed",
+			givenValue:     "malformed",
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-malformed-base64",
+			givenValue:     compactPrefix + "!!",
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-empty",
+			givenValue:     compactPrefix,
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-truncated",
+			givenValue:     compactPrefix + base64.RawURLEncoding.EncodeToString([]byte{0, 2, 0, 1}),
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-unknown-key-ref",
+			givenValue:     compactPrefix + base64.RawURLEncoding.EncodeToString([]byte{0, 1, 0, 0xff, 0x7f, 1, 'a', 0}),
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-string-too-long",
+			givenValue:     compactPrefix + base64.RawURLEncoding.EncodeToString([]byte{0, 1, 0, 1, 0xff, 0x7f, 'a'}),
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-truncated-attempts",
+			givenValue:     compactPrefix + base64.RawURLEncoding.EncodeToString([]byte{0, 0, 0, 1}),
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+		{
+			name:           "compact-malformed-compression",
+			givenValue:     compactPrefix + base64.RawURLEncoding.EncodeToString([]byte{compactFlagCompressed, 0xff, 0xff}),
+			expectedCookie: &Cookie{uids: map[string]UIDEntry{}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			decodedCookie := VersionedDecoder{}.Decode(test.givenValue)
+			assert.Equal(t, test.expectedCookie.uids, decodedCookie.uids)
+			assert.Equal(t, test.expectedCookie.optOut, decodedCookie.optOut)
+		})
+	}
+}
+
+func TestNewEncoder(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenConfig     *config.HostCookie
+		expectedEncoder Encoder
+	}{
+		{
+			name:            "nil",
+			givenConfig:     nil,
+			expectedEncoder: Base64Encoder{},
+		},
+		{
+			name:            "default",
+			givenConfig:     &config.HostCookie{},
+			expectedEncoder: Base64Encoder{},
+		},
+		{
+			name:            "json",
+			givenConfig:     &config.HostCookie{Encoding: config.CookieEncodingJSON},
+			expectedEncoder: Base64Encoder{},
+		},
+		{
+			name:            "compact",
+			givenConfig:     &config.HostCookie{Encoding: config.CookieEncodingCompact, Compress: true},
+			expectedEncoder: CompactEncoder{Compress: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedEncoder, NewEncoder(test.givenConfig))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usersync
 
 import (
 	"errors"
+	"net"
 	"net/http"
 	"time"
 
@@ -21,8 +22,11 @@ const uidTTL = 14 * 24 * time.Hour
 // To get an instance of this from a request, use ReadCookie.
 // To write an instance onto a response, use WriteCookie.
 type Cookie struct {
-	uids   map[string]UIDEntry
-	optOut bool
+	uids     map[string]UIDEntry
+	attempts map[string]SyncAttempts
+	optOut   bool
+	// bucket is the cohort bucket of the user, out of 100, or nil if none was assigned yet.
+	bucket *int
 }
 
 // UIDEntry bundles the UID with an Expiration date.
@@ -33,6 +37,14 @@ type UIDEntry struct {
 	Expires time.Time `json:"expires"`
 }
 
+// SyncAttempts counts the syncs offered to a user for a particular syncer key which haven't succeeded yet.
+type SyncAttempts struct {
+	// Count is the number of syncs offered since First.
+	Count int `json:"count"`
+	// First is the time at which the first of the counted syncs was offered.
+	First time.Time `json:"first"`
+}
+
 // NewCookie returns a new empty cookie.
 func NewCookie() *Cookie {
 	return &Cookie{
@@ -40,6 +52,16 @@ func NewCookie() *Cookie {
 	}
 }
 
+// ResolveHostCookie returns the host cookie of the account or, if none is defined for it, of the host name the
+// request was sent to.
+func ResolveHostCookie(r *http.Request, host *config.HostCookie, account string) *config.HostCookie {
+	hostname := r.Host
+	if name, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = name
+	}
+	return host.Resolve(hostname, account)
+}
+
 // ReadCookie reads the cookie from the request
 func ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cookie {
 	if hostOptOutCookie := checkHostCookieOptOut(r, host); hostOptOutCookie != nil {
@@ -56,9 +78,9 @@ func ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cook
 	return decodedCookie
 }
 
-// PrepareCookieForWrite ejects UIDs as long as the cookie is too full
+// PrepareCookieForWrite ejects UIDs as long as the cookie is too full. Sync attempts are dropped before any UID.
 func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Encoder, ejector Ejector) (string, error) {
-	for len(cookie.uids) > 0 {
+	for len(cookie.uids) > 0 || len(cookie.attempts) > 0 {
 		encodedCookie, err := encoder.Encode(cookie)
 		if err != nil {
 			return encodedCookie, nil
@@ -76,6 +98,9 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 		isCookieTooBig := cookieSize > cfg.MaxCookieSizeBytes && cfg.MaxCookieSizeBytes > 0
 		if !isCookieTooBig {
 			return encodedCookie, nil
+		} else if len(cookie.attempts) > 0 {
+			cookie.attempts = nil
+			continue
 		} else if len(cookie.uids) == 1 {
 			return "", errors.New("uid that's trying to be synced is bigger than MaxCookieSize")
 		}
@@ -89,27 +114,31 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 	return "", nil
 }
 
-// WriteCookie sets the prepared cookie onto the header
+// WriteCookie sets the prepared cookie onto the header, with the attributes configured for the host
 func WriteCookie(w http.ResponseWriter, encodedCookie string, cfg *config.HostCookie, setSiteCookie bool) {
+	WriteCookieWithAttributes(w, encodedCookie, cfg, hostCookieAttributes(cfg, setSiteCookie))
+}
+
+// WriteCookieWithAttributes sets the prepared cookie onto the header with the given attributes
+func WriteCookieWithAttributes(w http.ResponseWriter, encodedCookie string, cfg *config.HostCookie, attributes CookieAttributes) {
 	ttl := cfg.TTLDuration()
 
 	httpCookie := &http.Cookie{
-		Name:    uidCookieName,
-		Value:   encodedCookie,
-		Expires: time.Now().Add(ttl),
-		Path:    "/",
+		Name:     uidCookieName,
+		Value:    encodedCookie,
+		Expires:  time.Now().Add(ttl),
+		Path:     "/",
+		Domain:   attributes.Domain,
+		Secure:   attributes.Secure,
+		SameSite: attributes.sameSiteMode(),
 	}
 
-	if cfg.Domain != "" {
-		httpCookie.Domain = cfg.Domain
+	// the Partitioned attribute is written by hand as net/http only supports it since go 1.23
+	setCookie := httpCookie.String()
+	if attributes.Partitioned {
+		setCookie += "; Partitioned"
 	}
-
-	if setSiteCookie {
-		httpCookie.Secure = true
-		httpCookie.SameSite = http.SameSiteNoneMode
-	}
-
-	w.Header().Add("Set-Cookie", httpCookie.String())
+	w.Header().Add("Set-Cookie", setCookie)
 }
 
 // Sync tries to set the UID for some syncer key. It returns an error if the set didn't happen.
@@ -127,10 +156,42 @@ func (cookie *Cookie) Sync(key string, uid string) error {
 		UID:     uid,
 		Expires: time.Now().Add(uidTTL),
 	}
+	delete(cookie.attempts, key)
 
 	return nil
 }
 
+// RecordSyncAttempt counts a sync offered to the user for the syncer key. The count starts over once the
+// window since the first counted attempt has passed.
+func (cookie *Cookie) RecordSyncAttempt(key string, window time.Duration) {
+	if !cookie.AllowSyncs() {
+		return
+	}
+
+	now := time.Now()
+	attempts, ok := cookie.attempts[key]
+	if !ok || !now.Before(attempts.First.Add(window)) {
+		attempts = SyncAttempts{First: now}
+	}
+	attempts.Count++
+
+	if cookie.attempts == nil {
+		cookie.attempts = make(map[string]SyncAttempts)
+	}
+	cookie.attempts[key] = attempts
+}
+
+// SyncAttemptCount returns the number of syncs offered to the user for the syncer key within the window.
+func (cookie *Cookie) SyncAttemptCount(key string, window time.Duration) int {
+	if cookie == nil {
+		return 0
+	}
+	if attempts, ok := cookie.attempts[key]; ok && time.Now().Before(attempts.First.Add(window)) {
+		return attempts.Count
+	}
+	return 0
+}
+
 // SyncHostCookie syncs the request cookie with the host cookie
 func SyncHostCookie(r *http.Request, requestCookie *Cookie, host *config.HostCookie) {
 	if uid, _, _ := requestCookie.GetUID(host.Family); uid == "" && host.CookieName != "" {
@@ -163,6 +224,7 @@ func (cookie *Cookie) SetOptOut(optOut bool) {
 
 	if optOut {
 		cookie.uids = make(map[string]UIDEntry)
+		cookie.attempts = nil
 	}
 }
 
@@ -199,6 +261,18 @@ func (cookie *Cookie) HasLiveSync(key string) bool {
 	return isLive
 }
 
+// IsSyncOlderThan returns true if the UID for the given syncer key was synced more than maxAge ago. The sync
+// time is derived from the expiration, as UIDs are always synced with the same lifetime.
+func (cookie *Cookie) IsSyncOlderThan(key string, maxAge time.Duration) bool {
+	if cookie == nil {
+		return false
+	}
+	if uid, ok := cookie.uids[key]; ok {
+		return time.Since(uid.Expires.Add(-uidTTL)) > maxAge
+	}
+	return false
+}
+
 // HasAnyLiveSyncs returns true if this cookie has at least one active sync.
 func (cookie *Cookie) HasAnyLiveSyncs() bool {
 	now := time.Now()
@@ -221,14 +295,18 @@ func checkAudienceNetwork(key string, uid string) bool {
 // This exists so that Cookie (which is public) can have private fields, and the rest of
 // the code doesn't have to worry about the cookie data storage format.
 type cookieJson struct {
-	UIDs   map[string]UIDEntry `json:"tempUIDs,omitempty"`
-	OptOut bool                `json:"optout,omitempty"`
+	UIDs     map[string]UIDEntry     `json:"tempUIDs,omitempty"`
+	Attempts map[string]SyncAttempts `json:"attempts,omitempty"`
+	OptOut   bool                    `json:"optout,omitempty"`
+	Bucket   *int                    `json:"bucket,omitempty"`
 }
 
 func (cookie *Cookie) MarshalJSON() ([]byte, error) { // nosemgrep: marshal-json-pointer-receiver
 	return jsonutil.Marshal(cookieJson{
-		UIDs:   cookie.uids,
-		OptOut: cookie.optOut,
+		UIDs:     cookie.uids,
+		Attempts: cookie.attempts,
+		OptOut:   cookie.optOut,
+		Bucket:   cookie.bucket,
 	})
 }
 
@@ -239,11 +317,14 @@ func (cookie *Cookie) UnmarshalJSON(b []byte) error {
 	}
 
 	cookie.optOut = cookieContract.OptOut
+	cookie.bucket = validBucket(cookieContract.Bucket)
 
 	if cookie.optOut {
 		cookie.uids = nil
+		cookie.attempts = nil
 	} else {
 		cookie.uids = cookieContract.UIDs
+		cookie.attempts = cookieContract.Attempts
 	}
 
 	if cookie.uids == nil {
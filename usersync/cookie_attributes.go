@@ -0,0 +1,156 @@
+package usersync
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// CookieAttributes are the attributes a uids cookie is written with.
+type CookieAttributes struct {
+	// Cohort is the name of the experiment cohort the attributes come from, empty if none.
+	Cohort string `json:"cohort,omitempty"`
+	// SameSite is one of none, lax or strict, or empty if the attribute isn't written.
+	SameSite    string `json:"same_site,omitempty"`
+	Secure      bool   `json:"secure"`
+	Partitioned bool   `json:"partitioned"`
+	Domain      string `json:"domain,omitempty"`
+}
+
+func (a CookieAttributes) sameSiteMode() http.SameSite {
+	switch a.SameSite {
+	case config.CookieSameSiteNone:
+		return http.SameSiteNoneMode
+	case config.CookieSameSiteLax:
+		return http.SameSiteLaxMode
+	case config.CookieSameSiteStrict:
+		return http.SameSiteStrictMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
+
+// hostCookieAttributes returns the attributes the host writes the uids cookie with. SameSite=None is only
+// written to browsers supporting it.
+func hostCookieAttributes(host *config.HostCookie, setSiteCookie bool) CookieAttributes {
+	attributes := CookieAttributes{Domain: host.Domain}
+	if setSiteCookie {
+		attributes.SameSite = config.CookieSameSiteNone
+		attributes.Secure = true
+	}
+	attributes.apply(host.Attributes, setSiteCookie)
+	return attributes
+}
+
+// NewCookieAttributes returns the attributes of the uids cookie written in response to the request. The host
+// attributes are overridden by the ones of the account and then by the ones of the experiment cohort the user
+// falls in, if any. The cohort bucket of the user is kept in the cookie, so the attributes are resolved before the
+// cookie is encoded.
+func NewCookieAttributes(r *http.Request, cookie *Cookie, host *config.HostCookie, account *config.AccountCookie, setSiteCookie bool) CookieAttributes {
+	attributes := hostCookieAttributes(host, setSiteCookie)
+	if account != nil {
+		attributes.apply(account.Attributes, setSiteCookie)
+	}
+
+	cohorts := host.Cohorts
+	if account != nil && len(account.Cohorts) > 0 {
+		cohorts = account.Cohorts
+	}
+	if cohort := chooseCookieCohort(cohorts, cookieBucket(cookie, host), browserFamily(r.UserAgent())); cohort != nil {
+		attributes.Cohort = cohort.Name
+		attributes.apply(cohort.Attributes, setSiteCookie)
+	}
+	return attributes
+}
+
+func (a *CookieAttributes) apply(cfg config.CookieAttributes, setSiteCookie bool) {
+	if cfg.Domain != "" {
+		a.Domain = cfg.Domain
+	}
+
+	switch cfg.SameSite {
+	case config.CookieSameSiteNone:
+		if setSiteCookie {
+			a.SameSite = config.CookieSameSiteNone
+			a.Secure = true
+		} else {
+			a.SameSite = ""
+		}
+	case config.CookieSameSiteLax, config.CookieSameSiteStrict:
+		a.SameSite = cfg.SameSite
+	}
+
+	if cfg.Partitioned != nil {
+		a.Partitioned = *cfg.Partitioned
+	}
+	// partitioned cookies are rejected by browsers unless secure
+	if a.Partitioned {
+		a.Secure = true
+	}
+}
+
+// chooseCookieCohort returns the cohort covering the bucket of the user, if the cohort applies to the browser.
+// Cohorts take consecutive ranges of the 100 buckets in their order, so each user stays in the same cohort.
+func chooseCookieCohort(cohorts []config.CookieCohort, bucket int, browser string) *config.CookieCohort {
+	upper := 0
+	for i := range cohorts {
+		upper += cohorts[i].Percent
+		if bucket >= upper {
+			continue
+		}
+		if len(cohorts[i].Browsers) > 0 && !slices.Contains(cohorts[i].Browsers, browser) {
+			return nil
+		}
+		return &cohorts[i]
+	}
+	return nil
+}
+
+// cookieBucket returns the bucket, out of 100, of the user. Users are bucketed on the host cookie, if they have
+// one, and at random otherwise. The bucket is then kept in the cookie, so the user stays in the same cohort until
+// the cookie expires.
+func cookieBucket(cookie *Cookie, host *config.HostCookie) int {
+	if cookie == nil {
+		return rand.Intn(100)
+	}
+	if cookie.bucket != nil {
+		return *cookie.bucket
+	}
+
+	bucket := rand.Intn(100)
+	if uid, _, _ := cookie.GetUID(host.Family); uid != "" {
+		h := fnv.New32a()
+		h.Write([]byte(uid))
+		bucket = int(h.Sum32() % 100)
+	}
+	cookie.bucket = &bucket
+	return bucket
+}
+
+// validBucket returns the bucket read from a cookie, or nil if it's out of range.
+func validBucket(bucket *int) *int {
+	if bucket == nil || *bucket < 0 || *bucket >= 100 {
+		return nil
+	}
+	return bucket
+}
+
+// browserFamily returns the family of the browser from its user agent, one of config.CookieBrowsers.
+func browserFamily(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Edg/") || strings.Contains(userAgent, "EdgA/") || strings.Contains(userAgent, "EdgiOS/"):
+		return "edge"
+	case strings.Contains(userAgent, "Firefox/") || strings.Contains(userAgent, "FxiOS/"):
+		return "firefox"
+	case strings.Contains(userAgent, "Chrome/") || strings.Contains(userAgent, "CriOS/"):
+		return "chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		return "safari"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,246 @@
+package usersync
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const chromeUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+func TestNewCookieAttributes(t *testing.T) {
+	allCohort := []config.CookieCohort{{Name: "chips", Percent: 100, Attributes: config.CookieAttributes{Partitioned: ptrutil.ToPtr(true)}}}
+
+	testCases := []struct {
+		name               string
+		givenHost          config.HostCookie
+		givenAccount       *config.AccountCookie
+		givenSetSiteCookie bool
+		givenUserAgent     string
+		expectedAttributes CookieAttributes
+	}{
+		{
+			name:               "default-site-cookie",
+			givenHost:          config.HostCookie{Domain: "host.com"},
+			givenSetSiteCookie: true,
+			expectedAttributes: CookieAttributes{SameSite: "none", Secure: true, Domain: "host.com"},
+		},
+		{
+			name:               "default-not-site-cookie",
+			givenHost:          config.HostCookie{Domain: "host.com"},
+			givenSetSiteCookie: false,
+			expectedAttributes: CookieAttributes{Domain: "host.com"},
+		},
+		{
+			name:               "host-attributes",
+			givenHost:          config.HostCookie{Domain: "host.com", Attributes: config.CookieAttributes{SameSite: "lax", Domain: "cookie.host.com"}},
+			givenSetSiteCookie: true,
+			expectedAttributes: CookieAttributes{SameSite: "lax", Secure: true, Domain: "cookie.host.com"},
+		},
+		{
+			name:               "account-overrides-host",
+			givenHost:          config.HostCookie{Attributes: config.CookieAttributes{SameSite: "lax"}},
+			givenAccount:       &config.AccountCookie{Attributes: config.CookieAttributes{SameSite: "strict", Partitioned: ptrutil.ToPtr(true), Domain: "publisher.com"}},
+			givenSetSiteCookie: false,
+			expectedAttributes: CookieAttributes{SameSite: "strict", Secure: true, Partitioned: true, Domain: "publisher.com"},
+		},
+		{
+			name:               "same-site-none-unsupported",
+			givenHost:          config.HostCookie{Attributes: config.CookieAttributes{SameSite: "lax"}},
+			givenAccount:       &config.AccountCookie{Attributes: config.CookieAttributes{SameSite: "none"}},
+			givenSetSiteCookie: false,
+			expectedAttributes: CookieAttributes{},
+		},
+		{
+			name:               "host-cohort",
+			givenHost:          config.HostCookie{Cohorts: allCohort},
+			givenSetSiteCookie: true,
+			expectedAttributes: CookieAttributes{Cohort: "chips", SameSite: "none", Secure: true, Partitioned: true},
+		},
+		{
+			name:      "account-cohorts-replace-host-cohorts",
+			givenHost: config.HostCookie{Cohorts: allCohort},
+			givenAccount: &config.AccountCookie{Cohorts: []config.CookieCohort{
+				{Name: "lax", Percent: 100, Attributes: config.CookieAttributes{SameSite: "lax"}},
+			}},
+			givenSetSiteCookie: true,
+			expectedAttributes: CookieAttributes{Cohort: "lax", SameSite: "lax", Secure: true},
+		},
+		{
+			name: "cohort-of-other-browser",
+			givenHost: config.HostCookie{Cohorts: []config.CookieCohort{
+				{Name: "safari", Percent: 100, Browsers: []string{"safari"}, Attributes: config.CookieAttributes{SameSite: "lax"}},
+			}},
+			givenSetSiteCookie: true,
+			givenUserAgent:     chromeUserAgent,
+			expectedAttributes: CookieAttributes{SameSite: "none", Secure: true},
+		},
+		{
+			name: "cohort-of-browser",
+			givenHost: config.HostCookie{Cohorts: []config.CookieCohort{
+				{Name: "chrome", Percent: 100, Browsers: []string{"chrome"}, Attributes: config.CookieAttributes{Partitioned: ptrutil.ToPtr(true)}},
+			}},
+			givenSetSiteCookie: true,
+			givenUserAgent:     chromeUserAgent,
+			expectedAttributes: CookieAttributes{Cohort: "chrome", SameSite: "none", Secure: true, Partitioned: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/setuid", nil)
+			r.Header.Set("User-Agent", test.givenUserAgent)
+
+			attributes := NewCookieAttributes(r, NewCookie(), &test.givenHost, test.givenAccount, test.givenSetSiteCookie)
+			assert.Equal(t, test.expectedAttributes, attributes)
+		})
+	}
+}
+
+func TestChooseCookieCohort(t *testing.T) {
+	cohorts := []config.CookieCohort{
+		{Name: "a", Percent: 10},
+		{Name: "b", Percent: 20, Browsers: []string{"chrome"}},
+	}
+
+	testCases := []struct {
+		name           string
+		givenBucket    int
+		givenBrowser   string
+		expectedCohort string
+	}{
+		{name: "first-cohort", givenBucket: 0, givenBrowser: "safari", expectedCohort: "a"},
+		{name: "first-cohort-upper-bound", givenBucket: 9, givenBrowser: "safari", expectedCohort: "a"},
+		{name: "second-cohort", givenBucket: 10, givenBrowser: "chrome", expectedCohort: "b"},
+		{name: "second-cohort-other-browser", givenBucket: 10, givenBrowser: "safari", expectedCohort: ""},
+		{name: "no-cohort", givenBucket: 30, givenBrowser: "chrome", expectedCohort: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cohort := chooseCookieCohort(cohorts, test.givenBucket, test.givenBrowser)
+			if test.expectedCohort == "" {
+				assert.Nil(t, cohort)
+			} else {
+				assert.Equal(t, test.expectedCohort, cohort.Name)
+			}
+		})
+	}
+}
+
+func TestCookieBucket(t *testing.T) {
+	host := &config.HostCookie{Family: "host"}
+
+	testCases := []struct {
+		name           string
+		givenCookie    func() *Cookie
+		expectedBucket *int
+	}{
+		{
+			name: "identified-user",
+			givenCookie: func() *Cookie {
+				cookie := NewCookie()
+				cookie.Sync("host", "host-id")
+				return cookie
+			},
+		},
+		{
+			name:        "unidentified-user",
+			givenCookie: NewCookie,
+		},
+		{
+			name: "stored-bucket",
+			givenCookie: func() *Cookie {
+				cookie := NewCookie()
+				cookie.Sync("host", "host-id")
+				cookie.bucket = ptrutil.ToPtr(42)
+				return cookie
+			},
+			expectedBucket: ptrutil.ToPtr(42),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cookie := test.givenCookie()
+			bucket := cookieBucket(cookie, host)
+			if test.expectedBucket != nil {
+				assert.Equal(t, *test.expectedBucket, bucket)
+			}
+			assert.GreaterOrEqual(t, bucket, 0)
+			assert.Less(t, bucket, 100)
+
+			// the bucket must survive the cookie being written and read back
+			encoded, err := Base64Encoder{}.Encode(cookie)
+			assert.NoError(t, err)
+			decoded := Base64Decoder{}.Decode(encoded)
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, bucket, cookieBucket(decoded, host), "the bucket of a user must be stable")
+			}
+		})
+	}
+}
+
+func TestBrowserFamily(t *testing.T) {
+	testCases := []struct {
+		userAgent string
+		expected  string
+	}{
+		{userAgent: chromeUserAgent, expected: "chrome"},
+		{userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", expected: "chrome"},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", expected: "edge"},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", expected: "firefox"},
+		{userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15", expected: "safari"},
+		{userAgent: "curl/8.4.0", expected: "other"},
+		{userAgent: "", expected: "other"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, browserFamily(test.userAgent))
+		})
+	}
+}
+
+func TestWriteCookieWithAttributes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenAttributes    CookieAttributes
+		expectedAttributes string
+	}{
+		{
+			name:               "none",
+			givenAttributes:    CookieAttributes{},
+			expectedAttributes: "; Path=/; Expires=",
+		},
+		{
+			name:               "partitioned",
+			givenAttributes:    CookieAttributes{SameSite: "none", Secure: true, Partitioned: true, Domain: "host.com"},
+			expectedAttributes: "; Secure; SameSite=None; Partitioned",
+		},
+		{
+			name:               "lax",
+			givenAttributes:    CookieAttributes{SameSite: "lax"},
+			expectedAttributes: "; SameSite=Lax",
+		},
+		{
+			name:               "strict-with-domain",
+			givenAttributes:    CookieAttributes{SameSite: "strict", Domain: "host.com"},
+			expectedAttributes: "; Domain=host.com;",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			WriteCookieWithAttributes(w, "value", &config.HostCookie{TTL: 1}, test.givenAttributes)
+
+			setCookie := w.Header().Get("Set-Cookie")
+			assert.Contains(t, setCookie, "uids=value")
+			assert.Contains(t, setCookie, test.expectedAttributes)
+		})
+	}
+}
@@ -253,6 +253,77 @@ func TestSync(t *testing.T) {
 	}
 }
 
+func TestSyncClearsAttempts(t *testing.T) {
+	cookie := NewCookie()
+	cookie.RecordSyncAttempt("adnxs", time.Hour)
+	cookie.RecordSyncAttempt("rubicon", time.Hour)
+
+	assert.NoError(t, cookie.Sync("adnxs", "123"))
+	assert.Equal(t, 0, cookie.SyncAttemptCount("adnxs", time.Hour))
+	assert.Equal(t, 1, cookie.SyncAttemptCount("rubicon", time.Hour))
+}
+
+func TestRecordSyncAttempt(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenCookie      *Cookie
+		givenWindow      time.Duration
+		expectedAttempts int
+	}{
+		{
+			name:             "first-attempt",
+			givenCookie:      NewCookie(),
+			givenWindow:      time.Hour,
+			expectedAttempts: 1,
+		},
+		{
+			name: "within-window",
+			givenCookie: &Cookie{
+				uids:     map[string]UIDEntry{},
+				attempts: map[string]SyncAttempts{"adnxs": {Count: 2, First: time.Now().Add(-30 * time.Minute)}},
+			},
+			givenWindow:      time.Hour,
+			expectedAttempts: 3,
+		},
+		{
+			name: "window-passed",
+			givenCookie: &Cookie{
+				uids:     map[string]UIDEntry{},
+				attempts: map[string]SyncAttempts{"adnxs": {Count: 2, First: time.Now().Add(-2 * time.Hour)}},
+			},
+			givenWindow:      time.Hour,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "opt-out",
+			givenCookie:      &Cookie{uids: map[string]UIDEntry{}, optOut: true},
+			givenWindow:      time.Hour,
+			expectedAttempts: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			test.givenCookie.RecordSyncAttempt("adnxs", test.givenWindow)
+			assert.Equal(t, test.expectedAttempts, test.givenCookie.SyncAttemptCount("adnxs", test.givenWindow))
+		})
+	}
+}
+
+func TestIsSyncOlderThan(t *testing.T) {
+	cookie := &Cookie{
+		uids: map[string]UIDEntry{
+			"fresh": {UID: "1", Expires: time.Now().Add(uidTTL - time.Hour)},
+			"old":   {UID: "2", Expires: time.Now().Add(uidTTL - 48*time.Hour)},
+		},
+	}
+
+	assert.False(t, cookie.IsSyncOlderThan("fresh", 24*time.Hour))
+	assert.True(t, cookie.IsSyncOlderThan("old", 24*time.Hour))
+	assert.False(t, cookie.IsSyncOlderThan("missing", 24*time.Hour))
+	assert.False(t, (*Cookie)(nil).IsSyncOlderThan("old", 24*time.Hour))
+}
+
 func TestGetUIDs(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -533,6 +604,25 @@ func TestPrepareCookieForWrite(t *testing.T) {
 	}
 }
 
+func TestPrepareCookieForWriteDropsAttempts(t *testing.T) {
+	cookie := &Cookie{
+		uids: map[string]UIDEntry{"adnxs": newTempId("123", 10)},
+		attempts: map[string]SyncAttempts{
+			"rubicon":  {Count: 1, First: time.Now()},
+			"pubmatic": {Count: 2, First: time.Now()},
+		},
+	}
+	uidOnlyCookie, err := Base64Encoder{}.Encode(&Cookie{uids: cookie.uids})
+	assert.NoError(t, err)
+
+	encodedCookie, err := cookie.PrepareCookieForWrite(&config.HostCookie{MaxCookieSizeBytes: len(uidOnlyCookie) + 80}, Base64Encoder{}, &OldestEjector{})
+	assert.NoError(t, err)
+
+	decodedCookie := Base64Decoder{}.Decode(encodedCookie)
+	assert.Len(t, decodedCookie.uids, 1)
+	assert.Empty(t, decodedCookie.attempts)
+}
+
 func TestSyncHostCookie(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -622,6 +712,74 @@ func TestSyncHostCookie(t *testing.T) {
 	}
 }
 
+func TestResolveHostCookie(t *testing.T) {
+	host := &config.HostCookie{
+		Family:     "pbs",
+		CookieName: "pbs_id",
+		Definitions: []config.HostCookieDefinition{
+			{Name: "brand-a", Hosts: []string{"pbs.brand-a.com"}, Family: "brand-a", CookieName: "a_id"},
+			{Name: "brand-b", Accounts: []string{"1001"}, Family: "brand-b", CookieName: "b_id"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		givenURL       string
+		givenAccount   string
+		expectedFamily string
+	}{
+		{
+			name:           "default",
+			givenURL:       "http://pbs.other.com/setuid",
+			expectedFamily: "pbs",
+		},
+		{
+			name:           "host",
+			givenURL:       "http://pbs.brand-a.com/setuid",
+			expectedFamily: "brand-a",
+		},
+		{
+			name:           "host-with-port",
+			givenURL:       "http://pbs.brand-a.com:8000/setuid",
+			expectedFamily: "brand-a",
+		},
+		{
+			name:           "account",
+			givenURL:       "http://pbs.brand-a.com/setuid",
+			givenAccount:   "1001",
+			expectedFamily: "brand-b",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", test.givenURL, nil)
+			resolved := ResolveHostCookie(r, host, test.givenAccount)
+			assert.Equal(t, test.expectedFamily, resolved.Family)
+		})
+	}
+}
+
+func TestReadCookieHostCookieDefinition(t *testing.T) {
+	host := &config.HostCookie{
+		Family:     "pbs",
+		CookieName: "pbs_id",
+		Definitions: []config.HostCookieDefinition{
+			{Name: "brand-a", Hosts: []string{"pbs.brand-a.com"}, Family: "brand-a", CookieName: "a_id"},
+		},
+	}
+
+	r := httptest.NewRequest("GET", "http://pbs.brand-a.com/getuids", nil)
+	r.AddCookie(&http.Cookie{Name: "pbs_id", Value: "pbs-user"})
+	r.AddCookie(&http.Cookie{Name: "a_id", Value: "brand-a-user"})
+
+	resolved := ResolveHostCookie(r, host, "")
+	cookie := ReadCookie(r, Base64Decoder{}, resolved)
+	SyncHostCookie(r, cookie, resolved)
+
+	assert.Equal(t, map[string]string{"brand-a": "brand-a-user"}, cookie.GetUIDs())
+}
+
 func TestBidderNameGets(t *testing.T) {
 	cookie := newSampleCookie()
 	id, exists, _ := cookie.GetUID("adnxs")
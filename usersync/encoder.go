@@ -3,6 +3,7 @@ package usersync
 import (
 	"encoding/base64"
 
+	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
 
@@ -11,6 +12,14 @@ type Encoder interface {
 	Encode(c *Cookie) (string, error)
 }
 
+// NewEncoder returns the encoder for the uids cookie encoding configured for the host.
+func NewEncoder(cfg *config.HostCookie) Encoder {
+	if cfg != nil && cfg.Encoding == config.CookieEncodingCompact {
+		return CompactEncoder{Compress: cfg.Compress}
+	}
+	return Base64Encoder{}
+}
+
 type Base64Encoder struct{}
 
 func (e Base64Encoder) Encode(c *Cookie) (string, error) {
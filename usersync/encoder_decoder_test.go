@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,6 +41,39 @@ func TestEncoderDecoder(t *testing.T) {
 				optOut: false,
 			},
 		},
+		{
+			name: "cookie-with-bucket",
+			givenCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "UID"}},
+				bucket: ptrutil.ToPtr(42),
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "UID"}},
+				bucket: ptrutil.ToPtr(42),
+			},
+		},
+		{
+			name: "opt-out-cookie-with-bucket",
+			givenCookie: &Cookie{
+				optOut: true,
+				bucket: ptrutil.ToPtr(7),
+			},
+			expectedCookie: &Cookie{
+				uids:   map[string]UIDEntry{},
+				optOut: true,
+				bucket: ptrutil.ToPtr(7),
+			},
+		},
+		{
+			name: "out-of-range-bucket",
+			givenCookie: &Cookie{
+				uids:   map[string]UIDEntry{"adnxs": {UID: "UID"}},
+				bucket: ptrutil.ToPtr(100),
+			},
+			expectedCookie: &Cookie{
+				uids: map[string]UIDEntry{"adnxs": {UID: "UID"}},
+			},
+		},
 		{
 			name:        "empty-cookie",
 			givenCookie: &Cookie{},
@@ -66,6 +100,7 @@ func TestEncoderDecoder(t *testing.T) {
 
 			assert.Equal(t, test.expectedCookie.uids, decodedCookie.uids)
 			assert.Equal(t, test.expectedCookie.optOut, decodedCookie.optOut)
+			assert.Equal(t, test.expectedCookie.bucket, decodedCookie.bucket)
 		})
 	}
 }
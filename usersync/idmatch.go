@@ -0,0 +1,11 @@
+package usersync
+
+import "github.com/prebid/openrtb/v20/openrtb2"
+
+// IDMatcher maps the IDs a user is known by to the UIDs of partners which sync server to server through match
+// tables rather than through pixels.
+type IDMatcher interface {
+	// Match returns the UID of the bidder matched to the host cookie ID or to one of the user's EIDs, and false
+	// if the user isn't matched.
+	Match(bidder, hostID string, eids []openrtb2.EID) (string, bool)
+}
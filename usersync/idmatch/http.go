@@ -0,0 +1,70 @@
+package idmatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// httpRunner invokes the load function with the table served at url whenever the endpoint reports it has
+// changed since the previous successful load.
+type httpRunner struct {
+	url          string
+	client       *http.Client
+	timeout      time.Duration
+	load         func(data []byte) error
+	lastModified string
+	mutex        sync.Mutex
+}
+
+func newHTTPRunner(url string, timeout time.Duration, load func(data []byte) error) *httpRunner {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &httpRunner{url: url, client: http.DefaultClient, timeout: timeout, load: load}
+}
+
+func (r *httpRunner) Run() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	if r.lastModified != "" {
+		request.Header.Set("If-Modified-Since", r.lastModified)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if err := r.load(data); err != nil {
+		return err
+	}
+
+	r.lastModified = response.Header.Get("Last-Modified")
+	return nil
+}
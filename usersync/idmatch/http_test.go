@@ -0,0 +1,73 @@
+package idmatch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRunner(t *testing.T) {
+	const lastModified = "Mon, 19 Oct 2026 10:00:00 GMT"
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("id-1,uid-1\n"))
+	}))
+	defer server.Close()
+
+	loads := 0
+	runner := newHTTPRunner(server.URL, 0, func(data []byte) error {
+		loads++
+		assert.Equal(t, "id-1,uid-1\n", string(data))
+		return nil
+	})
+	assert.Equal(t, defaultTimeout, runner.timeout, "default-timeout")
+
+	assert.NoError(t, runner.Run(), "first")
+	assert.NoError(t, runner.Run(), "not-modified")
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, loads)
+}
+
+func TestHTTPRunnerErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		handler     http.HandlerFunc
+		timeout     time.Duration
+	}{
+		{
+			description: "bad-status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{
+			description: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+			},
+			timeout: time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+
+			runner := newHTTPRunner(server.URL, test.timeout, func(data []byte) error {
+				assert.Fail(t, "unexpected load")
+				return nil
+			})
+			assert.Error(t, runner.Run())
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package idmatch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// New returns the matcher of the configured match tables, or nil if there are none. Each table is loaded from
+// its file or its HTTP endpoint and reloaded periodically. It returns an error if a table cannot be loaded, while
+// a failed reload keeps the previous contents of the table.
+func New(cfgs []config.MatchTable) (usersync.IDMatcher, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+
+	matcher := &Matcher{tables: make(map[string][]*table, len(cfgs))}
+	for _, cfg := range cfgs {
+		t := &table{idSource: cfg.IDSource}
+		var reloader task.Runner
+		source := cfg.Path
+		if cfg.URL != "" {
+			reloader = newHTTPRunner(cfg.URL, cfg.Timeout(), t.load)
+			source = cfg.URL
+		} else {
+			reloader = task.NewFileRunner(cfg.Path, t.load)
+		}
+		if err := reloader.Run(); err != nil {
+			return nil, fmt.Errorf("match table of %s could not be loaded from %s: %v", cfg.Bidder, source, err)
+		}
+		if cfg.RefreshIntervalSeconds > 0 {
+			task.NewTickerTask(cfg.RefreshInterval(), reloader).Start()
+		}
+		matcher.add(cfg.Bidder, t)
+	}
+	return matcher, nil
+}
+
+// Matcher looks users up in the match tables of the bidders. A bidder may have several tables keyed by
+// different IDs, which are looked up in the configured order.
+type Matcher struct {
+	tables map[string][]*table
+}
+
+func (m *Matcher) add(bidder string, t *table) {
+	bidder = strings.ToLower(bidder)
+	m.tables[bidder] = append(m.tables[bidder], t)
+}
+
+func (m *Matcher) Match(bidder, hostID string, eids []openrtb2.EID) (string, bool) {
+	for _, t := range m.tables[strings.ToLower(bidder)] {
+		if uid, ok := t.match(hostID, eids); ok {
+			return uid, true
+		}
+	}
+	return "", false
+}
+
+// table maps the IDs of a single source to the UIDs of a partner. The table is empty until it's loaded
+// successfully, after which a failed reload keeps the previous contents.
+type table struct {
+	idSource string
+	uids     atomic.Pointer[map[string]string]
+}
+
+func (t *table) match(hostID string, eids []openrtb2.EID) (string, bool) {
+	uids := t.uids.Load()
+	if uids == nil {
+		return "", false
+	}
+
+	if t.idSource == "" {
+		return lookup(*uids, hostID)
+	}
+	for _, eid := range eids {
+		if eid.Source != t.idSource {
+			continue
+		}
+		for _, uid := range eid.UIDs {
+			if partnerUID, ok := lookup(*uids, uid.ID); ok {
+				return partnerUID, true
+			}
+		}
+	}
+	return "", false
+}
+
+func lookup(uids map[string]string, id string) (string, bool) {
+	if id == "" {
+		return "", false
+	}
+	uid, ok := uids[id]
+	return uid, ok
+}
+
+// load parses a table made of CSV rows holding a user ID followed by the partner UID.
+func (t *table) load(data []byte) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 2
+	reader.ReuseRecord = true
+
+	uids := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if record[0] == "" || record[1] == "" {
+			return errors.New("match table rows must hold both a user ID and a partner UID")
+		}
+		uids[record[0]] = record[1]
+	}
+	t.uids.Store(&uids)
+	return nil
+}
@@ -0,0 +1,206 @@
+package idmatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	matcher, err := New(nil)
+	assert.NoError(t, err, "none")
+	assert.Nil(t, matcher, "none")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "appnexus.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("host-1,adnxs-1\n"), 0644))
+
+	matcher, err = New([]config.MatchTable{
+		{Bidder: "appnexus", Path: path},
+	})
+	assert.NoError(t, err)
+	if !assert.NotNil(t, matcher) {
+		return
+	}
+
+	uid, ok := matcher.Match("appnexus", "host-1", nil)
+	assert.True(t, ok, "loaded")
+	assert.Equal(t, "adnxs-1", uid, "loaded")
+
+	_, ok = matcher.Match("rubicon", "host-1", nil)
+	assert.False(t, ok, "no-table")
+}
+
+func TestNewLoadError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "appnexus.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("host-1,adnxs-1\n"), 0644))
+	malformed := filepath.Join(dir, "malformed.csv")
+	assert.NoError(t, os.WriteFile(malformed, []byte("host-1\n"), 0644))
+
+	testCases := []struct {
+		description string
+		tables      []config.MatchTable
+	}{
+		{
+			description: "missing-file",
+			tables: []config.MatchTable{
+				{Bidder: "appnexus", Path: path},
+				{Bidder: "rubicon", Path: filepath.Join(dir, "missing.csv")},
+			},
+		},
+		{
+			description: "malformed-file",
+			tables: []config.MatchTable{
+				{Bidder: "rubicon", Path: malformed},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			matcher, err := New(test.tables)
+			assert.ErrorContains(t, err, "match table of rubicon could not be loaded")
+			assert.Nil(t, matcher)
+		})
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	hostTable := &table{}
+	hostTable.uids.Store(&map[string]string{"host-1": "host-matched"})
+	eidTable := &table{idSource: "pubcid.org"}
+	eidTable.uids.Store(&map[string]string{"pubcid-1": "pubcid-matched", "host-2": "eid-not-host"})
+
+	matcher := &Matcher{tables: map[string][]*table{}}
+	matcher.add("Appnexus", hostTable)
+	matcher.add("appnexus", eidTable)
+	matcher.add("rubicon", &table{})
+
+	pubcid := []openrtb2.EID{
+		{Source: "other.org", UIDs: []openrtb2.UID{{ID: "pubcid-1"}}},
+		{Source: "pubcid.org", UIDs: []openrtb2.UID{{ID: "unknown"}, {ID: "pubcid-1"}}},
+	}
+
+	testCases := []struct {
+		description string
+		bidder      string
+		hostID      string
+		eids        []openrtb2.EID
+		expectedUID string
+		expectedOK  bool
+	}{
+		{
+			description: "host-id",
+			bidder:      "appnexus",
+			hostID:      "host-1",
+			expectedUID: "host-matched",
+			expectedOK:  true,
+		},
+		{
+			description: "host-id-first",
+			bidder:      "appnexus",
+			hostID:      "host-1",
+			eids:        pubcid,
+			expectedUID: "host-matched",
+			expectedOK:  true,
+		},
+		{
+			description: "eid",
+			bidder:      "appnexus",
+			hostID:      "host-unknown",
+			eids:        pubcid,
+			expectedUID: "pubcid-matched",
+			expectedOK:  true,
+		},
+		{
+			description: "case-insensitive-bidder",
+			bidder:      "APPNEXUS",
+			hostID:      "host-1",
+			expectedUID: "host-matched",
+			expectedOK:  true,
+		},
+		{
+			description: "eid-table-ignores-host-id",
+			bidder:      "appnexus",
+			hostID:      "host-2",
+			expectedOK:  false,
+		},
+		{
+			description: "no-ids",
+			bidder:      "appnexus",
+			expectedOK:  false,
+		},
+		{
+			description: "table-not-loaded",
+			bidder:      "rubicon",
+			hostID:      "host-1",
+			expectedOK:  false,
+		},
+		{
+			description: "no-table",
+			bidder:      "pubmatic",
+			hostID:      "host-1",
+			expectedOK:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			uid, ok := matcher.Match(test.bidder, test.hostID, test.eids)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedUID, uid)
+		})
+	}
+}
+
+func TestTableLoad(t *testing.T) {
+	testCases := []struct {
+		description  string
+		data         string
+		expectedUIDs map[string]string
+		expectError  bool
+	}{
+		{
+			description:  "valid",
+			data:         "id-1,uid-1\n\"id,2\",uid-2\n",
+			expectedUIDs: map[string]string{"id-1": "uid-1", "id,2": "uid-2"},
+		},
+		{
+			description:  "empty",
+			data:         "",
+			expectedUIDs: map[string]string{},
+		},
+		{
+			description:  "wrong-column-count",
+			data:         "id-1,uid-1\nid-2\n",
+			expectedUIDs: map[string]string{"previous": "uid"},
+			expectError:  true,
+		},
+		{
+			description:  "empty-uid",
+			data:         "id-1,\n",
+			expectedUIDs: map[string]string{"previous": "uid"},
+			expectError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			table := &table{}
+			table.uids.Store(&map[string]string{"previous": "uid"})
+
+			err := table.load([]byte(test.data))
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedUIDs, *table.uids.Load())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package usersync
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSetUIDSignatureMissing = errors.New("the setuid request must be signed by the bidder")
+	ErrSetUIDSignatureExpired = errors.New("the setuid request signature expired")
+	ErrSetUIDSignatureInvalid = errors.New("the setuid request signature is invalid")
+	ErrSetUIDSignatureReplay  = errors.New("the setuid request signature was already used")
+	ErrReplayGuardFull        = errors.New("too many setuid request signatures are in use, please retry later")
+)
+
+// SignSetUID returns the signature of a /setuid redirect, the hex encoded HMAC-SHA256 of the bidder, the UID and
+// the unix timestamp in seconds, joined with '|'. Bidders sign their redirects with it and send the signature
+// in the "sig" query param and the timestamp in the "ts" query param.
+func SignSetUID(key, bidder, uid string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(bidder + "|" + uid + "|" + strconv.FormatInt(timestamp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySetUIDSignature verifies the signature of a /setuid redirect signed with any of the keys no longer than
+// maxAge ago. It returns the time the signature was made at.
+func VerifySetUIDSignature(keys []string, bidder, uid, timestamp, signature string, now time.Time, maxAge time.Duration) (time.Time, error) {
+	if timestamp == "" || signature == "" {
+		return time.Time{}, ErrSetUIDSignatureMissing
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrSetUIDSignatureInvalid
+	}
+	signedAt := time.Unix(seconds, 0)
+	if age := now.Sub(signedAt); age > maxAge || age < -maxAge {
+		return time.Time{}, ErrSetUIDSignatureExpired
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return time.Time{}, ErrSetUIDSignatureInvalid
+	}
+	for _, key := range keys {
+		expected, _ := hex.DecodeString(SignSetUID(key, bidder, uid, seconds))
+		if hmac.Equal(given, expected) {
+			return signedAt, nil
+		}
+	}
+	return time.Time{}, ErrSetUIDSignatureInvalid
+}
+
+// ReplayGuard remembers the signatures used until they expire, to reject the redirects replayed while their
+// signature is still valid. The number of signatures remembered is bounded. A signature still valid is never
+// forgotten, so new signatures are rejected while the guard is full of them: the guard accepts at most size
+// signatures per signature lifetime.
+type ReplayGuard struct {
+	size int
+
+	mutex   sync.Mutex
+	expires map[string]time.Time
+	order   *list.List
+}
+
+// NewReplayGuard returns a guard remembering up to size signatures.
+func NewReplayGuard(size int) *ReplayGuard {
+	return &ReplayGuard{
+		size:    size,
+		expires: make(map[string]time.Time),
+		order:   list.New(),
+	}
+}
+
+type replayEntry struct {
+	signature string
+	expires   time.Time
+}
+
+// Use records the signature as used until it expires. It returns ErrSetUIDSignatureReplay if the signature was
+// already used and didn't expire yet, and ErrReplayGuardFull if the guard is full of signatures which didn't
+// expire yet.
+func (g *ReplayGuard) Use(signature string, expires, now time.Time) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	// the signatures are mostly used in the order they expire, so the expired ones are usually at the front
+	for g.order.Len() > 0 {
+		oldest := g.order.Front()
+		if oldest.Value.(replayEntry).expires.After(now) {
+			break
+		}
+		g.forget(oldest)
+	}
+
+	if expiry, ok := g.expires[signature]; ok && expiry.After(now) {
+		return ErrSetUIDSignatureReplay
+	}
+
+	if g.order.Len() >= g.size {
+		for element := g.order.Front(); element != nil; {
+			next := element.Next()
+			if !element.Value.(replayEntry).expires.After(now) {
+				g.forget(element)
+			}
+			element = next
+		}
+		if g.order.Len() >= g.size {
+			return ErrReplayGuardFull
+		}
+	}
+
+	g.expires[signature] = expires
+	g.order.PushBack(replayEntry{signature: signature, expires: expires})
+	return nil
+}
+
+func (g *ReplayGuard) forget(element *list.Element) {
+	entry := g.order.Remove(element).(replayEntry)
+	if g.expires[entry.signature].Equal(entry.expires) {
+		delete(g.expires, entry.signature)
+	}
+}
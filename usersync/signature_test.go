@@ -0,0 +1,165 @@
+package usersync
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignSetUID(t *testing.T) {
+	signature := SignSetUID("key", "appnexus", "123", 1700000000)
+
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, SignSetUID("key", "appnexus", "123", 1700000000), "deterministic")
+	assert.NotEqual(t, signature, SignSetUID("other", "appnexus", "123", 1700000000), "key")
+	assert.NotEqual(t, signature, SignSetUID("key", "rubicon", "123", 1700000000), "bidder")
+	assert.NotEqual(t, signature, SignSetUID("key", "appnexus", "456", 1700000000), "uid")
+	assert.NotEqual(t, signature, SignSetUID("key", "appnexus", "123", 1700000001), "timestamp")
+}
+
+func TestVerifySetUIDSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	maxAge := 5 * time.Minute
+	signedAt := now.Add(-time.Minute).Unix()
+	timestamp := strconv.FormatInt(signedAt, 10)
+
+	testCases := []struct {
+		description      string
+		givenKeys        []string
+		givenUID         string
+		givenTimestamp   string
+		givenSignature   string
+		expectedSignedAt time.Time
+		expectedError    error
+	}{
+		{
+			description:      "valid",
+			givenKeys:        []string{"key"},
+			givenUID:         "123",
+			givenTimestamp:   timestamp,
+			givenSignature:   SignSetUID("key", "appnexus", "123", signedAt),
+			expectedSignedAt: time.Unix(signedAt, 0),
+		},
+		{
+			description:      "valid-rotated-key",
+			givenKeys:        []string{"new", "old"},
+			givenUID:         "123",
+			givenTimestamp:   timestamp,
+			givenSignature:   SignSetUID("old", "appnexus", "123", signedAt),
+			expectedSignedAt: time.Unix(signedAt, 0),
+		},
+		{
+			description:      "valid-empty-uid",
+			givenKeys:        []string{"key"},
+			givenUID:         "",
+			givenTimestamp:   timestamp,
+			givenSignature:   SignSetUID("key", "appnexus", "", signedAt),
+			expectedSignedAt: time.Unix(signedAt, 0),
+		},
+		{
+			description:    "missing-signature",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: timestamp,
+			expectedError:  ErrSetUIDSignatureMissing,
+		},
+		{
+			description:    "missing-timestamp",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenSignature: SignSetUID("key", "appnexus", "123", signedAt),
+			expectedError:  ErrSetUIDSignatureMissing,
+		},
+		{
+			description:    "malformed-timestamp",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: "yesterday",
+			givenSignature: SignSetUID("key", "appnexus", "123", signedAt),
+			expectedError:  ErrSetUIDSignatureInvalid,
+		},
+		{
+			description:    "expired",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: strconv.FormatInt(now.Add(-6*time.Minute).Unix(), 10),
+			givenSignature: SignSetUID("key", "appnexus", "123", now.Add(-6*time.Minute).Unix()),
+			expectedError:  ErrSetUIDSignatureExpired,
+		},
+		{
+			description:    "future",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: strconv.FormatInt(now.Add(6*time.Minute).Unix(), 10),
+			givenSignature: SignSetUID("key", "appnexus", "123", now.Add(6*time.Minute).Unix()),
+			expectedError:  ErrSetUIDSignatureExpired,
+		},
+		{
+			description:    "wrong-key",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: timestamp,
+			givenSignature: SignSetUID("other", "appnexus", "123", signedAt),
+			expectedError:  ErrSetUIDSignatureInvalid,
+		},
+		{
+			description:    "tampered-uid",
+			givenKeys:      []string{"key"},
+			givenUID:       "456",
+			givenTimestamp: timestamp,
+			givenSignature: SignSetUID("key", "appnexus", "123", signedAt),
+			expectedError:  ErrSetUIDSignatureInvalid,
+		},
+		{
+			description:    "malformed-signature",
+			givenKeys:      []string{"key"},
+			givenUID:       "123",
+			givenTimestamp: timestamp,
+			givenSignature: "not-hex",
+			expectedError:  ErrSetUIDSignatureInvalid,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			signedAt, err := VerifySetUIDSignature(test.givenKeys, "appnexus", test.givenUID, test.givenTimestamp, test.givenSignature, now, maxAge)
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedSignedAt, signedAt)
+		})
+	}
+}
+
+func TestReplayGuard(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := NewReplayGuard(10)
+
+	assert.NoError(t, guard.Use("a", now.Add(time.Minute), now), "first use")
+	assert.Equal(t, ErrSetUIDSignatureReplay, guard.Use("a", now.Add(time.Minute), now), "replay")
+	assert.NoError(t, guard.Use("b", now.Add(time.Minute), now), "other signature")
+
+	later := now.Add(2 * time.Minute)
+	assert.NoError(t, guard.Use("a", later.Add(time.Minute), later), "expired")
+	assert.Equal(t, ErrSetUIDSignatureReplay, guard.Use("a", later.Add(time.Minute), later), "replay after reuse")
+	assert.Len(t, guard.expires, 1, "expired signatures forgotten")
+}
+
+func TestReplayGuardBounded(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := NewReplayGuard(2)
+
+	assert.NoError(t, guard.Use("a", now.Add(2*time.Minute), now))
+	assert.NoError(t, guard.Use("b", now.Add(time.Minute), now))
+	assert.Equal(t, ErrReplayGuardFull, guard.Use("c", now.Add(time.Minute), now), "full of valid signatures")
+	assert.Equal(t, ErrSetUIDSignatureReplay, guard.Use("a", now.Add(2*time.Minute), now), "valid signatures never forgotten")
+
+	// the expired signature is forgotten even though it isn't the oldest one
+	later := now.Add(90 * time.Second)
+	assert.NoError(t, guard.Use("c", later.Add(time.Minute), later), "expired signature purged")
+	assert.Len(t, guard.expires, 2)
+	assert.NotContains(t, guard.expires, "b", "expired signature forgotten")
+	assert.Equal(t, ErrSetUIDSignatureReplay, guard.Use("a", now.Add(2*time.Minute), later))
+	assert.Equal(t, ErrSetUIDSignatureReplay, guard.Use("c", later.Add(time.Minute), later))
+	assert.Equal(t, ErrReplayGuardFull, guard.Use("d", later.Add(time.Minute), later), "still full")
+}
@@ -0,0 +1,79 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// UIDStore keeps the bidder UIDs of a user on the server, keyed by a host identifier, so they survive browsers
+// limiting the size or the lifetime of the uids cookie.
+type UIDStore interface {
+	// Get returns the UIDs stored for the host identifier, leaving out the expired ones.
+	Get(ctx context.Context, id string) (map[string]UIDEntry, error)
+	// Set stores the UID of the syncer key for the host identifier until the entry expires.
+	Set(ctx context.Context, id string, key string, entry UIDEntry) error
+	// Delete removes the UID of the syncer key, or all the UIDs of the host identifier if the key is empty.
+	Delete(ctx context.Context, id string, key string) error
+}
+
+// StoreID returns the identifier of the user the UIDs are stored under. It's read from the configured first
+// party cookie, else from the host cookie. An empty string means the user can't be identified.
+func StoreID(r *http.Request, cookie *Cookie, host *config.HostCookie, cfg *config.UIDStore) string {
+	if cfg.IDCookieName != "" {
+		if idCookie, err := r.Cookie(cfg.IDCookieName); err == nil {
+			return idCookie.Value
+		}
+		return ""
+	}
+
+	if host.CookieName != "" {
+		if hostCookie, err := r.Cookie(host.CookieName); err == nil && hostCookie.Value != "" {
+			return hostCookie.Value
+		}
+	}
+	if uid, _, notExpired := cookie.GetUID(host.Family); notExpired {
+		return uid
+	}
+	return ""
+}
+
+// StoreUID stores the UID of the syncer key with the same expiry and checks as Cookie.Sync.
+func StoreUID(ctx context.Context, store UIDStore, cookie *Cookie, id string, key string, uid string) error {
+	if !cookie.AllowSyncs() {
+		return errors.New("the user has opted out of prebid server cookie syncs")
+	}
+	if checkAudienceNetwork(key, uid) {
+		return errors.New("audienceNetwork uses a UID of 0 as \"not yet recognized\"")
+	}
+
+	return store.Set(ctx, id, key, UIDEntry{
+		UID:     uid,
+		Expires: time.Now().Add(uidTTL),
+	})
+}
+
+// StoredUIDs are the UIDs read from a UIDStore for a user.
+type StoredUIDs map[string]UIDEntry
+
+// GetUID returns the stored UID of the syncer key, whether it exists and whether it is still live.
+func (s StoredUIDs) GetUID(key string) (string, bool, bool) {
+	if entry, ok := s[key]; ok {
+		return entry.UID, true, time.Now().Before(entry.Expires)
+	}
+	return "", false, false
+}
+
+// HasAnyLiveSyncs returns true if any of the stored UIDs is still live.
+func (s StoredUIDs) HasAnyLiveSyncs() bool {
+	now := time.Now()
+	for _, entry := range s {
+		if now.Before(entry.Expires) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package usersync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreID(t *testing.T) {
+	host := &config.HostCookie{Family: "host", CookieName: "host_cookie"}
+
+	testCases := []struct {
+		name          string
+		givenCookies  []*http.Cookie
+		givenUIDs     map[string]UIDEntry
+		givenIDCookie string
+		expectedID    string
+	}{
+		{
+			name:         "host-cookie",
+			givenCookies: []*http.Cookie{{Name: "host_cookie", Value: "host-id"}},
+			expectedID:   "host-id",
+		},
+		{
+			name:       "host-uid-in-cookie",
+			givenUIDs:  map[string]UIDEntry{"host": {UID: "synced-host-id", Expires: time.Now().Add(time.Hour)}},
+			expectedID: "synced-host-id",
+		},
+		{
+			name:       "expired-host-uid-in-cookie",
+			givenUIDs:  map[string]UIDEntry{"host": {UID: "synced-host-id", Expires: time.Now().Add(-time.Hour)}},
+			expectedID: "",
+		},
+		{
+			name:          "first-party-cookie",
+			givenCookies:  []*http.Cookie{{Name: "host_cookie", Value: "host-id"}, {Name: "fpid", Value: "first-party-id"}},
+			givenIDCookie: "fpid",
+			expectedID:    "first-party-id",
+		},
+		{
+			name:          "first-party-cookie-missing",
+			givenCookies:  []*http.Cookie{{Name: "host_cookie", Value: "host-id"}},
+			givenIDCookie: "fpid",
+			expectedID:    "",
+		},
+		{
+			name:       "none",
+			expectedID: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/setuid", nil)
+			for _, c := range test.givenCookies {
+				r.AddCookie(c)
+			}
+			cookie := NewCookie()
+			for key, entry := range test.givenUIDs {
+				cookie.uids[key] = entry
+			}
+
+			assert.Equal(t, test.expectedID, StoreID(r, cookie, host, &config.UIDStore{IDCookieName: test.givenIDCookie}))
+		})
+	}
+}
+
+func TestStoreUID(t *testing.T) {
+	optOutCookie := NewCookie()
+	optOutCookie.SetOptOut(true)
+
+	testCases := []struct {
+		name          string
+		givenCookie   *Cookie
+		givenKey      string
+		givenUID      string
+		expectedError string
+		expectedSet   bool
+	}{
+		{
+			name:        "stored",
+			givenCookie: NewCookie(),
+			givenKey:    "adnxs",
+			givenUID:    "123",
+			expectedSet: true,
+		},
+		{
+			name:          "opted-out",
+			givenCookie:   optOutCookie,
+			givenKey:      "adnxs",
+			givenUID:      "123",
+			expectedError: "the user has opted out of prebid server cookie syncs",
+		},
+		{
+			name:          "audience-network-unrecognized",
+			givenCookie:   NewCookie(),
+			givenKey:      "audienceNetwork",
+			givenUID:      "0",
+			expectedError: "audienceNetwork uses a UID of 0 as \"not yet recognized\"",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			store := &fakeUIDStore{}
+			err := StoreUID(context.Background(), store, test.givenCookie, "id", test.givenKey, test.givenUID)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			if test.expectedSet {
+				assert.Equal(t, "id", store.id)
+				assert.Equal(t, test.givenKey, store.key)
+				assert.Equal(t, test.givenUID, store.entry.UID)
+				assert.WithinDuration(t, time.Now().Add(uidTTL), store.entry.Expires, time.Minute)
+			} else {
+				assert.Empty(t, store.id)
+			}
+		})
+	}
+}
+
+func TestStoredUIDs(t *testing.T) {
+	uids := StoredUIDs{
+		"live":    {UID: "live-uid", Expires: time.Now().Add(time.Hour)},
+		"expired": {UID: "expired-uid", Expires: time.Now().Add(-time.Hour)},
+	}
+
+	uid, exists, notExpired := uids.GetUID("live")
+	assert.Equal(t, "live-uid", uid)
+	assert.True(t, exists)
+	assert.True(t, notExpired)
+
+	uid, exists, notExpired = uids.GetUID("expired")
+	assert.Equal(t, "expired-uid", uid)
+	assert.True(t, exists)
+	assert.False(t, notExpired)
+
+	uid, exists, notExpired = uids.GetUID("missing")
+	assert.Empty(t, uid)
+	assert.False(t, exists)
+	assert.False(t, notExpired)
+
+	assert.True(t, uids.HasAnyLiveSyncs())
+	assert.False(t, StoredUIDs{"expired": uids["expired"]}.HasAnyLiveSyncs())
+}
+
+type fakeUIDStore struct {
+	id    string
+	key   string
+	entry UIDEntry
+}
+
+func (s *fakeUIDStore) Get(ctx context.Context, id string) (map[string]UIDEntry, error) {
+	return nil, nil
+}
+
+func (s *fakeUIDStore) Set(ctx context.Context, id string, key string, entry UIDEntry) error {
+	s.id, s.key, s.entry = id, key, entry
+	return nil
+}
+
+func (s *fakeUIDStore) Delete(ctx context.Context, id string, key string) error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package usersync
+
+// SyncValues provide the value of a synced UID to each bidder, which orders the bidders to sync by yield.
+type SyncValues interface {
+	// Value returns the value of a synced UID to the bidder for the account and the country, and false if it's
+	// unknown.
+	Value(account, country, bidder string) (float64, bool)
+}
+
+// SyncValueRecorder learns the values of synced UIDs from auction outcomes.
+type SyncValueRecorder interface {
+	// RecordSyncValue records the revenue offered in an auction of the account in the country by a bidder which
+	// was sent a synced UID.
+	RecordSyncValue(account, country, bidder string, value float64)
+}
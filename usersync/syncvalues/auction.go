@@ -0,0 +1,52 @@
+package syncvalues
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// AuctionValues are the values of synced UIDs learned from auction outcomes, as the moving average of the
+// revenue offered by each bidder holding a synced UID. The values are kept for the account and country of the
+// auction as well as for the account, the country and all auctions, so bidders are ranked even where few
+// auctions were seen.
+type AuctionValues struct {
+	weight     float64
+	maxEntries int
+	mutex      sync.RWMutex
+	values     map[valueKey]float64
+}
+
+// NewAuctionValues returns empty values, learned as auction outcomes are recorded.
+func NewAuctionValues(cfg config.SyncValuesAuction) *AuctionValues {
+	return &AuctionValues{
+		weight:     cfg.Weight,
+		maxEntries: cfg.MaxEntries,
+		values:     make(map[valueKey]float64),
+	}
+}
+
+func (v *AuctionValues) Value(account, country, bidder string) (float64, bool) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return lookup(v.values, account, country, bidder)
+}
+
+// RecordSyncValue updates the moving averages of the bidder. New values are no longer added once the maximum
+// number of entries is reached, but the existing ones keep being updated.
+func (v *AuctionValues) RecordSyncValue(account, country, bidder string, value float64) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for _, key := range fallbackKeys(account, country, bidder) {
+		current, ok := v.values[key]
+		if !ok {
+			if len(v.values) >= v.maxEntries {
+				continue
+			}
+			v.values[key] = value
+			continue
+		}
+		v.values[key] = current + v.weight*(value-current)
+	}
+}
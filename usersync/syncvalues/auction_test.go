@@ -0,0 +1,51 @@
+package syncvalues
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionValuesRecord(t *testing.T) {
+	values := NewAuctionValues(config.SyncValuesAuction{Weight: 0.5, MaxEntries: 100})
+
+	values.RecordSyncValue("1001", "USA", "a", 4)
+	values.RecordSyncValue("1001", "USA", "a", 2)
+	values.RecordSyncValue("1002", "CAN", "a", 1)
+
+	testCases := []struct {
+		description   string
+		givenAccount  string
+		givenCountry  string
+		expectedValue float64
+	}{
+		{description: "account-and-country", givenAccount: "1001", givenCountry: "USA", expectedValue: 3},
+		{description: "account", givenAccount: "1001", givenCountry: "GBR", expectedValue: 3},
+		{description: "country", givenAccount: "1003", givenCountry: "CAN", expectedValue: 1},
+		{description: "global", givenAccount: "1003", givenCountry: "GBR", expectedValue: 2},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			value, found := values.Value(test.givenAccount, test.givenCountry, "a")
+			assert.True(t, found)
+			assert.Equal(t, test.expectedValue, value)
+		})
+	}
+}
+
+func TestAuctionValuesMaxEntries(t *testing.T) {
+	values := NewAuctionValues(config.SyncValuesAuction{Weight: 0.5, MaxEntries: 4})
+
+	values.RecordSyncValue("1001", "USA", "a", 4)
+	values.RecordSyncValue("1001", "USA", "b", 2)
+	values.RecordSyncValue("1001", "USA", "a", 2)
+
+	assert.Len(t, values.values, 4)
+	value, found := values.Value("1001", "USA", "a")
+	assert.True(t, found, "existing values must keep being updated")
+	assert.Equal(t, 3.0, value)
+	_, found = values.Value("1001", "USA", "b")
+	assert.False(t, found, "new values must not be added once full")
+}
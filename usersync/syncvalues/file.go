@@ -0,0 +1,81 @@
+package syncvalues
+
+import (
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// FileValues are the values of synced UIDs read from a local file, which is reloaded whenever it changes. The
+// file holds the global values of each bidder, along with the ones of countries and accounts:
+//
+//	{
+//	  "bidders": {"appnexus": 1.2},
+//	  "countries": {"USA": {"appnexus": 2.5}},
+//	  "accounts": {"1001": {"bidders": {"appnexus": 0.8}, "countries": {"USA": {"appnexus": 1.4}}}}
+//	}
+type FileValues struct {
+	values atomic.Pointer[map[valueKey]float64]
+}
+
+type fileValuesJSON struct {
+	Bidders   map[string]float64               `json:"bidders"`
+	Countries map[string]map[string]float64    `json:"countries"`
+	Accounts  map[string]fileAccountValuesJSON `json:"accounts"`
+}
+
+type fileAccountValuesJSON struct {
+	Bidders   map[string]float64            `json:"bidders"`
+	Countries map[string]map[string]float64 `json:"countries"`
+}
+
+// NewFileValues loads the configured file and reloads it periodically. The values are unknown until the file
+// is loaded successfully.
+func NewFileValues(cfg config.SyncValuesFile) *FileValues {
+	values := &FileValues{}
+	reloader := task.NewFileRunner(cfg.Path, values.load)
+	if err := reloader.Run(); err != nil {
+		glog.Errorf("Sync values could not be loaded from %s: %v", cfg.Path, err)
+	}
+	if cfg.RefreshIntervalSeconds > 0 {
+		task.NewTickerTask(cfg.RefreshInterval(), reloader).Start()
+	}
+	return values
+}
+
+func (v *FileValues) Value(account, country, bidder string) (float64, bool) {
+	values := v.values.Load()
+	if values == nil {
+		return 0, false
+	}
+	return lookup(*values, account, country, bidder)
+}
+
+func (v *FileValues) load(data []byte) error {
+	var file fileValuesJSON
+	if err := jsonutil.UnmarshalValid(data, &file); err != nil {
+		return err
+	}
+
+	values := make(map[valueKey]float64)
+	addValues(values, "", file.Bidders, file.Countries)
+	for account, accountValues := range file.Accounts {
+		addValues(values, account, accountValues.Bidders, accountValues.Countries)
+	}
+	v.values.Store(&values)
+	return nil
+}
+
+func addValues(values map[valueKey]float64, account string, bidders map[string]float64, countries map[string]map[string]float64) {
+	for bidder, value := range bidders {
+		values[valueKey{account: account, bidder: bidder}] = value
+	}
+	for country, countryBidders := range countries {
+		for bidder, value := range countryBidders {
+			values[valueKey{account: account, country: country, bidder: bidder}] = value
+		}
+	}
+}
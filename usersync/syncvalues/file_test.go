@@ -0,0 +1,60 @@
+package syncvalues
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	err := os.WriteFile(path, []byte(`{
+		"bidders": {"a": 1, "b": 2},
+		"countries": {"USA": {"a": 3}},
+		"accounts": {"1001": {"bidders": {"b": 4}, "countries": {"USA": {"b": 5}}}}
+	}`), 0644)
+	assert.NoError(t, err)
+
+	values := NewFileValues(config.SyncValuesFile{Path: path})
+
+	testCases := []struct {
+		description   string
+		givenAccount  string
+		givenCountry  string
+		givenBidder   string
+		expectedValue float64
+		expectedFound bool
+	}{
+		{description: "global", givenAccount: "1002", givenCountry: "CAN", givenBidder: "a", expectedValue: 1, expectedFound: true},
+		{description: "country", givenAccount: "1001", givenCountry: "USA", givenBidder: "a", expectedValue: 3, expectedFound: true},
+		{description: "account", givenAccount: "1001", givenCountry: "CAN", givenBidder: "b", expectedValue: 4, expectedFound: true},
+		{description: "account-and-country", givenAccount: "1001", givenCountry: "USA", givenBidder: "b", expectedValue: 5, expectedFound: true},
+		{description: "unknown-bidder", givenAccount: "1001", givenCountry: "USA", givenBidder: "c", expectedValue: 0, expectedFound: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			value, found := values.Value(test.givenAccount, test.givenCountry, test.givenBidder)
+			assert.Equal(t, test.expectedValue, value)
+			assert.Equal(t, test.expectedFound, found)
+		})
+	}
+}
+
+func TestFileValuesLoadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	values := NewFileValues(config.SyncValuesFile{Path: path})
+
+	_, found := values.Value("1001", "USA", "a")
+	assert.False(t, found, "values must be unknown while the file is missing")
+
+	assert.NoError(t, values.load([]byte(`{"bidders": {"a": 1}}`)))
+	assert.Error(t, values.load([]byte(`malformed`)))
+
+	value, found := values.Value("1001", "USA", "a")
+	assert.True(t, found, "a malformed file must not replace the loaded values")
+	assert.Equal(t, 1.0, value)
+}
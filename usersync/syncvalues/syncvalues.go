@@ -0,0 +1,54 @@
+// Package syncvalues provides the values of synced UIDs to each bidder, which order the bidders to sync for
+// accounts using the yield cookie sync prioritization.
+package syncvalues
+
+import (
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+)
+
+// New returns the sync values of the configured source, or nil if there is none. The values learned from
+// auctions also implement usersync.SyncValueRecorder.
+func New(cfg *config.SyncValues) usersync.SyncValues {
+	switch cfg.Source {
+	case config.SyncValuesSourceFile:
+		return NewFileValues(cfg.File)
+	case config.SyncValuesSourceAuction:
+		return NewAuctionValues(cfg.Auction)
+	default:
+		return nil
+	}
+}
+
+// valueKey identifies the value of a synced UID to a bidder for an account and a country. An empty account or
+// country stands for all of them.
+type valueKey struct {
+	account string
+	country string
+	bidder  string
+}
+
+// lookup returns the most specific value of the bidder, from the account and country one to the global one.
+func lookup(values map[valueKey]float64, account, country, bidder string) (float64, bool) {
+	for _, key := range fallbackKeys(account, country, bidder) {
+		if value, ok := values[key]; ok {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// fallbackKeys returns the keys of the values applying to the account and country, most specific first.
+func fallbackKeys(account, country, bidder string) []valueKey {
+	keys := make([]valueKey, 0, 4)
+	if account != "" && country != "" {
+		keys = append(keys, valueKey{account: account, country: country, bidder: bidder})
+	}
+	if account != "" {
+		keys = append(keys, valueKey{account: account, bidder: bidder})
+	}
+	if country != "" {
+		keys = append(keys, valueKey{country: country, bidder: bidder})
+	}
+	return append(keys, valueKey{bidder: bidder})
+}
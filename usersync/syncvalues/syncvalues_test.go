@@ -0,0 +1,46 @@
+package syncvalues
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(&config.SyncValues{}))
+	assert.IsType(t, &FileValues{}, New(&config.SyncValues{Source: config.SyncValuesSourceFile, File: config.SyncValuesFile{Path: "missing.json"}}))
+	assert.IsType(t, &AuctionValues{}, New(&config.SyncValues{Source: config.SyncValuesSourceAuction, Auction: config.SyncValuesAuction{Weight: 0.1, MaxEntries: 10}}))
+}
+
+func TestLookup(t *testing.T) {
+	values := map[valueKey]float64{
+		{bidder: "a"}:                                  1,
+		{country: "USA", bidder: "a"}:                  2,
+		{account: "1001", bidder: "a"}:                 3,
+		{account: "1001", country: "USA", bidder: "a"}: 4,
+	}
+
+	testCases := []struct {
+		description   string
+		givenAccount  string
+		givenCountry  string
+		givenBidder   string
+		expectedValue float64
+		expectedFound bool
+	}{
+		{description: "account-and-country", givenAccount: "1001", givenCountry: "USA", givenBidder: "a", expectedValue: 4, expectedFound: true},
+		{description: "account", givenAccount: "1001", givenCountry: "CAN", givenBidder: "a", expectedValue: 3, expectedFound: true},
+		{description: "country", givenAccount: "1002", givenCountry: "USA", givenBidder: "a", expectedValue: 2, expectedFound: true},
+		{description: "global", givenAccount: "1002", givenCountry: "", givenBidder: "a", expectedValue: 1, expectedFound: true},
+		{description: "unknown-bidder", givenAccount: "1001", givenCountry: "USA", givenBidder: "b", expectedValue: 0, expectedFound: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			value, found := lookup(values, test.givenAccount, test.givenCountry, test.givenBidder)
+			assert.Equal(t, test.expectedValue, value)
+			assert.Equal(t, test.expectedFound, found)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package uidstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coocood/freecache"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// MemoryStore keeps the UIDs in a bounded in-process cache, so they're only shared by the requests served by
+// the same Prebid Server instance.
+type MemoryStore struct {
+	cache *freecache.Cache
+	// mutex serializes the updates, which read and write the whole set of UIDs of a user
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+// NewMemoryStore returns a MemoryStore which holds up to sizeBytes of UIDs, evicting the least recently used users.
+func NewMemoryStore(sizeBytes int) *MemoryStore {
+	return &MemoryStore{
+		cache: freecache.NewCache(sizeBytes),
+		now:   time.Now,
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, id string) (map[string]usersync.UIDEntry, error) {
+	entries, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	liveEntries, _ := live(entries, s.now())
+	return liveEntries, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, id string, key string, entry usersync.UIDEntry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	entries[key] = entry
+	return s.put(id, entries)
+}
+
+func (s *MemoryStore) Delete(_ context.Context, id string, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if key == "" {
+		s.cache.Del([]byte(id))
+		return nil
+	}
+
+	entries, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	delete(entries, key)
+	return s.put(id, entries)
+}
+
+func (s *MemoryStore) get(id string) (map[string]usersync.UIDEntry, error) {
+	entries := make(map[string]usersync.UIDEntry)
+
+	value, err := s.cache.Get([]byte(id))
+	if err == freecache.ErrNotFound {
+		return entries, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := jsonutil.UnmarshalValid(value, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) put(id string, entries map[string]usersync.UIDEntry) error {
+	now := s.now()
+	liveEntries, latest := live(entries, now)
+	if len(liveEntries) == 0 {
+		s.cache.Del([]byte(id))
+		return nil
+	}
+
+	value, err := jsonutil.Marshal(liveEntries)
+	if err != nil {
+		return err
+	}
+	// round up so the user isn't evicted before its last UID expires
+	ttlSeconds := int(latest.Sub(now)/time.Second) + 1
+	return s.cache.Set([]byte(id), value, ttlSeconds)
+}
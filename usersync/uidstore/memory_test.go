@@ -0,0 +1,52 @@
+package uidstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	store := NewMemoryStore(1024 * 1024)
+	store.now = func() time.Time { return now }
+
+	entries, err := store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NoError(t, store.Set(ctx, "user", "adnxs", usersync.UIDEntry{UID: "adnxs-uid", Expires: now.Add(time.Hour)}))
+	assert.NoError(t, store.Set(ctx, "user", "pubmatic", usersync.UIDEntry{UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)}))
+	assert.NoError(t, store.Set(ctx, "other-user", "adnxs", usersync.UIDEntry{UID: "other-uid", Expires: now.Add(time.Hour)}))
+
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"adnxs":    {UID: "adnxs-uid", Expires: now.Add(time.Hour)},
+		"pubmatic": {UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)},
+	}, entries)
+
+	// expired entries are left out
+	store.now = func() time.Time { return now.Add(90 * time.Minute) }
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"pubmatic": {UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)},
+	}, entries)
+
+	assert.NoError(t, store.Delete(ctx, "user", "pubmatic"))
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	store.now = func() time.Time { return now }
+	assert.NoError(t, store.Delete(ctx, "other-user", ""))
+	entries, err = store.Get(ctx, "other-user")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
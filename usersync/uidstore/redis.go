@@ -0,0 +1,75 @@
+package uidstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the UIDs of each user in a Redis hash, one field per syncer key, which expires along with
+// the latest UID stored in it.
+type RedisStore struct {
+	keyPrefix string
+	client    *redis.Client
+	now       func() time.Time
+}
+
+// NewRedisStore returns a RedisStore connecting to the configured server. Connections are opened on demand.
+func NewRedisStore(cfg config.UIDStoreRedis) *RedisStore {
+	return &RedisStore{
+		keyPrefix: cfg.KeyPrefix,
+		client: redis.NewClient(&redis.Options{
+			Addr:         cfg.Address,
+			Password:     cfg.Password,
+			MaxIdleConns: cfg.MaxIdle,
+		}),
+		now: time.Now,
+	}
+}
+
+func (s *RedisStore) Get(ctx context.Context, id string) (map[string]usersync.UIDEntry, error) {
+	fields, err := s.client.HGetAll(ctx, s.keyPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]usersync.UIDEntry, len(fields))
+	for key, value := range fields {
+		var entry usersync.UIDEntry
+		if err := jsonutil.UnmarshalValid([]byte(value), &entry); err != nil {
+			continue
+		}
+		entries[key] = entry
+	}
+
+	liveEntries, _ := live(entries, s.now())
+	return liveEntries, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, id string, key string, entry usersync.UIDEntry) error {
+	value, err := jsonutil.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	redisKey := s.keyPrefix + id
+	// UIDs are always stored with the same lifetime, so the latest one written outlives the others
+	ttl := entry.Expires.Sub(s.now()).Truncate(time.Second) + time.Second
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, redisKey, key, string(value))
+		pipe.Expire(ctx, redisKey, ttl)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) Delete(ctx context.Context, id string, key string) error {
+	if key == "" {
+		return s.client.Del(ctx, s.keyPrefix+id).Err()
+	}
+	return s.client.HDel(ctx, s.keyPrefix+id, key).Err()
+}
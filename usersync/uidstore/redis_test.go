@@ -0,0 +1,77 @@
+package uidstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	store := NewRedisStore(config.UIDStoreRedis{Address: server.Addr(), Password: "secret", KeyPrefix: "uids:", MaxIdle: 1})
+	store.now = func() time.Time { return now }
+
+	entries, err := store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NoError(t, store.Set(ctx, "user", "adnxs", usersync.UIDEntry{UID: "adnxs-uid", Expires: now.Add(time.Hour)}))
+	assert.NoError(t, store.Set(ctx, "user", "pubmatic", usersync.UIDEntry{UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)}))
+	assert.Equal(t, 7201*time.Second, server.TTL("uids:user"))
+
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"adnxs":    {UID: "adnxs-uid", Expires: now.Add(time.Hour)},
+		"pubmatic": {UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)},
+	}, entries)
+
+	// expired entries are left out
+	store.now = func() time.Time { return now.Add(90 * time.Minute) }
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]usersync.UIDEntry{
+		"pubmatic": {UID: "pubmatic-uid", Expires: now.Add(2 * time.Hour)},
+	}, entries)
+
+	assert.NoError(t, store.Delete(ctx, "user", "pubmatic"))
+	entries, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NoError(t, store.Delete(ctx, "user", ""))
+	assert.False(t, server.Exists("uids:user"))
+}
+
+func TestRedisStoreErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+	ctx := context.Background()
+
+	store := NewRedisStore(config.UIDStoreRedis{Address: server.Addr(), Password: "wrong", KeyPrefix: "uids:"})
+	_, err := store.Get(ctx, "user")
+	assert.Error(t, err, "Authentication failure must be returned.")
+
+	store = NewRedisStore(config.UIDStoreRedis{Address: server.Addr(), Password: "secret", KeyPrefix: "uids:", MaxIdle: 1})
+	server.SetError("server down")
+	_, err = store.Get(ctx, "user")
+	assert.Error(t, err, "Error replies must be returned.")
+
+	// the client is still usable after an error reply
+	server.SetError("")
+	_, err = store.Get(ctx, "user")
+	assert.NoError(t, err)
+
+	unreachable := NewRedisStore(config.UIDStoreRedis{Address: "127.0.0.1:1"})
+	_, err = unreachable.Get(ctx, "user")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package uidstore
+
+import (
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/usersync"
+)
+
+// New returns the UID store configured for the host, or nil if the store is disabled.
+func New(cfg *config.UIDStore) usersync.UIDStore {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Backend {
+	case config.UIDStoreBackendRedis:
+		return NewRedisStore(cfg.Redis)
+	default:
+		return NewMemoryStore(cfg.Memory.SizeBytes)
+	}
+}
+
+// live returns the entries which haven't expired yet, along with the latest expiry among them.
+func live(entries map[string]usersync.UIDEntry, now time.Time) (map[string]usersync.UIDEntry, time.Time) {
+	var latest time.Time
+	liveEntries := make(map[string]usersync.UIDEntry, len(entries))
+	for key, entry := range entries {
+		if now.Before(entry.Expires) {
+			liveEntries[key] = entry
+			if entry.Expires.After(latest) {
+				latest = entry.Expires
+			}
+		}
+	}
+	return liveEntries, latest
+}
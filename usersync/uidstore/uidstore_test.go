@@ -0,0 +1,14 @@
+package uidstore
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(&config.UIDStore{Enabled: false, Backend: config.UIDStoreBackendMemory}))
+	assert.IsType(t, &MemoryStore{}, New(&config.UIDStore{Enabled: true, Backend: config.UIDStoreBackendMemory, Memory: config.UIDStoreMemory{SizeBytes: 1024 * 1024}}))
+	assert.IsType(t, &RedisStore{}, New(&config.UIDStore{Enabled: true, Backend: config.UIDStoreBackendRedis, Redis: config.UIDStoreRedis{Address: "localhost:6379"}}))
+}
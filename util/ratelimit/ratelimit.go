@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// Limiter limits the rate of the requests of each key, such as a client IP, with a token bucket per key. The
+// number of keys tracked is bounded, the least recently seen are forgotten first.
+type Limiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	maxKeys int
+	time    timeutil.Time
+
+	mutex   sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter allowing requestsPerMinute sustained requests per key, and burst requests at once. The
+// burst defaults to requestsPerMinute if 0. It returns nil, which allows every request, if requestsPerMinute
+// is 0.
+func New(requestsPerMinute, burst, maxKeys int) *Limiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = requestsPerMinute
+	}
+	return &Limiter{
+		rate:    float64(requestsPerMinute) / 60,
+		burst:   float64(burst),
+		maxKeys: maxKeys,
+		time:    &timeutil.RealTime{},
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Allow returns true if a request of the key is allowed, and consumes a token of the key if so.
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	now := l.time.Now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var b *bucket
+	if element, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(element)
+		b = element.Value.(*bucket)
+		if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+			b.tokens = min(l.burst, b.tokens+elapsed*l.rate)
+		}
+		b.last = now
+	} else {
+		b = &bucket{key: key, tokens: l.burst, last: now}
+		l.buckets[key] = l.lru.PushFront(b)
+		for l.lru.Len() > l.maxKeys {
+			oldest := l.lru.Back()
+			l.lru.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
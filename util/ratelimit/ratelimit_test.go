@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
+
+func TestNewDisabled(t *testing.T) {
+	limiter := New(0, 10, 10)
+
+	assert.Nil(t, limiter)
+	for i := 0; i < 100; i++ {
+		assert.True(t, limiter.Allow("1.2.3.4"))
+	}
+}
+
+func TestAllow(t *testing.T) {
+	clock := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := New(60, 2, 10)
+	limiter.time = clock
+
+	assert.True(t, limiter.Allow("a"), "burst 1")
+	assert.True(t, limiter.Allow("a"), "burst 2")
+	assert.False(t, limiter.Allow("a"), "burst exhausted")
+	assert.True(t, limiter.Allow("b"), "other key")
+
+	clock.time = clock.time.Add(500 * time.Millisecond)
+	assert.False(t, limiter.Allow("a"), "half a token refilled")
+
+	clock.time = clock.time.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("a"), "one token refilled")
+	assert.False(t, limiter.Allow("a"), "refilled token consumed")
+
+	clock.time = clock.time.Add(time.Hour)
+	assert.True(t, limiter.Allow("a"), "refilled up to burst 1")
+	assert.True(t, limiter.Allow("a"), "refilled up to burst 2")
+	assert.False(t, limiter.Allow("a"), "refill capped to burst")
+}
+
+func TestAllowBurstDefault(t *testing.T) {
+	limiter := New(3, 0, 10)
+	limiter.time = &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+}
+
+func TestAllowEvictsLeastRecentlySeen(t *testing.T) {
+	limiter := New(1, 1, 2)
+	limiter.time = &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("b"))
+	assert.False(t, limiter.Allow("a"), "a still tracked")
+	assert.True(t, limiter.Allow("c"), "b evicted")
+
+	assert.Len(t, limiter.buckets, 2)
+	assert.NotContains(t, limiter.buckets, "b")
+	assert.True(t, limiter.Allow("b"), "b forgotten")
+}
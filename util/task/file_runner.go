@@ -0,0 +1,48 @@
+package task
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// fileRunner invokes the load function with the file contents whenever the file
+// modification time differs from the one observed at the previous successful load.
+type fileRunner struct {
+	path    string
+	load    func(data []byte) error
+	modTime time.Time
+	mutex   sync.Mutex
+}
+
+func (r *fileRunner) Run() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+
+	if !r.modTime.IsZero() && info.ModTime().Equal(r.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.load(data); err != nil {
+		return err
+	}
+
+	r.modTime = info.ModTime()
+	return nil
+}
+
+// NewFileRunner returns a Runner which reloads the file located at path only when it has changed
+// since the previous successful run. The first run always loads the file.
+func NewFileRunner(path string, load func(data []byte) error) Runner {
+	return &fileRunner{path: path, load: load}
+}
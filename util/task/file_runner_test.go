@@ -0,0 +1,58 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRunner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("first"), 0644))
+
+	var loaded []string
+	runner := NewFileRunner(path, func(data []byte) error {
+		loaded = append(loaded, string(data))
+		return nil
+	})
+
+	// first run loads the file
+	assert.NoError(t, runner.Run())
+	assert.Equal(t, []string{"first"}, loaded)
+
+	// unchanged file is not reloaded
+	assert.NoError(t, runner.Run())
+	assert.Equal(t, []string{"first"}, loaded)
+
+	// changed file is reloaded
+	assert.NoError(t, os.WriteFile(path, []byte("second"), 0644))
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, future, future))
+	assert.NoError(t, runner.Run())
+	assert.Equal(t, []string{"first", "second"}, loaded)
+}
+
+func TestFileRunnerErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+
+	loadCalls := 0
+	loadErr := errors.New("invalid data")
+	runner := NewFileRunner(path, func(data []byte) error {
+		loadCalls++
+		return loadErr
+	})
+
+	// missing file
+	assert.Error(t, runner.Run())
+	assert.Equal(t, 0, loadCalls)
+
+	// failed load is retried on the next run
+	assert.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+	assert.Equal(t, loadErr, runner.Run())
+	assert.Equal(t, loadErr, runner.Run())
+	assert.Equal(t, 2, loadCalls)
+}